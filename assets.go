@@ -0,0 +1,10 @@
+// Package e2clicker exposes files from the repository root that the backend
+// needs at runtime.
+package e2clicker
+
+import _ "embed"
+
+// LogoPNG is the e2clicker logo as a 512x512 PNG image.
+//
+//go:embed assets/logo.png
+var LogoPNG []byte
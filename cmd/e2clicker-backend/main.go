@@ -8,6 +8,10 @@ import (
 	"os"
 	"strings"
 
+	// Users' time zones are loaded by name, and the server may run somewhere
+	// without a time zone database.
+	_ "time/tzdata"
+
 	"e2clicker.app/services/api"
 	"e2clicker.app/services/dosage"
 	"e2clicker.app/services/notification"
@@ -91,6 +95,14 @@ func main() {
 		fx.Invoke(func(*dosage.DosageReminderService) {
 			slog.Info("Dosage reminder service started successfully")
 		}),
+		// Invoke the background MQTT state publisher.
+		fx.Invoke(func(*dosage.DosageMQTTService) {}),
+		// Invoke the background digest sender.
+		fx.Invoke(func(*dosage.DosageDigestService) {}),
+		// Invoke the background expired session cleanup.
+		fx.Invoke(func(*user.SessionCleanupService) {}),
+		// Invoke the background purge of deleted accounts.
+		fx.Invoke(func(*user.AccountDeletionService) {}),
 	).Run()
 }
 
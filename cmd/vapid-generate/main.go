@@ -1,27 +1,127 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/SherClockHolmes/webpush-go"
+	"github.com/spf13/pflag"
+
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
+)
+
+// vapidKey is the type of a previous key in the keys file.
+type vapidKey = struct {
+	PrivateKey string `json:"privateKey"`
+	PublicKey  string `json:"publicKey"`
+}
+
+var (
+	addTo string
+	keyID string
 )
 
 func init() {
 	log.SetFlags(0)
+
+	pflag.StringVar(&addTo, "add", addTo, "Add a new current key to an existing keys file, keeping the old key as a previous key")
+	pflag.StringVar(&keyID, "id", keyID, "ID of the new key, random if empty")
 }
 
 func main() {
+	pflag.Parse()
+
+	if keyID == "" {
+		keyID = randomKeyID()
+	}
+
 	priv, pub, err := webpush.GenerateVAPIDKeys()
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	enc := json.NewEncoder(os.Stdout)
+	if addTo == "" {
+		if err := writeKeys(os.Stdout, e2clickermodule.WebPushSubmodule{
+			ID:           keyID,
+			PreviousKeys: map[string]vapidKey{},
+			PrivateKey:   priv,
+			PublicKey:    pub,
+		}); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
+	if err := addKey(addTo, keyID, priv, pub); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// addKey makes the given key the current key of the keys file at path. The
+// current key is moved to the previous keys, so that existing subscriptions
+// keep working.
+func addKey(path, id, priv, pub string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var keys e2clickermodule.WebPushSubmodule
+	if err := json.Unmarshal(b, &keys); err != nil {
+		return fmt.Errorf("cannot unmarshal %s: %w", path, err)
+	}
+
+	if _, ok := keys.PreviousKeys[id]; ok || id == keys.ID {
+		return fmt.Errorf("key %q already exists in %s", id, path)
+	}
+
+	if keys.PreviousKeys == nil {
+		keys.PreviousKeys = make(map[string]vapidKey)
+	}
+	keys.PreviousKeys[keys.ID] = vapidKey{
+		PrivateKey: keys.PrivateKey,
+		PublicKey:  keys.PublicKey,
+	}
+
+	keys.ID = id
+	keys.PrivateKey = priv
+	keys.PublicKey = pub
+
+	// Write to a temporary file first so that the keys are never lost halfway.
+	f, err := os.CreateTemp(filepath.Dir(path), ".vapid-keys-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := f.Chmod(0600); err != nil {
+		return err
+	}
+	if err := writeKeys(f, keys); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func writeKeys(f *os.File, keys e2clickermodule.WebPushSubmodule) error {
+	enc := json.NewEncoder(f)
 	enc.SetIndent("", "  ")
-	enc.Encode(map[string]string{
-		"publicKey":  pub,
-		"privateKey": priv,
-	})
+	return enc.Encode(keys)
+}
+
+func randomKeyID() string {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b[:])
 }
@@ -16,11 +16,11 @@ import (
 
 const deleteDosageSchedule = `-- name: DeleteDosageSchedule :exec
 DELETE FROM dosage_schedule
-WHERE user_secret = $1
+WHERE user_id = $1
 `
 
-func (q *Queries) DeleteDosageSchedule(ctx context.Context, userSecret userservice.Secret) error {
-	_, err := q.db.Exec(ctx, deleteDosageSchedule, userSecret)
+func (q *Queries) DeleteDosageSchedule(ctx context.Context, userID userservice.ID) error {
+	_, err := q.db.Exec(ctx, deleteDosageSchedule, userID)
 	return err
 }
 
@@ -28,28 +28,28 @@ const dosageSchedule = `-- name: DosageSchedule :one
 /*
  * Dosage and dosage-related
  */
-SELECT user_secret, delivery_method, dose, interval, concurrence
+SELECT delivery_method, dose, interval, concurrence, user_id
 FROM dosage_schedule
-WHERE user_secret = $1
+WHERE user_id = $1
 `
 
-func (q *Queries) DosageSchedule(ctx context.Context, userSecret userservice.Secret) (DosageSchedule, error) {
-	row := q.db.QueryRow(ctx, dosageSchedule, userSecret)
+func (q *Queries) DosageSchedule(ctx context.Context, userID userservice.ID) (DosageSchedule, error) {
+	row := q.db.QueryRow(ctx, dosageSchedule, userID)
 	var i DosageSchedule
 	err := row.Scan(
-		&i.UserSecret,
 		&i.DeliveryMethod,
 		&i.Dose,
 		&i.Interval,
 		&i.Concurrence,
+		&i.UserID,
 	)
 	return i, err
 }
 
 const doseHistory = `-- name: DoseHistory :iter
-SELECT user_secret, delivery_method, dose, taken_at, taken_off_at, comment
+SELECT delivery_method, dose, taken_at, taken_off_at, comment, user_id
 FROM dosage_history
-WHERE user_secret = $1
+WHERE user_id = $1
   AND taken_at >= $2
   AND taken_at < $3
   -- order latest last
@@ -57,13 +57,13 @@ ORDER BY taken_at ASC
 `
 
 type DoseHistoryParams struct {
-	UserSecret userservice.Secret
-	Start      pgtype.Timestamptz
-	End        pgtype.Timestamptz
+	UserID userservice.ID
+	Start  pgtype.Timestamptz
+	End    pgtype.Timestamptz
 }
 
 func (q *Queries) DoseHistory(ctx context.Context, arg DoseHistoryParams) DoseHistoryRows {
-	rows, err := q.db.Query(ctx, doseHistory, arg.UserSecret, arg.Start, arg.End)
+	rows, err := q.db.Query(ctx, doseHistory, arg.UserID, arg.Start, arg.End)
 	if err != nil {
 		return DoseHistoryRows{err: err}
 	}
@@ -86,12 +86,12 @@ func (r *DoseHistoryRows) Iterate() iter.Seq[DosageHistory] {
 		for r.rows.Next() {
 			var i DosageHistory
 			err := r.rows.Scan(
-				&i.UserSecret,
 				&i.DeliveryMethod,
 				&i.Dose,
 				&i.TakenAt,
 				&i.TakenOffAt,
 				&i.Comment,
+				&i.UserID,
 			)
 			if err != nil {
 				r.err = err
@@ -120,7 +120,7 @@ const editDose = `-- name: EditDose :execrows
 UPDATE
   dosage_history
 SET delivery_method = $1, dose = $2, taken_at = $3, taken_off_at = $4
-WHERE user_secret = $5
+WHERE user_id = $5
   AND taken_at = $6
 `
 
@@ -129,7 +129,7 @@ type EditDoseParams struct {
 	Dose           float32
 	TakenAt        pgtype.Timestamptz
 	TakenOffAt     pgtype.Timestamptz
-	UserSecret     userservice.Secret
+	UserID         userservice.ID
 	OldTakenAt     pgtype.Timestamptz
 }
 
@@ -139,7 +139,7 @@ func (q *Queries) EditDose(ctx context.Context, arg EditDoseParams) (int64, erro
 		arg.Dose,
 		arg.TakenAt,
 		arg.TakenOffAt,
-		arg.UserSecret,
+		arg.UserID,
 		arg.OldTakenAt,
 	)
 	if err != nil {
@@ -150,30 +150,48 @@ func (q *Queries) EditDose(ctx context.Context, arg EditDoseParams) (int64, erro
 
 const forgetDoses = `-- name: ForgetDoses :execrows
 DELETE FROM dosage_history
-WHERE user_secret = $1
+WHERE user_id = $1
   AND taken_at = ANY ($2::timestamp[])
 `
 
 type ForgetDosesParams struct {
-	UserSecret userservice.Secret
-	TakenAt    []pgtype.Timestamp
+	UserID  userservice.ID
+	TakenAt []pgtype.Timestamp
 }
 
 func (q *Queries) ForgetDoses(ctx context.Context, arg ForgetDosesParams) (int64, error) {
-	result, err := q.db.Exec(ctx, forgetDoses, arg.UserSecret, arg.TakenAt)
+	result, err := q.db.Exec(ctx, forgetDoses, arg.UserID, arg.TakenAt)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected(), nil
 }
 
+const hasDosageData = `-- name: HasDosageData :one
+SELECT (EXISTS (
+    SELECT 1
+    FROM dosage_schedule
+    WHERE dosage_schedule.user_id = $1)
+  OR EXISTS (
+    SELECT 1
+    FROM dosage_history
+    WHERE dosage_history.user_id = $1))::boolean AS has_data
+`
+
+func (q *Queries) HasDosageData(ctx context.Context, userID userservice.ID) (bool, error) {
+	row := q.db.QueryRow(ctx, hasDosageData, userID)
+	var has_data bool
+	err := row.Scan(&has_data)
+	return has_data, err
+}
+
 const recordDose = `-- name: RecordDose :exec
-INSERT INTO dosage_history (user_secret, delivery_method, dose, taken_at, taken_off_at)
+INSERT INTO dosage_history (user_id, delivery_method, dose, taken_at, taken_off_at)
   VALUES ($1, $2, $3, $4, $5)
 `
 
 type RecordDoseParams struct {
-	UserSecret     userservice.Secret
+	UserID         userservice.ID
 	DeliveryMethod pgtype.Text
 	Dose           float32
 	TakenAt        pgtype.Timestamptz
@@ -182,7 +200,7 @@ type RecordDoseParams struct {
 
 func (q *Queries) RecordDose(ctx context.Context, arg RecordDoseParams) error {
 	_, err := q.db.Exec(ctx, recordDose,
-		arg.UserSecret,
+		arg.UserID,
 		arg.DeliveryMethod,
 		arg.Dose,
 		arg.TakenAt,
@@ -192,12 +210,12 @@ func (q *Queries) RecordDose(ctx context.Context, arg RecordDoseParams) error {
 }
 
 const recordRemindedDoseAttempt = `-- name: RecordRemindedDoseAttempt :exec
-INSERT INTO notification_history (user_secret, sent_at, supposed_entity_time, error_reason)
+INSERT INTO notification_history (user_id, sent_at, supposed_entity_time, error_reason)
   VALUES ($1, $2, $3, $4)
 `
 
 type RecordRemindedDoseAttemptParams struct {
-	UserSecret         userservice.Secret
+	UserID             userservice.ID
 	SentAt             pgtype.Timestamptz
 	SupposedEntityTime pgtype.Timestamptz
 	ErrorReason        pgtype.Text
@@ -205,7 +223,7 @@ type RecordRemindedDoseAttemptParams struct {
 
 func (q *Queries) RecordRemindedDoseAttempt(ctx context.Context, arg RecordRemindedDoseAttemptParams) error {
 	_, err := q.db.Exec(ctx, recordRemindedDoseAttempt,
-		arg.UserSecret,
+		arg.UserID,
 		arg.SentAt,
 		arg.SupposedEntityTime,
 		arg.ErrorReason,
@@ -213,16 +231,75 @@ func (q *Queries) RecordRemindedDoseAttempt(ctx context.Context, arg RecordRemin
 	return err
 }
 
+const reminderHistory = `-- name: ReminderHistory :many
+SELECT supposed_entity_time, sent_at, error_reason
+FROM notification_history
+WHERE user_id = $1
+ORDER BY sent_at ASC
+`
+
+type ReminderHistoryRow struct {
+	SupposedEntityTime pgtype.Timestamptz
+	SentAt             pgtype.Timestamptz
+	ErrorReason        pgtype.Text
+}
+
+func (q *Queries) ReminderHistory(ctx context.Context, userID userservice.ID) ([]ReminderHistoryRow, error) {
+	rows, err := q.db.Query(ctx, reminderHistory, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReminderHistoryRow
+	for rows.Next() {
+		var i ReminderHistoryRow
+		if err := rows.Scan(&i.SupposedEntityTime, &i.SentAt, &i.ErrorReason); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reminderHistoryCounts = `-- name: ReminderHistoryCounts :one
+SELECT count(*) FILTER (WHERE NOT errored) AS sent, count(*) FILTER (WHERE errored) AS failed
+FROM notification_history
+WHERE user_id = $1
+  AND sent_at >= $2
+  AND sent_at < $3
+`
+
+type ReminderHistoryCountsParams struct {
+	UserID userservice.ID
+	Start  pgtype.Timestamptz
+	End    pgtype.Timestamptz
+}
+
+type ReminderHistoryCountsRow struct {
+	Sent   int64
+	Failed int64
+}
+
+func (q *Queries) ReminderHistoryCounts(ctx context.Context, arg ReminderHistoryCountsParams) (ReminderHistoryCountsRow, error) {
+	row := q.db.QueryRow(ctx, reminderHistoryCounts, arg.UserID, arg.Start, arg.End)
+	var i ReminderHistoryCountsRow
+	err := row.Scan(&i.Sent, &i.Failed)
+	return i, err
+}
+
 const setDosageSchedule = `-- name: SetDosageSchedule :exec
-INSERT INTO dosage_schedule (user_secret, delivery_method, dose, interval, concurrence)
+INSERT INTO dosage_schedule (user_id, delivery_method, dose, interval, concurrence)
   VALUES ($1, $2, $3, $4, $5)
-ON CONFLICT (user_secret)
+ON CONFLICT (user_id)
   DO UPDATE SET
     delivery_method = $2, dose = $3, interval = $4, concurrence = $5
 `
 
 type SetDosageScheduleParams struct {
-	UserSecret     userservice.Secret
+	UserID         userservice.ID
 	DeliveryMethod pgtype.Text
 	Dose           float32
 	Interval       pgtype.Interval
@@ -231,7 +308,7 @@ type SetDosageScheduleParams struct {
 
 func (q *Queries) SetDosageSchedule(ctx context.Context, arg SetDosageScheduleParams) error {
 	_, err := q.db.Exec(ctx, setDosageSchedule,
-		arg.UserSecret,
+		arg.UserID,
 		arg.DeliveryMethod,
 		arg.Dose,
 		arg.Interval,
@@ -241,21 +318,22 @@ func (q *Queries) SetDosageSchedule(ctx context.Context, arg SetDosageSchedulePa
 }
 
 const upcomingDosageReminders = `-- name: UpcomingDosageReminders :iter
-SELECT DISTINCT ON (users.secret)
-  users.secret AS user_secret, users.name AS user_name, dosage_schedule.user_secret, dosage_schedule.delivery_method, dosage_schedule.dose, dosage_schedule.interval, dosage_schedule.concurrence,
-    dosage_history.user_secret, dosage_history.delivery_method, dosage_history.dose, dosage_history.taken_at, dosage_history.taken_off_at, dosage_history.comment, -- 
+SELECT DISTINCT ON (users.id)
+  users.id AS user_id, users.name AS user_name, dosage_schedule.delivery_method, dosage_schedule.dose, dosage_schedule.interval, dosage_schedule.concurrence, dosage_schedule.user_id,
+    dosage_history.delivery_method, dosage_history.dose, dosage_history.taken_at, dosage_history.taken_off_at, dosage_history.comment, dosage_history.user_id, -- 
   (
     SELECT supposed_entity_time
     FROM notification_history
-    WHERE user_secret = users.secret ORDER BY supposed_entity_time DESC LIMIT 1) AS last_notification_time
+    WHERE user_id = users.id ORDER BY supposed_entity_time DESC LIMIT 1) AS last_notification_time
 FROM users
-  INNER JOIN dosage_schedule ON users.secret = dosage_schedule.user_secret
-  INNER JOIN dosage_history ON users.secret = dosage_history.user_secret
-ORDER BY users.secret, dosage_history.taken_at DESC
+  INNER JOIN dosage_schedule ON users.id = dosage_schedule.user_id
+  INNER JOIN dosage_history ON users.id = dosage_history.user_id
+WHERE users.purge_at IS NULL
+ORDER BY users.id, dosage_history.taken_at DESC
 `
 
 type UpcomingDosageRemindersRow struct {
-	UserSecret           userservice.Secret
+	UserID               userservice.ID
 	UserName             string
 	DosageSchedule       DosageSchedule
 	DosageHistory        DosageHistory
@@ -286,19 +364,19 @@ func (r *UpcomingDosageRemindersRows) Iterate() iter.Seq[UpcomingDosageReminders
 		for r.rows.Next() {
 			var i UpcomingDosageRemindersRow
 			err := r.rows.Scan(
-				&i.UserSecret,
+				&i.UserID,
 				&i.UserName,
-				&i.DosageSchedule.UserSecret,
 				&i.DosageSchedule.DeliveryMethod,
 				&i.DosageSchedule.Dose,
 				&i.DosageSchedule.Interval,
 				&i.DosageSchedule.Concurrence,
-				&i.DosageHistory.UserSecret,
+				&i.DosageSchedule.UserID,
 				&i.DosageHistory.DeliveryMethod,
 				&i.DosageHistory.Dose,
 				&i.DosageHistory.TakenAt,
 				&i.DosageHistory.TakenOffAt,
 				&i.DosageHistory.Comment,
+				&i.DosageHistory.UserID,
 				&i.LastNotificationTime,
 			)
 			if err != nil {
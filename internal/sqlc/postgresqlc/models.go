@@ -18,20 +18,20 @@ type DeliveryMethod struct {
 }
 
 type DosageHistory struct {
-	UserSecret     userservice.Secret
 	DeliveryMethod pgtype.Text
 	Dose           float32
 	TakenAt        pgtype.Timestamptz
 	TakenOffAt     pgtype.Timestamptz
 	Comment        pgtype.Text
+	UserID         userservice.ID
 }
 
 type DosageSchedule struct {
-	UserSecret     userservice.Secret
 	DeliveryMethod pgtype.Text
 	Dose           float32
 	Interval       pgtype.Interval
 	Concurrence    pgtype.Int2
+	UserID         userservice.ID
 }
 
 type Meta struct {
@@ -41,26 +41,91 @@ type Meta struct {
 
 type NotificationHistory struct {
 	NotificationID     pgtype.UUID
-	UserSecret         userservice.Secret
 	SupposedEntityTime pgtype.Timestamptz
 	SentAt             pgtype.Timestamptz
 	ErrorReason        pgtype.Text
 	Errored            pgtype.Bool
+	UserID             userservice.ID
+}
+
+type ShareLink struct {
+	ID         int64
+	UserID     userservice.ID
+	TokenHash  []byte
+	Name       string
+	RangeStart pgtype.Timestamptz
+	RangeEnd   pgtype.Timestamptz
+	Fields     []string
+	PinHash    []byte
+	CreatedAt  pgtype.Timestamptz
+	ExpiresAt  pgtype.Timestamptz
+	RevokedAt  pgtype.Timestamptz
+}
+
+type ShareLinkAccess struct {
+	ShareLinkID int64
+	AccessedAt  pgtype.Timestamptz
+	UserAgent   pgtype.Text
+	Granted     bool
+	LockedOut   bool
 }
 
 type User struct {
-	Secret                  userservice.Secret
+	Secret                  interface{}
 	Name                    string
 	Locale                  userservice.Locale
 	RegisteredAt            pgtype.Timestamp
 	NotificationPreferences notificationservice.UserPreferences
+	ID                      userservice.ID
+	SecretHash              []byte
+	PurgeAt                 pgtype.Timestamptz
+}
+
+type UserDelegation struct {
+	ID                  int64
+	OwnerID             userservice.ID
+	DelegateID          *userservice.ID
+	Access              string
+	InviteHash          []byte
+	InviteExpiresAt     pgtype.Timestamptz
+	MirrorNotifications bool
+	CreatedAt           pgtype.Timestamptz
+	AcceptedAt          pgtype.Timestamptz
+}
+
+type UserPasskey struct {
+	ID           int64
+	UserID       userservice.ID
+	CredentialID []byte
+	Credential   []byte
+	Name         string
+	CreatedAt    pgtype.Timestamp
+	LastUsed     pgtype.Timestamp
+}
+
+type UserRecoveryCode struct {
+	UserID    userservice.ID
+	CodeHash  []byte
+	CreatedAt pgtype.Timestamp
 }
 
 type UserSession struct {
-	ID         int64
-	UserSecret userservice.Secret
-	Token      []byte
-	CreatedAt  pgtype.Timestamp
-	LastUsed   pgtype.Timestamp
-	UserAgent  pgtype.Text
+	ID        int64
+	Token     []byte
+	CreatedAt pgtype.Timestamp
+	LastUsed  pgtype.Timestamp
+	UserAgent pgtype.Text
+	UserID    userservice.ID
+	TokenHash []byte
+}
+
+type UserToken struct {
+	ID        int64
+	UserID    userservice.ID
+	Name      string
+	TokenHash []byte
+	Scopes    []string
+	CreatedAt pgtype.Timestamp
+	LastUsed  pgtype.Timestamp
+	ExpiresAt pgtype.Timestamptz
 }
@@ -0,0 +1,58 @@
+/*
+ * User delegations
+ */
+-- name: CreateDelegation :one
+INSERT INTO user_delegations (owner_id, access, invite_hash, invite_expires_at)
+  VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: AcceptDelegation :one
+UPDATE
+  user_delegations
+SET delegate_id = sqlc.arg('delegate_id')::bigint,
+  mirror_notifications = sqlc.arg('mirror_notifications'),
+  invite_hash = NULL,
+  invite_expires_at = NULL,
+  accepted_at = now()
+WHERE invite_hash = sqlc.arg('invite_hash')
+  AND invite_expires_at > now()
+  AND owner_id <> sqlc.arg('delegate_id')::bigint
+RETURNING *;
+
+-- name: ListDelegations :many
+SELECT user_delegations.*, owners.name AS owner_name, delegates.name AS delegate_name
+FROM user_delegations
+  JOIN users owners ON owners.id = user_delegations.owner_id
+  LEFT JOIN users delegates ON delegates.id = user_delegations.delegate_id
+WHERE (user_delegations.owner_id = sqlc.arg('user_id')
+    OR user_delegations.delegate_id = sqlc.arg('user_id'))
+  AND (user_delegations.delegate_id IS NOT NULL
+    OR user_delegations.invite_expires_at > now())
+ORDER BY user_delegations.created_at DESC;
+
+-- name: Delegation :one
+SELECT *
+FROM user_delegations
+WHERE owner_id = sqlc.arg('owner_id')
+  AND delegate_id = sqlc.arg('delegate_id')::bigint
+  AND owner_id IN (
+    SELECT id
+    FROM users
+    WHERE purge_at IS NULL);
+
+-- name: DeleteDelegation :execrows
+DELETE FROM user_delegations
+WHERE id = sqlc.arg('id')
+  AND (owner_id = sqlc.arg('user_id')
+    OR delegate_id = sqlc.arg('user_id'));
+
+-- name: MirroringDelegates :many
+SELECT delegate_id::bigint AS delegate_id
+FROM user_delegations
+WHERE owner_id = $1
+  AND delegate_id IS NOT NULL
+  AND mirror_notifications
+  AND delegate_id IN (
+    SELECT id
+    FROM users
+    WHERE purge_at IS NULL);
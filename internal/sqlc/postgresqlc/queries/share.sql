@@ -0,0 +1,58 @@
+/*
+ * Share links
+ */
+-- name: CreateShareLink :one
+INSERT INTO share_links (user_id, token_hash, name, range_start, range_end, fields, pin_hash, expires_at)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: ListShareLinks :many
+SELECT *
+FROM share_links
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: ShareLinkByToken :one
+SELECT *
+FROM share_links
+WHERE token_hash = $1
+  AND user_id IN (
+    SELECT id
+    FROM users
+    WHERE purge_at IS NULL);
+
+-- name: RevokeShareLink :execrows
+UPDATE
+  share_links
+SET revoked_at = COALESCE(revoked_at, now())
+WHERE user_id = $1
+  AND id = $2;
+
+-- name: LockShareLink :exec
+UPDATE
+  share_links
+SET revoked_at = COALESCE(revoked_at, now())
+WHERE id = $1;
+
+-- name: RecordShareLinkAccess :exec
+INSERT INTO share_link_accesses (share_link_id, user_agent, granted, locked_out)
+  VALUES ($1, $2, $3, $4);
+
+-- name: IncorrectSharePINCount :one
+SELECT count(*)
+FROM share_link_accesses
+WHERE share_link_id = $1
+  AND NOT granted
+  AND accessed_at > COALESCE((
+      SELECT max(accessed_at)
+      FROM share_link_accesses AS a
+      WHERE a.share_link_id = $1
+        AND a.granted), '-infinity');
+
+-- name: ListShareLinkAccesses :many
+SELECT share_link_accesses.*
+FROM share_link_accesses
+  JOIN share_links ON share_links.id = share_link_accesses.share_link_id
+WHERE share_links.user_id = $1
+  AND share_links.id = $2
+ORDER BY share_link_accesses.accessed_at DESC;
@@ -2,26 +2,64 @@
  * User
  */
 -- name: CreateUser :one
-INSERT INTO users (secret, name)
+INSERT INTO users (secret_hash, name)
   VALUES ($1, $2)
 RETURNING *;
 
+-- name: UserIDBySecret :one
+SELECT id
+FROM users
+WHERE secret_hash = $1;
+
 -- name: User :one
 SELECT *
 FROM users
-WHERE secret = $1;
+WHERE id = $1;
 
 -- name: UpdateUserName :exec
 UPDATE
   users
 SET name = $2
-WHERE secret = $1;
+WHERE id = $1;
 
 -- name: UpdateUserLocale :exec
 UPDATE
   users
 SET locale = $2
-WHERE secret = $1;
+WHERE id = $1;
+
+-- name: PlainUserSecrets :many
+SELECT id, secret::text AS secret
+FROM users
+WHERE secret IS NOT NULL;
+
+-- name: SetUserSecretHash :exec
+UPDATE
+  users
+SET secret_hash = $2, secret = NULL
+WHERE id = $1;
+
+-- name: ScheduleUserPurge :exec
+UPDATE
+  users
+SET purge_at = $2
+WHERE id = $1;
+
+-- name: CancelUserPurge :execrows
+UPDATE
+  users
+SET purge_at = NULL
+WHERE id = $1
+  AND purge_at IS NOT NULL;
+
+-- name: UsersDueForPurge :many
+SELECT id
+FROM users
+WHERE purge_at <= now();
+
+-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1;
 
 
 /*
@@ -30,35 +68,173 @@ WHERE secret = $1;
 -- name: UserNotificationPreferences :one
 SELECT notification_preferences
 FROM users
-WHERE secret = $1;
+WHERE id = $1;
+
+-- name: UserNotificationPreferencesForUpdate :one
+SELECT notification_preferences
+FROM users
+WHERE id = $1
+FOR UPDATE;
 
 -- name: SetUserNotificationPreferences :exec
 UPDATE
   users
 SET notification_preferences = $2::jsonb
-WHERE secret = $1;
+WHERE id = $1;
+
+-- name: UsersWithNotificationMethod :iter
+SELECT id
+FROM users
+WHERE notification_preferences -> 'notificationConfigs' @> jsonb_build_array(jsonb_build_object('method', sqlc.arg('method')::text));
+
+-- name: UsersWithDigest :iter
+SELECT id
+FROM users
+WHERE notification_preferences ->> 'digestFrequency' IS NOT NULL
+  AND purge_at IS NULL;
 
 
 /*                                                                                 
  * User Session                                                                    
  */
 -- name: RegisterSession :exec
-INSERT INTO user_sessions (user_secret, token, created_at, last_used, user_agent)
+INSERT INTO user_sessions (user_id, token_hash, created_at, last_used, user_agent)
   VALUES ($1, $2, now(), now(), $3);
 
 -- name: ValidateSession :one
 UPDATE
   user_sessions
 SET last_used = now()
-WHERE token = $1
+WHERE token_hash = sqlc.arg('token_hash')
+  AND (sqlc.narg('max_age')::interval IS NULL
+    OR created_at > now() - sqlc.narg('max_age')::interval)
+  AND (sqlc.narg('max_idle')::interval IS NULL
+    OR last_used > now() - sqlc.narg('max_idle')::interval)
 RETURNING *;
 
 -- name: ListSessions :many
 SELECT *
 FROM user_sessions
-WHERE user_secret = $1;
+WHERE user_id = $1;
 
 -- name: DeleteSession :exec
 DELETE FROM user_sessions
-WHERE user_secret = $1
+WHERE user_id = $1
   AND id = $2;
+
+-- name: DeleteOtherSessions :exec
+DELETE FROM user_sessions
+WHERE user_id = $1
+  AND id != $2;
+
+-- name: DeleteAllSessions :exec
+DELETE FROM user_sessions
+WHERE user_id = $1;
+
+-- name: DeleteExpiredSessions :execrows
+DELETE FROM user_sessions
+WHERE (sqlc.narg('max_age')::interval IS NOT NULL
+    AND created_at <= now() - sqlc.narg('max_age')::interval)
+  OR (sqlc.narg('max_idle')::interval IS NOT NULL
+    AND last_used <= now() - sqlc.narg('max_idle')::interval);
+
+-- name: PlainSessionTokens :many
+SELECT id, token::bytea AS token
+FROM user_sessions
+WHERE token IS NOT NULL;
+
+-- name: SetSessionTokenHash :exec
+UPDATE
+  user_sessions
+SET token_hash = $2, token = NULL
+WHERE id = $1;
+
+
+/*
+ * User Passkeys
+ */
+-- name: AddPasskey :one
+INSERT INTO user_passkeys (user_id, credential_id, credential, name)
+  VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListPasskeys :many
+SELECT *
+FROM user_passkeys
+WHERE user_id = $1
+ORDER BY created_at ASC;
+
+-- name: UpdatePasskeyCredential :exec
+UPDATE
+  user_passkeys
+SET credential = $3, last_used = now()
+WHERE user_id = $1
+  AND credential_id = $2;
+
+-- name: DeletePasskey :execrows
+DELETE FROM user_passkeys
+WHERE user_id = $1
+  AND id = $2;
+
+-- name: DeleteAllPasskeys :exec
+DELETE FROM user_passkeys
+WHERE user_id = $1;
+
+
+/*
+ * User Recovery Codes
+ */
+-- name: AddRecoveryCode :exec
+INSERT INTO user_recovery_codes (user_id, code_hash)
+  VALUES ($1, $2);
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM user_recovery_codes
+WHERE user_id = $1;
+
+-- name: RecoveryCodeCount :one
+SELECT count(*)
+FROM user_recovery_codes
+WHERE user_id = $1;
+
+-- name: UseRecoveryCode :one
+DELETE FROM user_recovery_codes
+WHERE code_hash = $1
+RETURNING user_id;
+
+
+/*
+ * User Tokens
+ */
+-- name: CreateToken :one
+INSERT INTO user_tokens (user_id, name, token_hash, scopes, expires_at)
+  VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListTokens :many
+SELECT *
+FROM user_tokens
+WHERE user_id = $1
+ORDER BY created_at ASC;
+
+-- name: DeleteToken :execrows
+DELETE FROM user_tokens
+WHERE user_id = $1
+  AND id = $2;
+
+-- name: DeleteAllTokens :exec
+DELETE FROM user_tokens
+WHERE user_id = $1;
+
+-- name: ValidateToken :one
+UPDATE
+  user_tokens
+SET last_used = now()
+WHERE token_hash = $1
+  AND (expires_at IS NULL
+    OR expires_at > now())
+  AND user_id IN (
+    SELECT id
+    FROM users
+    WHERE purge_at IS NULL)
+RETURNING *;
@@ -107,3 +107,306 @@ CREATE TABLE notification_history (
   -- True if the notification errored.
   errored boolean GENERATED ALWAYS AS (error_reason IS NOT NULL) STORED
 );
+
+-- NEW VERSION
+UPDATE
+  meta
+SET v = 3;
+
+-- Notification configs are now a list of {method, config} entries instead of
+-- an object of config lists keyed by method.
+UPDATE
+  users
+SET notification_preferences = jsonb_set(notification_preferences, '{notificationConfigs}', coalesce((
+    SELECT
+      jsonb_agg(jsonb_build_object('method', m.method, 'config', c.config) ORDER BY m.method)
+    FROM jsonb_each(notification_preferences -> 'notificationConfigs') AS m (method, configs)
+    CROSS JOIN LATERAL jsonb_array_elements(
+      CASE jsonb_typeof(m.configs)
+      WHEN 'array' THEN
+        m.configs
+      ELSE
+        '[]'::jsonb
+      END) AS c (config)), '[]'::jsonb))
+WHERE
+  jsonb_typeof(notification_preferences -> 'notificationConfigs') = 'object';
+
+-- NEW VERSION
+UPDATE
+  meta
+SET v = 4;
+
+-- Every notification config now has an ID, which its health is tracked by.
+UPDATE
+  users
+SET notification_preferences = jsonb_set(notification_preferences, '{notificationConfigs}', (
+    SELECT
+      jsonb_agg(
+        CASE WHEN c.config ? 'id' THEN
+          c.config
+        ELSE
+          c.config || jsonb_build_object('id', left(replace(gen_random_uuid()::text, '-', ''), 12))
+        END ORDER BY c.i)
+    FROM jsonb_array_elements(notification_preferences -> 'notificationConfigs')
+    WITH ORDINALITY AS c (config, i)))
+WHERE
+  jsonb_typeof(notification_preferences -> 'notificationConfigs') = 'array'
+  AND jsonb_array_length(notification_preferences -> 'notificationConfigs') > 0;
+
+-- NEW VERSION
+UPDATE
+  meta
+SET v = 5;
+
+-- Users are now referred to by a non-secret ID, and their secrets and session
+-- tokens are only stored as keyed hashes. The hashes need the server's pepper,
+-- so the server fills them in when it starts and clears the plain columns,
+-- see [user.UserStorage.HashPlainSecrets].
+ALTER TABLE users
+  ADD COLUMN id bigint GENERATED ALWAYS AS IDENTITY,
+  ADD COLUMN secret_hash bytea UNIQUE,
+  ALTER COLUMN secret DROP NOT NULL;
+
+ALTER TABLE user_sessions
+  ADD COLUMN user_id bigint,
+  ADD COLUMN token_hash bytea UNIQUE,
+  ALTER COLUMN token DROP NOT NULL;
+
+ALTER TABLE dosage_schedule
+  ADD COLUMN user_id bigint;
+
+ALTER TABLE dosage_history
+  ADD COLUMN user_id bigint;
+
+ALTER TABLE notification_history
+  ADD COLUMN user_id bigint;
+
+UPDATE
+  user_sessions
+SET user_id = users.id
+FROM users
+WHERE users.secret = user_sessions.user_secret;
+
+UPDATE
+  dosage_schedule
+SET user_id = users.id
+FROM users
+WHERE users.secret = dosage_schedule.user_secret;
+
+UPDATE
+  dosage_history
+SET user_id = users.id
+FROM users
+WHERE users.secret = dosage_history.user_secret;
+
+UPDATE
+  notification_history
+SET user_id = users.id
+FROM users
+WHERE users.secret = notification_history.user_secret;
+
+-- Dropping the secret columns also drops the constraints and indexes on them.
+ALTER TABLE user_sessions
+  DROP COLUMN user_secret;
+
+ALTER TABLE dosage_schedule
+  DROP COLUMN user_secret;
+
+ALTER TABLE dosage_history
+  DROP COLUMN user_secret;
+
+ALTER TABLE notification_history
+  DROP COLUMN user_secret;
+
+DROP INDEX users_secret;
+
+DROP INDEX user_sessions_token;
+
+ALTER TABLE users
+  DROP CONSTRAINT users_pkey,
+  ADD PRIMARY KEY (id),
+  -- Every user has either a secret that is yet to be hashed or its hash.
+  ADD CHECK (secret IS NOT NULL OR secret_hash IS NOT NULL);
+
+ALTER TABLE user_sessions
+  ALTER COLUMN user_id SET NOT NULL,
+  ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+  ADD CHECK (token IS NOT NULL OR token_hash IS NOT NULL);
+
+ALTER TABLE dosage_schedule
+  ALTER COLUMN user_id SET NOT NULL,
+  ADD PRIMARY KEY (user_id),
+  ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE dosage_history
+  ALTER COLUMN user_id SET NOT NULL,
+  ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+  ADD UNIQUE (user_id, taken_at);
+
+ALTER TABLE notification_history
+  ALTER COLUMN user_id SET NOT NULL,
+  ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+CREATE INDEX user_sessions_user_id ON user_sessions USING HASH (user_id);
+
+CREATE INDEX dosage_history_user_id ON dosage_history USING HASH (user_id);
+
+CREATE INDEX notification_history_user_id ON notification_history USING HASH (user_id);
+
+-- NEW VERSION
+UPDATE
+  meta
+SET v = 6;
+
+CREATE TABLE user_passkeys (
+  -- The passkey ID, used to manage the passkey.
+  id bigint PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  -- The user that the passkey logs in as.
+  user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  -- The WebAuthn credential ID, which the authenticator gives back when
+  -- logging in.
+  credential_id bytea UNIQUE NOT NULL,
+  -- The [webauthn.Credential] type in the Go codebase, which holds the public
+  -- key and the authenticator's state.
+  credential jsonb NOT NULL,
+  -- The name that the user gave the passkey.
+  name text NOT NULL,
+  -- The time the passkey was added.
+  created_at timestamp NOT NULL DEFAULT now(),
+  -- The time the passkey was last used to log in, if ever.
+  last_used timestamp
+);
+
+CREATE INDEX user_passkeys_user_id ON user_passkeys USING HASH (user_id);
+
+-- NEW VERSION
+UPDATE
+  meta
+SET v = 7;
+
+CREATE TABLE user_recovery_codes (
+  -- The user that the recovery code logs in as.
+  user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  -- The keyed hash of the recovery code, like users.secret_hash.
+  code_hash bytea PRIMARY KEY,
+  -- The time the recovery code was generated.
+  created_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX user_recovery_codes_user_id ON user_recovery_codes USING HASH (user_id);
+
+-- NEW VERSION
+UPDATE
+  meta
+SET v = 8;
+
+CREATE TABLE user_tokens (
+  -- The token ID. Like the session ID, it cannot be used to log in.
+  id bigint PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  -- The user that the token belongs to.
+  user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  -- The name that the user gave the token.
+  name text NOT NULL,
+  -- The keyed hash of the token, like user_sessions.token_hash.
+  token_hash bytea UNIQUE NOT NULL,
+  -- The scopes that the token is allowed to be used for.
+  scopes text[] NOT NULL,
+  -- The time the token was created.
+  created_at timestamp NOT NULL DEFAULT now(),
+  -- The time the token was last used, or null if it was never used.
+  last_used timestamp,
+  -- The time the token expires, or null if it never expires.
+  expires_at timestamptz
+);
+
+CREATE INDEX user_tokens_user_id ON user_tokens USING HASH (user_id);
+
+-- NEW VERSION
+UPDATE
+  meta
+SET v = 9;
+
+CREATE TABLE share_links (
+  -- The share link ID, which the owner uses to manage it.
+  id bigint PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  -- The user whose data is shared.
+  user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  -- The hash of the token in the link.
+  token_hash bytea UNIQUE NOT NULL,
+  -- The name that the user gave the share link.
+  name text NOT NULL,
+  -- The range of dose history that is shared.
+  range_start timestamptz NOT NULL,
+  range_end timestamptz NOT NULL,
+  -- The fields that are shared, see dosage.ShareField.
+  fields text[] NOT NULL,
+  -- The hash of the PIN needed to open the link, or null if there is none.
+  pin_hash bytea,
+  -- The time the share link was created.
+  created_at timestamptz NOT NULL DEFAULT now(),
+  -- The time the share link expires.
+  expires_at timestamptz NOT NULL,
+  -- The time the share link was revoked, or null if it wasn't.
+  revoked_at timestamptz,
+  CHECK (range_start < range_end)
+);
+
+CREATE INDEX share_links_user_id ON share_links USING HASH (user_id);
+
+CREATE TABLE share_link_accesses (
+  -- The share link that was accessed.
+  share_link_id bigint NOT NULL REFERENCES share_links (id) ON DELETE CASCADE,
+  -- The time the share link was accessed.
+  accessed_at timestamptz NOT NULL DEFAULT now(),
+  -- The user agent string, if any.
+  user_agent text,
+  -- Whether the data was shown, which is false if the PIN was wrong.
+  granted boolean NOT NULL,
+  -- Whether the attempt locked the share link because of too many incorrect
+  -- PINs in a row, which revokes it.
+  locked_out boolean NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX share_link_accesses_share_link_id ON share_link_accesses USING HASH (share_link_id);
+
+-- NEW VERSION
+UPDATE
+  meta
+SET v = 10;
+
+CREATE TABLE user_delegations (
+  -- The delegation ID, which either user uses to manage it.
+  id bigint PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  -- The user whose data is delegated.
+  owner_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  -- The user that was given access, or null if the invite wasn't accepted
+  -- yet.
+  delegate_id bigint REFERENCES users (id) ON DELETE CASCADE,
+  -- The access that the delegate has, see user.DelegationAccess.
+  access text NOT NULL,
+  -- The keyed hash of the invite code, or null once the invite was accepted.
+  invite_hash bytea UNIQUE,
+  -- The time the invite expires, or null once the invite was accepted.
+  invite_expires_at timestamptz,
+  -- Whether the owner's reminders are also sent to the delegate.
+  mirror_notifications boolean NOT NULL DEFAULT FALSE,
+  -- The time the invite was created.
+  created_at timestamptz NOT NULL DEFAULT now(),
+  -- The time the invite was accepted, or null if it wasn't yet.
+  accepted_at timestamptz,
+  UNIQUE (owner_id, delegate_id),
+  CHECK (owner_id <> delegate_id),
+  CHECK ((delegate_id IS NULL) = (invite_hash IS NOT NULL))
+);
+
+CREATE INDEX user_delegations_delegate_id ON user_delegations USING HASH (delegate_id);
+
+-- NEW VERSION
+UPDATE
+  meta
+SET v = 11;
+
+ALTER TABLE users
+  -- The time the user's account is deleted, or null if the user didn't ask
+  -- for it to be.
+  ADD COLUMN purge_at timestamptz;
@@ -7,28 +7,139 @@ package postgresqlc
 
 import (
 	"context"
+	"iter"
 
 	notificationservice "e2clicker.app/services/notification"
 	userservice "e2clicker.app/services/user"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addPasskey = `-- name: AddPasskey :one
+/*
+ * User Passkeys
+ */
+INSERT INTO user_passkeys (user_id, credential_id, credential, name)
+  VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, credential_id, credential, name, created_at, last_used
+`
+
+type AddPasskeyParams struct {
+	UserID       userservice.ID
+	CredentialID []byte
+	Credential   []byte
+	Name         string
+}
+
+func (q *Queries) AddPasskey(ctx context.Context, arg AddPasskeyParams) (UserPasskey, error) {
+	row := q.db.QueryRow(ctx, addPasskey,
+		arg.UserID,
+		arg.CredentialID,
+		arg.Credential,
+		arg.Name,
+	)
+	var i UserPasskey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CredentialID,
+		&i.Credential,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastUsed,
+	)
+	return i, err
+}
+
+const addRecoveryCode = `-- name: AddRecoveryCode :exec
+/*
+ * User Recovery Codes
+ */
+INSERT INTO user_recovery_codes (user_id, code_hash)
+  VALUES ($1, $2)
+`
+
+type AddRecoveryCodeParams struct {
+	UserID   userservice.ID
+	CodeHash []byte
+}
+
+func (q *Queries) AddRecoveryCode(ctx context.Context, arg AddRecoveryCodeParams) error {
+	_, err := q.db.Exec(ctx, addRecoveryCode, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const cancelUserPurge = `-- name: CancelUserPurge :execrows
+UPDATE
+  users
+SET purge_at = NULL
+WHERE id = $1
+  AND purge_at IS NOT NULL
+`
+
+func (q *Queries) CancelUserPurge(ctx context.Context, id userservice.ID) (int64, error) {
+	result, err := q.db.Exec(ctx, cancelUserPurge, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createToken = `-- name: CreateToken :one
+/*
+ * User Tokens
+ */
+INSERT INTO user_tokens (user_id, name, token_hash, scopes, expires_at)
+  VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, name, token_hash, scopes, created_at, last_used, expires_at
+`
+
+type CreateTokenParams struct {
+	UserID    userservice.ID
+	Name      string
+	TokenHash []byte
+	Scopes    []string
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateToken(ctx context.Context, arg CreateTokenParams) (UserToken, error) {
+	row := q.db.QueryRow(ctx, createToken,
+		arg.UserID,
+		arg.Name,
+		arg.TokenHash,
+		arg.Scopes,
+		arg.ExpiresAt,
+	)
+	var i UserToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsed,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 /*
  * User
  */
-INSERT INTO users (secret, name)
+INSERT INTO users (secret_hash, name)
   VALUES ($1, $2)
-RETURNING secret, name, locale, registered_at, notification_preferences
+RETURNING secret, name, locale, registered_at, notification_preferences, id, secret_hash, purge_at
 `
 
 type CreateUserParams struct {
-	Secret userservice.Secret
-	Name   string
+	SecretHash []byte
+	Name       string
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, arg.Secret, arg.Name)
+	row := q.db.QueryRow(ctx, createUser, arg.SecretHash, arg.Name)
 	var i User
 	err := row.Scan(
 		&i.Secret,
@@ -36,34 +147,200 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Locale,
 		&i.RegisteredAt,
 		&i.NotificationPreferences,
+		&i.ID,
+		&i.SecretHash,
+		&i.PurgeAt,
 	)
 	return i, err
 }
 
+const deleteAllPasskeys = `-- name: DeleteAllPasskeys :exec
+DELETE FROM user_passkeys
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteAllPasskeys(ctx context.Context, userID userservice.ID) error {
+	_, err := q.db.Exec(ctx, deleteAllPasskeys, userID)
+	return err
+}
+
+const deleteAllSessions = `-- name: DeleteAllSessions :exec
+DELETE FROM user_sessions
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteAllSessions(ctx context.Context, userID userservice.ID) error {
+	_, err := q.db.Exec(ctx, deleteAllSessions, userID)
+	return err
+}
+
+const deleteAllTokens = `-- name: DeleteAllTokens :exec
+DELETE FROM user_tokens
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteAllTokens(ctx context.Context, userID userservice.ID) error {
+	_, err := q.db.Exec(ctx, deleteAllTokens, userID)
+	return err
+}
+
+const deleteExpiredSessions = `-- name: DeleteExpiredSessions :execrows
+DELETE FROM user_sessions
+WHERE ($1::interval IS NOT NULL
+    AND created_at <= now() - $1::interval)
+  OR ($2::interval IS NOT NULL
+    AND last_used <= now() - $2::interval)
+`
+
+type DeleteExpiredSessionsParams struct {
+	MaxAge  pgtype.Interval
+	MaxIdle pgtype.Interval
+}
+
+func (q *Queries) DeleteExpiredSessions(ctx context.Context, arg DeleteExpiredSessionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredSessions, arg.MaxAge, arg.MaxIdle)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteOtherSessions = `-- name: DeleteOtherSessions :exec
+DELETE FROM user_sessions
+WHERE user_id = $1
+  AND id != $2
+`
+
+type DeleteOtherSessionsParams struct {
+	UserID userservice.ID
+	ID     int64
+}
+
+func (q *Queries) DeleteOtherSessions(ctx context.Context, arg DeleteOtherSessionsParams) error {
+	_, err := q.db.Exec(ctx, deleteOtherSessions, arg.UserID, arg.ID)
+	return err
+}
+
+const deletePasskey = `-- name: DeletePasskey :execrows
+DELETE FROM user_passkeys
+WHERE user_id = $1
+  AND id = $2
+`
+
+type DeletePasskeyParams struct {
+	UserID userservice.ID
+	ID     int64
+}
+
+func (q *Queries) DeletePasskey(ctx context.Context, arg DeletePasskeyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePasskey, arg.UserID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE FROM user_recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, userID userservice.ID) error {
+	_, err := q.db.Exec(ctx, deleteRecoveryCodes, userID)
+	return err
+}
+
 const deleteSession = `-- name: DeleteSession :exec
 DELETE FROM user_sessions
-WHERE user_secret = $1
+WHERE user_id = $1
   AND id = $2
 `
 
 type DeleteSessionParams struct {
-	UserSecret userservice.Secret
-	ID         int64
+	UserID userservice.ID
+	ID     int64
 }
 
 func (q *Queries) DeleteSession(ctx context.Context, arg DeleteSessionParams) error {
-	_, err := q.db.Exec(ctx, deleteSession, arg.UserSecret, arg.ID)
+	_, err := q.db.Exec(ctx, deleteSession, arg.UserID, arg.ID)
 	return err
 }
 
+const deleteToken = `-- name: DeleteToken :execrows
+DELETE FROM user_tokens
+WHERE user_id = $1
+  AND id = $2
+`
+
+type DeleteTokenParams struct {
+	UserID userservice.ID
+	ID     int64
+}
+
+func (q *Queries) DeleteToken(ctx context.Context, arg DeleteTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteToken, arg.UserID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUser = `-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id userservice.ID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listPasskeys = `-- name: ListPasskeys :many
+SELECT id, user_id, credential_id, credential, name, created_at, last_used
+FROM user_passkeys
+WHERE user_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListPasskeys(ctx context.Context, userID userservice.ID) ([]UserPasskey, error) {
+	rows, err := q.db.Query(ctx, listPasskeys, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserPasskey
+	for rows.Next() {
+		var i UserPasskey
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CredentialID,
+			&i.Credential,
+			&i.Name,
+			&i.CreatedAt,
+			&i.LastUsed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSessions = `-- name: ListSessions :many
-SELECT id, user_secret, token, created_at, last_used, user_agent
+SELECT id, token, created_at, last_used, user_agent, user_id, token_hash
 FROM user_sessions
-WHERE user_secret = $1
+WHERE user_id = $1
 `
 
-func (q *Queries) ListSessions(ctx context.Context, userSecret userservice.Secret) ([]UserSession, error) {
-	rows, err := q.db.Query(ctx, listSessions, userSecret)
+func (q *Queries) ListSessions(ctx context.Context, userID userservice.ID) ([]UserSession, error) {
+	rows, err := q.db.Query(ctx, listSessions, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -73,11 +350,12 @@ func (q *Queries) ListSessions(ctx context.Context, userSecret userservice.Secre
 		var i UserSession
 		if err := rows.Scan(
 			&i.ID,
-			&i.UserSecret,
 			&i.Token,
 			&i.CreatedAt,
 			&i.LastUsed,
 			&i.UserAgent,
+			&i.UserID,
+			&i.TokenHash,
 		); err != nil {
 			return nil, err
 		}
@@ -89,22 +367,167 @@ func (q *Queries) ListSessions(ctx context.Context, userSecret userservice.Secre
 	return items, nil
 }
 
+const listTokens = `-- name: ListTokens :many
+SELECT id, user_id, name, token_hash, scopes, created_at, last_used, expires_at
+FROM user_tokens
+WHERE user_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListTokens(ctx context.Context, userID userservice.ID) ([]UserToken, error) {
+	rows, err := q.db.Query(ctx, listTokens, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserToken
+	for rows.Next() {
+		var i UserToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.TokenHash,
+			&i.Scopes,
+			&i.CreatedAt,
+			&i.LastUsed,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const plainSessionTokens = `-- name: PlainSessionTokens :many
+SELECT id, token::bytea AS token
+FROM user_sessions
+WHERE token IS NOT NULL
+`
+
+type PlainSessionTokensRow struct {
+	ID    int64
+	Token []byte
+}
+
+func (q *Queries) PlainSessionTokens(ctx context.Context) ([]PlainSessionTokensRow, error) {
+	rows, err := q.db.Query(ctx, plainSessionTokens)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PlainSessionTokensRow
+	for rows.Next() {
+		var i PlainSessionTokensRow
+		if err := rows.Scan(&i.ID, &i.Token); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const plainUserSecrets = `-- name: PlainUserSecrets :many
+SELECT id, secret::text AS secret
+FROM users
+WHERE secret IS NOT NULL
+`
+
+type PlainUserSecretsRow struct {
+	ID     userservice.ID
+	Secret string
+}
+
+func (q *Queries) PlainUserSecrets(ctx context.Context) ([]PlainUserSecretsRow, error) {
+	rows, err := q.db.Query(ctx, plainUserSecrets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PlainUserSecretsRow
+	for rows.Next() {
+		var i PlainUserSecretsRow
+		if err := rows.Scan(&i.ID, &i.Secret); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recoveryCodeCount = `-- name: RecoveryCodeCount :one
+SELECT count(*)
+FROM user_recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) RecoveryCodeCount(ctx context.Context, userID userservice.ID) (int64, error) {
+	row := q.db.QueryRow(ctx, recoveryCodeCount, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const registerSession = `-- name: RegisterSession :exec
 /*                                                                                 
  * User Session                                                                    
  */
-INSERT INTO user_sessions (user_secret, token, created_at, last_used, user_agent)
+INSERT INTO user_sessions (user_id, token_hash, created_at, last_used, user_agent)
   VALUES ($1, $2, now(), now(), $3)
 `
 
 type RegisterSessionParams struct {
-	UserSecret userservice.Secret
-	Token      []byte
-	UserAgent  pgtype.Text
+	UserID    userservice.ID
+	TokenHash []byte
+	UserAgent pgtype.Text
 }
 
 func (q *Queries) RegisterSession(ctx context.Context, arg RegisterSessionParams) error {
-	_, err := q.db.Exec(ctx, registerSession, arg.UserSecret, arg.Token, arg.UserAgent)
+	_, err := q.db.Exec(ctx, registerSession, arg.UserID, arg.TokenHash, arg.UserAgent)
+	return err
+}
+
+const scheduleUserPurge = `-- name: ScheduleUserPurge :exec
+UPDATE
+  users
+SET purge_at = $2
+WHERE id = $1
+`
+
+type ScheduleUserPurgeParams struct {
+	ID      userservice.ID
+	PurgeAt pgtype.Timestamptz
+}
+
+func (q *Queries) ScheduleUserPurge(ctx context.Context, arg ScheduleUserPurgeParams) error {
+	_, err := q.db.Exec(ctx, scheduleUserPurge, arg.ID, arg.PurgeAt)
+	return err
+}
+
+const setSessionTokenHash = `-- name: SetSessionTokenHash :exec
+UPDATE
+  user_sessions
+SET token_hash = $2, token = NULL
+WHERE id = $1
+`
+
+type SetSessionTokenHashParams struct {
+	ID        int64
+	TokenHash []byte
+}
+
+func (q *Queries) SetSessionTokenHash(ctx context.Context, arg SetSessionTokenHashParams) error {
+	_, err := q.db.Exec(ctx, setSessionTokenHash, arg.ID, arg.TokenHash)
 	return err
 }
 
@@ -112,16 +535,52 @@ const setUserNotificationPreferences = `-- name: SetUserNotificationPreferences
 UPDATE
   users
 SET notification_preferences = $2::jsonb
-WHERE secret = $1
+WHERE id = $1
 `
 
 type SetUserNotificationPreferencesParams struct {
-	Secret  userservice.Secret
+	ID      userservice.ID
 	Column2 []byte
 }
 
 func (q *Queries) SetUserNotificationPreferences(ctx context.Context, arg SetUserNotificationPreferencesParams) error {
-	_, err := q.db.Exec(ctx, setUserNotificationPreferences, arg.Secret, arg.Column2)
+	_, err := q.db.Exec(ctx, setUserNotificationPreferences, arg.ID, arg.Column2)
+	return err
+}
+
+const setUserSecretHash = `-- name: SetUserSecretHash :exec
+UPDATE
+  users
+SET secret_hash = $2, secret = NULL
+WHERE id = $1
+`
+
+type SetUserSecretHashParams struct {
+	ID         userservice.ID
+	SecretHash []byte
+}
+
+func (q *Queries) SetUserSecretHash(ctx context.Context, arg SetUserSecretHashParams) error {
+	_, err := q.db.Exec(ctx, setUserSecretHash, arg.ID, arg.SecretHash)
+	return err
+}
+
+const updatePasskeyCredential = `-- name: UpdatePasskeyCredential :exec
+UPDATE
+  user_passkeys
+SET credential = $3, last_used = now()
+WHERE user_id = $1
+  AND credential_id = $2
+`
+
+type UpdatePasskeyCredentialParams struct {
+	UserID       userservice.ID
+	CredentialID []byte
+	Credential   []byte
+}
+
+func (q *Queries) UpdatePasskeyCredential(ctx context.Context, arg UpdatePasskeyCredentialParams) error {
+	_, err := q.db.Exec(ctx, updatePasskeyCredential, arg.UserID, arg.CredentialID, arg.Credential)
 	return err
 }
 
@@ -129,16 +588,16 @@ const updateUserLocale = `-- name: UpdateUserLocale :exec
 UPDATE
   users
 SET locale = $2
-WHERE secret = $1
+WHERE id = $1
 `
 
 type UpdateUserLocaleParams struct {
-	Secret userservice.Secret
+	ID     userservice.ID
 	Locale userservice.Locale
 }
 
 func (q *Queries) UpdateUserLocale(ctx context.Context, arg UpdateUserLocaleParams) error {
-	_, err := q.db.Exec(ctx, updateUserLocale, arg.Secret, arg.Locale)
+	_, err := q.db.Exec(ctx, updateUserLocale, arg.ID, arg.Locale)
 	return err
 }
 
@@ -146,27 +605,40 @@ const updateUserName = `-- name: UpdateUserName :exec
 UPDATE
   users
 SET name = $2
-WHERE secret = $1
+WHERE id = $1
 `
 
 type UpdateUserNameParams struct {
-	Secret userservice.Secret
-	Name   string
+	ID   userservice.ID
+	Name string
 }
 
 func (q *Queries) UpdateUserName(ctx context.Context, arg UpdateUserNameParams) error {
-	_, err := q.db.Exec(ctx, updateUserName, arg.Secret, arg.Name)
+	_, err := q.db.Exec(ctx, updateUserName, arg.ID, arg.Name)
 	return err
 }
 
+const useRecoveryCode = `-- name: UseRecoveryCode :one
+DELETE FROM user_recovery_codes
+WHERE code_hash = $1
+RETURNING user_id
+`
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, codeHash []byte) (userservice.ID, error) {
+	row := q.db.QueryRow(ctx, useRecoveryCode, codeHash)
+	var user_id userservice.ID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
 const user = `-- name: User :one
-SELECT secret, name, locale, registered_at, notification_preferences
+SELECT secret, name, locale, registered_at, notification_preferences, id, secret_hash, purge_at
 FROM users
-WHERE secret = $1
+WHERE id = $1
 `
 
-func (q *Queries) User(ctx context.Context, secret userservice.Secret) (User, error) {
-	row := q.db.QueryRow(ctx, user, secret)
+func (q *Queries) User(ctx context.Context, id userservice.ID) (User, error) {
+	row := q.db.QueryRow(ctx, user, id)
 	var i User
 	err := row.Scan(
 		&i.Secret,
@@ -174,44 +646,248 @@ func (q *Queries) User(ctx context.Context, secret userservice.Secret) (User, er
 		&i.Locale,
 		&i.RegisteredAt,
 		&i.NotificationPreferences,
+		&i.ID,
+		&i.SecretHash,
+		&i.PurgeAt,
 	)
 	return i, err
 }
 
+const userIDBySecret = `-- name: UserIDBySecret :one
+SELECT id
+FROM users
+WHERE secret_hash = $1
+`
+
+func (q *Queries) UserIDBySecret(ctx context.Context, secretHash []byte) (userservice.ID, error) {
+	row := q.db.QueryRow(ctx, userIDBySecret, secretHash)
+	var id userservice.ID
+	err := row.Scan(&id)
+	return id, err
+}
+
 const userNotificationPreferences = `-- name: UserNotificationPreferences :one
 /*
  * User Notifications
  */
 SELECT notification_preferences
 FROM users
-WHERE secret = $1
+WHERE id = $1
 `
 
-func (q *Queries) UserNotificationPreferences(ctx context.Context, secret userservice.Secret) (notificationservice.UserPreferences, error) {
-	row := q.db.QueryRow(ctx, userNotificationPreferences, secret)
+func (q *Queries) UserNotificationPreferences(ctx context.Context, id userservice.ID) (notificationservice.UserPreferences, error) {
+	row := q.db.QueryRow(ctx, userNotificationPreferences, id)
 	var notification_preferences notificationservice.UserPreferences
 	err := row.Scan(&notification_preferences)
 	return notification_preferences, err
 }
 
+const userNotificationPreferencesForUpdate = `-- name: UserNotificationPreferencesForUpdate :one
+SELECT notification_preferences
+FROM users
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) UserNotificationPreferencesForUpdate(ctx context.Context, id userservice.ID) (notificationservice.UserPreferences, error) {
+	row := q.db.QueryRow(ctx, userNotificationPreferencesForUpdate, id)
+	var notification_preferences notificationservice.UserPreferences
+	err := row.Scan(&notification_preferences)
+	return notification_preferences, err
+}
+
+const usersDueForPurge = `-- name: UsersDueForPurge :many
+SELECT id
+FROM users
+WHERE purge_at <= now()
+`
+
+func (q *Queries) UsersDueForPurge(ctx context.Context) ([]userservice.ID, error) {
+	rows, err := q.db.Query(ctx, usersDueForPurge)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []userservice.ID
+	for rows.Next() {
+		var id userservice.ID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const usersWithDigest = `-- name: UsersWithDigest :iter
+SELECT id
+FROM users
+WHERE notification_preferences ->> 'digestFrequency' IS NOT NULL
+  AND purge_at IS NULL
+`
+
+func (q *Queries) UsersWithDigest(ctx context.Context) UsersWithDigestRows {
+	rows, err := q.db.Query(ctx, usersWithDigest)
+	if err != nil {
+		return UsersWithDigestRows{err: err}
+	}
+	return UsersWithDigestRows{rows: rows}
+}
+
+type UsersWithDigestRows struct {
+	rows pgx.Rows
+	err  error
+}
+
+func (r *UsersWithDigestRows) Iterate() iter.Seq[userservice.ID] {
+	if r.rows == nil {
+		return func(yield func(userservice.ID) bool) {}
+	}
+
+	return func(yield func(userservice.ID) bool) {
+		defer r.rows.Close()
+
+		for r.rows.Next() {
+			var id userservice.ID
+			err := r.rows.Scan(&id)
+			if err != nil {
+				r.err = err
+				return
+			}
+
+			if !yield(id) {
+				return
+			}
+		}
+	}
+}
+
+func (r *UsersWithDigestRows) Close() {
+	r.rows.Close()
+}
+
+func (r *UsersWithDigestRows) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.rows.Err()
+}
+
+const usersWithNotificationMethod = `-- name: UsersWithNotificationMethod :iter
+SELECT id
+FROM users
+WHERE notification_preferences -> 'notificationConfigs' @> jsonb_build_array(jsonb_build_object('method', $1::text))
+`
+
+func (q *Queries) UsersWithNotificationMethod(ctx context.Context, method string) UsersWithNotificationMethodRows {
+	rows, err := q.db.Query(ctx, usersWithNotificationMethod, method)
+	if err != nil {
+		return UsersWithNotificationMethodRows{err: err}
+	}
+	return UsersWithNotificationMethodRows{rows: rows}
+}
+
+type UsersWithNotificationMethodRows struct {
+	rows pgx.Rows
+	err  error
+}
+
+func (r *UsersWithNotificationMethodRows) Iterate() iter.Seq[userservice.ID] {
+	if r.rows == nil {
+		return func(yield func(userservice.ID) bool) {}
+	}
+
+	return func(yield func(userservice.ID) bool) {
+		defer r.rows.Close()
+
+		for r.rows.Next() {
+			var id userservice.ID
+			err := r.rows.Scan(&id)
+			if err != nil {
+				r.err = err
+				return
+			}
+
+			if !yield(id) {
+				return
+			}
+		}
+	}
+}
+
+func (r *UsersWithNotificationMethodRows) Close() {
+	r.rows.Close()
+}
+
+func (r *UsersWithNotificationMethodRows) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.rows.Err()
+}
+
 const validateSession = `-- name: ValidateSession :one
 UPDATE
   user_sessions
 SET last_used = now()
-WHERE token = $1
-RETURNING id, user_secret, token, created_at, last_used, user_agent
+WHERE token_hash = $1
+  AND ($2::interval IS NULL
+    OR created_at > now() - $2::interval)
+  AND ($3::interval IS NULL
+    OR last_used > now() - $3::interval)
+RETURNING id, token, created_at, last_used, user_agent, user_id, token_hash
 `
 
-func (q *Queries) ValidateSession(ctx context.Context, token []byte) (UserSession, error) {
-	row := q.db.QueryRow(ctx, validateSession, token)
+type ValidateSessionParams struct {
+	TokenHash []byte
+	MaxAge    pgtype.Interval
+	MaxIdle   pgtype.Interval
+}
+
+func (q *Queries) ValidateSession(ctx context.Context, arg ValidateSessionParams) (UserSession, error) {
+	row := q.db.QueryRow(ctx, validateSession, arg.TokenHash, arg.MaxAge, arg.MaxIdle)
 	var i UserSession
 	err := row.Scan(
 		&i.ID,
-		&i.UserSecret,
 		&i.Token,
 		&i.CreatedAt,
 		&i.LastUsed,
 		&i.UserAgent,
+		&i.UserID,
+		&i.TokenHash,
+	)
+	return i, err
+}
+
+const validateToken = `-- name: ValidateToken :one
+UPDATE
+  user_tokens
+SET last_used = now()
+WHERE token_hash = $1
+  AND (expires_at IS NULL
+    OR expires_at > now())
+  AND user_id IN (
+    SELECT id
+    FROM users
+    WHERE purge_at IS NULL)
+RETURNING id, user_id, name, token_hash, scopes, created_at, last_used, expires_at
+`
+
+func (q *Queries) ValidateToken(ctx context.Context, tokenHash []byte) (UserToken, error) {
+	row := q.db.QueryRow(ctx, validateToken, tokenHash)
+	var i UserToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsed,
+		&i.ExpiresAt,
 	)
 	return i, err
 }
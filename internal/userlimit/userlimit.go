@@ -103,6 +103,12 @@ func (l *UserRateLimiter[IDType]) ReserveN(id IDType, t time.Time, n int) *rate.
 	return r
 }
 
+// Forget drops the rate limit of the given ID, as if it never made any
+// requests. It is used when the ID no longer exists.
+func (l *UserRateLimiter[IDType]) Forget(id IDType) {
+	l.users.Delete(id)
+}
+
 func (l *UserRateLimiter[IDType]) cleanup() {
 	l.users.Range(func(key IDType, value *rateLimiter) bool {
 		if time.Since(value.lastUsedTime()) > 5*time.Minute {
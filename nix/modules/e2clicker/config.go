@@ -33,9 +33,20 @@ type PostgreSQL struct {
 type Notification struct {
 	// ClientTimeout: HTTP timeout when making requests to notification servers.
 	ClientTimeout string `json:"clientTimeout"`
+	// CredentialKeys: keys used to encrypt credentials in users'
+	// notification configs, such as Gotify tokens and web push keys. See
+	// `secrets/credential-keys.example.json` for an example. If null,
+	// credentials are stored in plain text.
+	CredentialKeys *CredentialKeysJSON `json:"credentialKeys"`
 	// Email: path to the file containing the email configuration in JSON.
 	// See `secrets/email-config.example.json` for an example.
 	Email *EmailJSON `json:"email"`
+	// FailureThreshold: number of notifications in a row that may fail to be
+	// delivered to a notification config before it is paused.
+	FailureThreshold int `json:"failureThreshold"`
+	// MQTT: MQTT broker configuration. If set, users can have their
+	// reminders, doses and estimated levels published to the broker.
+	MQTT *MQTTJSON `json:"mqtt"`
 	// WebPush: web push notification configuration. This contains the
 	// VAPID keys that are used to encrypt the notifications. Use `just
 	// generate-vapid` to generate the keys.
@@ -47,8 +58,17 @@ type SMTP struct {
 	Auth Auth `json:"auth"`
 	// Host: SMTP server host.
 	Host string `json:"host"`
+	// IdleTimeout: how long an SMTP connection may stay idle before it is
+	// closed.
+	IdleTimeout string `json:"idleTimeout"`
+	// MaxConnections: maximum number of SMTP connections that are kept open
+	// to send emails over.
+	MaxConnections int `json:"maxConnections"`
 	// Port: SMTP server port.
 	Port int `json:"port"`
+	// QueueSize: number of emails that may wait to be sent before sending
+	// blocks.
+	QueueSize int `json:"queueSize"`
 	// Secure: whether to use a secure connection.
 	Secure bool `json:"secure"`
 }
@@ -63,10 +83,58 @@ type Auth struct {
 
 // API is the struct type for `config.api`.
 type API struct {
+	// AcceptUnpepperedHashes: whether user secrets and session tokens that
+	// were hashed without a pepper, before `pepperFile` was set, are still
+	// accepted. They are rehashed with the pepper when they are used. This is
+	// meant to be turned on for a while after adding a pepper, and off again
+	// once most users have logged in.
+	AcceptUnpepperedHashes bool `json:"acceptUnpepperedHashes"`
+	// AccountDeletionGracePeriod: how long after a user asks for their
+	// account to be deleted that it is actually deleted, as a Go duration.
+	// Until then, the user can log in again and cancel the deletion. If zero,
+	// accounts are deleted right away.
+	AccountDeletionGracePeriod string `json:"accountDeletionGracePeriod"`
 	// DebugRequests: enable debug logging for requests.
 	DebugRequests bool `json:"debugRequests"`
 	// ListenAddress address the API server should listen on.
 	ListenAddress string `json:"listenAddress"`
+	// PepperFile: path to a file containing the pepper that user secrets and
+	// session tokens are hashed with before they are stored. It is 32 random
+	// bytes encoded in base64, which can be generated with `openssl rand
+	// -base64 32`. To change it, move the path of the old pepper to
+	// `previousPepperFiles`, so that users can still log in. If null, the
+	// hashes are not keyed; to add a pepper later, see
+	// `acceptUnpepperedHashes`.
+	PepperFile *string `json:"pepperFile"`
+	// PreviousPepperFiles: paths to files containing the peppers that were
+	// used before `pepperFile`, newest first. User secrets and session tokens
+	// that were hashed with them are rehashed with the current pepper when
+	// they are used.
+	PreviousPepperFiles []string `json:"previousPepperFiles"`
+	// SessionMaxAge: how long a session stays valid after the user logged
+	// in, as a Go duration. If null, sessions are valid regardless of their
+	// age.
+	SessionMaxAge *string `json:"sessionMaxAge"`
+	// SessionMaxIdle: how long a session stays valid after it was last used,
+	// as a Go duration. If null, sessions are valid regardless of how long
+	// they were unused.
+	SessionMaxIdle *string `json:"sessionMaxIdle"`
+	// WebAuthn: WebAuthn relying party configuration. If set, users can add
+	// passkeys to their account and log in with them instead of their secret.
+	WebAuthn *WebAuthn `json:"webAuthn"`
+}
+
+// WebAuthn is the struct type for `config.api.webAuthn`.
+type WebAuthn struct {
+	// Origins: origins of the frontend that passkeys may be used from, e.g.
+	// `https://e2clicker.app`.
+	Origins []string `json:"origins"`
+	// RelyingPartyID: relying party ID, which is the domain of the frontend,
+	// e.g. `e2clicker.app`. Passkeys only work for this domain, so it must
+	// not change once users have added passkeys.
+	RelyingPartyID string `json:"relyingPartyID"`
+	// RelyingPartyName: name of the relying party that authenticators show.
+	RelyingPartyName string `json:"relyingPartyName"`
 }
 
 // LogFormat is the enum type for `config.logFormat`.
@@ -88,10 +156,23 @@ type EmailPath string
 
 // EmailSubmodule is one of the types that satisfy [Email].
 type EmailSubmodule struct {
+	// BaseURL: public URL of the e2clicker frontend, e.g.
+	// `https://e2clicker.app`. It is used for the account, unsubscribe and
+	// confirmation links in emails. New email addresses cannot be added if
+	// this is empty, since they cannot be confirmed.
+	BaseURL string `json:"baseURL"`
 	// From: email address to send notifications from.
 	From string `json:"from"`
 	// SMTP: SMTP server configuration.
 	SMTP SMTP `json:"smtp"`
+	// TemplatesDir: a directory containing email templates that override the
+	// built-in ones by file name. See `services/notification/emails` for the
+	// built-in templates.
+	TemplatesDir *string `json:"templatesDir"`
+	// TokenKey: a random secret used to seal the tokens in email links, such
+	// as unsubscribe and confirmation links. Generate one with `openssl rand
+	// -base64 32`. New email addresses cannot be added if this is empty.
+	TokenKey string `json:"tokenKey"`
 }
 
 func (e EmailPath) isEmail() {
@@ -106,10 +187,23 @@ func NewEmailPath(e string) Email {
 
 // NewEmailSubmodule constructs a value of type `submodule` that satisfies [Email].
 func NewEmailSubmodule(e struct {
+	// BaseURL: public URL of the e2clicker frontend, e.g.
+	// `https://e2clicker.app`. It is used for the account, unsubscribe and
+	// confirmation links in emails. New email addresses cannot be added if
+	// this is empty, since they cannot be confirmed.
+	BaseURL string `json:"baseURL"`
 	// From: email address to send notifications from.
 	From string `json:"from"`
 	// SMTP: SMTP server configuration.
 	SMTP SMTP `json:"smtp"`
+	// TemplatesDir: a directory containing email templates that override the
+	// built-in ones by file name. See `services/notification/emails` for the
+	// built-in templates.
+	TemplatesDir *string `json:"templatesDir"`
+	// TokenKey: a random secret used to seal the tokens in email links, such
+	// as unsubscribe and confirmation links. Generate one with `openssl rand
+	// -base64 32`. New email addresses cannot be added if this is empty.
+	TokenKey string `json:"tokenKey"`
 }) Email {
 	return EmailSubmodule(e)
 }
@@ -140,10 +234,23 @@ func unmarshalEmail(data json.RawMessage) (Email, error) {
 	}
 
 	var v1 struct {
+		// BaseURL: public URL of the e2clicker frontend, e.g.
+		// `https://e2clicker.app`. It is used for the account, unsubscribe and
+		// confirmation links in emails. New email addresses cannot be added if
+		// this is empty, since they cannot be confirmed.
+		BaseURL string `json:"baseURL"`
 		// From: email address to send notifications from.
 		From string `json:"from"`
 		// SMTP: SMTP server configuration.
 		SMTP SMTP `json:"smtp"`
+		// TemplatesDir: a directory containing email templates that override the
+		// built-in ones by file name. See `services/notification/emails` for the
+		// built-in templates.
+		TemplatesDir *string `json:"templatesDir"`
+		// TokenKey: a random secret used to seal the tokens in email links, such
+		// as unsubscribe and confirmation links. Generate one with `openssl rand
+		// -base64 32`. New email addresses cannot be added if this is empty.
+		TokenKey string `json:"tokenKey"`
 	}
 	if err := json.Unmarshal(data, &v1); err == nil {
 		return EmailSubmodule(v1), nil
@@ -162,6 +269,18 @@ type WebPushPath string
 
 // WebPushSubmodule is one of the types that satisfy [WebPush].
 type WebPushSubmodule struct {
+	// ID: ID of the VAPID key. Push subscriptions record the ID of the key
+	// that they were created with.
+	ID string `json:"id"`
+	// PreviousKeys: VAPID keys that were rotated out, by ID. Subscriptions
+	// created with them are still sent to with their key, and their clients
+	// are asked to subscribe again with the current key.
+	PreviousKeys map[string]struct {
+		// PrivateKey: VAPID private key.
+		PrivateKey string `json:"privateKey"`
+		// PublicKey: VAPID public key.
+		PublicKey string `json:"publicKey"`
+	} `json:"previousKeys"`
 	// PrivateKey: VAPID private key.
 	PrivateKey string `json:"privateKey"`
 	// PublicKey: VAPID public key.
@@ -180,6 +299,18 @@ func NewWebPushPath(w string) WebPush {
 
 // NewWebPushSubmodule constructs a value of type `submodule` that satisfies [WebPush].
 func NewWebPushSubmodule(w struct {
+	// ID: ID of the VAPID key. Push subscriptions record the ID of the key
+	// that they were created with.
+	ID string `json:"id"`
+	// PreviousKeys: VAPID keys that were rotated out, by ID. Subscriptions
+	// created with them are still sent to with their key, and their clients
+	// are asked to subscribe again with the current key.
+	PreviousKeys map[string]struct {
+		// PrivateKey: VAPID private key.
+		PrivateKey string `json:"privateKey"`
+		// PublicKey: VAPID public key.
+		PublicKey string `json:"publicKey"`
+	} `json:"previousKeys"`
 	// PrivateKey: VAPID private key.
 	PrivateKey string `json:"privateKey"`
 	// PublicKey: VAPID public key.
@@ -214,6 +345,18 @@ func unmarshalWebPush(data json.RawMessage) (WebPush, error) {
 	}
 
 	var v1 struct {
+		// ID: ID of the VAPID key. Push subscriptions record the ID of the key
+		// that they were created with.
+		ID string `json:"id"`
+		// PreviousKeys: VAPID keys that were rotated out, by ID. Subscriptions
+		// created with them are still sent to with their key, and their clients
+		// are asked to subscribe again with the current key.
+		PreviousKeys map[string]struct {
+			// PrivateKey: VAPID private key.
+			PrivateKey string `json:"privateKey"`
+			// PublicKey: VAPID public key.
+			PublicKey string `json:"publicKey"`
+		} `json:"previousKeys"`
 		// PrivateKey: VAPID private key.
 		PrivateKey string `json:"privateKey"`
 		// PublicKey: VAPID public key.
@@ -225,3 +368,208 @@ func unmarshalWebPush(data json.RawMessage) (WebPush, error) {
 
 	return nil, errors.New("failed to unmarshal WebPush: unknown type received")
 }
+
+// MQTT describes the `either` type for `config.notification.mqtt`.
+type MQTT interface {
+	isMQTT()
+}
+
+// MQTTPath is one of the types that satisfy [MQTT].
+type MQTTPath string
+
+// MQTTSubmodule is one of the types that satisfy [MQTT].
+type MQTTSubmodule struct {
+	// Broker: URL of the MQTT broker, e.g. `tcp://localhost:1883`.
+	Broker string `json:"broker"`
+	// ClientID: client ID to connect to the broker with.
+	ClientID string `json:"clientID"`
+	// HomeAssistantPrefix: home Assistant discovery prefix. Set this to an
+	// empty string to disable discovery.
+	HomeAssistantPrefix string `json:"homeAssistantPrefix"`
+	// Password: MQTT password.
+	Password string `json:"password"`
+	// Qos: QoS level to publish messages with.
+	Qos int `json:"qos"`
+	// StateInterval: how often the retained state topic of each user is
+	// refreshed.
+	StateInterval string `json:"stateInterval"`
+	// TopicPrefix: prefix of all topics that are published to.
+	TopicPrefix string `json:"topicPrefix"`
+	// Username: MQTT username.
+	Username string `json:"username"`
+}
+
+func (m MQTTPath) isMQTT() {
+}
+func (m MQTTSubmodule) isMQTT() {
+}
+
+// NewMQTTPath constructs a value of type `path` that satisfies [MQTT].
+func NewMQTTPath(m string) MQTT {
+	return MQTTPath(m)
+}
+
+// NewMQTTSubmodule constructs a value of type `submodule` that satisfies [MQTT].
+func NewMQTTSubmodule(m struct {
+	// Broker: URL of the MQTT broker, e.g. `tcp://localhost:1883`.
+	Broker string `json:"broker"`
+	// ClientID: client ID to connect to the broker with.
+	ClientID string `json:"clientID"`
+	// HomeAssistantPrefix: home Assistant discovery prefix. Set this to an
+	// empty string to disable discovery.
+	HomeAssistantPrefix string `json:"homeAssistantPrefix"`
+	// Password: MQTT password.
+	Password string `json:"password"`
+	// Qos: QoS level to publish messages with.
+	Qos int `json:"qos"`
+	// StateInterval: how often the retained state topic of each user is
+	// refreshed.
+	StateInterval string `json:"stateInterval"`
+	// TopicPrefix: prefix of all topics that are published to.
+	TopicPrefix string `json:"topicPrefix"`
+	// Username: MQTT username.
+	Username string `json:"username"`
+}) MQTT {
+	return MQTTSubmodule(m)
+}
+
+// MQTTJSON wraps [MQTT] and implements the json.Unmarshaler interface.
+type MQTTJSON struct{ Value MQTT }
+
+// UnmarshalJSON implements the [json.Unmarshaler] interface for [MQTT].
+func (m *MQTTJSON) UnmarshalJSON(data []byte) error {
+	_v, err := unmarshalMQTT(data)
+	if err != nil {
+		return err
+	}
+	m.Value = _v
+	return nil
+}
+
+// MarshalJSON implements the [json.Marshaler] interface for [MQTT].
+func (m MQTTJSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Value)
+}
+
+func unmarshalMQTT(data json.RawMessage) (MQTT, error) {
+
+	var v0 string
+	if err := json.Unmarshal(data, &v0); err == nil {
+		return MQTTPath(v0), nil
+	}
+
+	var v1 struct {
+		// Broker: URL of the MQTT broker, e.g. `tcp://localhost:1883`.
+		Broker string `json:"broker"`
+		// ClientID: client ID to connect to the broker with.
+		ClientID string `json:"clientID"`
+		// HomeAssistantPrefix: home Assistant discovery prefix. Set this to an
+		// empty string to disable discovery.
+		HomeAssistantPrefix string `json:"homeAssistantPrefix"`
+		// Password: MQTT password.
+		Password string `json:"password"`
+		// Qos: QoS level to publish messages with.
+		Qos int `json:"qos"`
+		// StateInterval: how often the retained state topic of each user is
+		// refreshed.
+		StateInterval string `json:"stateInterval"`
+		// TopicPrefix: prefix of all topics that are published to.
+		TopicPrefix string `json:"topicPrefix"`
+		// Username: MQTT username.
+		Username string `json:"username"`
+	}
+	if err := json.Unmarshal(data, &v1); err == nil {
+		return MQTTSubmodule(v1), nil
+	}
+
+	return nil, errors.New("failed to unmarshal MQTT: unknown type received")
+}
+
+// CredentialKeys describes the `either` type for `config.notification.credentialKeys`.
+type CredentialKeys interface {
+	isCredentialKeys()
+}
+
+// CredentialKeysPath is one of the types that satisfy [CredentialKeys].
+type CredentialKeysPath string
+
+// CredentialKeysSubmodule is one of the types that satisfy [CredentialKeys].
+type CredentialKeysSubmodule struct {
+	// Keys: keys by ID. Each key is 32 random bytes encoded in base64, which
+	// can be generated with `openssl rand -base64 32`. Old keys must be kept
+	// until every credential has been re-encrypted with the primary key.
+	Keys map[string]string `json:"keys"`
+	// Primary: ID of the key that credentials are encrypted with.
+	// Credentials encrypted with other keys are re-encrypted with this key
+	// when the server starts, so a key can be rotated by adding a new one and
+	// making it the primary key.
+	Primary string `json:"primary"`
+}
+
+func (c CredentialKeysPath) isCredentialKeys() {
+}
+func (c CredentialKeysSubmodule) isCredentialKeys() {
+}
+
+// NewCredentialKeysPath constructs a value of type `path` that satisfies [CredentialKeys].
+func NewCredentialKeysPath(c string) CredentialKeys {
+	return CredentialKeysPath(c)
+}
+
+// NewCredentialKeysSubmodule constructs a value of type `submodule` that satisfies [CredentialKeys].
+func NewCredentialKeysSubmodule(c struct {
+	// Keys: keys by ID. Each key is 32 random bytes encoded in base64, which
+	// can be generated with `openssl rand -base64 32`. Old keys must be kept
+	// until every credential has been re-encrypted with the primary key.
+	Keys map[string]string `json:"keys"`
+	// Primary: ID of the key that credentials are encrypted with.
+	// Credentials encrypted with other keys are re-encrypted with this key
+	// when the server starts, so a key can be rotated by adding a new one and
+	// making it the primary key.
+	Primary string `json:"primary"`
+}) CredentialKeys {
+	return CredentialKeysSubmodule(c)
+}
+
+// CredentialKeysJSON wraps [CredentialKeys] and implements the json.Unmarshaler interface.
+type CredentialKeysJSON struct{ Value CredentialKeys }
+
+// UnmarshalJSON implements the [json.Unmarshaler] interface for [CredentialKeys].
+func (c *CredentialKeysJSON) UnmarshalJSON(data []byte) error {
+	_v, err := unmarshalCredentialKeys(data)
+	if err != nil {
+		return err
+	}
+	c.Value = _v
+	return nil
+}
+
+// MarshalJSON implements the [json.Marshaler] interface for [CredentialKeys].
+func (c CredentialKeysJSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Value)
+}
+
+func unmarshalCredentialKeys(data json.RawMessage) (CredentialKeys, error) {
+
+	var v0 string
+	if err := json.Unmarshal(data, &v0); err == nil {
+		return CredentialKeysPath(v0), nil
+	}
+
+	var v1 struct {
+		// Keys: keys by ID. Each key is 32 random bytes encoded in base64, which
+		// can be generated with `openssl rand -base64 32`. Old keys must be kept
+		// until every credential has been re-encrypted with the primary key.
+		Keys map[string]string `json:"keys"`
+		// Primary: ID of the key that credentials are encrypted with.
+		// Credentials encrypted with other keys are re-encrypted with this key
+		// when the server starts, so a key can be rotated by adding a new one and
+		// making it the primary key.
+		Primary string `json:"primary"`
+	}
+	if err := json.Unmarshal(data, &v1); err == nil {
+		return CredentialKeysSubmodule(v1), nil
+	}
+
+	return nil, errors.New("failed to unmarshal CredentialKeys: unknown type received")
+}
@@ -38,6 +38,106 @@ in
             default = false;
             description = "Enable debug logging for requests.";
           };
+
+          accountDeletionGracePeriod = mkOption {
+            type = types.str;
+            default = "168h";
+            description = ''
+              How long after a user asks for their account to be deleted that
+              it is actually deleted, as a Go duration. Until then, the user
+              can log in again and cancel the deletion. If zero, accounts are
+              deleted right away.
+            '';
+          };
+
+          pepperFile = mkOption {
+            type = types.nullOr types.path;
+            default = null;
+            description = ''
+              The path to a file containing the pepper that user secrets and
+              session tokens are hashed with before they are stored. It is 32
+              random bytes encoded in base64, which can be generated with
+              `openssl rand -base64 32`. To change it, move the path of the
+              old pepper to `previousPepperFiles`, so that users can still log
+              in. If null, the hashes are not keyed; to add a pepper later,
+              see `acceptUnpepperedHashes`.
+            '';
+          };
+
+          previousPepperFiles = mkOption {
+            type = types.listOf types.path;
+            default = [ ];
+            description = ''
+              The paths to files containing the peppers that were used before
+              `pepperFile`, newest first. User secrets and session tokens that
+              were hashed with them are rehashed with the current pepper when
+              they are used.
+            '';
+          };
+
+          acceptUnpepperedHashes = mkOption {
+            type = types.bool;
+            default = false;
+            description = ''
+              Whether user secrets and session tokens that were hashed without
+              a pepper, before `pepperFile` was set, are still accepted. They
+              are rehashed with the pepper when they are used. This is meant
+              to be turned on for a while after adding a pepper, and off again
+              once most users have logged in.
+            '';
+          };
+
+          sessionMaxAge = mkOption {
+            type = types.nullOr types.str;
+            default = "2160h";
+            description = ''
+              How long a session stays valid after the user logged in, as a Go
+              duration. If null, sessions are valid regardless of their age.
+            '';
+          };
+
+          sessionMaxIdle = mkOption {
+            type = types.nullOr types.str;
+            default = "720h";
+            description = ''
+              How long a session stays valid after it was last used, as a Go
+              duration. If null, sessions are valid regardless of how long
+              they were unused.
+            '';
+          };
+
+          webAuthn = mkOption {
+            description = ''
+              The WebAuthn relying party configuration. If set, users can add
+              passkeys to their account and log in with them instead of their
+              secret.
+            '';
+            type = typeNullableSubmodule {
+              options = {
+                relyingPartyID = mkOption {
+                  type = types.str;
+                  description = ''
+                    The relying party ID, which is the domain of the frontend,
+                    e.g. `e2clicker.app`. Passkeys only work for this domain,
+                    so it must not change once users have added passkeys.
+                  '';
+                };
+                relyingPartyName = mkOption {
+                  type = types.str;
+                  default = "e2clicker";
+                  description = "The name of the relying party that authenticators show.";
+                };
+                origins = mkOption {
+                  type = types.listOf types.str;
+                  description = ''
+                    The origins of the frontend that passkeys may be used from,
+                    e.g. `https://e2clicker.app`.
+                  '';
+                };
+              };
+            };
+            default = null;
+          };
         };
       };
 
@@ -60,14 +160,32 @@ in
             default = "2m";
           };
 
+          failureThreshold = mkOption {
+            description = ''
+              The number of notifications in a row that may fail to be
+              delivered to a notification config before it is paused.
+            '';
+            type = types.ints.positive;
+            default = 10;
+          };
+
           webPush = mkOption {
             description = ''
               The web push notification configuration. This contains the VAPID
               keys that are used to encrypt the notifications. Use `just
-              generate-vapid` to generate the keys.
+              generate-vapid` to generate the keys, and `go run
+              ./cmd/vapid-generate --add` to rotate them.
             '';
             type = types.nullOr (typeJSONFile {
               options = {
+                id = mkOption {
+                  type = types.str;
+                  default = "";
+                  description = ''
+                    The ID of the VAPID key. Push subscriptions record the ID
+                    of the key that they were created with.
+                  '';
+                };
                 privateKey = mkOption {
                   type = types.str;
                   description = "The VAPID private key.";
@@ -76,6 +194,29 @@ in
                   type = types.str;
                   description = "The VAPID public key.";
                 };
+                previousKeys = mkOption {
+                  type = types.attrsOf (
+                    types.submodule {
+                      options = {
+                        privateKey = mkOption {
+                          type = types.str;
+                          description = "The VAPID private key.";
+                        };
+                        publicKey = mkOption {
+                          type = types.str;
+                          description = "The VAPID public key.";
+                        };
+                      };
+                    }
+                  );
+                  default = { };
+                  description = ''
+                    The VAPID keys that were rotated out, by ID. Subscriptions
+                    created with them are still sent to with their key, and
+                    their clients are asked to subscribe again with the
+                    current key.
+                  '';
+                };
               };
             });
           };
@@ -93,6 +234,39 @@ in
                   description = "The email address to send notifications from.";
                 };
 
+                baseURL = mkOption {
+                  type = types.str;
+                  default = "";
+                  description = ''
+                    The public URL of the e2clicker frontend, e.g.
+                    `https://e2clicker.app`. It is used for the account,
+                    unsubscribe and confirmation links in emails. New email
+                    addresses cannot be added if this is empty, since they
+                    cannot be confirmed.
+                  '';
+                };
+
+                tokenKey = mkOption {
+                  type = types.str;
+                  default = "";
+                  description = ''
+                    A random secret used to seal the tokens in email links,
+                    such as unsubscribe and confirmation links. Generate one
+                    with `openssl rand -base64 32`. New email addresses cannot
+                    be added if this is empty.
+                  '';
+                };
+
+                templatesDir = mkOption {
+                  type = types.nullOr types.str;
+                  default = null;
+                  description = ''
+                    A directory containing email templates that override the
+                    built-in ones by file name. See
+                    `services/notification/emails` for the built-in templates.
+                  '';
+                };
+
                 smtp = mkOption {
                   description = "The SMTP server configuration.";
                   type = types.submodule {
@@ -110,6 +284,30 @@ in
                         default = true;
                         description = "Whether to use a secure connection.";
                       };
+                      maxConnections = mkOption {
+                        type = types.ints.positive;
+                        default = 2;
+                        description = ''
+                          The maximum number of SMTP connections that are kept
+                          open to send emails over.
+                        '';
+                      };
+                      idleTimeout = mkOption {
+                        type = types.str;
+                        default = "30s";
+                        description = ''
+                          How long an SMTP connection may stay idle before it
+                          is closed.
+                        '';
+                      };
+                      queueSize = mkOption {
+                        type = types.ints.positive;
+                        default = 64;
+                        description = ''
+                          The number of emails that may wait to be sent before
+                          sending blocks.
+                        '';
+                      };
                       auth = mkOption {
                         type = types.submodule {
                           options = {
@@ -130,6 +328,91 @@ in
               };
             });
           };
+
+          mqtt = mkOption {
+            description = ''
+              The MQTT broker configuration. If set, users can have their
+              reminders, doses and estimated levels published to the broker.
+            '';
+            type = types.nullOr (typeJSONFile {
+              options = {
+                broker = mkOption {
+                  type = types.str;
+                  description = "The URL of the MQTT broker, e.g. `tcp://localhost:1883`.";
+                };
+                clientID = mkOption {
+                  type = types.str;
+                  default = "e2clicker";
+                  description = "The client ID to connect to the broker with.";
+                };
+                username = mkOption {
+                  type = types.str;
+                  default = "";
+                  description = "The MQTT username.";
+                };
+                password = mkOption {
+                  type = types.str;
+                  default = "";
+                  description = "The MQTT password.";
+                };
+                topicPrefix = mkOption {
+                  type = types.str;
+                  default = "e2clicker";
+                  description = "The prefix of all topics that are published to.";
+                };
+                qos = mkOption {
+                  type = types.ints.between 0 2;
+                  default = 1;
+                  description = "The QoS level to publish messages with.";
+                };
+                stateInterval = mkOption {
+                  type = types.str;
+                  default = "15m";
+                  description = "How often the retained state topic of each user is refreshed.";
+                };
+                homeAssistantPrefix = mkOption {
+                  type = types.str;
+                  default = "homeassistant";
+                  description = ''
+                    The Home Assistant discovery prefix. Set this to an empty
+                    string to disable discovery.
+                  '';
+                };
+              };
+            });
+          };
+
+          credentialKeys = mkOption {
+            description = ''
+              The keys used to encrypt credentials in users' notification
+              configs, such as Gotify tokens and web push keys. See
+              `secrets/credential-keys.example.json` for an example. If null,
+              credentials are stored in plain text.
+            '';
+            default = null;
+            type = types.nullOr (typeJSONFile {
+              options = {
+                primary = mkOption {
+                  type = types.str;
+                  description = ''
+                    The ID of the key that credentials are encrypted with.
+                    Credentials encrypted with other keys are re-encrypted
+                    with this key when the server starts, so a key can be
+                    rotated by adding a new one and making it the primary key.
+                  '';
+                };
+                keys = mkOption {
+                  type = types.attrsOf types.str;
+                  description = ''
+                    The keys by ID. Each key is 32 random bytes encoded in
+                    base64, which can be generated with `openssl rand -base64
+                    32`. Old keys must be kept until every credential has been
+                    re-encrypted with the primary key.
+                  '';
+                };
+              };
+            });
+          };
         };
       };
 
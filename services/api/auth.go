@@ -3,20 +3,48 @@ package api
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
 	"libdb.so/ctxt"
 	"e2clicker.app/internal/publicerrors"
 	"e2clicker.app/services/user"
 )
 
 func init() {
-	publicerrors.MarkValuesPublic(ErrNotBearerAuth)
+	publicerrors.MarkValuesPublic(ErrNotBearerAuth, ErrInsufficientScope, ErrInvalidOnBehalfOf, ErrNotDelegable)
 }
 
 var ErrNotBearerAuth = errors.New("not a bearer authentication token")
 
+// ErrInsufficientScope is returned when a personal access token is used for an
+// operation that its scopes don't allow.
+var ErrInsufficientScope = errors.New("personal access token does not have the required scopes")
+
+// ErrInvalidOnBehalfOf is returned when the X-On-Behalf-Of header is not a user
+// ID.
+var ErrInvalidOnBehalfOf = errors.New("invalid X-On-Behalf-Of header")
+
+// ErrNotDelegable is returned when the X-On-Behalf-Of header is set for an
+// operation that always acts on the session's own user.
+var ErrNotDelegable = errors.New("operation cannot be done on behalf of another user")
+
+// onBehalfOfHeader is the header that a delegate sets to the ID of the owner
+// whose data they want to act on. See [user.Delegation].
+const onBehalfOfHeader = "X-On-Behalf-Of"
+
+// delegableExtension marks the operations in the OpenAPI spec that a delegate
+// may do on behalf of the owner. Their handlers use [ownerFromCtx].
+const delegableExtension = "x-delegable"
+
+// dataOwner is the user whose data a request acts on. It is the user of the
+// session unless the request is made on behalf of another user.
+type dataOwner struct {
+	userID user.ID
+}
+
 // Authenticator is an authenticator that authenticates requests.
 type Authenticator struct {
 	users *user.UserService
@@ -48,18 +76,70 @@ func (a *Authenticator) authenticateBearerAuth(ctx context.Context, auth *openap
 		return auth.NewError(ErrNotBearerAuth)
 	}
 
-	s, err := a.users.ValidateSession(ctx, user.SessionToken(token))
-	if err != nil {
-		return auth.NewError(err)
+	var s user.Session
+	if user.IsPersonalToken(token) {
+		t, err := a.users.ValidatePersonalToken(ctx, token)
+		if err != nil {
+			return auth.NewError(err)
+		}
+
+		// Operations that don't list any scopes, such as managing the
+		// account, can only be done with a session.
+		if len(auth.Scopes) == 0 || !t.HasScopes(convertScopes(auth.Scopes)...) {
+			return auth.NewError(ErrInsufficientScope)
+		}
+
+		// Handlers only need to know the user. Operations that use the
+		// session ID don't list scopes, so they never get here.
+		s = user.Session{UserID: t.UserID}
+	} else {
+		var err error
+		s, err = a.users.ValidateSession(ctx, user.SessionToken(token))
+		if err != nil {
+			return auth.NewError(err)
+		}
+	}
+
+	owner := s.UserID
+	if v := r.Header.Get(onBehalfOfHeader); v != "" {
+		// Handlers of other operations use the session's user, so they would
+		// silently act on the delegate's own data.
+		if !isDelegable(auth.RequestValidationInput.Route) {
+			return auth.NewError(ErrNotDelegable)
+		}
+
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return auth.NewError(ErrInvalidOnBehalfOf)
+		}
+		owner = user.ID(id)
+
+		// Delegations allow operations by their scopes, just like personal
+		// access tokens, so operations without scopes are never allowed.
+		if err := a.users.AuthorizeDelegate(ctx, owner, s.UserID, convertScopes(auth.Scopes)); err != nil {
+			return auth.NewError(err)
+		}
 	}
 
 	// Awful hack to pass the session to the handler.
 	// Blame the OpenAPI generator for this.
-	addToRequestContext(ctx, s)
+	addToRequestContext(ctxt.With(ctx, s), dataOwner{owner})
 
 	return nil
 }
 
+func isDelegable(route *routers.Route) bool {
+	if route == nil || route.Operation == nil {
+		return false
+	}
+	delegable, _ := route.Operation.Extensions[delegableExtension].(bool)
+	return delegable
+}
+
+func convertScopes(scopes []string) []user.Scope {
+	return convertList(scopes, func(s string) user.Scope { return user.Scope(s) })
+}
+
 func sessionFromCtx(ctx context.Context) user.Session {
 	s, ok := ctxt.From[user.Session](ctx)
 	if ok {
@@ -67,3 +147,14 @@ func sessionFromCtx(ctx context.Context) user.Session {
 	}
 	panic("BUG: session not found in context")
 }
+
+// ownerFromCtx returns the ID of the user whose data the request acts on.
+// Handlers of operations on a user's data must use this instead of the
+// session's user, since a delegate may be acting on behalf of the owner.
+func ownerFromCtx(ctx context.Context) user.ID {
+	o, ok := ctxt.From[dataOwner](ctx)
+	if ok {
+		return o.userID
+	}
+	panic("BUG: data owner not found in context")
+}
@@ -23,10 +23,13 @@ import (
 type openAPIHandler struct {
 	logger      *slog.Logger
 	users       *user.UserService
+	deletions   *user.AccountDeletionService
 	notifs      *notification.UserNotificationService
 	notif       *notification.NotificationService
 	dosage      dosage.DosageStorage
 	doseHistory dosage.DoseHistoryStorage
+	doseMQTT    *dosage.DosageMQTTService
+	shares      *dosage.ShareService
 }
 
 // OpenAPIHandlerServices is the set of service dependencies required by the
@@ -35,10 +38,13 @@ type OpenAPIHandlerServices struct {
 	fx.In
 
 	Users             *user.UserService
+	AccountDeletions  *user.AccountDeletionService
 	UserNotifications *notification.UserNotificationService
 	Notification      *notification.NotificationService
 	Dosage            dosage.DosageStorage
 	DoseHistory       dosage.DoseHistoryStorage
+	DoseMQTT          *dosage.DosageMQTTService
+	Shares            *dosage.ShareService
 }
 
 // newOpenAPIHandler creates a new OpenAPIHandler.
@@ -46,10 +52,13 @@ func newOpenAPIHandler(deps OpenAPIHandlerServices, logger *slog.Logger) *openAP
 	return &openAPIHandler{
 		logger:      logger,
 		users:       deps.Users,
+		deletions:   deps.AccountDeletions,
 		notifs:      deps.UserNotifications,
 		notif:       deps.Notification,
 		dosage:      deps.Dosage,
 		doseHistory: deps.DoseHistory,
+		doseMQTT:    deps.DoseMQTT,
+		shares:      deps.Shares,
 	}
 }
 
@@ -96,34 +105,140 @@ func (h *openAPIHandler) Auth(ctx context.Context, request openapi.AuthRequestOb
 	}, nil
 }
 
+// Begin logging in with a passkey
+// (POST /auth/passkey/begin)
+func (h *openAPIHandler) BeginPasskeyLogin(ctx context.Context, request openapi.BeginPasskeyLoginRequestObject) (openapi.BeginPasskeyLoginResponseObject, error) {
+	c, err := h.users.BeginPasskeyLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.BeginPasskeyLogin200JSONResponse(convertPasskeyChallenge(c)), nil
+}
+
+// Authenticate a user with a passkey and obtain a session
+// (POST /auth/passkey/finish)
+func (h *openAPIHandler) FinishPasskeyLogin(ctx context.Context, request openapi.FinishPasskeyLoginRequestObject) (openapi.FinishPasskeyLoginResponseObject, error) {
+	t, err := h.users.CreateSessionFromPasskey(ctx, request.Body.CeremonyID, request.Body.Credential, optstr(request.Params.UserAgent))
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.FinishPasskeyLogin200JSONResponse{
+		Token: string(t),
+	}, nil
+}
+
+// Authenticate a user with a recovery code and obtain a session
+// (POST /auth/recovery)
+func (h *openAPIHandler) RecoveryAuth(ctx context.Context, request openapi.RecoveryAuthRequestObject) (openapi.RecoveryAuthResponseObject, error) {
+	t, err := h.users.CreateSessionFromRecoveryCode(ctx, request.Body.Code, optstr(request.Params.UserAgent))
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.RecoveryAuth200JSONResponse{
+		Token: string(t),
+	}, nil
+}
+
 // Get the current user
 // (GET /me)
 func (h *openAPIHandler) CurrentUser(ctx context.Context, request openapi.CurrentUserRequestObject) (openapi.CurrentUserResponseObject, error) {
 	session := sessionFromCtx(ctx)
 
-	u, err := h.users.User(ctx, session.UserSecret)
+	u, err := h.users.User(ctx, session.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	return openapi.CurrentUser200JSONResponse{
-		Name:   u.Name,
-		Locale: u.Locale,
-		Secret: session.UserSecret,
+	prefs, err := h.notifs.UserPreferences(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	r := convertUser(u)
+	r.Timezone = maybeNil(prefs.Timezone, prefs.Timezone != "")
+
+	return openapi.CurrentUser200JSONResponse(r), nil
+}
+
+// Update the current user's profile
+// (PATCH /me)
+func (h *openAPIHandler) UpdateCurrentUser(ctx context.Context, request openapi.UpdateCurrentUserRequestObject) (openapi.UpdateCurrentUserResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	u, err := h.users.UpdateUserProfile(ctx, session.UserID, user.ProfileUpdate{
+		Name:     request.Body.Name,
+		Locale:   request.Body.Locale,
+		Timezone: request.Body.Timezone,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prefs, err := h.notifs.UserPreferences(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	r := convertUser(u)
+	r.Timezone = maybeNil(prefs.Timezone, prefs.Timezone != "")
+
+	return openapi.UpdateCurrentUser200JSONResponse(r), nil
+}
+
+// Delete the current user's account
+// (DELETE /me)
+func (h *openAPIHandler) DeleteCurrentUser(ctx context.Context, request openapi.DeleteCurrentUserRequestObject) (openapi.DeleteCurrentUserResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	purgeAt, err := h.deletions.DeleteAccount(ctx, session.UserID, request.Body.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.DeleteCurrentUser200JSONResponse{
+		PurgeAt: purgeAt,
 	}, nil
 }
 
+// Cancel the deletion of the current user's account
+// (DELETE /me/deletion)
+func (h *openAPIHandler) CancelCurrentUserDeletion(ctx context.Context, request openapi.CancelCurrentUserDeletionRequestObject) (openapi.CancelCurrentUserDeletionResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	if err := h.deletions.CancelAccountDeletion(ctx, session.UserID); err != nil {
+		return nil, err
+	}
+
+	return openapi.CancelCurrentUserDeletion204Response{}, nil
+}
+
+func (h *openAPIHandler) ExportTakeout(ctx context.Context, request openapi.ExportTakeoutRequestObject) (openapi.ExportTakeoutResponseObject, error) {
+	panic("unreachable") // see handler_importexport.go
+}
+
+func (h *openAPIHandler) ImportTakeout(ctx context.Context, request openapi.ImportTakeoutRequestObject) (openapi.ImportTakeoutResponseObject, error) {
+	panic("unreachable") // see handler_importexport.go
+}
+
 // List the current user's sessions
 // (GET /me/sessions)
 func (h *openAPIHandler) CurrentUserSessions(ctx context.Context, request openapi.CurrentUserSessionsRequestObject) (openapi.CurrentUserSessionsResponseObject, error) {
 	session := sessionFromCtx(ctx)
 
-	s, err := h.users.ListSessions(ctx, session.UserSecret)
+	s, err := h.users.ListSessions(ctx, session.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	return openapi.CurrentUserSessions200JSONResponse(convertList(s, convertSession)), nil
+	sessions := convertList(s, convertSession)
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == session.ID
+	}
+
+	return openapi.CurrentUserSessions200JSONResponse(sessions), nil
 }
 
 // Delete one of the current user's sessions
@@ -131,7 +246,7 @@ func (h *openAPIHandler) CurrentUserSessions(ctx context.Context, request openap
 func (h *openAPIHandler) DeleteUserSession(ctx context.Context, request openapi.DeleteUserSessionRequestObject) (openapi.DeleteUserSessionResponseObject, error) {
 	session := sessionFromCtx(ctx)
 
-	err := h.users.DeleteSession(ctx, session.UserSecret, request.Params.ID)
+	err := h.users.DeleteSession(ctx, session.UserID, request.Params.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +254,250 @@ func (h *openAPIHandler) DeleteUserSession(ctx context.Context, request openapi.
 	return openapi.DeleteUserSession204Response{}, nil
 }
 
+// Delete all of the current user's sessions except the current one
+// (DELETE /me/sessions/all-others)
+func (h *openAPIHandler) DeleteOtherUserSessions(ctx context.Context, request openapi.DeleteOtherUserSessionsRequestObject) (openapi.DeleteOtherUserSessionsResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	if err := h.users.DeleteOtherSessions(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return openapi.DeleteOtherUserSessions204Response{}, nil
+}
+
+// Rotate the current user's secret
+// (POST /me/secret/rotate)
+func (h *openAPIHandler) RotateUserSecret(ctx context.Context, request openapi.RotateUserSecretRequestObject) (openapi.RotateUserSecretResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	secret, err := h.users.RotateSecret(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.RotateUserSecret200JSONResponse{
+		Secret: secret,
+	}, nil
+}
+
+// List the current user's passkeys
+// (GET /me/passkeys)
+func (h *openAPIHandler) CurrentUserPasskeys(ctx context.Context, request openapi.CurrentUserPasskeysRequestObject) (openapi.CurrentUserPasskeysResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	p, err := h.users.Passkeys(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.CurrentUserPasskeys200JSONResponse(convertList(p, convertPasskey)), nil
+}
+
+// Delete one of the current user's passkeys
+// (DELETE /me/passkeys)
+func (h *openAPIHandler) DeleteUserPasskey(ctx context.Context, request openapi.DeleteUserPasskeyRequestObject) (openapi.DeleteUserPasskeyResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	if err := h.users.DeletePasskey(ctx, session.UserID, request.Params.ID); err != nil {
+		return nil, err
+	}
+
+	return openapi.DeleteUserPasskey204Response{}, nil
+}
+
+// Begin adding a passkey to the current user
+// (POST /me/passkeys/begin)
+func (h *openAPIHandler) BeginPasskeyRegistration(ctx context.Context, request openapi.BeginPasskeyRegistrationRequestObject) (openapi.BeginPasskeyRegistrationResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	c, err := h.users.BeginPasskeyRegistration(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.BeginPasskeyRegistration200JSONResponse(convertPasskeyChallenge(c)), nil
+}
+
+// Finish adding a passkey to the current user
+// (POST /me/passkeys/finish)
+func (h *openAPIHandler) FinishPasskeyRegistration(ctx context.Context, request openapi.FinishPasskeyRegistrationRequestObject) (openapi.FinishPasskeyRegistrationResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	p, err := h.users.FinishPasskeyRegistration(ctx,
+		session.UserID, request.Body.CeremonyID, optstr(request.Body.Name), request.Body.Credential)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.FinishPasskeyRegistration200JSONResponse(convertPasskey(p)), nil
+}
+
+// Get the number of the current user's unused recovery codes
+// (GET /me/recovery-codes)
+func (h *openAPIHandler) CurrentUserRecoveryCodes(ctx context.Context, request openapi.CurrentUserRecoveryCodesRequestObject) (openapi.CurrentUserRecoveryCodesResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	n, err := h.users.RecoveryCodesRemaining(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.CurrentUserRecoveryCodes200JSONResponse{
+		Remaining: n,
+	}, nil
+}
+
+// Generate new recovery codes for the current user
+// (POST /me/recovery-codes)
+func (h *openAPIHandler) GenerateUserRecoveryCodes(ctx context.Context, request openapi.GenerateUserRecoveryCodesRequestObject) (openapi.GenerateUserRecoveryCodesResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	codes, err := h.users.GenerateRecoveryCodes(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.GenerateUserRecoveryCodes200JSONResponse{
+		Codes: codes,
+	}, nil
+}
+
+// Delete the current user's recovery codes
+// (DELETE /me/recovery-codes)
+func (h *openAPIHandler) DeleteUserRecoveryCodes(ctx context.Context, request openapi.DeleteUserRecoveryCodesRequestObject) (openapi.DeleteUserRecoveryCodesResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	if err := h.users.DeleteRecoveryCodes(ctx, session.UserID); err != nil {
+		return nil, err
+	}
+
+	return openapi.DeleteUserRecoveryCodes204Response{}, nil
+}
+
+// List the current user's personal access tokens
+// (GET /me/tokens)
+func (h *openAPIHandler) CurrentUserTokens(ctx context.Context, request openapi.CurrentUserTokensRequestObject) (openapi.CurrentUserTokensResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	t, err := h.users.PersonalTokens(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.CurrentUserTokens200JSONResponse(convertList(t, convertPersonalToken)), nil
+}
+
+// Create a personal access token for the current user
+// (POST /me/tokens)
+func (h *openAPIHandler) CreateUserToken(ctx context.Context, request openapi.CreateUserTokenRequestObject) (openapi.CreateUserTokenResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	t, err := h.users.CreatePersonalToken(ctx,
+		session.UserID, optstr(request.Body.Name), request.Body.Scopes, optPtr(request.Body.ExpiresAt))
+	if err != nil {
+		return nil, err
+	}
+
+	o := convertPersonalToken(t.PersonalToken)
+	return openapi.CreateUserToken200JSONResponse{
+		ID:        o.ID,
+		Name:      o.Name,
+		Scopes:    o.Scopes,
+		CreatedAt: o.CreatedAt,
+		LastUsed:  o.LastUsed,
+		ExpiresAt: o.ExpiresAt,
+		Token:     t.Token,
+	}, nil
+}
+
+// Delete one of the current user's personal access tokens
+// (DELETE /me/tokens)
+func (h *openAPIHandler) DeleteUserToken(ctx context.Context, request openapi.DeleteUserTokenRequestObject) (openapi.DeleteUserTokenResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	if err := h.users.DeletePersonalToken(ctx, session.UserID, request.Params.ID); err != nil {
+		return nil, err
+	}
+
+	return openapi.DeleteUserToken204Response{}, nil
+}
+
+// List the current user's delegations
+// (GET /me/delegations)
+func (h *openAPIHandler) CurrentUserDelegations(ctx context.Context, request openapi.CurrentUserDelegationsRequestObject) (openapi.CurrentUserDelegationsResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	d, err := h.users.Delegations(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.CurrentUserDelegations200JSONResponse(
+		convertList(d, func(d user.Delegation) openapi.Delegation {
+			return convertDelegation(d, session.UserID)
+		}),
+	), nil
+}
+
+// Invite someone to access the current user's data
+// (POST /me/delegations)
+func (h *openAPIHandler) InviteDelegate(ctx context.Context, request openapi.InviteDelegateRequestObject) (openapi.InviteDelegateResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	u, err := h.users.User(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := h.users.InviteDelegate(ctx, session.UserID, request.Body.Access)
+	if err != nil {
+		return nil, err
+	}
+	i.OwnerName = u.Name
+
+	o := convertDelegation(i.Delegation, session.UserID)
+	return openapi.InviteDelegate200JSONResponse{
+		ID:                  o.ID,
+		Role:                o.Role,
+		OwnerID:             o.OwnerID,
+		OwnerName:           o.OwnerName,
+		DelegateName:        o.DelegateName,
+		Access:              o.Access,
+		MirrorNotifications: o.MirrorNotifications,
+		CreatedAt:           o.CreatedAt,
+		InviteExpiresAt:     o.InviteExpiresAt,
+		AcceptedAt:          o.AcceptedAt,
+		Code:                i.Code.PrettyString(),
+	}, nil
+}
+
+// End one of the current user's delegations
+// (DELETE /me/delegations)
+func (h *openAPIHandler) DeleteDelegation(ctx context.Context, request openapi.DeleteDelegationRequestObject) (openapi.DeleteDelegationResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	if err := h.users.DeleteDelegation(ctx, session.UserID, request.Params.ID); err != nil {
+		return nil, err
+	}
+
+	return openapi.DeleteDelegation204Response{}, nil
+}
+
+// Accept an invite to access another user's data
+// (POST /me/delegations/accept)
+func (h *openAPIHandler) AcceptDelegation(ctx context.Context, request openapi.AcceptDelegationRequestObject) (openapi.AcceptDelegationResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	d, err := h.users.AcceptDelegation(ctx,
+		session.UserID, user.InviteCode(request.Body.Code), optPtr(request.Body.MirrorNotifications))
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.AcceptDelegation200JSONResponse(convertDelegation(d, session.UserID)), nil
+}
+
 // List all available delivery methods
 // (GET /delivery-methods)
 func (h *openAPIHandler) DeliveryMethods(ctx context.Context, request openapi.DeliveryMethodsRequestObject) (openapi.DeliveryMethodsResponseObject, error) {
@@ -155,7 +514,7 @@ func (h *openAPIHandler) DeliveryMethods(ctx context.Context, request openapi.De
 }
 
 func (h *openAPIHandler) SetDosage(ctx context.Context, request openapi.SetDosageRequestObject) (openapi.SetDosageResponseObject, error) {
-	session := sessionFromCtx(ctx)
+	owner := ownerFromCtx(ctx)
 
 	methods, err := h.dosage.DeliveryMethods(ctx)
 	if err != nil {
@@ -163,7 +522,7 @@ func (h *openAPIHandler) SetDosage(ctx context.Context, request openapi.SetDosag
 	}
 
 	s := dosage.Dosage{
-		UserSecret:     session.UserSecret,
+		UserID:         owner,
 		DeliveryMethod: request.Body.DeliveryMethod,
 		Dose:           request.Body.Dose,
 		Interval:       dosage.Days(request.Body.Interval),
@@ -180,22 +539,25 @@ func (h *openAPIHandler) SetDosage(ctx context.Context, request openapi.SetDosag
 		return nil, err
 	}
 
+	h.doseMQTT.DosesChanged(ctx, owner)
+
 	return openapi.SetDosage204Response{}, nil
 }
 
 func (h *openAPIHandler) ClearDosage(ctx context.Context, request openapi.ClearDosageRequestObject) (openapi.ClearDosageResponseObject, error) {
-	session := sessionFromCtx(ctx)
-	if err := h.dosage.ClearDosage(ctx, session.UserSecret); err != nil {
+	owner := ownerFromCtx(ctx)
+	if err := h.dosage.ClearDosage(ctx, owner); err != nil {
 		return nil, err
 	}
+	h.doseMQTT.DosesChanged(ctx, owner)
 	return openapi.ClearDosage204Response{}, nil
 }
 
 func (h *openAPIHandler) RecordDose(ctx context.Context, request openapi.RecordDoseRequestObject) (openapi.RecordDoseResponseObject, error) {
-	session := sessionFromCtx(ctx)
+	owner := ownerFromCtx(ctx)
 	now := time.Now()
 
-	d, err := h.dosage.Dosage(ctx, session.UserSecret)
+	d, err := h.dosage.Dosage(ctx, owner)
 	if err != nil {
 		return nil, publicerrors.New("no dosage set")
 	}
@@ -206,15 +568,17 @@ func (h *openAPIHandler) RecordDose(ctx context.Context, request openapi.RecordD
 		TakenAt:        now,
 	}
 
-	if err := h.doseHistory.RecordDose(ctx, session.UserSecret, dose); err != nil {
+	if err := h.doseHistory.RecordDose(ctx, owner, dose); err != nil {
 		return nil, err
 	}
 
+	h.doseMQTT.DoseRecorded(ctx, owner, dose)
+
 	return openapi.RecordDose200JSONResponse(openapi.Dose(dose.ToOpenAPI())), nil
 }
 
 func (h *openAPIHandler) EditDose(ctx context.Context, request openapi.EditDoseRequestObject) (openapi.EditDoseResponseObject, error) {
-	session := sessionFromCtx(ctx)
+	owner := ownerFromCtx(ctx)
 
 	o := dosage.Dose{
 		DeliveryMethod: request.Body.DeliveryMethod,
@@ -223,35 +587,39 @@ func (h *openAPIHandler) EditDose(ctx context.Context, request openapi.EditDoseR
 		TakenOffAt:     request.Body.TakenOffAt,
 	}
 
-	if err := h.doseHistory.EditDose(ctx, session.UserSecret, request.DoseTime, o); err != nil {
+	if err := h.doseHistory.EditDose(ctx, owner, request.DoseTime, o); err != nil {
 		return nil, err
 	}
 
+	h.doseMQTT.DosesChanged(ctx, owner)
+
 	return openapi.EditDose204Response{}, nil
 }
 
 func (h *openAPIHandler) ForgetDose(ctx context.Context, request openapi.ForgetDoseRequestObject) (openapi.ForgetDoseResponseObject, error) {
-	session := sessionFromCtx(ctx)
-	if err := h.doseHistory.ForgetDoses(ctx, session.UserSecret, []time.Time{request.DoseTime}); err != nil {
+	owner := ownerFromCtx(ctx)
+	if err := h.doseHistory.ForgetDoses(ctx, owner, []time.Time{request.DoseTime}); err != nil {
 		return nil, err
 	}
+	h.doseMQTT.DosesChanged(ctx, owner)
 	return openapi.ForgetDose204Response{}, nil
 }
 
 func (h *openAPIHandler) ForgetDoses(ctx context.Context, request openapi.ForgetDosesRequestObject) (openapi.ForgetDosesResponseObject, error) {
-	session := sessionFromCtx(ctx)
-	if err := h.doseHistory.ForgetDoses(ctx, session.UserSecret, request.Params.DoseTimes); err != nil {
+	owner := ownerFromCtx(ctx)
+	if err := h.doseHistory.ForgetDoses(ctx, owner, request.Params.DoseTimes); err != nil {
 		return nil, err
 	}
+	h.doseMQTT.DosesChanged(ctx, owner)
 	return openapi.ForgetDoses204Response{}, nil
 }
 
 func (h *openAPIHandler) Dosage(ctx context.Context, request openapi.DosageRequestObject) (openapi.DosageResponseObject, error) {
-	session := sessionFromCtx(ctx)
+	owner := ownerFromCtx(ctx)
 
 	var r openapi.Dosage200JSONResponse
 
-	dosage, err := h.dosage.Dosage(ctx, session.UserSecret)
+	dosage, err := h.dosage.Dosage(ctx, owner)
 	if err != nil {
 		return nil, fmt.Errorf("cannot get dosage: %w", err)
 	}
@@ -276,7 +644,7 @@ func (h *openAPIHandler) Dosage(ctx context.Context, request openapi.DosageReque
 		r.History = &os
 
 		for dose, err := range h.doseHistory.DoseHistory(
-			ctx, session.UserSecret,
+			ctx, owner,
 			*request.Params.Start,
 			*request.Params.End) {
 
@@ -299,6 +667,119 @@ func (h *openAPIHandler) ImportDoses(ctx context.Context, request openapi.Import
 	panic("unreachable") // see handler_importexport.go
 }
 
+// List the user's share links
+// (GET /dosage/shares)
+func (h *openAPIHandler) ShareLinks(ctx context.Context, request openapi.ShareLinksRequestObject) (openapi.ShareLinksResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	links, err := h.shares.ShareLinks(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.ShareLinks200JSONResponse(convertList(links, convertShareLink)), nil
+}
+
+// Create a share link
+// (POST /dosage/shares)
+func (h *openAPIHandler) CreateShareLink(ctx context.Context, request openapi.CreateShareLinkRequestObject) (openapi.CreateShareLinkResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	l, err := h.shares.CreateShareLink(ctx, session.UserID, dosage.CreateShareLinkOptions{
+		Name:      optstr(request.Body.Name),
+		Start:     request.Body.Start,
+		End:       request.Body.End,
+		Fields:    convertList(request.Body.Fields, func(f openapi.ShareField) dosage.ShareField { return dosage.ShareField(f) }),
+		ExpiresAt: request.Body.ExpiresAt,
+		PIN:       optstr(request.Body.Pin),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o := convertShareLink(l.ShareLink)
+	return openapi.CreateShareLink200JSONResponse{
+		ID:        o.ID,
+		Name:      o.Name,
+		Start:     o.Start,
+		End:       o.End,
+		Fields:    o.Fields,
+		HasPIN:    o.HasPIN,
+		CreatedAt: o.CreatedAt,
+		ExpiresAt: o.ExpiresAt,
+		RevokedAt: o.RevokedAt,
+		Token:     l.Token,
+	}, nil
+}
+
+// Revoke one of the user's share links
+// (DELETE /dosage/shares)
+func (h *openAPIHandler) RevokeShareLink(ctx context.Context, request openapi.RevokeShareLinkRequestObject) (openapi.RevokeShareLinkResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	if err := h.shares.RevokeShareLink(ctx, session.UserID, request.Params.ID); err != nil {
+		return nil, err
+	}
+
+	return openapi.RevokeShareLink204Response{}, nil
+}
+
+// Get the access log of one of the user's share links
+// (GET /dosage/shares/{id}/accesses)
+func (h *openAPIHandler) ShareLinkAccesses(ctx context.Context, request openapi.ShareLinkAccessesRequestObject) (openapi.ShareLinkAccessesResponseObject, error) {
+	session := sessionFromCtx(ctx)
+
+	accesses, err := h.shares.ShareLinkAccesses(ctx, session.UserID, request.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.ShareLinkAccesses200JSONResponse(
+		convertList(accesses, func(a dosage.ShareLinkAccess) openapi.ShareLinkAccess {
+			return openapi.ShareLinkAccess{
+				AccessedAt: a.AccessedAt,
+				UserAgent:  maybeNil(a.UserAgent, a.UserAgent != ""),
+				Granted:    a.Granted,
+				LockedOut:  a.LockedOut,
+			}
+		}),
+	), nil
+}
+
+// Open a share link
+// (GET /shared/{token})
+func (h *openAPIHandler) OpenShareLink(ctx context.Context, request openapi.OpenShareLinkRequestObject) (openapi.OpenShareLinkResponseObject, error) {
+	data, err := h.shares.OpenShareLink(ctx,
+		request.Token,
+		optstr(request.Params.XSharePin),
+		optstr(request.Params.UserAgent))
+	if err != nil {
+		return nil, err
+	}
+
+	r := openapi.OpenShareLink200JSONResponse{
+		Start:        data.Start,
+		End:          data.End,
+		ExpiresAt:    data.ExpiresAt,
+		LevelUnits:   dosage.LevelUnits,
+		CurrentLevel: data.CurrentLevel,
+	}
+	if data.Doses != nil {
+		doses := openapi.DosageHistory(convertList(data.Doses, func(d dosage.Dose) openapi.Dose {
+			return openapi.Dose(d.ToOpenAPI())
+		}))
+		r.Doses = &doses
+	}
+	if data.Levels != nil {
+		levels := convertList(data.Levels, func(l dosage.LevelSample) openapi.LevelSample {
+			return openapi.LevelSample{Time: l.Time, Level: l.Level}
+		})
+		r.Levels = &levels
+	}
+
+	return r, nil
+}
+
 func (h *openAPIHandler) WebPushInfo(ctx context.Context, request openapi.WebPushInfoRequestObject) (openapi.WebPushInfoResponseObject, error) {
 	i, err := h.notifs.WebPushInfo(ctx)
 	if err != nil {
@@ -310,22 +791,17 @@ func (h *openAPIHandler) WebPushInfo(ctx context.Context, request openapi.WebPus
 // Get the server's supported notification methods
 // (GET /notifications/methods)
 func (h *openAPIHandler) SupportedNotificationMethods(ctx context.Context, request openapi.SupportedNotificationMethodsRequestObject) (openapi.SupportedNotificationMethodsResponseObject, error) {
-	addIfTrue := func(ret []string, b bool, value string) []string {
-		if b {
-			ret = append(ret, value)
+	notifiers := h.notif.Notifiers()
+
+	ret := make(openapi.NotificationMethods, len(notifiers))
+	for i, n := range notifiers {
+		ret[i] = openapi.NotificationMethod{
+			Name:         n.Method(),
+			ConfigSchema: n.ConfigSchema(),
 		}
-		return ret
 	}
 
-	supports := h.notif.Supports()
-
-	var ret openapi.NotificationMethodSupports
-	ret = addIfTrue(ret, supports.Gotify, "gotify")
-	ret = addIfTrue(ret, supports.Pushover, "pushover")
-	ret = addIfTrue(ret, supports.WebPush, "webPush")
-	ret = addIfTrue(ret, supports.Email, "email")
-
-	return openapi.SupportedNotificationMethods200JSONResponse(openapi.NotificationMethodSupports(ret)), nil
+	return openapi.SupportedNotificationMethods200JSONResponse(ret), nil
 }
 
 // Get the user's notification preferences
@@ -333,38 +809,29 @@ func (h *openAPIHandler) SupportedNotificationMethods(ctx context.Context, reque
 func (h *openAPIHandler) UserNotificationPreferences(ctx context.Context, request openapi.UserNotificationPreferencesRequestObject) (openapi.UserNotificationPreferencesResponseObject, error) {
 	session := sessionFromCtx(ctx)
 
-	p, err := h.notifs.UserPreferences(ctx, session.UserSecret)
+	p, err := h.notifs.UserPreferences(ctx, session.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	var ret openapi.NotificationPreferences
-
-	if len(p.CustomNotifications) > 0 {
-		ret.CustomNotifications = make(map[string]openapi.NotificationMessage, len(p.CustomNotifications))
-		for k, v := range p.CustomNotifications {
-			ret.CustomNotifications[k] = openapi.NotificationMessage(v)
-		}
+	ret := openapi.NotificationPreferences{
+		NotificationConfigs: p.NotificationConfigs.Grouped(),
+		Timezone:            p.Timezone,
+		DigestFrequency:     openapi.DigestFrequency(p.DigestFrequency),
 	}
 
-	if len(p.NotificationConfigs.WebPush) > 0 {
-		s := make([]openapi.PushSubscription, len(p.NotificationConfigs.WebPush))
-		for i, sub := range p.NotificationConfigs.WebPush {
-			s[i] = openapi.PushSubscription{
-				DeviceID:       sub.DeviceID,
-				ExpirationTime: sub.ExpirationTime,
-			}
-			s[i].Keys.P256Dh = sub.Keys.P256Dh
+	if len(p.Routes) > 0 {
+		ret.Routes = make(openapi.NotificationRoutes, len(p.Routes))
+		for t, route := range p.Routes {
+			ret.Routes[t] = openapi.NotificationRoute(route)
 		}
-		ret.NotificationConfigs.WebPush = &s
 	}
 
-	if len(p.NotificationConfigs.Email) > 0 {
-		s := make([]openapi.EmailSubscription, len(p.NotificationConfigs.Email))
-		for i, sub := range p.NotificationConfigs.Email {
-			s[i] = openapi.EmailSubscription(sub)
+	if len(p.CustomNotifications) > 0 {
+		ret.CustomNotifications = make(map[string]openapi.NotificationMessage, len(p.CustomNotifications))
+		for k, v := range p.CustomNotifications {
+			ret.CustomNotifications[k] = openapi.NotificationMessage(v)
 		}
-		ret.NotificationConfigs.Email = &s
 	}
 
 	return openapi.UserNotificationPreferences200JSONResponse(ret), nil
@@ -375,30 +842,27 @@ func (h *openAPIHandler) UserNotificationPreferences(ctx context.Context, reques
 func (h *openAPIHandler) UserUpdateNotificationPreferences(ctx context.Context, request openapi.UserUpdateNotificationPreferencesRequestObject) (openapi.UserUpdateNotificationPreferencesResponseObject, error) {
 	session := sessionFromCtx(ctx)
 
-	newPreferences := &notification.UserPreferences{}
-
-	if request.Body.CustomNotifications != nil {
-		newPreferences.CustomNotifications = make(notificationapi.CustomNotifications, len(request.Body.CustomNotifications))
-		for k, v := range request.Body.CustomNotifications {
-			newPreferences.CustomNotifications[k] = notificationapi.NotificationMessage(v)
-		}
+	newPreferences := &notification.UserPreferences{
+		NotificationConfigs: notification.GroupedNotificationConfigs(request.Body.NotificationConfigs),
+		Timezone:            request.Body.Timezone,
+		DigestFrequency:     notification.DigestFrequency(request.Body.DigestFrequency),
 	}
 
-	if request.Body.NotificationConfigs.Email != nil {
-		newPreferences.NotificationConfigs.Email = make([]notificationapi.EmailSubscription, len(*request.Body.NotificationConfigs.Email))
-		for i, v := range *request.Body.NotificationConfigs.Email {
-			newPreferences.NotificationConfigs.Email[i] = notificationapi.EmailSubscription(v)
+	if len(request.Body.Routes) > 0 {
+		newPreferences.Routes = make(notificationapi.NotificationRoutes, len(request.Body.Routes))
+		for t, route := range request.Body.Routes {
+			newPreferences.Routes[t] = notificationapi.NotificationRoute(route)
 		}
 	}
 
-	if request.Body.NotificationConfigs.WebPush != nil {
-		newPreferences.NotificationConfigs.WebPush = make([]notificationapi.PushSubscription, len(*request.Body.NotificationConfigs.WebPush))
-		for i, v := range *request.Body.NotificationConfigs.WebPush {
-			newPreferences.NotificationConfigs.WebPush[i] = notificationapi.PushSubscription(v)
+	if request.Body.CustomNotifications != nil {
+		newPreferences.CustomNotifications = make(notificationapi.CustomNotifications, len(request.Body.CustomNotifications))
+		for k, v := range request.Body.CustomNotifications {
+			newPreferences.CustomNotifications[k] = notificationapi.NotificationMessage(v)
 		}
 	}
 
-	if err := h.notifs.SetUserPreferences(ctx, session.UserSecret, newPreferences); err != nil {
+	if err := h.notifs.SetUserPreferences(ctx, session.UserID, newPreferences); err != nil {
 		return nil, err
 	}
 
@@ -410,9 +874,30 @@ func (h *openAPIHandler) UserUpdateNotificationPreferences(ctx context.Context,
 func (h *openAPIHandler) SendTestNotification(ctx context.Context, request openapi.SendTestNotificationRequestObject) (openapi.SendTestNotificationResponseObject, error) {
 	session := sessionFromCtx(ctx)
 
-	if err := h.notifs.NotifyUser(ctx, session.UserSecret, notificationapi.NotificationType(openapi.TestMessage)); err != nil {
+	vars, err := dosage.LoadMessageVariables(ctx, h.dosage, h.doseHistory, session.UserID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var target notification.TestNotificationTarget
+	if request.Body != nil {
+		target = notification.TestNotificationTarget(*request.Body)
+	}
+
+	results, err := h.notifs.SendTestNotification(ctx, session.UserID, target, vars)
+	if err != nil {
 		return nil, fmt.Errorf("cannot send test notification: %w", err)
 	}
 
-	return openapi.SendTestNotification204Response{}, nil
+	response := make(openapi.SendTestNotification200JSONResponse, len(results))
+	for i, r := range results {
+		response[i] = openapi.TestNotificationResult{
+			Method:     r.Method,
+			ID:         r.ID,
+			Status:     openapi.TestNotificationStatus(r.Status),
+			Error:      r.Error,
+			HTTPStatus: r.HTTPStatus,
+		}
+	}
+	return response, nil
 }
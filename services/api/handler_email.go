@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+
+	"e2clicker.app/services/api/openapi"
+)
+
+// emailLinkPage is the page shown by links in emails, such as the unsubscribe
+// and confirmation links. It asks for confirmation with a form that POSTs back
+// to the same URL, so that link scanners don't act on the user's behalf.
+// It is deliberately plain, since it's served by the API and not the frontend.
+var emailLinkPage = template.Must(template.New("email-link").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="color-scheme" content="light dark">
+<title>{{ .Title }} - e2clicker</title>
+<style>
+body { font-family: Nunito, -apple-system, "Segoe UI", Roboto, sans-serif; max-width: 32em; margin: 4em auto; padding: 0 1em; line-height: 1.5; }
+button { font: inherit; padding: 0.5em 1.25em; border: 0; border-radius: 0.45rem; background: #f89fb1; color: #201b1f; cursor: pointer; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<p>{{ .Text }}</p>
+{{- if .Button }}
+<form method="post">
+<input type="hidden" name="token" value="{{ .Token }}">
+<button type="submit">{{ .Button }}</button>
+</form>
+{{- end }}
+</body>
+</html>
+`))
+
+type emailLinkPageData struct {
+	Title string
+	Text  string
+	// Button is the label of the button that submits the form.
+	// If empty, no form is shown.
+	Button string
+	Token  string
+}
+
+func renderEmailLinkPage(data emailLinkPageData) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	if err := emailLinkPage.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+// Show the page to unsubscribe an email address
+// (GET /notifications/email/unsubscribe)
+func (h *openAPIHandler) EmailUnsubscribePage(ctx context.Context, request openapi.EmailUnsubscribePageRequestObject) (openapi.EmailUnsubscribePageResponseObject, error) {
+	buf, err := renderEmailLinkPage(emailLinkPageData{
+		Title:  "Unsubscribe",
+		Text:   "Stop sending e2clicker notifications to this email address?",
+		Button: "Unsubscribe",
+		Token:  request.Params.Token,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.EmailUnsubscribePage200TextHTMLResponse{
+		Body:          buf,
+		ContentLength: int64(buf.Len()),
+	}, nil
+}
+
+// Unsubscribe an email address
+// (POST /notifications/email/unsubscribe)
+func (h *openAPIHandler) EmailUnsubscribe(ctx context.Context, request openapi.EmailUnsubscribeRequestObject) (openapi.EmailUnsubscribeResponseObject, error) {
+	address, err := h.notifs.UnsubscribeEmail(ctx, request.Params.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := renderEmailLinkPage(emailLinkPageData{
+		Title: "Unsubscribed",
+		Text:  address + " will no longer receive notifications from e2clicker.",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.EmailUnsubscribe200TextHTMLResponse{
+		Body:          buf,
+		ContentLength: int64(buf.Len()),
+	}, nil
+}
+
+// Show the page to confirm an email address
+// (GET /notifications/email/confirm)
+func (h *openAPIHandler) EmailConfirmPage(ctx context.Context, request openapi.EmailConfirmPageRequestObject) (openapi.EmailConfirmPageResponseObject, error) {
+	buf, err := renderEmailLinkPage(emailLinkPageData{
+		Title:  "Confirm email address",
+		Text:   "Start sending e2clicker notifications to this email address?",
+		Button: "Confirm",
+		Token:  request.Params.Token,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.EmailConfirmPage200TextHTMLResponse{
+		Body:          buf,
+		ContentLength: int64(buf.Len()),
+	}, nil
+}
+
+// Confirm an email address
+// (POST /notifications/email/confirm)
+func (h *openAPIHandler) EmailConfirm(ctx context.Context, request openapi.EmailConfirmRequestObject) (openapi.EmailConfirmResponseObject, error) {
+	address, err := h.notifs.ConfirmEmail(ctx, request.Params.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := renderEmailLinkPage(emailLinkPageData{
+		Title: "Email address confirmed",
+		Text:  address + " will now receive notifications from e2clicker.",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.EmailConfirm200TextHTMLResponse{
+		Body:          buf,
+		ContentLength: int64(buf.Len()),
+	}, nil
+}
@@ -1,10 +1,12 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"mime"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/timewasted/go-accept-headers"
@@ -16,15 +18,20 @@ import (
 type openAPIHandlerForImportExport struct {
 	openapi.ServerInterface
 	doseExporter *dosage.ExporterService
+	doseMQTT     *dosage.DosageMQTTService
+	takeouts     *dosage.TakeoutService
 }
 
 func newOpenAPIHandlerForImportExport(
 	h *openAPIHandler,
 	doseExporter *dosage.ExporterService,
+	takeouts *dosage.TakeoutService,
 ) *openAPIHandlerForImportExport {
 	return &openAPIHandlerForImportExport{
 		ServerInterface: h.asHandler(),
 		doseExporter:    doseExporter,
+		doseMQTT:        h.doseMQTT,
+		takeouts:        takeouts,
 	}
 }
 
@@ -34,7 +41,7 @@ func (h *openAPIHandlerForImportExport) asHandler() openapi.ServerInterface {
 
 func (h *openAPIHandlerForImportExport) ExportDoses(w http.ResponseWriter, r *http.Request, params openapi.ExportDosesParams) {
 	ctx := r.Context()
-	session := sessionFromCtx(ctx)
+	owner := ownerFromCtx(ctx)
 
 	var format dosage.ExportFormat
 acceptSearch:
@@ -66,7 +73,7 @@ acceptSearch:
 	w.Header().Set("Content-Type", format.AsMIME())
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", exportName))
 
-	n, err := h.doseExporter.ExportDoseHistory(ctx, w, session.UserSecret, dosage.ExportDoseHistoryOptions{
+	n, err := h.doseExporter.ExportDoseHistory(ctx, w, owner, dosage.ExportDoseHistoryOptions{
 		Begin:  optPtr(params.Start),
 		End:    optPtr(params.End),
 		Format: format,
@@ -83,7 +90,7 @@ acceptSearch:
 
 func (h *openAPIHandlerForImportExport) ImportDoses(w http.ResponseWriter, r *http.Request, params openapi.ImportDosesParams) {
 	ctx := r.Context()
-	session := sessionFromCtx(ctx)
+	owner := ownerFromCtx(ctx)
 
 	contentType, ctParams, err := mime.ParseMediaType(string(params.ContentType))
 	if err != nil {
@@ -107,7 +114,7 @@ func (h *openAPIHandlerForImportExport) ImportDoses(w http.ResponseWriter, r *ht
 		return
 	}
 
-	result, err := h.doseExporter.ImportDoseHistory(ctx, r.Body, session.UserSecret, dosage.ImportDoseHistoryOptions{
+	result, err := h.doseExporter.ImportDoseHistory(ctx, r.Body, owner, dosage.ImportDoseHistoryOptions{
 		Format: format,
 	})
 	if result.Records == 0 && err != nil {
@@ -115,6 +122,10 @@ func (h *openAPIHandlerForImportExport) ImportDoses(w http.ResponseWriter, r *ht
 		return
 	}
 
+	if result.Succeeded > 0 {
+		h.doseMQTT.DosesChanged(ctx, owner)
+	}
+
 	var oapiError *openapi.Error
 	if err != nil {
 		converted := convertError[errorResponse](ctx, err)
@@ -127,3 +138,92 @@ func (h *openAPIHandlerForImportExport) ImportDoses(w http.ResponseWriter, r *ht
 		Error:     oapiError,
 	})
 }
+
+func (h *openAPIHandlerForImportExport) ExportTakeout(w http.ResponseWriter, r *http.Request, params openapi.ExportTakeoutParams) {
+	ctx := r.Context()
+	session := sessionFromCtx(ctx)
+
+	var format dosage.TakeoutFormat
+acceptSearch:
+	for _, t := range accept.Parse(string(params.Accept)) {
+		switch t.Type + "/" + t.Subtype {
+		case "application/zip":
+			format = dosage.TakeoutZip
+			break acceptSearch
+		case "application/x-tar":
+			format = dosage.TakeoutTar
+			break acceptSearch
+		}
+	}
+
+	if format == "" {
+		writeError(w, r, ErrNoAcceptableContentType, http.StatusNotAcceptable)
+		return
+	}
+
+	exportExtension := "zip"
+	if format == dosage.TakeoutTar {
+		exportExtension = "tar"
+	}
+
+	exportTime := time.Now().Format(time.RFC3339)
+	exportName := fmt.Sprintf("e2clicker-takeout-%s.%s", exportTime, exportExtension)
+
+	// The archive is only written once everything was read, so errors can
+	// still be written until then.
+	var buf bytes.Buffer
+	if err := h.takeouts.ExportTakeout(ctx, &buf, session.UserID, dosage.ExportTakeoutOptions{
+		Format:             format,
+		IncludeCredentials: optPtr(params.IncludeCredentials),
+	}); err != nil {
+		writeError(w, r, err, 0)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.AsMIME())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", exportName))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	buf.WriteTo(w)
+}
+
+func (h *openAPIHandlerForImportExport) ImportTakeout(w http.ResponseWriter, r *http.Request, params openapi.ImportTakeoutParams) {
+	ctx := r.Context()
+	session := sessionFromCtx(ctx)
+
+	contentType, _, err := mime.ParseMediaType(string(params.ContentType))
+	if err != nil {
+		writeError(w, r, publicerrors.Errorf("invalid content type: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	var format dosage.TakeoutFormat
+	switch contentType {
+	case "application/zip":
+		format = dosage.TakeoutZip
+	case "application/x-tar":
+		format = dosage.TakeoutTar
+	default:
+		writeError(w, r, ErrNoAcceptableContentType, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	result, err := h.takeouts.ImportTakeout(ctx, r.Body, session.UserID, dosage.ImportTakeoutOptions{
+		Format: format,
+	})
+	if err != nil {
+		writeError(w, r, err, 0)
+		return
+	}
+
+	if result.Doses > 0 {
+		h.doseMQTT.DosesChanged(ctx, session.UserID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(openapi.ImportTakeout200JSONResponse{
+		Version:                    result.Manifest.Version,
+		ExportedAt:                 result.Manifest.ExportedAt,
+		Doses:                      int(result.Doses),
+		SkippedNotificationConfigs: result.SkippedNotificationConfigs,
+	})
+}
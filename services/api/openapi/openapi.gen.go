@@ -32,11 +32,41 @@ const (
 
 // Defines values for NotificationType.
 const (
-	AccountNoticeMessage   NotificationType = "account_notice_message"
-	ReminderMessage        NotificationType = "reminder_message"
-	TestMessage            NotificationType = "test_message"
-	WebPushExpiringMessage NotificationType = "web_push_expiring_message"
-	WelcomeMessage         NotificationType = "welcome_message"
+	AccountDeletedMessage     NotificationType = "account_deleted_message"
+	AccountNoticeMessage      NotificationType = "account_notice_message"
+	DigestMessage             NotificationType = "digest_message"
+	ReminderMessage           NotificationType = "reminder_message"
+	SubscriptionPausedMessage NotificationType = "subscription_paused_message"
+	TestMessage               NotificationType = "test_message"
+	WebPushExpiringMessage    NotificationType = "web_push_expiring_message"
+	WelcomeMessage            NotificationType = "welcome_message"
+)
+
+// Defines values for DelegationRole.
+const (
+	Delegate DelegationRole = "delegate"
+	Owner    DelegationRole = "owner"
+)
+
+// Defines values for DigestFrequency.
+const (
+	MonthlyDigest DigestFrequency = "monthly"
+	WeeklyDigest  DigestFrequency = "weekly"
+)
+
+// Defines values for ShareField.
+const (
+	Comments     ShareField = "comments"
+	CurrentLevel ShareField = "currentLevel"
+	Doses        ShareField = "doses"
+	Levels       ShareField = "levels"
+)
+
+// Defines values for TestNotificationStatus.
+const (
+	TestNotificationFailed  TestNotificationStatus = "failed"
+	TestNotificationSent    TestNotificationStatus = "sent"
+	TestNotificationSkipped TestNotificationStatus = "skipped"
 )
 
 // Defines values for ExportDosesParamsAccept.
@@ -51,6 +81,18 @@ const (
 	ImportDosesParamsContentTypeTextCsv         ImportDosesParamsContentType = "text/csv"
 )
 
+// Defines values for ExportTakeoutParamsAccept.
+const (
+	ExportTakeoutParamsAcceptApplicationXTar ExportTakeoutParamsAccept = "application/x-tar"
+	ExportTakeoutParamsAcceptApplicationZip  ExportTakeoutParamsAccept = "application/zip"
+)
+
+// Defines values for ImportTakeoutParamsContentType.
+const (
+	ImportTakeoutParamsContentTypeApplicationXTar ImportTakeoutParamsContentType = "application/x-tar"
+	ImportTakeoutParamsContentTypeApplicationZip  ImportTakeoutParamsContentType = "application/zip"
+)
+
 // PushDeviceID A short ID associated with the device that the push subscription is for This is used to identify the device when updating its push subscription later on.
 // Realistically, this will be handled as an opaque random string generated on the device side, so the server has no way to correlate  it with any fingerprinting.
 // The recommended way to generate this string in JavaScript is:
@@ -67,11 +109,67 @@ type PushDeviceID = string
 //   - `web_push_expiring_message` is sent to notify the user that their
 //     web push subscription is expiring.
 //   - `test_message` is sent to test your notification settings.
+//   - `subscription_paused_message` is sent to notify the user that one
+//     of their notification configs kept failing and has been paused.
+//   - `digest_message` is the weekly or monthly summary of the user's
+//     doses, if they opted in to it.
+//   - `account_deleted_message` is sent to confirm that the user's
+//     account and all of its data were deleted. It is the last
+//     notification that the user gets.
 type NotificationType string
 
+// AccountDeletion The deletion of a user's account.
+type AccountDeletion struct {
+	// PurgeAt The time the account is purged. If the server has no grace period, the account was already purged and this is the current time.
+	PurgeAt time.Time `json:"purgeAt"`
+}
+
 // CustomNotifications Custom notifications that the user can override with. The object keys are the notification types.
+//
+// The title and message are Go [text/template](https://pkg.go.dev/text/template) templates. They can use `{{ .Username }}`, `{{ .Dose }}`, `{{ .Units }}`, `{{ .DeliveryMethod }}`, `{{ .LastDoseAt }}`, `{{ .SinceLastDose }}`, `{{ .DueAt }}` and `{{ .Overdue }}`, which are empty or zero for notifications that aren't about a dose. Digests can also use `{{ .Digest }}`, which is a `DigestSummary` with Go field names, e.g. `{{ .Digest.DosesTaken }}`. Durations and times can be formatted with the `duration`, `time`, `date` and `datetime` functions, e.g. `{{ duration .Overdue }}` or `{{ time .DueAt }}`, and `minutes` turns a number of minutes into a duration. Loops and nested templates are not allowed.
 type CustomNotifications map[string]NotificationMessage
 
+// Delegation Access that one user, the owner, gave another user, the delegate, to their data.
+type Delegation struct {
+	// ID The delegation identifier
+	ID int64 `json:"id"`
+
+	// OwnerID The ID of the owner, which the delegate puts in the `X-On-Behalf-Of` header
+	OwnerID int64 `json:"ownerID"`
+
+	// OwnerName The name of the owner
+	OwnerName string `json:"ownerName"`
+
+	// DelegateName The name of the delegate, or null if the invite wasn't accepted yet
+	DelegateName *string `json:"delegateName,omitempty"`
+
+	// MirrorNotifications Whether the owner's reminders and digests are also sent to the delegate
+	MirrorNotifications bool `json:"mirrorNotifications"`
+
+	// CreatedAt The time the invite was created
+	CreatedAt time.Time `json:"createdAt"`
+
+	// InviteExpiresAt The time the invite expires, or null if it was accepted
+	InviteExpiresAt *time.Time `json:"inviteExpiresAt,omitempty"`
+
+	// AcceptedAt The time the invite was accepted, or null if it wasn't yet
+	AcceptedAt *time.Time `json:"acceptedAt,omitempty"`
+
+	// Access What a delegate may do with the owner's data:
+	// - `read`: see the dosage and dose history - `record`: also record, edit and forget doses
+	Access DelegationAccess `json:"access"`
+
+	// Role Whether the current user is the owner or the delegate of a delegation.
+	Role DelegationRole `json:"role"`
+}
+
+// DelegationAccess What a delegate may do with the owner's data:
+// - `read`: see the dosage and dose history - `record`: also record, edit and forget doses
+type DelegationAccess = user.DelegationAccess
+
+// DelegationRole Whether the current user is the owner or the delegate of a delegation.
+type DelegationRole string
+
 // DeliveryMethod defines model for DeliveryMethod.
 type DeliveryMethod struct {
 	// ID A short string representing the delivery method. This is what goes into the DeliveryMethod fields.
@@ -87,6 +185,44 @@ type DeliveryMethod struct {
 	Description string `json:"description,omitempty"`
 }
 
+// DigestFrequency How often the user gets a digest of their doses. Weekly digests cover Monday to Sunday and are sent on Monday morning, and monthly digests cover the previous month and are sent on its first morning, in the user's time zone.
+type DigestFrequency string
+
+// DigestSummary A summary of the user's doses over a week or a month.
+type DigestSummary struct {
+	Frequency DigestFrequency `json:"frequency"`
+
+	// PeriodStart The start of the period that the digest covers.
+	PeriodStart time.Time `json:"periodStart"`
+
+	// PeriodEnd The end of the period that the digest covers. It is exclusive.
+	PeriodEnd time.Time `json:"periodEnd"`
+
+	// DosesTaken The number of doses taken in the period.
+	DosesTaken int `json:"dosesTaken"`
+
+	// DosesExpected The number of doses that the user's schedule called for in the period.
+	DosesExpected int `json:"dosesExpected"`
+
+	// AverageLateMinutes How many minutes late the doses in the period were taken on average. Doses taken early count as on time.
+	AverageLateMinutes int `json:"averageLateMinutes"`
+
+	// RemindersSent The number of dose reminders that were sent in the period.
+	RemindersSent int `json:"remindersSent"`
+
+	// RemindersFailed The number of dose reminders that failed to be sent in the period.
+	RemindersFailed int `json:"remindersFailed"`
+
+	// NextDoseAt When the next dose is due, if the user has taken any dose.
+	NextDoseAt *time.Time `json:"nextDoseAt,omitempty"`
+
+	// Trough The estimated serum estradiol level right before the next dose, in `troughUnits`. It is omitted if it can't be estimated for the user's delivery method.
+	Trough float64 `json:"trough,omitempty"`
+
+	// TroughUnits The units of `trough`, e.g. `pg/mL`.
+	TroughUnits string `json:"troughUnits,omitempty"`
+}
+
 // Dosage defines model for Dosage.
 type Dosage struct {
 	// DeliveryMethod The delivery method to use.
@@ -133,6 +269,9 @@ type EmailSubscription struct {
 
 	// Name The name of the user to send the email to. This name will be used with the email address in the `To` field.
 	Name *string `json:"name,omitempty"`
+
+	// Pending Whether the email address is waiting to be confirmed. A confirmation link is sent to every newly added address, and no notifications are sent to it until the link is followed. This is set by the server and ignored when updating preferences.
+	Pending bool `json:"pending,omitempty"`
 }
 
 // Error defines model for Error.
@@ -153,6 +292,12 @@ type Error struct {
 	Message string `json:"message"`
 }
 
+// LevelSample An estimated level at a point in time.
+type LevelSample struct {
+	Time  time.Time `json:"time"`
+	Level float64   `json:"level"`
+}
+
 // Locale A locale identifier.
 type Locale = user.Locale
 
@@ -168,6 +313,13 @@ type Notification struct {
 	//   - `web_push_expiring_message` is sent to notify the user that their
 	//     web push subscription is expiring.
 	//   - `test_message` is sent to test your notification settings.
+	//   - `subscription_paused_message` is sent to notify the user that one
+	//     of their notification configs kept failing and has been paused.
+	//   - `digest_message` is the weekly or monthly summary of the user's
+	//     doses, if they opted in to it.
+	//   - `account_deleted_message` is sent to confirm that the user's
+	//     account and all of its data were deleted. It is the last
+	//     notification that the user gets.
 	Type NotificationType `json:"type"`
 
 	// Message The message of the notification.
@@ -175,6 +327,40 @@ type Notification struct {
 
 	// Username The username of the user to send the notification to.
 	Username string `json:"username"`
+
+	// Digest The summary that a `digest_message` notification is about. It is only set for digests.
+	Digest *DigestSummary `json:"digest,omitempty"`
+
+	// Resubscribe This is only set on web push notifications. It is true if the push subscription was created with a VAPID key that the server has since rotated out, in which case the client should subscribe again using the key from `PushInfo`.
+	Resubscribe bool `json:"resubscribe,omitempty"`
+}
+
+// NotificationConfig The config of a single notification channel. For example, an `email` config is an `EmailSubscription`, and a `webPush` config is a `PushSubscription`.
+//
+// Besides the properties in the method's config schema, every config has an `_id` and a `_health` set by the server. The `_id` should be sent back with the config so that it keeps its health. Configs without a known `_id` are treated as new.
+type NotificationConfig = json.RawMessage
+
+// NotificationConfigs The user's notification channels, grouped by notification method. Each config must follow the config schema of its method, as returned by `GET /notifications/methods`. The configs of methods that the server does not support are rejected.
+//
+// Credentials, which are the properties marked with `x-secret: true` in the config schema, may be stored encrypted. They are then returned encrypted and can be sent back as they are to keep them unchanged.
+type NotificationConfigs map[string][]NotificationConfig
+
+// NotificationHealth Whether notifications are being delivered to a notification config. A config that fails too many times in a row is paused: no notifications other than test notifications are sent to it until a test notification succeeds.
+type NotificationHealth struct {
+	// LastSuccessAt The last time a notification was delivered.
+	LastSuccessAt *time.Time `json:"lastSuccessAt,omitempty"`
+
+	// LastErrorAt The last time a notification failed to be delivered.
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"`
+
+	// LastError The error of the last failed notification.
+	LastError string `json:"lastError,omitempty"`
+
+	// ConsecutiveFailures The number of notifications that failed in a row.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+
+	// Paused Whether the config is paused because it failed too many times in a row.
+	Paused bool `json:"paused"`
 }
 
 // NotificationMessage The message of the notification. This is derived from the notification type but can be overridden by the user.
@@ -186,23 +372,100 @@ type NotificationMessage struct {
 	Message string `json:"message"`
 }
 
-// NotificationMethodSupports A list of notification methods that the server supports.
-type NotificationMethodSupports = []string
+// NotificationMethod A notification method that the server supports.
+type NotificationMethod struct {
+	// Name The name of the method. This is the key of the method's configs in `NotificationConfigs`.
+	Name string `json:"name"`
+
+	// ConfigSchema The JSON Schema of the method's config. Clients can use it to render the settings form of the method.
+	ConfigSchema json.RawMessage `json:"configSchema"`
+}
+
+// NotificationMethods A list of notification methods that the server supports.
+type NotificationMethods = []NotificationMethod
 
 // NotificationPreferences The user's notification preferences.
 // Each key is a notification type and the value is the notification configuration for that type. It may be nil if the server does not support a particular notification type.
 type NotificationPreferences struct {
 	CustomNotifications CustomNotifications `json:"customNotifications,omitempty"`
-	NotificationConfigs struct {
-		Email   *[]EmailSubscription `json:"email,omitempty"`
-		WebPush *[]PushSubscription  `json:"webPush,omitempty"`
-	} `json:"notificationConfigs"`
+
+	// DigestFrequency How often the user gets a `digest_message`. Digests are off if this is not set.
+	DigestFrequency DigestFrequency `json:"digestFrequency,omitempty"`
+
+	// NotificationConfigs The user's notification channels, grouped by notification method. Each config must follow the config schema of its method, as returned by `GET /notifications/methods`. The configs of methods that the server does not support are rejected.
+	//
+	// Credentials, which are the properties marked with `x-secret: true` in the config schema, may be stored encrypted. They are then returned encrypted and can be sent back as they are to keep them unchanged.
+	NotificationConfigs NotificationConfigs `json:"notificationConfigs"`
+	Routes              NotificationRoutes  `json:"routes,omitempty"`
+
+	// Timezone The IANA time zone of the user, e.g. `America/Los_Angeles`. Times in custom notification messages are shown in this time zone. If empty, UTC is used.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// NotificationRoute The channels that a type of notification is sent to.
+type NotificationRoute struct {
+	// Methods The notification methods that receive this type of notification. An empty list mutes the notification type.
+	Methods []string `json:"methods"`
+
+	// Devices If set, web push notifications of this type are only sent to these devices instead of every device. This only matters if `webPush` is in `methods`.
+	Devices []PushDeviceID `json:"devices,omitempty"`
+}
+
+// NotificationRoutes Routing rules that decide where each type of notification is sent. The object keys are the notification types. Types without a rule are sent to every configured channel.
+type NotificationRoutes map[string]NotificationRoute
+
+// Passkey A passkey that a user can log in with instead of their secret.
+type Passkey struct {
+	// ID The passkey identifier
+	ID int64 `json:"id"`
+
+	// Name The name of the passkey
+	Name string `json:"name"`
+
+	// CreatedAt The time the passkey was added
+	CreatedAt time.Time `json:"createdAt"`
+
+	// LastUsed The last time the passkey was used to log in, or null if it was never used
+	LastUsed *time.Time `json:"lastUsed,omitempty"`
+}
+
+// PasskeyChallenge The start of a WebAuthn ceremony.
+type PasskeyChallenge struct {
+	// CeremonyID The ceremony identifier, which must be sent back to finish the ceremony
+	CeremonyID string `json:"ceremonyID"`
+
+	// Options The options to give to `navigator.credentials.create()` or `navigator.credentials.get()`, which contain a `publicKey` object
+	Options json.RawMessage `json:"options"`
+}
+
+// PersonalToken A long-lived token that can be used instead of a session for the operations that its scopes allow.
+type PersonalToken struct {
+	// ID The token identifier
+	ID int64 `json:"id"`
+
+	// Name The name of the token
+	Name string `json:"name"`
+
+	// Scopes The scopes that the token may be used for
+	Scopes []Scope `json:"scopes"`
+
+	// CreatedAt The time the token was created
+	CreatedAt time.Time `json:"createdAt"`
+
+	// LastUsed The last time the token was used, or null if it was never used
+	LastUsed *time.Time `json:"lastUsed,omitempty"`
+
+	// ExpiresAt The time the token expires, or null if it never expires
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
 // PushInfo This is returned by the server and contains information that the client would need to subscribe to push notifications.
 type PushInfo struct {
 	// ApplicationServerKey A Base64-encoded string or ArrayBuffer containing an ECDSA P-256 public key that the push server will use to authenticate your application server. If specified, all messages from your application server must use the VAPID authentication scheme, and include a JWT signed with the corresponding private key. This key IS NOT the same ECDH key that you use to encrypt the data. For more information, see "Using VAPID with WebPush".
 	ApplicationServerKey string `json:"applicationServerKey"`
+
+	// KeyID The ID of the VAPID key in `applicationServerKey`. Clients should store it in the `keyID` of the push subscription that they create with the key.
+	KeyID string `json:"keyID,omitempty"`
 }
 
 // PushSubscription The configuration for a push notification subscription.
@@ -228,8 +491,17 @@ type PushSubscription struct {
 		// Auth An authentication secret, as described in Message Encryption for Web Push.
 		Auth string `json:"auth"`
 	} `json:"keys"`
+
+	// KeyID The ID of the VAPID key that the subscription was created with, as returned in `PushInfo`. Subscriptions without one were created with the key that had no ID.
+	KeyID string `json:"keyID,omitempty"`
 }
 
+// RecoveryCode A single-use code that a user can log in with if they lost their secret. Spaces and dashes in it are ignored.
+type RecoveryCode = user.RecoveryCode
+
+// Scope A scope that a personal access token may be used for.
+type Scope = user.Scope
+
 // Session A session for a user.
 type Session struct {
 	// ID The session identifier
@@ -243,6 +515,140 @@ type Session struct {
 
 	// ExpiresAt The time the session expires, or null if it never expires
 	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+
+	// Current Whether this is the session that made the request
+	Current bool `json:"current"`
+}
+
+// ShareField A kind of data that a share link can show:
+// - `doses`: the doses taken within the shared range - `comments`: the comments of the shared doses - `levels`: the estimated levels within the shared range - `currentLevel`: the estimated level at the time the link is opened
+type ShareField string
+
+// ShareLink A link that shows some of a user's dosage data to anyone who has it.
+type ShareLink struct {
+	// ID The share link identifier
+	ID int64 `json:"id"`
+
+	// Name The name of the share link
+	Name string `json:"name"`
+
+	// Start The start of the shared time range
+	Start time.Time `json:"start"`
+
+	// End The end of the shared time range
+	End time.Time `json:"end"`
+
+	// Fields The kinds of data that are shared
+	Fields []ShareField `json:"fields"`
+
+	// HasPIN Whether a PIN is needed to open the link
+	HasPIN bool `json:"hasPIN"`
+
+	// CreatedAt The time the share link was created
+	CreatedAt time.Time `json:"createdAt"`
+
+	// ExpiresAt The time the share link expires
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// RevokedAt The time the share link was revoked, or null if it was not
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+// ShareLinkAccess An attempt to open a share link.
+type ShareLinkAccess struct {
+	// AccessedAt The time the share link was opened
+	AccessedAt time.Time `json:"accessedAt"`
+
+	// UserAgent The user agent that opened the share link, if any
+	UserAgent *string `json:"userAgent,omitempty"`
+
+	// Granted Whether the shared data was shown. This is false if the PIN was wrong.
+	Granted bool `json:"granted"`
+
+	// LockedOut Whether the PIN was wrong too many times in a row, so this attempt locked the share link, which revokes it.
+	LockedOut bool `json:"lockedOut"`
+}
+
+// SharedData The data that a share link shows. Data that isn't shared is left out.
+type SharedData struct {
+	// Start The start of the shared time range
+	Start time.Time `json:"start"`
+
+	// End The end of the shared time range
+	End time.Time `json:"end"`
+
+	// ExpiresAt The time the share link expires
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// LevelUnits The units of the estimated levels
+	LevelUnits string         `json:"levelUnits"`
+	Doses      *DosageHistory `json:"doses,omitempty"`
+
+	// Levels The estimated levels within the shared range, up to the time the link was opened
+	Levels *[]LevelSample `json:"levels,omitempty"`
+
+	// CurrentLevel The estimated level at the time the link was opened
+	CurrentLevel *float64 `json:"currentLevel,omitempty"`
+}
+
+// TakeoutImportResult The result of restoring an account export.
+type TakeoutImportResult struct {
+	// Doses The number of doses that were restored.
+	Doses int `json:"doses"`
+
+	// ExportedAt The time the export was made.
+	ExportedAt time.Time `json:"exportedAt"`
+
+	// SkippedNotificationConfigs The number of notification configs that couldn't be restored on this server.
+	SkippedNotificationConfigs int `json:"skippedNotificationConfigs"`
+
+	// Version The version of the archive format that the export was made with.
+	Version int `json:"version"`
+}
+
+// TestNotificationResult The result of sending a test notification to a single config.
+type TestNotificationResult struct {
+	// Method The method of the config.
+	Method string `json:"method"`
+
+	// ID The `_id` of the config. It is omitted for unsaved configs.
+	ID string `json:"id,omitempty"`
+
+	// Status Whether the notification was delivered:
+	//
+	//   - `sent` if it was delivered.
+	//   - `skipped` if the config can't receive notifications yet, such as
+	//     an email address that isn't confirmed.
+	//   - `failed` if it couldn't be delivered. `error` says why.
+	Status TestNotificationStatus `json:"status"`
+
+	// Error The error message if the notification failed. For unsaved configs, it doesn't say why.
+	Error string `json:"error,omitempty"`
+
+	// HTTPStatus The HTTP status code that the notification service responded with, if it responded with one that wasn't expected. It is omitted for unsaved configs.
+	HTTPStatus int `json:"httpStatus,omitempty"`
+}
+
+// TestNotificationStatus Whether the notification was delivered:
+//
+//   - `sent` if it was delivered.
+//   - `skipped` if the config can't receive notifications yet, such as
+//     an email address that isn't confirmed.
+//   - `failed` if it couldn't be delivered. `error` says why.
+type TestNotificationStatus string
+
+// TestNotificationTarget The notification configs that a test notification is sent to. All configs are used if nothing is set.
+type TestNotificationTarget struct {
+	// Method Only send the notification to configs of this method. It is required if `config` is set.
+	Method string `json:"method,omitempty"`
+
+	// ID Only send the notification to the config with this `_id`.
+	ID string `json:"id,omitempty"`
+
+	// Config The config of a single notification channel. For example, an `email` config is an `EmailSubscription`, and a `webPush` config is a `PushSubscription`.
+	//
+	// Besides the properties in the method's config schema, every config has an `_id` and a `_health` set by the server. The `_id` should be sent back with the config so that it keeps its health. Configs without a known `_id` are treated as new.
+	Config *NotificationConfig `json:"config,omitempty"`
 }
 
 // User A user of the system.
@@ -252,11 +658,38 @@ type User struct {
 
 	// Locale A locale identifier.
 	Locale Locale `json:"locale"`
+
+	// PurgeAt The time the user's account is deleted, if the user asked for it to be
+	PurgeAt *time.Time `json:"purgeAt,omitempty"`
+
+	// Timezone The IANA time zone of the user, such as `Europe/Paris`. Times in notifications are shown in it. If empty, UTC is used.
+	Timezone *string `json:"timezone,omitempty"`
 }
 
-// UserSecret A secret and unique user identifier. This secret is generated once and never changes. It is used to both authenticate and identify a user, so it should be kept secret.
+// UserSecret A secret and unique user identifier. This secret is generated when registering and only changes when the user rotates it. It is used to authenticate a user, so it should be kept secret. The server only stores a hash of it, so it is only ever returned when registering or rotating it.
 type UserSecret = user.Secret
 
+// UserUpdate A change to a user's profile. Fields that are not given are left as they are.
+type UserUpdate struct {
+	// Name The user's new name. Surrounding whitespace is trimmed, and it must be between 1 and 64 characters long.
+	Name *string `json:"name,omitempty"`
+
+	// Locale A locale identifier.
+	Locale *Locale `json:"locale,omitempty"`
+
+	// Timezone The user's new IANA time zone, such as `Europe/Paris`. An empty string resets it to UTC. This is the same time zone as the one in the notification preferences.
+	Timezone *string `json:"timezone,omitempty"`
+}
+
+// WebAuthnCredential The `PublicKeyCredential` that the browser returned, encoded as JSON with `toJSON()`.
+type WebAuthnCredential = json.RawMessage
+
+// EmailConfirmToken defines model for EmailConfirmToken.
+type EmailConfirmToken = string
+
+// EmailUnsubscribeToken defines model for EmailUnsubscribeToken.
+type EmailUnsubscribeToken = string
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse = Error
 
@@ -265,7 +698,7 @@ type RateLimitedResponse = Error
 
 // AuthJSONBody defines parameters for Auth.
 type AuthJSONBody struct {
-	// Secret A secret and unique user identifier. This secret is generated once and never changes. It is used to both authenticate and identify a user, so it should be kept secret.
+	// Secret A secret and unique user identifier. This secret is generated when registering and only changes when the user rotates it. It is used to authenticate a user, so it should be kept secret. The server only stores a hash of it, so it is only ever returned when registering or rotating it.
 	Secret UserSecret `json:"secret"`
 }
 
@@ -275,6 +708,33 @@ type AuthParams struct {
 	UserAgent *string `json:"User-Agent,omitempty"`
 }
 
+// FinishPasskeyLoginJSONBody defines parameters for FinishPasskeyLogin.
+type FinishPasskeyLoginJSONBody struct {
+	// CeremonyID The ceremony ID from `/auth/passkey/begin`
+	CeremonyID string `json:"ceremonyID"`
+
+	// Credential The `PublicKeyCredential` that the browser returned, encoded as JSON with `toJSON()`.
+	Credential WebAuthnCredential `json:"credential"`
+}
+
+// FinishPasskeyLoginParams defines parameters for FinishPasskeyLogin.
+type FinishPasskeyLoginParams struct {
+	// UserAgent The user agent of the client making the request.
+	UserAgent *string `json:"User-Agent,omitempty"`
+}
+
+// RecoveryAuthJSONBody defines parameters for RecoveryAuth.
+type RecoveryAuthJSONBody struct {
+	// Code A single-use code that a user can log in with if they lost their secret. Spaces and dashes in it are ignored.
+	Code RecoveryCode `json:"code"`
+}
+
+// RecoveryAuthParams defines parameters for RecoveryAuth.
+type RecoveryAuthParams struct {
+	// UserAgent The user agent of the client making the request.
+	UserAgent *string `json:"User-Agent,omitempty"`
+}
+
 // DosageParams defines parameters for Dosage.
 type DosageParams struct {
 	Start *time.Time `form:"start,omitempty" json:"start,omitempty"`
@@ -310,11 +770,144 @@ type ImportDosesParams struct {
 // ImportDosesParamsContentType defines parameters for ImportDoses.
 type ImportDosesParamsContentType string
 
+// RevokeShareLinkParams defines parameters for RevokeShareLink.
+type RevokeShareLinkParams struct {
+	// ID The identifier of the share link to revoke.
+	ID int64 `form:"id" json:"id"`
+}
+
+// CreateShareLinkJSONBody defines parameters for CreateShareLink.
+type CreateShareLinkJSONBody struct {
+	// End The end of the time range to share
+	End time.Time `json:"end"`
+
+	// ExpiresAt The time the share link expires
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// Fields The kinds of data to share
+	Fields []ShareField `json:"fields"`
+
+	// Name A name for the share link, e.g. who it is for
+	Name *string `json:"name,omitempty"`
+
+	// Pin A PIN of 6 to 12 digits that is needed to open the link. If not given, the link can be opened without one.
+	Pin *string `json:"pin,omitempty"`
+
+	// Start The start of the time range to share
+	Start time.Time `json:"start"`
+}
+
+// DeleteCurrentUserJSONBody defines parameters for DeleteCurrentUser.
+type DeleteCurrentUserJSONBody struct {
+	// Secret A secret and unique user identifier. This secret is generated when registering and only changes when the user rotates it. It is used to authenticate a user, so it should be kept secret. The server only stores a hash of it, so it is only ever returned when registering or rotating it.
+	Secret UserSecret `json:"secret"`
+}
+
+// DeleteDelegationParams defines parameters for DeleteDelegation.
+type DeleteDelegationParams struct {
+	ID int64 `form:"id" json:"id"`
+}
+
+// InviteDelegateJSONBody defines parameters for InviteDelegate.
+type InviteDelegateJSONBody struct {
+	// Access What a delegate may do with the owner's data:
+	// - `read`: see the dosage and dose history - `record`: also record, edit and forget doses
+	Access DelegationAccess `json:"access"`
+}
+
+// AcceptDelegationJSONBody defines parameters for AcceptDelegation.
+type AcceptDelegationJSONBody struct {
+	// Code The invite code that the owner gave the user
+	Code string `json:"code"`
+
+	// MirrorNotifications Whether the user also gets the owner's reminders and digests
+	MirrorNotifications *bool `json:"mirrorNotifications,omitempty"`
+}
+
+// DeleteUserPasskeyParams defines parameters for DeleteUserPasskey.
+type DeleteUserPasskeyParams struct {
+	ID int64 `form:"id" json:"id"`
+}
+
+// FinishPasskeyRegistrationJSONBody defines parameters for FinishPasskeyRegistration.
+type FinishPasskeyRegistrationJSONBody struct {
+	// CeremonyID The ceremony ID from `/me/passkeys/begin`
+	CeremonyID string `json:"ceremonyID"`
+
+	// Credential The `PublicKeyCredential` that the browser returned, encoded as JSON with `toJSON()`.
+	Credential WebAuthnCredential `json:"credential"`
+
+	// Name A name for the passkey, e.g. the device it is on
+	Name *string `json:"name,omitempty"`
+}
+
 // DeleteUserSessionParams defines parameters for DeleteUserSession.
 type DeleteUserSessionParams struct {
 	ID int64 `form:"id" json:"id"`
 }
 
+// ExportTakeoutParams defines parameters for ExportTakeout.
+type ExportTakeoutParams struct {
+	// IncludeCredentials Include the credentials of the user's notification configs instead of redacting them. Configs with redacted credentials are skipped when the archive is restored.
+	IncludeCredentials *bool `form:"includeCredentials,omitempty" json:"includeCredentials,omitempty"`
+
+	// Accept The archive format to export the account in.
+	Accept ExportTakeoutParamsAccept `json:"Accept"`
+}
+
+// ExportTakeoutParamsAccept defines parameters for ExportTakeout.
+type ExportTakeoutParamsAccept string
+
+// ImportTakeoutParams defines parameters for ImportTakeout.
+type ImportTakeoutParams struct {
+	// ContentType The archive format of the export.
+	ContentType ImportTakeoutParamsContentType `json:"Content-Type"`
+}
+
+// ImportTakeoutParamsContentType defines parameters for ImportTakeout.
+type ImportTakeoutParamsContentType string
+
+// DeleteUserTokenParams defines parameters for DeleteUserToken.
+type DeleteUserTokenParams struct {
+	ID int64 `form:"id" json:"id"`
+}
+
+// CreateUserTokenJSONBody defines parameters for CreateUserToken.
+type CreateUserTokenJSONBody struct {
+	// ExpiresAt The time the token expires. If not given, the token never expires.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// Name A name for the token, e.g. what it is used for
+	Name *string `json:"name,omitempty"`
+
+	// Scopes The scopes that the token may be used for
+	Scopes []Scope `json:"scopes"`
+}
+
+// EmailConfirmPageParams defines parameters for EmailConfirmPage.
+type EmailConfirmPageParams struct {
+	// Token The token from the link of a confirmation email.
+	Token EmailConfirmToken `form:"token" json:"token"`
+}
+
+// EmailConfirmParams defines parameters for EmailConfirm.
+type EmailConfirmParams struct {
+	// Token The token from the link of a confirmation email.
+	Token EmailConfirmToken `form:"token" json:"token"`
+}
+
+// EmailUnsubscribePageParams defines parameters for EmailUnsubscribePage.
+type EmailUnsubscribePageParams struct {
+	// Token The token from the unsubscribe link of an email.
+	Token EmailUnsubscribeToken `form:"token" json:"token"`
+}
+
+// EmailUnsubscribeParams defines parameters for EmailUnsubscribe.
+type EmailUnsubscribeParams struct {
+	// Token The token from the unsubscribe link of an email.
+	Token EmailUnsubscribeToken `form:"token" json:"token"`
+}
+
 // UserUpdateNotificationPreferencesJSONBody defines parameters for UserUpdateNotificationPreferences.
 type UserUpdateNotificationPreferencesJSONBody struct {
 	// Current The current notification preferences. This is used to determine whether the notification method update is still valid.
@@ -322,10 +915,18 @@ type UserUpdateNotificationPreferencesJSONBody struct {
 	// TODO: Implement this field.
 	Current             *NotificationPreferences `json:"_current,omitempty"`
 	CustomNotifications CustomNotifications      `json:"customNotifications,omitempty"`
-	NotificationConfigs struct {
-		Email   *[]EmailSubscription `json:"email,omitempty"`
-		WebPush *[]PushSubscription  `json:"webPush,omitempty"`
-	} `json:"notificationConfigs"`
+
+	// DigestFrequency How often the user gets a `digest_message`. Digests are off if this is not set.
+	DigestFrequency DigestFrequency `json:"digestFrequency,omitempty"`
+
+	// NotificationConfigs The user's notification channels, grouped by notification method. Each config must follow the config schema of its method, as returned by `GET /notifications/methods`. The configs of methods that the server does not support are rejected.
+	//
+	// Credentials, which are the properties marked with `x-secret: true` in the config schema, may be stored encrypted. They are then returned encrypted and can be sent back as they are to keep them unchanged.
+	NotificationConfigs NotificationConfigs `json:"notificationConfigs"`
+	Routes              NotificationRoutes  `json:"routes,omitempty"`
+
+	// Timezone The IANA time zone of the user, e.g. `America/Los_Angeles`. Times in custom notification messages are shown in this time zone. If empty, UTC is used.
+	Timezone string `json:"timezone,omitempty"`
 }
 
 // RegisterJSONBody defines parameters for Register.
@@ -334,9 +935,24 @@ type RegisterJSONBody struct {
 	Name string `json:"name"`
 }
 
+// OpenShareLinkParams defines parameters for OpenShareLink.
+type OpenShareLinkParams struct {
+	// XSharePin The PIN of the share link, if it has one.
+	XSharePin *string `json:"X-Share-Pin,omitempty"`
+
+	// UserAgent The user agent of the client making the request.
+	UserAgent *string `json:"User-Agent,omitempty"`
+}
+
 // AuthJSONRequestBody defines body for Auth for application/json ContentType.
 type AuthJSONRequestBody AuthJSONBody
 
+// FinishPasskeyLoginJSONRequestBody defines body for FinishPasskeyLogin for application/json ContentType.
+type FinishPasskeyLoginJSONRequestBody FinishPasskeyLoginJSONBody
+
+// RecoveryAuthJSONRequestBody defines body for RecoveryAuth for application/json ContentType.
+type RecoveryAuthJSONRequestBody RecoveryAuthJSONBody
+
 // SetDosageJSONRequestBody defines body for SetDosage for application/json ContentType.
 type SetDosageJSONRequestBody = Dosage
 
@@ -346,9 +962,33 @@ type EditDoseJSONRequestBody = EditDoseJSONBody
 // ImportDosesJSONRequestBody defines body for ImportDoses for application/json ContentType.
 type ImportDosesJSONRequestBody = DosageHistory
 
+// CreateShareLinkJSONRequestBody defines body for CreateShareLink for application/json ContentType.
+type CreateShareLinkJSONRequestBody CreateShareLinkJSONBody
+
+// DeleteCurrentUserJSONRequestBody defines body for DeleteCurrentUser for application/json ContentType.
+type DeleteCurrentUserJSONRequestBody DeleteCurrentUserJSONBody
+
+// UpdateCurrentUserJSONRequestBody defines body for UpdateCurrentUser for application/json ContentType.
+type UpdateCurrentUserJSONRequestBody = UserUpdate
+
+// InviteDelegateJSONRequestBody defines body for InviteDelegate for application/json ContentType.
+type InviteDelegateJSONRequestBody InviteDelegateJSONBody
+
+// AcceptDelegationJSONRequestBody defines body for AcceptDelegation for application/json ContentType.
+type AcceptDelegationJSONRequestBody AcceptDelegationJSONBody
+
+// FinishPasskeyRegistrationJSONRequestBody defines body for FinishPasskeyRegistration for application/json ContentType.
+type FinishPasskeyRegistrationJSONRequestBody FinishPasskeyRegistrationJSONBody
+
+// CreateUserTokenJSONRequestBody defines body for CreateUserToken for application/json ContentType.
+type CreateUserTokenJSONRequestBody CreateUserTokenJSONBody
+
 // UserUpdateNotificationPreferencesJSONRequestBody defines body for UserUpdateNotificationPreferences for application/json ContentType.
 type UserUpdateNotificationPreferencesJSONRequestBody UserUpdateNotificationPreferencesJSONBody
 
+// SendTestNotificationJSONRequestBody defines body for SendTestNotification for application/json ContentType.
+type SendTestNotificationJSONRequestBody = TestNotificationTarget
+
 // RegisterJSONRequestBody defines body for Register for application/json ContentType.
 type RegisterJSONRequestBody RegisterJSONBody
 
@@ -357,6 +997,15 @@ type ServerInterface interface {
 	// Authenticate a user and obtain a session
 	// (POST /auth)
 	Auth(w http.ResponseWriter, r *http.Request, params AuthParams)
+	// Begin logging in with a passkey
+	// (POST /auth/passkey/begin)
+	BeginPasskeyLogin(w http.ResponseWriter, r *http.Request)
+	// Authenticate a user with a passkey and obtain a session
+	// (POST /auth/passkey/finish)
+	FinishPasskeyLogin(w http.ResponseWriter, r *http.Request, params FinishPasskeyLoginParams)
+	// Authenticate a user with a recovery code and obtain a session
+	// (POST /auth/recovery)
+	RecoveryAuth(w http.ResponseWriter, r *http.Request, params RecoveryAuthParams)
 	// List all available delivery methods
 	// (GET /delivery-methods)
 	DeliveryMethods(w http.ResponseWriter, r *http.Request)
@@ -387,15 +1036,102 @@ type ServerInterface interface {
 	// Import a CSV file of dosage history
 	// (POST /dosage/import-doses)
 	ImportDoses(w http.ResponseWriter, r *http.Request, params ImportDosesParams)
+	// Revoke one of the user's share links
+	// (DELETE /dosage/shares)
+	RevokeShareLink(w http.ResponseWriter, r *http.Request, params RevokeShareLinkParams)
+	// List the user's share links
+	// (GET /dosage/shares)
+	ShareLinks(w http.ResponseWriter, r *http.Request)
+	// Create a share link
+	// (POST /dosage/shares)
+	CreateShareLink(w http.ResponseWriter, r *http.Request)
+	// Get the access log of one of the user's share links
+	// (GET /dosage/shares/{id}/accesses)
+	ShareLinkAccesses(w http.ResponseWriter, r *http.Request, id int64)
+	// Delete the current user's account
+	// (DELETE /me)
+	DeleteCurrentUser(w http.ResponseWriter, r *http.Request)
 	// Get the current user
 	// (GET /me)
 	CurrentUser(w http.ResponseWriter, r *http.Request)
+	// Update the current user's profile
+	// (PATCH /me)
+	UpdateCurrentUser(w http.ResponseWriter, r *http.Request)
+	// End one of the current user's delegations
+	// (DELETE /me/delegations)
+	DeleteDelegation(w http.ResponseWriter, r *http.Request, params DeleteDelegationParams)
+	// List the current user's delegations
+	// (GET /me/delegations)
+	CurrentUserDelegations(w http.ResponseWriter, r *http.Request)
+	// Invite someone to access the current user's data
+	// (POST /me/delegations)
+	InviteDelegate(w http.ResponseWriter, r *http.Request)
+	// Accept an invite to access another user's data
+	// (POST /me/delegations/accept)
+	AcceptDelegation(w http.ResponseWriter, r *http.Request)
+	// Cancel the deletion of the current user's account
+	// (DELETE /me/deletion)
+	CancelCurrentUserDeletion(w http.ResponseWriter, r *http.Request)
+	// Delete one of the current user's passkeys
+	// (DELETE /me/passkeys)
+	DeleteUserPasskey(w http.ResponseWriter, r *http.Request, params DeleteUserPasskeyParams)
+	// List the current user's passkeys
+	// (GET /me/passkeys)
+	CurrentUserPasskeys(w http.ResponseWriter, r *http.Request)
+	// Begin adding a passkey to the current user
+	// (POST /me/passkeys/begin)
+	BeginPasskeyRegistration(w http.ResponseWriter, r *http.Request)
+	// Finish adding a passkey to the current user
+	// (POST /me/passkeys/finish)
+	FinishPasskeyRegistration(w http.ResponseWriter, r *http.Request)
+	// Delete the current user's recovery codes
+	// (DELETE /me/recovery-codes)
+	DeleteUserRecoveryCodes(w http.ResponseWriter, r *http.Request)
+	// Get the number of the current user's unused recovery codes
+	// (GET /me/recovery-codes)
+	CurrentUserRecoveryCodes(w http.ResponseWriter, r *http.Request)
+	// Generate new recovery codes for the current user
+	// (POST /me/recovery-codes)
+	GenerateUserRecoveryCodes(w http.ResponseWriter, r *http.Request)
+	// Rotate the current user's secret
+	// (POST /me/secret/rotate)
+	RotateUserSecret(w http.ResponseWriter, r *http.Request)
 	// Delete one of the current user's sessions
 	// (DELETE /me/sessions)
 	DeleteUserSession(w http.ResponseWriter, r *http.Request, params DeleteUserSessionParams)
 	// List the current user's sessions
 	// (GET /me/sessions)
 	CurrentUserSessions(w http.ResponseWriter, r *http.Request)
+	// Delete all of the current user's sessions except the current one
+	// (DELETE /me/sessions/all-others)
+	DeleteOtherUserSessions(w http.ResponseWriter, r *http.Request)
+	// Export the current user's whole account
+	// (GET /me/takeout)
+	ExportTakeout(w http.ResponseWriter, r *http.Request, params ExportTakeoutParams)
+	// Restore an account export into the current user's account
+	// (POST /me/takeout)
+	ImportTakeout(w http.ResponseWriter, r *http.Request, params ImportTakeoutParams)
+	// Delete one of the current user's personal access tokens
+	// (DELETE /me/tokens)
+	DeleteUserToken(w http.ResponseWriter, r *http.Request, params DeleteUserTokenParams)
+	// List the current user's personal access tokens
+	// (GET /me/tokens)
+	CurrentUserTokens(w http.ResponseWriter, r *http.Request)
+	// Create a personal access token for the current user
+	// (POST /me/tokens)
+	CreateUserToken(w http.ResponseWriter, r *http.Request)
+	// Show the page to confirm an email address
+	// (GET /notifications/email/confirm)
+	EmailConfirmPage(w http.ResponseWriter, r *http.Request, params EmailConfirmPageParams)
+	// Confirm an email address
+	// (POST /notifications/email/confirm)
+	EmailConfirm(w http.ResponseWriter, r *http.Request, params EmailConfirmParams)
+	// Show the page to unsubscribe an email address
+	// (GET /notifications/email/unsubscribe)
+	EmailUnsubscribePage(w http.ResponseWriter, r *http.Request, params EmailUnsubscribePageParams)
+	// Unsubscribe an email address
+	// (POST /notifications/email/unsubscribe)
+	EmailUnsubscribe(w http.ResponseWriter, r *http.Request, params EmailUnsubscribeParams)
 	// Get the server's supported notification methods
 	// (GET /notifications/methods)
 	SupportedNotificationMethods(w http.ResponseWriter, r *http.Request)
@@ -414,6 +1150,9 @@ type ServerInterface interface {
 	// Register a new account
 	// (POST /register)
 	Register(w http.ResponseWriter, r *http.Request)
+	// Open a share link
+	// (GET /shared/{token})
+	OpenShareLink(w http.ResponseWriter, r *http.Request, token string, params OpenShareLinkParams)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -465,11 +1204,11 @@ func (siw *ServerInterfaceWrapper) Auth(w http.ResponseWriter, r *http.Request)
 	handler.ServeHTTP(w, r)
 }
 
-// DeliveryMethods operation middleware
-func (siw *ServerInterfaceWrapper) DeliveryMethods(w http.ResponseWriter, r *http.Request) {
+// BeginPasskeyLogin operation middleware
+func (siw *ServerInterfaceWrapper) BeginPasskeyLogin(w http.ResponseWriter, r *http.Request) {
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeliveryMethods(w, r)
+		siw.Handler.BeginPasskeyLogin(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -479,17 +1218,111 @@ func (siw *ServerInterfaceWrapper) DeliveryMethods(w http.ResponseWriter, r *htt
 	handler.ServeHTTP(w, r)
 }
 
-// ClearDosage operation middleware
-func (siw *ServerInterfaceWrapper) ClearDosage(w http.ResponseWriter, r *http.Request) {
+// FinishPasskeyLogin operation middleware
+func (siw *ServerInterfaceWrapper) FinishPasskeyLogin(w http.ResponseWriter, r *http.Request) {
 
-	ctx := r.Context()
+	var err error
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+	// Parameter object where we will unmarshal all parameters from the context
+	var params FinishPasskeyLoginParams
 
-	r = r.WithContext(ctx)
+	headers := r.Header
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ClearDosage(w, r)
+	// ------------- Optional header parameter "User-Agent" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("User-Agent")]; found {
+		var UserAgent string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "User-Agent", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "User-Agent", valueList[0], &UserAgent, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "User-Agent", Err: err})
+			return
+		}
+
+		params.UserAgent = &UserAgent
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FinishPasskeyLogin(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RecoveryAuth operation middleware
+func (siw *ServerInterfaceWrapper) RecoveryAuth(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params RecoveryAuthParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "User-Agent" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("User-Agent")]; found {
+		var UserAgent string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "User-Agent", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "User-Agent", valueList[0], &UserAgent, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "User-Agent", Err: err})
+			return
+		}
+
+		params.UserAgent = &UserAgent
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RecoveryAuth(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeliveryMethods operation middleware
+func (siw *ServerInterfaceWrapper) DeliveryMethods(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeliveryMethods(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ClearDosage operation middleware
+func (siw *ServerInterfaceWrapper) ClearDosage(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"schedule:write"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ClearDosage(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -506,7 +1339,7 @@ func (siw *ServerInterfaceWrapper) Dosage(w http.ResponseWriter, r *http.Request
 
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"doses:read"})
 
 	r = r.WithContext(ctx)
 
@@ -545,7 +1378,7 @@ func (siw *ServerInterfaceWrapper) SetDosage(w http.ResponseWriter, r *http.Requ
 
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"schedule:write"})
 
 	r = r.WithContext(ctx)
 
@@ -567,7 +1400,7 @@ func (siw *ServerInterfaceWrapper) ForgetDoses(w http.ResponseWriter, r *http.Re
 
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"doses:write"})
 
 	r = r.WithContext(ctx)
 
@@ -605,7 +1438,7 @@ func (siw *ServerInterfaceWrapper) RecordDose(w http.ResponseWriter, r *http.Req
 
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"doses:write"})
 
 	r = r.WithContext(ctx)
 
@@ -636,7 +1469,7 @@ func (siw *ServerInterfaceWrapper) ForgetDose(w http.ResponseWriter, r *http.Req
 
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"doses:write"})
 
 	r = r.WithContext(ctx)
 
@@ -667,7 +1500,7 @@ func (siw *ServerInterfaceWrapper) EditDose(w http.ResponseWriter, r *http.Reque
 
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"doses:write"})
 
 	r = r.WithContext(ctx)
 
@@ -689,7 +1522,7 @@ func (siw *ServerInterfaceWrapper) ExportDoses(w http.ResponseWriter, r *http.Re
 
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"doses:read"})
 
 	r = r.WithContext(ctx)
 
@@ -755,7 +1588,7 @@ func (siw *ServerInterfaceWrapper) ImportDoses(w http.ResponseWriter, r *http.Re
 
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"doses:write"})
 
 	r = r.WithContext(ctx)
 
@@ -798,8 +1631,10 @@ func (siw *ServerInterfaceWrapper) ImportDoses(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r)
 }
 
-// CurrentUser operation middleware
-func (siw *ServerInterfaceWrapper) CurrentUser(w http.ResponseWriter, r *http.Request) {
+// RevokeShareLink operation middleware
+func (siw *ServerInterfaceWrapper) RevokeShareLink(w http.ResponseWriter, r *http.Request) {
+
+	var err error
 
 	ctx := r.Context()
 
@@ -807,8 +1642,26 @@ func (siw *ServerInterfaceWrapper) CurrentUser(w http.ResponseWriter, r *http.Re
 
 	r = r.WithContext(ctx)
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params RevokeShareLinkParams
+
+	// ------------- Required query parameter "id" -------------
+
+	if paramValue := r.URL.Query().Get("id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "id", r.URL.Query(), &params.ID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CurrentUser(w, r)
+		siw.Handler.RevokeShareLink(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -818,10 +1671,8 @@ func (siw *ServerInterfaceWrapper) CurrentUser(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r)
 }
 
-// DeleteUserSession operation middleware
-func (siw *ServerInterfaceWrapper) DeleteUserSession(w http.ResponseWriter, r *http.Request) {
-
-	var err error
+// ShareLinks operation middleware
+func (siw *ServerInterfaceWrapper) ShareLinks(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
@@ -829,26 +1680,59 @@ func (siw *ServerInterfaceWrapper) DeleteUserSession(w http.ResponseWriter, r *h
 
 	r = r.WithContext(ctx)
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params DeleteUserSessionParams
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ShareLinks(w, r)
+	}))
 
-	// ------------- Required query parameter "id" -------------
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-	if paramValue := r.URL.Query().Get("id"); paramValue != "" {
+	handler.ServeHTTP(w, r)
+}
 
-	} else {
-		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "id"})
-		return
+// CreateShareLink operation middleware
+func (siw *ServerInterfaceWrapper) CreateShareLink(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateShareLink(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
 	}
 
-	err = runtime.BindQueryParameter("form", true, true, "id", r.URL.Query(), &params.ID)
+	handler.ServeHTTP(w, r)
+}
+
+// ShareLinkAccesses operation middleware
+func (siw *ServerInterfaceWrapper) ShareLinkAccesses(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteUserSession(w, r, params)
+		siw.Handler.ShareLinkAccesses(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -858,8 +1742,8 @@ func (siw *ServerInterfaceWrapper) DeleteUserSession(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r)
 }
 
-// CurrentUserSessions operation middleware
-func (siw *ServerInterfaceWrapper) CurrentUserSessions(w http.ResponseWriter, r *http.Request) {
+// DeleteCurrentUser operation middleware
+func (siw *ServerInterfaceWrapper) DeleteCurrentUser(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
@@ -868,7 +1752,7 @@ func (siw *ServerInterfaceWrapper) CurrentUserSessions(w http.ResponseWriter, r
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CurrentUserSessions(w, r)
+		siw.Handler.DeleteCurrentUser(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -878,11 +1762,17 @@ func (siw *ServerInterfaceWrapper) CurrentUserSessions(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
-// SupportedNotificationMethods operation middleware
-func (siw *ServerInterfaceWrapper) SupportedNotificationMethods(w http.ResponseWriter, r *http.Request) {
+// CurrentUser operation middleware
+func (siw *ServerInterfaceWrapper) CurrentUser(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"profile:read"})
+
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.SupportedNotificationMethods(w, r)
+		siw.Handler.CurrentUser(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -892,8 +1782,8 @@ func (siw *ServerInterfaceWrapper) SupportedNotificationMethods(w http.ResponseW
 	handler.ServeHTTP(w, r)
 }
 
-// UserNotificationPreferences operation middleware
-func (siw *ServerInterfaceWrapper) UserNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+// UpdateCurrentUser operation middleware
+func (siw *ServerInterfaceWrapper) UpdateCurrentUser(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
@@ -902,7 +1792,7 @@ func (siw *ServerInterfaceWrapper) UserNotificationPreferences(w http.ResponseWr
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UserNotificationPreferences(w, r)
+		siw.Handler.UpdateCurrentUser(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -912,8 +1802,10 @@ func (siw *ServerInterfaceWrapper) UserNotificationPreferences(w http.ResponseWr
 	handler.ServeHTTP(w, r)
 }
 
-// UserUpdateNotificationPreferences operation middleware
-func (siw *ServerInterfaceWrapper) UserUpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+// DeleteDelegation operation middleware
+func (siw *ServerInterfaceWrapper) DeleteDelegation(w http.ResponseWriter, r *http.Request) {
+
+	var err error
 
 	ctx := r.Context()
 
@@ -921,8 +1813,26 @@ func (siw *ServerInterfaceWrapper) UserUpdateNotificationPreferences(w http.Resp
 
 	r = r.WithContext(ctx)
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteDelegationParams
+
+	// ------------- Required query parameter "id" -------------
+
+	if paramValue := r.URL.Query().Get("id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "id", r.URL.Query(), &params.ID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UserUpdateNotificationPreferences(w, r)
+		siw.Handler.DeleteDelegation(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -932,8 +1842,8 @@ func (siw *ServerInterfaceWrapper) UserUpdateNotificationPreferences(w http.Resp
 	handler.ServeHTTP(w, r)
 }
 
-// SendTestNotification operation middleware
-func (siw *ServerInterfaceWrapper) SendTestNotification(w http.ResponseWriter, r *http.Request) {
+// CurrentUserDelegations operation middleware
+func (siw *ServerInterfaceWrapper) CurrentUserDelegations(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
@@ -942,7 +1852,7 @@ func (siw *ServerInterfaceWrapper) SendTestNotification(w http.ResponseWriter, r
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.SendTestNotification(w, r)
+		siw.Handler.CurrentUserDelegations(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -952,11 +1862,17 @@ func (siw *ServerInterfaceWrapper) SendTestNotification(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
-// WebPushInfo operation middleware
-func (siw *ServerInterfaceWrapper) WebPushInfo(w http.ResponseWriter, r *http.Request) {
+// InviteDelegate operation middleware
+func (siw *ServerInterfaceWrapper) InviteDelegate(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.WebPushInfo(w, r)
+		siw.Handler.InviteDelegate(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -966,11 +1882,17 @@ func (siw *ServerInterfaceWrapper) WebPushInfo(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r)
 }
 
-// Register operation middleware
-func (siw *ServerInterfaceWrapper) Register(w http.ResponseWriter, r *http.Request) {
+// AcceptDelegation operation middleware
+func (siw *ServerInterfaceWrapper) AcceptDelegation(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.Register(w, r)
+		siw.Handler.AcceptDelegation(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -980,868 +1902,3730 @@ func (siw *ServerInterfaceWrapper) Register(w http.ResponseWriter, r *http.Reque
 	handler.ServeHTTP(w, r)
 }
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
-}
+// CancelCurrentUserDeletion operation middleware
+func (siw *ServerInterfaceWrapper) CancelCurrentUserDeletion(w http.ResponseWriter, r *http.Request) {
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+	ctx := r.Context()
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-type UnmarshalingParamError struct {
-	ParamName string
-	Err       error
-}
+	r = r.WithContext(ctx)
 
-func (e *UnmarshalingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CancelCurrentUserDeletion(w, r)
+	}))
 
-func (e *UnmarshalingParamError) Unwrap() error {
-	return e.Err
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type RequiredParamError struct {
-	ParamName string
+	handler.ServeHTTP(w, r)
 }
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
-}
-
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
-}
+// DeleteUserPasskey operation middleware
+func (siw *ServerInterfaceWrapper) DeleteUserPasskey(w http.ResponseWriter, r *http.Request) {
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
-}
+	var err error
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
-}
+	ctx := r.Context()
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-func (e *InvalidParamFormatError) Error() string {
-	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
-}
+	r = r.WithContext(ctx)
 
-func (e *InvalidParamFormatError) Unwrap() error {
-	return e.Err
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteUserPasskeyParams
 
-type TooManyValuesForParamError struct {
-	ParamName string
-	Count     int
-}
+	// ------------- Required query parameter "id" -------------
 
-func (e *TooManyValuesForParamError) Error() string {
-	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
-}
+	if paramValue := r.URL.Query().Get("id"); paramValue != "" {
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{})
-}
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "id"})
+		return
+	}
 
-// ServeMux is an abstraction of http.ServeMux.
-type ServeMux interface {
-	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
-	ServeHTTP(w http.ResponseWriter, r *http.Request)
-}
+	err = runtime.BindQueryParameter("form", true, true, "id", r.URL.Query(), &params.ID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-type StdHTTPServerOptions struct {
-	BaseURL          string
-	BaseRouter       ServeMux
-	Middlewares      []MiddlewareFunc
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteUserPasskey(w, r, params)
+	}))
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
-func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{
-		BaseRouter: m,
-	})
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{
-		BaseURL:    baseURL,
-		BaseRouter: m,
-	})
+	handler.ServeHTTP(w, r)
 }
 
-// HandlerWithOptions creates http.Handler with additional options
-func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
-	m := options.BaseRouter
+// CurrentUserPasskeys operation middleware
+func (siw *ServerInterfaceWrapper) CurrentUserPasskeys(w http.ResponseWriter, r *http.Request) {
 
-	if m == nil {
-		m = http.NewServeMux()
-	}
-	if options.ErrorHandlerFunc == nil {
-		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		}
-	}
+	ctx := r.Context()
 
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandlerFunc:   options.ErrorHandlerFunc,
-	}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-	m.HandleFunc("POST "+options.BaseURL+"/auth", wrapper.Auth)
-	m.HandleFunc("GET "+options.BaseURL+"/delivery-methods", wrapper.DeliveryMethods)
-	m.HandleFunc("DELETE "+options.BaseURL+"/dosage", wrapper.ClearDosage)
-	m.HandleFunc("GET "+options.BaseURL+"/dosage", wrapper.Dosage)
-	m.HandleFunc("PUT "+options.BaseURL+"/dosage", wrapper.SetDosage)
-	m.HandleFunc("DELETE "+options.BaseURL+"/dosage/dose", wrapper.ForgetDoses)
-	m.HandleFunc("POST "+options.BaseURL+"/dosage/dose", wrapper.RecordDose)
-	m.HandleFunc("DELETE "+options.BaseURL+"/dosage/dose/{doseTime}", wrapper.ForgetDose)
-	m.HandleFunc("PUT "+options.BaseURL+"/dosage/dose/{doseTime}", wrapper.EditDose)
-	m.HandleFunc("GET "+options.BaseURL+"/dosage/export-doses", wrapper.ExportDoses)
-	m.HandleFunc("POST "+options.BaseURL+"/dosage/import-doses", wrapper.ImportDoses)
-	m.HandleFunc("GET "+options.BaseURL+"/me", wrapper.CurrentUser)
-	m.HandleFunc("DELETE "+options.BaseURL+"/me/sessions", wrapper.DeleteUserSession)
-	m.HandleFunc("GET "+options.BaseURL+"/me/sessions", wrapper.CurrentUserSessions)
-	m.HandleFunc("GET "+options.BaseURL+"/notifications/methods", wrapper.SupportedNotificationMethods)
-	m.HandleFunc("GET "+options.BaseURL+"/notifications/preferences", wrapper.UserNotificationPreferences)
-	m.HandleFunc("PUT "+options.BaseURL+"/notifications/preferences", wrapper.UserUpdateNotificationPreferences)
-	m.HandleFunc("POST "+options.BaseURL+"/notifications/test", wrapper.SendTestNotification)
-	m.HandleFunc("GET "+options.BaseURL+"/push-info", wrapper.WebPushInfo)
-	m.HandleFunc("POST "+options.BaseURL+"/register", wrapper.Register)
+	r = r.WithContext(ctx)
 
-	return m
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CurrentUserPasskeys(w, r)
+	}))
 
-type ErrorResponseJSONResponse Error
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type RateLimitedResponseResponseHeaders struct {
-	RetryAfter int32
+	handler.ServeHTTP(w, r)
 }
-type RateLimitedResponseJSONResponse struct {
-	Body Error
 
-	Headers RateLimitedResponseResponseHeaders
-}
+// BeginPasskeyRegistration operation middleware
+func (siw *ServerInterfaceWrapper) BeginPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
 
-type AuthRequestObject struct {
-	Params AuthParams
-	Body   *AuthJSONRequestBody
-}
+	ctx := r.Context()
 
-type AuthResponseObject interface {
-	VisitAuthResponse(w http.ResponseWriter) error
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-type Auth200JSONResponse struct {
-	// Token The session token
-	Token string `json:"token"`
-}
+	r = r.WithContext(ctx)
 
-func (response Auth200JSONResponse) VisitAuthResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.BeginPasskeyRegistration(w, r)
+	}))
 
-	return json.NewEncoder(w).Encode(response)
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type AuthdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
+	handler.ServeHTTP(w, r)
 }
 
-func (response AuthdefaultJSONResponse) VisitAuthResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+// FinishPasskeyRegistration operation middleware
+func (siw *ServerInterfaceWrapper) FinishPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	ctx := r.Context()
 
-type DeliveryMethodsRequestObject struct {
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-type DeliveryMethodsResponseObject interface {
-	VisitDeliveryMethodsResponse(w http.ResponseWriter) error
-}
+	r = r.WithContext(ctx)
 
-type DeliveryMethods200JSONResponse []DeliveryMethod
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FinishPasskeyRegistration(w, r)
+	}))
 
-func (response DeliveryMethods200JSONResponse) VisitDeliveryMethodsResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-	return json.NewEncoder(w).Encode(response)
+	handler.ServeHTTP(w, r)
 }
 
-type DeliveryMethodsdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+// DeleteUserRecoveryCodes operation middleware
+func (siw *ServerInterfaceWrapper) DeleteUserRecoveryCodes(w http.ResponseWriter, r *http.Request) {
 
-func (response DeliveryMethodsdefaultJSONResponse) VisitDeliveryMethodsResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+	ctx := r.Context()
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-type ClearDosageRequestObject struct {
-}
+	r = r.WithContext(ctx)
 
-type ClearDosageResponseObject interface {
-	VisitClearDosageResponse(w http.ResponseWriter) error
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteUserRecoveryCodes(w, r)
+	}))
 
-type ClearDosage204Response struct {
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-func (response ClearDosage204Response) VisitClearDosageResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
-	return nil
+	handler.ServeHTTP(w, r)
 }
 
-type ClearDosagedefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+// CurrentUserRecoveryCodes operation middleware
+func (siw *ServerInterfaceWrapper) CurrentUserRecoveryCodes(w http.ResponseWriter, r *http.Request) {
 
-func (response ClearDosagedefaultJSONResponse) VisitClearDosageResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+	ctx := r.Context()
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-type DosageRequestObject struct {
-	Params DosageParams
-}
+	r = r.WithContext(ctx)
 
-type DosageResponseObject interface {
-	VisitDosageResponse(w http.ResponseWriter) error
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CurrentUserRecoveryCodes(w, r)
+	}))
 
-type Dosage200JSONResponse struct {
-	// Dosage The user's current dosage schedule. This is null if the user has no dosage set.
-	Dosage *Dosage `json:"dosage,omitempty"`
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-	// History The user's dosage history within the requested time range. If either historyStart or historyEnd are not provided, this will be null.
-	History *DosageHistory `json:"history,omitempty"`
+	handler.ServeHTTP(w, r)
 }
 
-func (response Dosage200JSONResponse) VisitDosageResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+// GenerateUserRecoveryCodes operation middleware
+func (siw *ServerInterfaceWrapper) GenerateUserRecoveryCodes(w http.ResponseWriter, r *http.Request) {
 
-	return json.NewEncoder(w).Encode(response)
-}
+	ctx := r.Context()
 
-type SetDosageRequestObject struct {
-	Body *SetDosageJSONRequestBody
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-type SetDosageResponseObject interface {
-	VisitSetDosageResponse(w http.ResponseWriter) error
-}
+	r = r.WithContext(ctx)
 
-type SetDosage204Response struct {
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GenerateUserRecoveryCodes(w, r)
+	}))
 
-func (response SetDosage204Response) VisitSetDosageResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
-	return nil
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type SetDosagedefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
+	handler.ServeHTTP(w, r)
 }
 
-func (response SetDosagedefaultJSONResponse) VisitSetDosageResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+// RotateUserSecret operation middleware
+func (siw *ServerInterfaceWrapper) RotateUserSecret(w http.ResponseWriter, r *http.Request) {
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	ctx := r.Context()
 
-type ForgetDosesRequestObject struct {
-	Params ForgetDosesParams
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-type ForgetDosesResponseObject interface {
-	VisitForgetDosesResponse(w http.ResponseWriter) error
-}
+	r = r.WithContext(ctx)
 
-type ForgetDoses204Response struct {
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RotateUserSecret(w, r)
+	}))
 
-func (response ForgetDoses204Response) VisitForgetDosesResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
-	return nil
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type ForgetDosesdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
+	handler.ServeHTTP(w, r)
 }
 
-func (response ForgetDosesdefaultJSONResponse) VisitForgetDosesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
-
-	return json.NewEncoder(w).Encode(response.Body)
-}
+// DeleteUserSession operation middleware
+func (siw *ServerInterfaceWrapper) DeleteUserSession(w http.ResponseWriter, r *http.Request) {
 
-type RecordDoseRequestObject struct {
-}
+	var err error
 
-type RecordDoseResponseObject interface {
-	VisitRecordDoseResponse(w http.ResponseWriter) error
-}
+	ctx := r.Context()
 
-type RecordDose200JSONResponse Dose
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-func (response RecordDose200JSONResponse) VisitRecordDoseResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	r = r.WithContext(ctx)
 
-	return json.NewEncoder(w).Encode(response)
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteUserSessionParams
 
-type ForgetDoseRequestObject struct {
-	DoseTime time.Time `json:"doseTime"`
-}
+	// ------------- Required query parameter "id" -------------
 
-type ForgetDoseResponseObject interface {
-	VisitForgetDoseResponse(w http.ResponseWriter) error
-}
+	if paramValue := r.URL.Query().Get("id"); paramValue != "" {
 
-type ForgetDose204Response struct {
-}
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "id"})
+		return
+	}
 
-func (response ForgetDose204Response) VisitForgetDoseResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
-	return nil
-}
+	err = runtime.BindQueryParameter("form", true, true, "id", r.URL.Query(), &params.ID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-type ForgetDosedefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteUserSession(w, r, params)
+	}))
 
-func (response ForgetDosedefaultJSONResponse) VisitForgetDoseResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-	return json.NewEncoder(w).Encode(response.Body)
+	handler.ServeHTTP(w, r)
 }
 
-type EditDoseRequestObject struct {
-	DoseTime time.Time `json:"doseTime"`
-	Body     *EditDoseJSONRequestBody
-}
+// CurrentUserSessions operation middleware
+func (siw *ServerInterfaceWrapper) CurrentUserSessions(w http.ResponseWriter, r *http.Request) {
 
-type EditDoseResponseObject interface {
-	VisitEditDoseResponse(w http.ResponseWriter) error
-}
+	ctx := r.Context()
 
-type EditDose204Response struct {
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-func (response EditDose204Response) VisitEditDoseResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
-	return nil
-}
+	r = r.WithContext(ctx)
 
-type EditDosedefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CurrentUserSessions(w, r)
+	}))
 
-func (response EditDosedefaultJSONResponse) VisitEditDoseResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-	return json.NewEncoder(w).Encode(response.Body)
+	handler.ServeHTTP(w, r)
 }
 
-type ExportDosesRequestObject struct {
-	Params ExportDosesParams
-}
+// DeleteOtherUserSessions operation middleware
+func (siw *ServerInterfaceWrapper) DeleteOtherUserSessions(w http.ResponseWriter, r *http.Request) {
 
-type ExportDosesResponseObject interface {
-	VisitExportDosesResponse(w http.ResponseWriter) error
-}
+	ctx := r.Context()
 
-type ExportDoses200ResponseHeaders struct {
-	ContentDisposition string
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-type ExportDoses200JSONResponse struct {
-	Body    DosageHistory
-	Headers ExportDoses200ResponseHeaders
-}
+	r = r.WithContext(ctx)
 
-func (response ExportDoses200JSONResponse) VisitExportDosesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
-	w.WriteHeader(200)
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteOtherUserSessions(w, r)
+	}))
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type ExportDoses200TextCsvResponse struct {
-	Body          io.Reader
-	Headers       ExportDoses200ResponseHeaders
-	ContentLength int64
+	handler.ServeHTTP(w, r)
 }
 
-func (response ExportDoses200TextCsvResponse) VisitExportDosesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "text/csv")
-	if response.ContentLength != 0 {
-		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
-	}
-	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
-	w.WriteHeader(200)
+// ExportTakeout operation middleware
+func (siw *ServerInterfaceWrapper) ExportTakeout(w http.ResponseWriter, r *http.Request) {
 
-	if closer, ok := response.Body.(io.ReadCloser); ok {
-		defer closer.Close()
-	}
-	_, err := io.Copy(w, response.Body)
-	return err
-}
+	var err error
 
-type ExportDoses429JSONResponse struct {
-	RateLimitedResponseJSONResponse
-}
+	ctx := r.Context()
 
-func (response ExportDoses429JSONResponse) VisitExportDosesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
-	w.WriteHeader(429)
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	r = r.WithContext(ctx)
 
-type ExportDosesdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportTakeoutParams
 
-func (response ExportDosesdefaultJSONResponse) VisitExportDosesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+	// ------------- Optional query parameter "includeCredentials" -------------
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	err = runtime.BindQueryParameter("form", true, false, "includeCredentials", r.URL.Query(), &params.IncludeCredentials)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "includeCredentials", Err: err})
+		return
+	}
 
-type ImportDosesRequestObject struct {
-	Params   ImportDosesParams
-	JSONBody *ImportDosesJSONRequestBody
-	Body     io.Reader
-}
+	headers := r.Header
 
-type ImportDosesResponseObject interface {
-	VisitImportDosesResponse(w http.ResponseWriter) error
-}
+	// ------------- Required header parameter "Accept" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Accept")]; found {
+		var Accept ExportTakeoutParamsAccept
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Accept", Count: n})
+			return
+		}
 
-type ImportDoses200JSONResponse struct {
-	Error *Error `json:"error,omitempty"`
+		err = runtime.BindStyledParameterWithOptions("simple", "Accept", valueList[0], &Accept, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Accept", Err: err})
+			return
+		}
 
-	// Records The number of records in the file.
-	Records int `json:"records"`
+		params.Accept = Accept
 
-	// Succeeded The number of records actually imported successfully. This is not equal to #records if there were errors or duplicate entries.
-	Succeeded int `json:"succeeded"`
-}
+	} else {
+		err := fmt.Errorf("Header parameter Accept is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "Accept", Err: err})
+		return
+	}
 
-func (response ImportDoses200JSONResponse) VisitImportDosesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportTakeout(w, r, params)
+	}))
 
-	return json.NewEncoder(w).Encode(response)
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type CurrentUserRequestObject struct {
+	handler.ServeHTTP(w, r)
 }
 
-type CurrentUserResponseObject interface {
-	VisitCurrentUserResponse(w http.ResponseWriter) error
-}
+// ImportTakeout operation middleware
+func (siw *ServerInterfaceWrapper) ImportTakeout(w http.ResponseWriter, r *http.Request) {
 
-type CurrentUser200JSONResponse struct {
-	// Name The user's name
-	Name string `json:"name"`
+	var err error
 
-	// Locale A locale identifier.
-	Locale Locale `json:"locale"`
+	ctx := r.Context()
 
-	// Secret A secret and unique user identifier. This secret is generated once and never changes. It is used to both authenticate and identify a user, so it should be kept secret.
-	Secret UserSecret `json:"secret"`
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-func (response CurrentUser200JSONResponse) VisitCurrentUserResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	r = r.WithContext(ctx)
 
-	return json.NewEncoder(w).Encode(response)
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImportTakeoutParams
 
-type CurrentUserdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+	headers := r.Header
 
-func (response CurrentUserdefaultJSONResponse) VisitCurrentUserResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+	// ------------- Required header parameter "Content-Type" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Content-Type")]; found {
+		var ContentType ImportTakeoutParamsContentType
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Content-Type", Count: n})
+			return
+		}
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+		err = runtime.BindStyledParameterWithOptions("simple", "Content-Type", valueList[0], &ContentType, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Content-Type", Err: err})
+			return
+		}
 
-type DeleteUserSessionRequestObject struct {
-	Params DeleteUserSessionParams
-}
+		params.ContentType = ContentType
 
-type DeleteUserSessionResponseObject interface {
-	VisitDeleteUserSessionResponse(w http.ResponseWriter) error
-}
+	} else {
+		err := fmt.Errorf("Header parameter Content-Type is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "Content-Type", Err: err})
+		return
+	}
 
-type DeleteUserSession204Response struct {
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ImportTakeout(w, r, params)
+	}))
 
-func (response DeleteUserSession204Response) VisitDeleteUserSessionResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
-	return nil
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type DeleteUserSessiondefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
+	handler.ServeHTTP(w, r)
 }
 
-func (response DeleteUserSessiondefaultJSONResponse) VisitDeleteUserSessionResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+// DeleteUserToken operation middleware
+func (siw *ServerInterfaceWrapper) DeleteUserToken(w http.ResponseWriter, r *http.Request) {
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	var err error
 
-type CurrentUserSessionsRequestObject struct {
-}
+	ctx := r.Context()
 
-type CurrentUserSessionsResponseObject interface {
-	VisitCurrentUserSessionsResponse(w http.ResponseWriter) error
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-type CurrentUserSessions200JSONResponse []Session
+	r = r.WithContext(ctx)
 
-func (response CurrentUserSessions200JSONResponse) VisitCurrentUserSessionsResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteUserTokenParams
 
-	return json.NewEncoder(w).Encode(response)
-}
+	// ------------- Required query parameter "id" -------------
 
-type CurrentUserSessionsdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+	if paramValue := r.URL.Query().Get("id"); paramValue != "" {
 
-func (response CurrentUserSessionsdefaultJSONResponse) VisitCurrentUserSessionsResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "id"})
+		return
+	}
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	err = runtime.BindQueryParameter("form", true, true, "id", r.URL.Query(), &params.ID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-type SupportedNotificationMethodsRequestObject struct {
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteUserToken(w, r, params)
+	}))
 
-type SupportedNotificationMethodsResponseObject interface {
-	VisitSupportedNotificationMethodsResponse(w http.ResponseWriter) error
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
 }
 
-type SupportedNotificationMethods200JSONResponse NotificationMethodSupports
+// CurrentUserTokens operation middleware
+func (siw *ServerInterfaceWrapper) CurrentUserTokens(w http.ResponseWriter, r *http.Request) {
 
-func (response SupportedNotificationMethods200JSONResponse) VisitSupportedNotificationMethodsResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	ctx := r.Context()
 
-	return json.NewEncoder(w).Encode(response)
-}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-type SupportedNotificationMethodsdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+	r = r.WithContext(ctx)
 
-func (response SupportedNotificationMethodsdefaultJSONResponse) VisitSupportedNotificationMethodsResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CurrentUserTokens(w, r)
+	}))
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type UserNotificationPreferencesRequestObject struct {
+	handler.ServeHTTP(w, r)
 }
 
-type UserNotificationPreferencesResponseObject interface {
-	VisitUserNotificationPreferencesResponse(w http.ResponseWriter) error
-}
+// CreateUserToken operation middleware
+func (siw *ServerInterfaceWrapper) CreateUserToken(w http.ResponseWriter, r *http.Request) {
 
-type UserNotificationPreferences200JSONResponse NotificationPreferences
+	ctx := r.Context()
 
-func (response UserNotificationPreferences200JSONResponse) VisitUserNotificationPreferencesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-	return json.NewEncoder(w).Encode(response)
-}
+	r = r.WithContext(ctx)
 
-type UserNotificationPreferencesdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateUserToken(w, r)
+	}))
 
-func (response UserNotificationPreferencesdefaultJSONResponse) VisitUserNotificationPreferencesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-	return json.NewEncoder(w).Encode(response.Body)
+	handler.ServeHTTP(w, r)
 }
 
-type UserUpdateNotificationPreferencesRequestObject struct {
-	Body *UserUpdateNotificationPreferencesJSONRequestBody
-}
+// EmailConfirmPage operation middleware
+func (siw *ServerInterfaceWrapper) EmailConfirmPage(w http.ResponseWriter, r *http.Request) {
 
-type UserUpdateNotificationPreferencesResponseObject interface {
-	VisitUserUpdateNotificationPreferencesResponse(w http.ResponseWriter) error
-}
+	var err error
 
-type UserUpdateNotificationPreferences204Response struct {
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params EmailConfirmPageParams
 
-func (response UserUpdateNotificationPreferences204Response) VisitUserUpdateNotificationPreferencesResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
-	return nil
-}
+	// ------------- Required query parameter "token" -------------
 
-type UserUpdateNotificationPreferencesdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+	if paramValue := r.URL.Query().Get("token"); paramValue != "" {
 
-func (response UserUpdateNotificationPreferencesdefaultJSONResponse) VisitUserUpdateNotificationPreferencesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "token"})
+		return
+	}
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	err = runtime.BindQueryParameter("form", true, true, "token", r.URL.Query(), &params.Token)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+		return
+	}
 
-type SendTestNotificationRequestObject struct {
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.EmailConfirmPage(w, r, params)
+	}))
 
-type SendTestNotificationResponseObject interface {
-	VisitSendTestNotificationResponse(w http.ResponseWriter) error
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type SendTestNotification204Response struct {
+	handler.ServeHTTP(w, r)
 }
 
-func (response SendTestNotification204Response) VisitSendTestNotificationResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
-	return nil
-}
+// EmailConfirm operation middleware
+func (siw *ServerInterfaceWrapper) EmailConfirm(w http.ResponseWriter, r *http.Request) {
 
-type SendTestNotificationdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+	var err error
 
-func (response SendTestNotificationdefaultJSONResponse) VisitSendTestNotificationResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+	// Parameter object where we will unmarshal all parameters from the context
+	var params EmailConfirmParams
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	// ------------- Required query parameter "token" -------------
 
-type WebPushInfoRequestObject struct {
-}
+	if paramValue := r.URL.Query().Get("token"); paramValue != "" {
 
-type WebPushInfoResponseObject interface {
-	VisitWebPushInfoResponse(w http.ResponseWriter) error
-}
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "token"})
+		return
+	}
 
-type WebPushInfo200JSONResponse PushInfo
+	err = runtime.BindQueryParameter("form", true, true, "token", r.URL.Query(), &params.Token)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+		return
+	}
 
-func (response WebPushInfo200JSONResponse) VisitWebPushInfoResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.EmailConfirm(w, r, params)
+	}))
 
-	return json.NewEncoder(w).Encode(response)
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type WebPushInfodefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
+	handler.ServeHTTP(w, r)
 }
 
-func (response WebPushInfodefaultJSONResponse) VisitWebPushInfoResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+// EmailUnsubscribePage operation middleware
+func (siw *ServerInterfaceWrapper) EmailUnsubscribePage(w http.ResponseWriter, r *http.Request) {
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params EmailUnsubscribePageParams
+
+	// ------------- Required query parameter "token" -------------
+
+	if paramValue := r.URL.Query().Get("token"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "token"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "token", r.URL.Query(), &params.Token)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.EmailUnsubscribePage(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// EmailUnsubscribe operation middleware
+func (siw *ServerInterfaceWrapper) EmailUnsubscribe(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params EmailUnsubscribeParams
+
+	// ------------- Required query parameter "token" -------------
+
+	if paramValue := r.URL.Query().Get("token"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "token"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "token", r.URL.Query(), &params.Token)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.EmailUnsubscribe(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SupportedNotificationMethods operation middleware
+func (siw *ServerInterfaceWrapper) SupportedNotificationMethods(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SupportedNotificationMethods(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UserNotificationPreferences operation middleware
+func (siw *ServerInterfaceWrapper) UserNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"notifications:read"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UserNotificationPreferences(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UserUpdateNotificationPreferences operation middleware
+func (siw *ServerInterfaceWrapper) UserUpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"notifications:write"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UserUpdateNotificationPreferences(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SendTestNotification operation middleware
+func (siw *ServerInterfaceWrapper) SendTestNotification(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"notifications:write"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SendTestNotification(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// WebPushInfo operation middleware
+func (siw *ServerInterfaceWrapper) WebPushInfo(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.WebPushInfo(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// Register operation middleware
+func (siw *ServerInterfaceWrapper) Register(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Register(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// OpenShareLink operation middleware
+func (siw *ServerInterfaceWrapper) OpenShareLink(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "token" -------------
+	var token string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "token", r.PathValue("token"), &token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params OpenShareLinkParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-Share-Pin" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Share-Pin")]; found {
+		var XSharePin string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Share-Pin", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Share-Pin", valueList[0], &XSharePin, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Share-Pin", Err: err})
+			return
+		}
+
+		params.XSharePin = &XSharePin
+
+	}
+
+	// ------------- Optional header parameter "User-Agent" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("User-Agent")]; found {
+		var UserAgent string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "User-Agent", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "User-Agent", valueList[0], &UserAgent, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "User-Agent", Err: err})
+			return
+		}
+
+		params.UserAgent = &UserAgent
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.OpenShareLink(w, r, token, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("POST "+options.BaseURL+"/auth", wrapper.Auth)
+	m.HandleFunc("POST "+options.BaseURL+"/auth/passkey/begin", wrapper.BeginPasskeyLogin)
+	m.HandleFunc("POST "+options.BaseURL+"/auth/passkey/finish", wrapper.FinishPasskeyLogin)
+	m.HandleFunc("POST "+options.BaseURL+"/auth/recovery", wrapper.RecoveryAuth)
+	m.HandleFunc("GET "+options.BaseURL+"/delivery-methods", wrapper.DeliveryMethods)
+	m.HandleFunc("DELETE "+options.BaseURL+"/dosage", wrapper.ClearDosage)
+	m.HandleFunc("GET "+options.BaseURL+"/dosage", wrapper.Dosage)
+	m.HandleFunc("PUT "+options.BaseURL+"/dosage", wrapper.SetDosage)
+	m.HandleFunc("DELETE "+options.BaseURL+"/dosage/dose", wrapper.ForgetDoses)
+	m.HandleFunc("POST "+options.BaseURL+"/dosage/dose", wrapper.RecordDose)
+	m.HandleFunc("DELETE "+options.BaseURL+"/dosage/dose/{doseTime}", wrapper.ForgetDose)
+	m.HandleFunc("PUT "+options.BaseURL+"/dosage/dose/{doseTime}", wrapper.EditDose)
+	m.HandleFunc("GET "+options.BaseURL+"/dosage/export-doses", wrapper.ExportDoses)
+	m.HandleFunc("POST "+options.BaseURL+"/dosage/import-doses", wrapper.ImportDoses)
+	m.HandleFunc("DELETE "+options.BaseURL+"/dosage/shares", wrapper.RevokeShareLink)
+	m.HandleFunc("GET "+options.BaseURL+"/dosage/shares", wrapper.ShareLinks)
+	m.HandleFunc("POST "+options.BaseURL+"/dosage/shares", wrapper.CreateShareLink)
+	m.HandleFunc("GET "+options.BaseURL+"/dosage/shares/{id}/accesses", wrapper.ShareLinkAccesses)
+	m.HandleFunc("DELETE "+options.BaseURL+"/me", wrapper.DeleteCurrentUser)
+	m.HandleFunc("GET "+options.BaseURL+"/me", wrapper.CurrentUser)
+	m.HandleFunc("PATCH "+options.BaseURL+"/me", wrapper.UpdateCurrentUser)
+	m.HandleFunc("DELETE "+options.BaseURL+"/me/delegations", wrapper.DeleteDelegation)
+	m.HandleFunc("GET "+options.BaseURL+"/me/delegations", wrapper.CurrentUserDelegations)
+	m.HandleFunc("POST "+options.BaseURL+"/me/delegations", wrapper.InviteDelegate)
+	m.HandleFunc("POST "+options.BaseURL+"/me/delegations/accept", wrapper.AcceptDelegation)
+	m.HandleFunc("DELETE "+options.BaseURL+"/me/deletion", wrapper.CancelCurrentUserDeletion)
+	m.HandleFunc("DELETE "+options.BaseURL+"/me/passkeys", wrapper.DeleteUserPasskey)
+	m.HandleFunc("GET "+options.BaseURL+"/me/passkeys", wrapper.CurrentUserPasskeys)
+	m.HandleFunc("POST "+options.BaseURL+"/me/passkeys/begin", wrapper.BeginPasskeyRegistration)
+	m.HandleFunc("POST "+options.BaseURL+"/me/passkeys/finish", wrapper.FinishPasskeyRegistration)
+	m.HandleFunc("DELETE "+options.BaseURL+"/me/recovery-codes", wrapper.DeleteUserRecoveryCodes)
+	m.HandleFunc("GET "+options.BaseURL+"/me/recovery-codes", wrapper.CurrentUserRecoveryCodes)
+	m.HandleFunc("POST "+options.BaseURL+"/me/recovery-codes", wrapper.GenerateUserRecoveryCodes)
+	m.HandleFunc("POST "+options.BaseURL+"/me/secret/rotate", wrapper.RotateUserSecret)
+	m.HandleFunc("DELETE "+options.BaseURL+"/me/sessions", wrapper.DeleteUserSession)
+	m.HandleFunc("GET "+options.BaseURL+"/me/sessions", wrapper.CurrentUserSessions)
+	m.HandleFunc("DELETE "+options.BaseURL+"/me/sessions/all-others", wrapper.DeleteOtherUserSessions)
+	m.HandleFunc("GET "+options.BaseURL+"/me/takeout", wrapper.ExportTakeout)
+	m.HandleFunc("POST "+options.BaseURL+"/me/takeout", wrapper.ImportTakeout)
+	m.HandleFunc("DELETE "+options.BaseURL+"/me/tokens", wrapper.DeleteUserToken)
+	m.HandleFunc("GET "+options.BaseURL+"/me/tokens", wrapper.CurrentUserTokens)
+	m.HandleFunc("POST "+options.BaseURL+"/me/tokens", wrapper.CreateUserToken)
+	m.HandleFunc("GET "+options.BaseURL+"/notifications/email/confirm", wrapper.EmailConfirmPage)
+	m.HandleFunc("POST "+options.BaseURL+"/notifications/email/confirm", wrapper.EmailConfirm)
+	m.HandleFunc("GET "+options.BaseURL+"/notifications/email/unsubscribe", wrapper.EmailUnsubscribePage)
+	m.HandleFunc("POST "+options.BaseURL+"/notifications/email/unsubscribe", wrapper.EmailUnsubscribe)
+	m.HandleFunc("GET "+options.BaseURL+"/notifications/methods", wrapper.SupportedNotificationMethods)
+	m.HandleFunc("GET "+options.BaseURL+"/notifications/preferences", wrapper.UserNotificationPreferences)
+	m.HandleFunc("PUT "+options.BaseURL+"/notifications/preferences", wrapper.UserUpdateNotificationPreferences)
+	m.HandleFunc("POST "+options.BaseURL+"/notifications/test", wrapper.SendTestNotification)
+	m.HandleFunc("GET "+options.BaseURL+"/push-info", wrapper.WebPushInfo)
+	m.HandleFunc("POST "+options.BaseURL+"/register", wrapper.Register)
+	m.HandleFunc("GET "+options.BaseURL+"/shared/{token}", wrapper.OpenShareLink)
+
+	return m
+}
+
+type ErrorResponseJSONResponse Error
+
+type RateLimitedResponseResponseHeaders struct {
+	RetryAfter int32
+}
+type RateLimitedResponseJSONResponse struct {
+	Body Error
+
+	Headers RateLimitedResponseResponseHeaders
+}
+
+type AuthRequestObject struct {
+	Params AuthParams
+	Body   *AuthJSONRequestBody
+}
+
+type AuthResponseObject interface {
+	VisitAuthResponse(w http.ResponseWriter) error
+}
+
+type Auth200JSONResponse struct {
+	// Token The session token
+	Token string `json:"token"`
+}
+
+func (response Auth200JSONResponse) VisitAuthResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AuthdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response AuthdefaultJSONResponse) VisitAuthResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type BeginPasskeyLoginRequestObject struct {
+}
+
+type BeginPasskeyLoginResponseObject interface {
+	VisitBeginPasskeyLoginResponse(w http.ResponseWriter) error
+}
+
+type BeginPasskeyLogin200JSONResponse PasskeyChallenge
+
+func (response BeginPasskeyLogin200JSONResponse) VisitBeginPasskeyLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type BeginPasskeyLogindefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response BeginPasskeyLogindefaultJSONResponse) VisitBeginPasskeyLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type FinishPasskeyLoginRequestObject struct {
+	Params FinishPasskeyLoginParams
+	Body   *FinishPasskeyLoginJSONRequestBody
+}
+
+type FinishPasskeyLoginResponseObject interface {
+	VisitFinishPasskeyLoginResponse(w http.ResponseWriter) error
+}
+
+type FinishPasskeyLogin200JSONResponse struct {
+	// Token The session token
+	Token string `json:"token"`
+}
+
+func (response FinishPasskeyLogin200JSONResponse) VisitFinishPasskeyLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type FinishPasskeyLogindefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response FinishPasskeyLogindefaultJSONResponse) VisitFinishPasskeyLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RecoveryAuthRequestObject struct {
+	Params RecoveryAuthParams
+	Body   *RecoveryAuthJSONRequestBody
+}
+
+type RecoveryAuthResponseObject interface {
+	VisitRecoveryAuthResponse(w http.ResponseWriter) error
+}
+
+type RecoveryAuth200JSONResponse struct {
+	// Token The session token
+	Token string `json:"token"`
+}
+
+func (response RecoveryAuth200JSONResponse) VisitRecoveryAuthResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RecoveryAuthdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response RecoveryAuthdefaultJSONResponse) VisitRecoveryAuthResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeliveryMethodsRequestObject struct {
+}
+
+type DeliveryMethodsResponseObject interface {
+	VisitDeliveryMethodsResponse(w http.ResponseWriter) error
+}
+
+type DeliveryMethods200JSONResponse []DeliveryMethod
+
+func (response DeliveryMethods200JSONResponse) VisitDeliveryMethodsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeliveryMethodsdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response DeliveryMethodsdefaultJSONResponse) VisitDeliveryMethodsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ClearDosageRequestObject struct {
+}
+
+type ClearDosageResponseObject interface {
+	VisitClearDosageResponse(w http.ResponseWriter) error
+}
+
+type ClearDosage204Response struct {
+}
+
+func (response ClearDosage204Response) VisitClearDosageResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type ClearDosagedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ClearDosagedefaultJSONResponse) VisitClearDosageResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DosageRequestObject struct {
+	Params DosageParams
+}
+
+type DosageResponseObject interface {
+	VisitDosageResponse(w http.ResponseWriter) error
+}
+
+type Dosage200JSONResponse struct {
+	// Dosage The user's current dosage schedule. This is null if the user has no dosage set.
+	Dosage *Dosage `json:"dosage,omitempty"`
+
+	// History The user's dosage history within the requested time range. If either historyStart or historyEnd are not provided, this will be null.
+	History *DosageHistory `json:"history,omitempty"`
+}
+
+func (response Dosage200JSONResponse) VisitDosageResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SetDosageRequestObject struct {
+	Body *SetDosageJSONRequestBody
+}
+
+type SetDosageResponseObject interface {
+	VisitSetDosageResponse(w http.ResponseWriter) error
+}
+
+type SetDosage204Response struct {
+}
+
+func (response SetDosage204Response) VisitSetDosageResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type SetDosagedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response SetDosagedefaultJSONResponse) VisitSetDosageResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ForgetDosesRequestObject struct {
+	Params ForgetDosesParams
+}
+
+type ForgetDosesResponseObject interface {
+	VisitForgetDosesResponse(w http.ResponseWriter) error
+}
+
+type ForgetDoses204Response struct {
+}
+
+func (response ForgetDoses204Response) VisitForgetDosesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type ForgetDosesdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ForgetDosesdefaultJSONResponse) VisitForgetDosesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RecordDoseRequestObject struct {
+}
+
+type RecordDoseResponseObject interface {
+	VisitRecordDoseResponse(w http.ResponseWriter) error
+}
+
+type RecordDose200JSONResponse Dose
+
+func (response RecordDose200JSONResponse) VisitRecordDoseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ForgetDoseRequestObject struct {
+	DoseTime time.Time `json:"doseTime"`
+}
+
+type ForgetDoseResponseObject interface {
+	VisitForgetDoseResponse(w http.ResponseWriter) error
+}
+
+type ForgetDose204Response struct {
+}
+
+func (response ForgetDose204Response) VisitForgetDoseResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type ForgetDosedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ForgetDosedefaultJSONResponse) VisitForgetDoseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type EditDoseRequestObject struct {
+	DoseTime time.Time `json:"doseTime"`
+	Body     *EditDoseJSONRequestBody
+}
+
+type EditDoseResponseObject interface {
+	VisitEditDoseResponse(w http.ResponseWriter) error
+}
+
+type EditDose204Response struct {
+}
+
+func (response EditDose204Response) VisitEditDoseResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type EditDosedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response EditDosedefaultJSONResponse) VisitEditDoseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExportDosesRequestObject struct {
+	Params ExportDosesParams
+}
+
+type ExportDosesResponseObject interface {
+	VisitExportDosesResponse(w http.ResponseWriter) error
+}
+
+type ExportDoses200ResponseHeaders struct {
+	ContentDisposition string
+}
+
+type ExportDoses200JSONResponse struct {
+	Body    DosageHistory
+	Headers ExportDoses200ResponseHeaders
+}
+
+func (response ExportDoses200JSONResponse) VisitExportDosesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExportDoses200TextCsvResponse struct {
+	Body          io.Reader
+	Headers       ExportDoses200ResponseHeaders
+	ContentLength int64
+}
+
+func (response ExportDoses200TextCsvResponse) VisitExportDosesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/csv")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportDoses429JSONResponse struct {
+	RateLimitedResponseJSONResponse
+}
+
+func (response ExportDoses429JSONResponse) VisitExportDosesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExportDosesdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ExportDosesdefaultJSONResponse) VisitExportDosesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ImportDosesRequestObject struct {
+	Params   ImportDosesParams
+	JSONBody *ImportDosesJSONRequestBody
+	Body     io.Reader
+}
+
+type ImportDosesResponseObject interface {
+	VisitImportDosesResponse(w http.ResponseWriter) error
+}
+
+type ImportDoses200JSONResponse struct {
+	Error *Error `json:"error,omitempty"`
+
+	// Records The number of records in the file.
+	Records int `json:"records"`
+
+	// Succeeded The number of records actually imported successfully. This is not equal to #records if there were errors or duplicate entries.
+	Succeeded int `json:"succeeded"`
+}
+
+func (response ImportDoses200JSONResponse) VisitImportDosesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeShareLinkRequestObject struct {
+	Params RevokeShareLinkParams
+}
+
+type RevokeShareLinkResponseObject interface {
+	VisitRevokeShareLinkResponse(w http.ResponseWriter) error
+}
+
+type RevokeShareLink204Response struct {
+}
+
+func (response RevokeShareLink204Response) VisitRevokeShareLinkResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type RevokeShareLinkdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response RevokeShareLinkdefaultJSONResponse) VisitRevokeShareLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ShareLinksRequestObject struct {
+}
+
+type ShareLinksResponseObject interface {
+	VisitShareLinksResponse(w http.ResponseWriter) error
+}
+
+type ShareLinks200JSONResponse []ShareLink
+
+func (response ShareLinks200JSONResponse) VisitShareLinksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ShareLinksdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ShareLinksdefaultJSONResponse) VisitShareLinksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateShareLinkRequestObject struct {
+	Body *CreateShareLinkJSONRequestBody
+}
+
+type CreateShareLinkResponseObject interface {
+	VisitCreateShareLinkResponse(w http.ResponseWriter) error
+}
+
+type CreateShareLink200JSONResponse struct {
+	// ID The share link identifier
+	ID int64 `json:"id"`
+
+	// Name The name of the share link
+	Name string `json:"name"`
+
+	// Start The start of the shared time range
+	Start time.Time `json:"start"`
+
+	// End The end of the shared time range
+	End time.Time `json:"end"`
+
+	// Fields The kinds of data that are shared
+	Fields []ShareField `json:"fields"`
+
+	// HasPIN Whether a PIN is needed to open the link
+	HasPIN bool `json:"hasPIN"`
+
+	// CreatedAt The time the share link was created
+	CreatedAt time.Time `json:"createdAt"`
+
+	// ExpiresAt The time the share link expires
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// RevokedAt The time the share link was revoked, or null if it was not
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+
+	// Token The token in the share link's URL. The server only stores a hash of it, so it is only ever returned here.
+	Token string `json:"token"`
+}
+
+func (response CreateShareLink200JSONResponse) VisitCreateShareLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateShareLinkdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response CreateShareLinkdefaultJSONResponse) VisitCreateShareLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ShareLinkAccessesRequestObject struct {
+	ID int64 `json:"id"`
+}
+
+type ShareLinkAccessesResponseObject interface {
+	VisitShareLinkAccessesResponse(w http.ResponseWriter) error
+}
+
+type ShareLinkAccesses200JSONResponse []ShareLinkAccess
+
+func (response ShareLinkAccesses200JSONResponse) VisitShareLinkAccessesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ShareLinkAccessesdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ShareLinkAccessesdefaultJSONResponse) VisitShareLinkAccessesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteCurrentUserRequestObject struct {
+	Body *DeleteCurrentUserJSONRequestBody
+}
+
+type DeleteCurrentUserResponseObject interface {
+	VisitDeleteCurrentUserResponse(w http.ResponseWriter) error
+}
+
+type DeleteCurrentUser200JSONResponse AccountDeletion
+
+func (response DeleteCurrentUser200JSONResponse) VisitDeleteCurrentUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteCurrentUserdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response DeleteCurrentUserdefaultJSONResponse) VisitDeleteCurrentUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CurrentUserRequestObject struct {
+}
+
+type CurrentUserResponseObject interface {
+	VisitCurrentUserResponse(w http.ResponseWriter) error
+}
+
+type CurrentUser200JSONResponse User
+
+func (response CurrentUser200JSONResponse) VisitCurrentUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CurrentUserdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response CurrentUserdefaultJSONResponse) VisitCurrentUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpdateCurrentUserRequestObject struct {
+	Body *UpdateCurrentUserJSONRequestBody
+}
+
+type UpdateCurrentUserResponseObject interface {
+	VisitUpdateCurrentUserResponse(w http.ResponseWriter) error
+}
+
+type UpdateCurrentUser200JSONResponse User
+
+func (response UpdateCurrentUser200JSONResponse) VisitUpdateCurrentUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateCurrentUserdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response UpdateCurrentUserdefaultJSONResponse) VisitUpdateCurrentUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteDelegationRequestObject struct {
+	Params DeleteDelegationParams
+}
+
+type DeleteDelegationResponseObject interface {
+	VisitDeleteDelegationResponse(w http.ResponseWriter) error
+}
+
+type DeleteDelegation204Response struct {
+}
+
+func (response DeleteDelegation204Response) VisitDeleteDelegationResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteDelegationdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response DeleteDelegationdefaultJSONResponse) VisitDeleteDelegationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CurrentUserDelegationsRequestObject struct {
+}
+
+type CurrentUserDelegationsResponseObject interface {
+	VisitCurrentUserDelegationsResponse(w http.ResponseWriter) error
+}
+
+type CurrentUserDelegations200JSONResponse []Delegation
+
+func (response CurrentUserDelegations200JSONResponse) VisitCurrentUserDelegationsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CurrentUserDelegationsdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response CurrentUserDelegationsdefaultJSONResponse) VisitCurrentUserDelegationsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type InviteDelegateRequestObject struct {
+	Body *InviteDelegateJSONRequestBody
+}
+
+type InviteDelegateResponseObject interface {
+	VisitInviteDelegateResponse(w http.ResponseWriter) error
+}
+
+type InviteDelegate200JSONResponse struct {
+	// ID The delegation identifier
+	ID int64 `json:"id"`
+
+	// OwnerID The ID of the owner, which the delegate puts in the `X-On-Behalf-Of` header
+	OwnerID int64 `json:"ownerID"`
+
+	// OwnerName The name of the owner
+	OwnerName string `json:"ownerName"`
+
+	// DelegateName The name of the delegate, or null if the invite wasn't accepted yet
+	DelegateName *string `json:"delegateName,omitempty"`
+
+	// MirrorNotifications Whether the owner's reminders and digests are also sent to the delegate
+	MirrorNotifications bool `json:"mirrorNotifications"`
+
+	// CreatedAt The time the invite was created
+	CreatedAt time.Time `json:"createdAt"`
+
+	// InviteExpiresAt The time the invite expires, or null if it was accepted
+	InviteExpiresAt *time.Time `json:"inviteExpiresAt,omitempty"`
+
+	// AcceptedAt The time the invite was accepted, or null if it wasn't yet
+	AcceptedAt *time.Time `json:"acceptedAt,omitempty"`
+
+	// Access What a delegate may do with the owner's data:
+	// - `read`: see the dosage and dose history - `record`: also record, edit and forget doses
+	Access DelegationAccess `json:"access"`
+
+	// Code The code that the delegate enters to accept the invite. The server only stores a hash of it, so it is only ever returned here.
+	Code string `json:"code"`
+
+	// Role Whether the current user is the owner or the delegate of a delegation.
+	Role DelegationRole `json:"role"`
+}
+
+func (response InviteDelegate200JSONResponse) VisitInviteDelegateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type InviteDelegatedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response InviteDelegatedefaultJSONResponse) VisitInviteDelegateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type AcceptDelegationRequestObject struct {
+	Body *AcceptDelegationJSONRequestBody
+}
+
+type AcceptDelegationResponseObject interface {
+	VisitAcceptDelegationResponse(w http.ResponseWriter) error
+}
+
+type AcceptDelegation200JSONResponse Delegation
+
+func (response AcceptDelegation200JSONResponse) VisitAcceptDelegationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AcceptDelegationdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response AcceptDelegationdefaultJSONResponse) VisitAcceptDelegationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CancelCurrentUserDeletionRequestObject struct {
+}
+
+type CancelCurrentUserDeletionResponseObject interface {
+	VisitCancelCurrentUserDeletionResponse(w http.ResponseWriter) error
+}
+
+type CancelCurrentUserDeletion204Response struct {
+}
+
+func (response CancelCurrentUserDeletion204Response) VisitCancelCurrentUserDeletionResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type CancelCurrentUserDeletiondefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response CancelCurrentUserDeletiondefaultJSONResponse) VisitCancelCurrentUserDeletionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteUserPasskeyRequestObject struct {
+	Params DeleteUserPasskeyParams
+}
+
+type DeleteUserPasskeyResponseObject interface {
+	VisitDeleteUserPasskeyResponse(w http.ResponseWriter) error
+}
+
+type DeleteUserPasskey204Response struct {
+}
+
+func (response DeleteUserPasskey204Response) VisitDeleteUserPasskeyResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteUserPasskeydefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response DeleteUserPasskeydefaultJSONResponse) VisitDeleteUserPasskeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CurrentUserPasskeysRequestObject struct {
+}
+
+type CurrentUserPasskeysResponseObject interface {
+	VisitCurrentUserPasskeysResponse(w http.ResponseWriter) error
+}
+
+type CurrentUserPasskeys200JSONResponse []Passkey
+
+func (response CurrentUserPasskeys200JSONResponse) VisitCurrentUserPasskeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CurrentUserPasskeysdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response CurrentUserPasskeysdefaultJSONResponse) VisitCurrentUserPasskeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type BeginPasskeyRegistrationRequestObject struct {
+}
+
+type BeginPasskeyRegistrationResponseObject interface {
+	VisitBeginPasskeyRegistrationResponse(w http.ResponseWriter) error
+}
+
+type BeginPasskeyRegistration200JSONResponse PasskeyChallenge
+
+func (response BeginPasskeyRegistration200JSONResponse) VisitBeginPasskeyRegistrationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type BeginPasskeyRegistrationdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response BeginPasskeyRegistrationdefaultJSONResponse) VisitBeginPasskeyRegistrationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type FinishPasskeyRegistrationRequestObject struct {
+	Body *FinishPasskeyRegistrationJSONRequestBody
+}
+
+type FinishPasskeyRegistrationResponseObject interface {
+	VisitFinishPasskeyRegistrationResponse(w http.ResponseWriter) error
+}
+
+type FinishPasskeyRegistration200JSONResponse Passkey
+
+func (response FinishPasskeyRegistration200JSONResponse) VisitFinishPasskeyRegistrationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type FinishPasskeyRegistrationdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response FinishPasskeyRegistrationdefaultJSONResponse) VisitFinishPasskeyRegistrationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteUserRecoveryCodesRequestObject struct {
+}
+
+type DeleteUserRecoveryCodesResponseObject interface {
+	VisitDeleteUserRecoveryCodesResponse(w http.ResponseWriter) error
+}
+
+type DeleteUserRecoveryCodes204Response struct {
+}
+
+func (response DeleteUserRecoveryCodes204Response) VisitDeleteUserRecoveryCodesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteUserRecoveryCodesdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response DeleteUserRecoveryCodesdefaultJSONResponse) VisitDeleteUserRecoveryCodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CurrentUserRecoveryCodesRequestObject struct {
+}
+
+type CurrentUserRecoveryCodesResponseObject interface {
+	VisitCurrentUserRecoveryCodesResponse(w http.ResponseWriter) error
+}
+
+type CurrentUserRecoveryCodes200JSONResponse struct {
+	// Remaining The number of recovery codes that were not used yet
+	Remaining int `json:"remaining"`
+}
+
+func (response CurrentUserRecoveryCodes200JSONResponse) VisitCurrentUserRecoveryCodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CurrentUserRecoveryCodesdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response CurrentUserRecoveryCodesdefaultJSONResponse) VisitCurrentUserRecoveryCodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GenerateUserRecoveryCodesRequestObject struct {
+}
+
+type GenerateUserRecoveryCodesResponseObject interface {
+	VisitGenerateUserRecoveryCodesResponse(w http.ResponseWriter) error
+}
+
+type GenerateUserRecoveryCodes200JSONResponse struct {
+	Codes []RecoveryCode `json:"codes"`
+}
+
+func (response GenerateUserRecoveryCodes200JSONResponse) VisitGenerateUserRecoveryCodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GenerateUserRecoveryCodesdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response GenerateUserRecoveryCodesdefaultJSONResponse) VisitGenerateUserRecoveryCodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RotateUserSecretRequestObject struct {
+}
+
+type RotateUserSecretResponseObject interface {
+	VisitRotateUserSecretResponse(w http.ResponseWriter) error
+}
+
+type RotateUserSecret200JSONResponse struct {
+	// Secret A secret and unique user identifier. This secret is generated when registering and only changes when the user rotates it. It is used to authenticate a user, so it should be kept secret. The server only stores a hash of it, so it is only ever returned when registering or rotating it.
+	Secret UserSecret `json:"secret"`
+}
+
+func (response RotateUserSecret200JSONResponse) VisitRotateUserSecretResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RotateUserSecretdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response RotateUserSecretdefaultJSONResponse) VisitRotateUserSecretResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteUserSessionRequestObject struct {
+	Params DeleteUserSessionParams
+}
+
+type DeleteUserSessionResponseObject interface {
+	VisitDeleteUserSessionResponse(w http.ResponseWriter) error
+}
+
+type DeleteUserSession204Response struct {
+}
+
+func (response DeleteUserSession204Response) VisitDeleteUserSessionResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteUserSessiondefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response DeleteUserSessiondefaultJSONResponse) VisitDeleteUserSessionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CurrentUserSessionsRequestObject struct {
+}
+
+type CurrentUserSessionsResponseObject interface {
+	VisitCurrentUserSessionsResponse(w http.ResponseWriter) error
+}
+
+type CurrentUserSessions200JSONResponse []Session
+
+func (response CurrentUserSessions200JSONResponse) VisitCurrentUserSessionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CurrentUserSessionsdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response CurrentUserSessionsdefaultJSONResponse) VisitCurrentUserSessionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteOtherUserSessionsRequestObject struct {
+}
+
+type DeleteOtherUserSessionsResponseObject interface {
+	VisitDeleteOtherUserSessionsResponse(w http.ResponseWriter) error
+}
+
+type DeleteOtherUserSessions204Response struct {
+}
+
+func (response DeleteOtherUserSessions204Response) VisitDeleteOtherUserSessionsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteOtherUserSessionsdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response DeleteOtherUserSessionsdefaultJSONResponse) VisitDeleteOtherUserSessionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExportTakeoutRequestObject struct {
+	Params ExportTakeoutParams
+}
+
+type ExportTakeoutResponseObject interface {
+	VisitExportTakeoutResponse(w http.ResponseWriter) error
+}
+
+type ExportTakeout200ResponseHeaders struct {
+	ContentDisposition string
+}
+
+type ExportTakeout200ApplicationXTarResponse struct {
+	Body          io.Reader
+	Headers       ExportTakeout200ResponseHeaders
+	ContentLength int64
+}
+
+func (response ExportTakeout200ApplicationXTarResponse) VisitExportTakeoutResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/x-tar")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportTakeout200ApplicationZipResponse struct {
+	Body          io.Reader
+	Headers       ExportTakeout200ResponseHeaders
+	ContentLength int64
+}
+
+func (response ExportTakeout200ApplicationZipResponse) VisitExportTakeoutResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/zip")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportTakeout429JSONResponse struct {
+	RateLimitedResponseJSONResponse
+}
+
+func (response ExportTakeout429JSONResponse) VisitExportTakeoutResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExportTakeoutdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ExportTakeoutdefaultJSONResponse) VisitExportTakeoutResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ImportTakeoutRequestObject struct {
+	Params      ImportTakeoutParams
+	ContentType string
+	Body        io.Reader
+}
+
+type ImportTakeoutResponseObject interface {
+	VisitImportTakeoutResponse(w http.ResponseWriter) error
+}
+
+type ImportTakeout200JSONResponse TakeoutImportResult
+
+func (response ImportTakeout200JSONResponse) VisitImportTakeoutResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ImportTakeout429JSONResponse struct {
+	RateLimitedResponseJSONResponse
+}
+
+func (response ImportTakeout429JSONResponse) VisitImportTakeoutResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ImportTakeoutdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ImportTakeoutdefaultJSONResponse) VisitImportTakeoutResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteUserTokenRequestObject struct {
+	Params DeleteUserTokenParams
+}
+
+type DeleteUserTokenResponseObject interface {
+	VisitDeleteUserTokenResponse(w http.ResponseWriter) error
+}
+
+type DeleteUserToken204Response struct {
+}
+
+func (response DeleteUserToken204Response) VisitDeleteUserTokenResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteUserTokendefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response DeleteUserTokendefaultJSONResponse) VisitDeleteUserTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CurrentUserTokensRequestObject struct {
+}
+
+type CurrentUserTokensResponseObject interface {
+	VisitCurrentUserTokensResponse(w http.ResponseWriter) error
+}
+
+type CurrentUserTokens200JSONResponse []PersonalToken
+
+func (response CurrentUserTokens200JSONResponse) VisitCurrentUserTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CurrentUserTokensdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response CurrentUserTokensdefaultJSONResponse) VisitCurrentUserTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateUserTokenRequestObject struct {
+	Body *CreateUserTokenJSONRequestBody
+}
+
+type CreateUserTokenResponseObject interface {
+	VisitCreateUserTokenResponse(w http.ResponseWriter) error
+}
+
+type CreateUserToken200JSONResponse struct {
+	// ID The token identifier
+	ID int64 `json:"id"`
+
+	// Name The name of the token
+	Name string `json:"name"`
+
+	// Scopes The scopes that the token may be used for
+	Scopes []Scope `json:"scopes"`
+
+	// CreatedAt The time the token was created
+	CreatedAt time.Time `json:"createdAt"`
+
+	// LastUsed The last time the token was used, or null if it was never used
+	LastUsed *time.Time `json:"lastUsed,omitempty"`
+
+	// ExpiresAt The time the token expires, or null if it never expires
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// Token The token itself. The server only stores a hash of it, so it is only ever returned here.
+	Token string `json:"token"`
+}
+
+func (response CreateUserToken200JSONResponse) VisitCreateUserTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateUserTokendefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response CreateUserTokendefaultJSONResponse) VisitCreateUserTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type EmailConfirmPageRequestObject struct {
+	Params EmailConfirmPageParams
+}
+
+type EmailConfirmPageResponseObject interface {
+	VisitEmailConfirmPageResponse(w http.ResponseWriter) error
+}
+
+type EmailConfirmPage200TextHTMLResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response EmailConfirmPage200TextHTMLResponse) VisitEmailConfirmPageResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/html")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type EmailConfirmPagedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response EmailConfirmPagedefaultJSONResponse) VisitEmailConfirmPageResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type EmailConfirmRequestObject struct {
+	Params EmailConfirmParams
+}
+
+type EmailConfirmResponseObject interface {
+	VisitEmailConfirmResponse(w http.ResponseWriter) error
+}
+
+type EmailConfirm200TextHTMLResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response EmailConfirm200TextHTMLResponse) VisitEmailConfirmResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/html")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type EmailConfirmdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response EmailConfirmdefaultJSONResponse) VisitEmailConfirmResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type EmailUnsubscribePageRequestObject struct {
+	Params EmailUnsubscribePageParams
+}
+
+type EmailUnsubscribePageResponseObject interface {
+	VisitEmailUnsubscribePageResponse(w http.ResponseWriter) error
+}
+
+type EmailUnsubscribePage200TextHTMLResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response EmailUnsubscribePage200TextHTMLResponse) VisitEmailUnsubscribePageResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/html")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type EmailUnsubscribePagedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response EmailUnsubscribePagedefaultJSONResponse) VisitEmailUnsubscribePageResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type EmailUnsubscribeRequestObject struct {
+	Params EmailUnsubscribeParams
+}
+
+type EmailUnsubscribeResponseObject interface {
+	VisitEmailUnsubscribeResponse(w http.ResponseWriter) error
+}
+
+type EmailUnsubscribe200TextHTMLResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response EmailUnsubscribe200TextHTMLResponse) VisitEmailUnsubscribeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/html")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type EmailUnsubscribedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response EmailUnsubscribedefaultJSONResponse) VisitEmailUnsubscribeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SupportedNotificationMethodsRequestObject struct {
+}
+
+type SupportedNotificationMethodsResponseObject interface {
+	VisitSupportedNotificationMethodsResponse(w http.ResponseWriter) error
+}
+
+type SupportedNotificationMethods200JSONResponse NotificationMethods
+
+func (response SupportedNotificationMethods200JSONResponse) VisitSupportedNotificationMethodsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SupportedNotificationMethodsdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response SupportedNotificationMethodsdefaultJSONResponse) VisitSupportedNotificationMethodsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UserNotificationPreferencesRequestObject struct {
+}
+
+type UserNotificationPreferencesResponseObject interface {
+	VisitUserNotificationPreferencesResponse(w http.ResponseWriter) error
+}
+
+type UserNotificationPreferences200JSONResponse NotificationPreferences
+
+func (response UserNotificationPreferences200JSONResponse) VisitUserNotificationPreferencesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UserNotificationPreferencesdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response UserNotificationPreferencesdefaultJSONResponse) VisitUserNotificationPreferencesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UserUpdateNotificationPreferencesRequestObject struct {
+	Body *UserUpdateNotificationPreferencesJSONRequestBody
+}
+
+type UserUpdateNotificationPreferencesResponseObject interface {
+	VisitUserUpdateNotificationPreferencesResponse(w http.ResponseWriter) error
+}
+
+type UserUpdateNotificationPreferences204Response struct {
+}
+
+func (response UserUpdateNotificationPreferences204Response) VisitUserUpdateNotificationPreferencesResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type UserUpdateNotificationPreferencesdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response UserUpdateNotificationPreferencesdefaultJSONResponse) VisitUserUpdateNotificationPreferencesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SendTestNotificationRequestObject struct {
+	Body *SendTestNotificationJSONRequestBody
+}
+
+type SendTestNotificationResponseObject interface {
+	VisitSendTestNotificationResponse(w http.ResponseWriter) error
+}
+
+type SendTestNotification200JSONResponse []TestNotificationResult
+
+func (response SendTestNotification200JSONResponse) VisitSendTestNotificationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SendTestNotificationdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response SendTestNotificationdefaultJSONResponse) VisitSendTestNotificationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type WebPushInfoRequestObject struct {
+}
+
+type WebPushInfoResponseObject interface {
+	VisitWebPushInfoResponse(w http.ResponseWriter) error
+}
+
+type WebPushInfo200JSONResponse PushInfo
+
+func (response WebPushInfo200JSONResponse) VisitWebPushInfoResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type WebPushInfodefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response WebPushInfodefaultJSONResponse) VisitWebPushInfoResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
 
 type RegisterRequestObject struct {
 	Body *RegisterJSONRequestBody
 }
 
-type RegisterResponseObject interface {
-	VisitRegisterResponse(w http.ResponseWriter) error
+type RegisterResponseObject interface {
+	VisitRegisterResponse(w http.ResponseWriter) error
+}
+
+type Register200JSONResponse struct {
+	// Name The user's name
+	Name string `json:"name"`
+
+	// Locale A locale identifier.
+	Locale Locale `json:"locale"`
+
+	// PurgeAt The time the user's account is deleted, if the user asked for it to be
+	PurgeAt *time.Time `json:"purgeAt,omitempty"`
+
+	// Timezone The IANA time zone of the user, such as `Europe/Paris`. Times in notifications are shown in it. If empty, UTC is used.
+	Timezone *string `json:"timezone,omitempty"`
+
+	// Secret A secret and unique user identifier. This secret is generated when registering and only changes when the user rotates it. It is used to authenticate a user, so it should be kept secret. The server only stores a hash of it, so it is only ever returned when registering or rotating it.
+	Secret UserSecret `json:"secret"`
+}
+
+func (response Register200JSONResponse) VisitRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RegisterdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response RegisterdefaultJSONResponse) VisitRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type OpenShareLinkRequestObject struct {
+	Token  string `json:"token"`
+	Params OpenShareLinkParams
+}
+
+type OpenShareLinkResponseObject interface {
+	VisitOpenShareLinkResponse(w http.ResponseWriter) error
+}
+
+type OpenShareLink200JSONResponse SharedData
+
+func (response OpenShareLink200JSONResponse) VisitOpenShareLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type OpenShareLink429JSONResponse struct {
+	RateLimitedResponseJSONResponse
+}
+
+func (response OpenShareLink429JSONResponse) VisitOpenShareLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type OpenShareLinkdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response OpenShareLinkdefaultJSONResponse) VisitOpenShareLinkResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Authenticate a user and obtain a session
+	// (POST /auth)
+	Auth(ctx context.Context, request AuthRequestObject) (AuthResponseObject, error)
+	// Begin logging in with a passkey
+	// (POST /auth/passkey/begin)
+	BeginPasskeyLogin(ctx context.Context, request BeginPasskeyLoginRequestObject) (BeginPasskeyLoginResponseObject, error)
+	// Authenticate a user with a passkey and obtain a session
+	// (POST /auth/passkey/finish)
+	FinishPasskeyLogin(ctx context.Context, request FinishPasskeyLoginRequestObject) (FinishPasskeyLoginResponseObject, error)
+	// Authenticate a user with a recovery code and obtain a session
+	// (POST /auth/recovery)
+	RecoveryAuth(ctx context.Context, request RecoveryAuthRequestObject) (RecoveryAuthResponseObject, error)
+	// List all available delivery methods
+	// (GET /delivery-methods)
+	DeliveryMethods(ctx context.Context, request DeliveryMethodsRequestObject) (DeliveryMethodsResponseObject, error)
+	// Clear the user's dosage schedule
+	// (DELETE /dosage)
+	ClearDosage(ctx context.Context, request ClearDosageRequestObject) (ClearDosageResponseObject, error)
+	// Get the user's dosage and optionally their history
+	// (GET /dosage)
+	Dosage(ctx context.Context, request DosageRequestObject) (DosageResponseObject, error)
+	// Set the user's dosage
+	// (PUT /dosage)
+	SetDosage(ctx context.Context, request SetDosageRequestObject) (SetDosageResponseObject, error)
+	// Delete multiple dosages from the user's history
+	// (DELETE /dosage/dose)
+	ForgetDoses(ctx context.Context, request ForgetDosesRequestObject) (ForgetDosesResponseObject, error)
+	// Record a new dosage to the user's history
+	// (POST /dosage/dose)
+	RecordDose(ctx context.Context, request RecordDoseRequestObject) (RecordDoseResponseObject, error)
+	// Delete a dosage from the user's history
+	// (DELETE /dosage/dose/{doseTime})
+	ForgetDose(ctx context.Context, request ForgetDoseRequestObject) (ForgetDoseResponseObject, error)
+	// Update a dosage in the user's history
+	// (PUT /dosage/dose/{doseTime})
+	EditDose(ctx context.Context, request EditDoseRequestObject) (EditDoseResponseObject, error)
+	// Export the user's dosage history
+	// (GET /dosage/export-doses)
+	ExportDoses(ctx context.Context, request ExportDosesRequestObject) (ExportDosesResponseObject, error)
+	// Import a CSV file of dosage history
+	// (POST /dosage/import-doses)
+	ImportDoses(ctx context.Context, request ImportDosesRequestObject) (ImportDosesResponseObject, error)
+	// Revoke one of the user's share links
+	// (DELETE /dosage/shares)
+	RevokeShareLink(ctx context.Context, request RevokeShareLinkRequestObject) (RevokeShareLinkResponseObject, error)
+	// List the user's share links
+	// (GET /dosage/shares)
+	ShareLinks(ctx context.Context, request ShareLinksRequestObject) (ShareLinksResponseObject, error)
+	// Create a share link
+	// (POST /dosage/shares)
+	CreateShareLink(ctx context.Context, request CreateShareLinkRequestObject) (CreateShareLinkResponseObject, error)
+	// Get the access log of one of the user's share links
+	// (GET /dosage/shares/{id}/accesses)
+	ShareLinkAccesses(ctx context.Context, request ShareLinkAccessesRequestObject) (ShareLinkAccessesResponseObject, error)
+	// Delete the current user's account
+	// (DELETE /me)
+	DeleteCurrentUser(ctx context.Context, request DeleteCurrentUserRequestObject) (DeleteCurrentUserResponseObject, error)
+	// Get the current user
+	// (GET /me)
+	CurrentUser(ctx context.Context, request CurrentUserRequestObject) (CurrentUserResponseObject, error)
+	// Update the current user's profile
+	// (PATCH /me)
+	UpdateCurrentUser(ctx context.Context, request UpdateCurrentUserRequestObject) (UpdateCurrentUserResponseObject, error)
+	// End one of the current user's delegations
+	// (DELETE /me/delegations)
+	DeleteDelegation(ctx context.Context, request DeleteDelegationRequestObject) (DeleteDelegationResponseObject, error)
+	// List the current user's delegations
+	// (GET /me/delegations)
+	CurrentUserDelegations(ctx context.Context, request CurrentUserDelegationsRequestObject) (CurrentUserDelegationsResponseObject, error)
+	// Invite someone to access the current user's data
+	// (POST /me/delegations)
+	InviteDelegate(ctx context.Context, request InviteDelegateRequestObject) (InviteDelegateResponseObject, error)
+	// Accept an invite to access another user's data
+	// (POST /me/delegations/accept)
+	AcceptDelegation(ctx context.Context, request AcceptDelegationRequestObject) (AcceptDelegationResponseObject, error)
+	// Cancel the deletion of the current user's account
+	// (DELETE /me/deletion)
+	CancelCurrentUserDeletion(ctx context.Context, request CancelCurrentUserDeletionRequestObject) (CancelCurrentUserDeletionResponseObject, error)
+	// Delete one of the current user's passkeys
+	// (DELETE /me/passkeys)
+	DeleteUserPasskey(ctx context.Context, request DeleteUserPasskeyRequestObject) (DeleteUserPasskeyResponseObject, error)
+	// List the current user's passkeys
+	// (GET /me/passkeys)
+	CurrentUserPasskeys(ctx context.Context, request CurrentUserPasskeysRequestObject) (CurrentUserPasskeysResponseObject, error)
+	// Begin adding a passkey to the current user
+	// (POST /me/passkeys/begin)
+	BeginPasskeyRegistration(ctx context.Context, request BeginPasskeyRegistrationRequestObject) (BeginPasskeyRegistrationResponseObject, error)
+	// Finish adding a passkey to the current user
+	// (POST /me/passkeys/finish)
+	FinishPasskeyRegistration(ctx context.Context, request FinishPasskeyRegistrationRequestObject) (FinishPasskeyRegistrationResponseObject, error)
+	// Delete the current user's recovery codes
+	// (DELETE /me/recovery-codes)
+	DeleteUserRecoveryCodes(ctx context.Context, request DeleteUserRecoveryCodesRequestObject) (DeleteUserRecoveryCodesResponseObject, error)
+	// Get the number of the current user's unused recovery codes
+	// (GET /me/recovery-codes)
+	CurrentUserRecoveryCodes(ctx context.Context, request CurrentUserRecoveryCodesRequestObject) (CurrentUserRecoveryCodesResponseObject, error)
+	// Generate new recovery codes for the current user
+	// (POST /me/recovery-codes)
+	GenerateUserRecoveryCodes(ctx context.Context, request GenerateUserRecoveryCodesRequestObject) (GenerateUserRecoveryCodesResponseObject, error)
+	// Rotate the current user's secret
+	// (POST /me/secret/rotate)
+	RotateUserSecret(ctx context.Context, request RotateUserSecretRequestObject) (RotateUserSecretResponseObject, error)
+	// Delete one of the current user's sessions
+	// (DELETE /me/sessions)
+	DeleteUserSession(ctx context.Context, request DeleteUserSessionRequestObject) (DeleteUserSessionResponseObject, error)
+	// List the current user's sessions
+	// (GET /me/sessions)
+	CurrentUserSessions(ctx context.Context, request CurrentUserSessionsRequestObject) (CurrentUserSessionsResponseObject, error)
+	// Delete all of the current user's sessions except the current one
+	// (DELETE /me/sessions/all-others)
+	DeleteOtherUserSessions(ctx context.Context, request DeleteOtherUserSessionsRequestObject) (DeleteOtherUserSessionsResponseObject, error)
+	// Export the current user's whole account
+	// (GET /me/takeout)
+	ExportTakeout(ctx context.Context, request ExportTakeoutRequestObject) (ExportTakeoutResponseObject, error)
+	// Restore an account export into the current user's account
+	// (POST /me/takeout)
+	ImportTakeout(ctx context.Context, request ImportTakeoutRequestObject) (ImportTakeoutResponseObject, error)
+	// Delete one of the current user's personal access tokens
+	// (DELETE /me/tokens)
+	DeleteUserToken(ctx context.Context, request DeleteUserTokenRequestObject) (DeleteUserTokenResponseObject, error)
+	// List the current user's personal access tokens
+	// (GET /me/tokens)
+	CurrentUserTokens(ctx context.Context, request CurrentUserTokensRequestObject) (CurrentUserTokensResponseObject, error)
+	// Create a personal access token for the current user
+	// (POST /me/tokens)
+	CreateUserToken(ctx context.Context, request CreateUserTokenRequestObject) (CreateUserTokenResponseObject, error)
+	// Show the page to confirm an email address
+	// (GET /notifications/email/confirm)
+	EmailConfirmPage(ctx context.Context, request EmailConfirmPageRequestObject) (EmailConfirmPageResponseObject, error)
+	// Confirm an email address
+	// (POST /notifications/email/confirm)
+	EmailConfirm(ctx context.Context, request EmailConfirmRequestObject) (EmailConfirmResponseObject, error)
+	// Show the page to unsubscribe an email address
+	// (GET /notifications/email/unsubscribe)
+	EmailUnsubscribePage(ctx context.Context, request EmailUnsubscribePageRequestObject) (EmailUnsubscribePageResponseObject, error)
+	// Unsubscribe an email address
+	// (POST /notifications/email/unsubscribe)
+	EmailUnsubscribe(ctx context.Context, request EmailUnsubscribeRequestObject) (EmailUnsubscribeResponseObject, error)
+	// Get the server's supported notification methods
+	// (GET /notifications/methods)
+	SupportedNotificationMethods(ctx context.Context, request SupportedNotificationMethodsRequestObject) (SupportedNotificationMethodsResponseObject, error)
+	// Get the user's notification preferences
+	// (GET /notifications/preferences)
+	UserNotificationPreferences(ctx context.Context, request UserNotificationPreferencesRequestObject) (UserNotificationPreferencesResponseObject, error)
+	// Update the user's notification preferences
+	// (PUT /notifications/preferences)
+	UserUpdateNotificationPreferences(ctx context.Context, request UserUpdateNotificationPreferencesRequestObject) (UserUpdateNotificationPreferencesResponseObject, error)
+	// Send a test notification
+	// (POST /notifications/test)
+	SendTestNotification(ctx context.Context, request SendTestNotificationRequestObject) (SendTestNotificationResponseObject, error)
+	// Get the server's push notification information
+	// (GET /push-info)
+	WebPushInfo(ctx context.Context, request WebPushInfoRequestObject) (WebPushInfoResponseObject, error)
+	// Register a new account
+	// (POST /register)
+	Register(ctx context.Context, request RegisterRequestObject) (RegisterResponseObject, error)
+	// Open a share link
+	// (GET /shared/{token})
+	OpenShareLink(ctx context.Context, request OpenShareLinkRequestObject) (OpenShareLinkResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// Auth operation middleware
+func (sh *strictHandler) Auth(w http.ResponseWriter, r *http.Request, params AuthParams) {
+	var request AuthRequestObject
+
+	request.Params = params
+
+	var body AuthJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Auth(ctx, request.(AuthRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Auth")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(AuthResponseObject); ok {
+		if err := validResponse.VisitAuthResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// BeginPasskeyLogin operation middleware
+func (sh *strictHandler) BeginPasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	var request BeginPasskeyLoginRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.BeginPasskeyLogin(ctx, request.(BeginPasskeyLoginRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "BeginPasskeyLogin")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(BeginPasskeyLoginResponseObject); ok {
+		if err := validResponse.VisitBeginPasskeyLoginResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// FinishPasskeyLogin operation middleware
+func (sh *strictHandler) FinishPasskeyLogin(w http.ResponseWriter, r *http.Request, params FinishPasskeyLoginParams) {
+	var request FinishPasskeyLoginRequestObject
+
+	request.Params = params
+
+	var body FinishPasskeyLoginJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.FinishPasskeyLogin(ctx, request.(FinishPasskeyLoginRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "FinishPasskeyLogin")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(FinishPasskeyLoginResponseObject); ok {
+		if err := validResponse.VisitFinishPasskeyLoginResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RecoveryAuth operation middleware
+func (sh *strictHandler) RecoveryAuth(w http.ResponseWriter, r *http.Request, params RecoveryAuthParams) {
+	var request RecoveryAuthRequestObject
+
+	request.Params = params
+
+	var body RecoveryAuthJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RecoveryAuth(ctx, request.(RecoveryAuthRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RecoveryAuth")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RecoveryAuthResponseObject); ok {
+		if err := validResponse.VisitRecoveryAuthResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeliveryMethods operation middleware
+func (sh *strictHandler) DeliveryMethods(w http.ResponseWriter, r *http.Request) {
+	var request DeliveryMethodsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeliveryMethods(ctx, request.(DeliveryMethodsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeliveryMethods")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeliveryMethodsResponseObject); ok {
+		if err := validResponse.VisitDeliveryMethodsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ClearDosage operation middleware
+func (sh *strictHandler) ClearDosage(w http.ResponseWriter, r *http.Request) {
+	var request ClearDosageRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ClearDosage(ctx, request.(ClearDosageRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ClearDosage")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ClearDosageResponseObject); ok {
+		if err := validResponse.VisitClearDosageResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Dosage operation middleware
+func (sh *strictHandler) Dosage(w http.ResponseWriter, r *http.Request, params DosageParams) {
+	var request DosageRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Dosage(ctx, request.(DosageRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Dosage")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DosageResponseObject); ok {
+		if err := validResponse.VisitDosageResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// SetDosage operation middleware
+func (sh *strictHandler) SetDosage(w http.ResponseWriter, r *http.Request) {
+	var request SetDosageRequestObject
+
+	var body SetDosageJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SetDosage(ctx, request.(SetDosageRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SetDosage")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SetDosageResponseObject); ok {
+		if err := validResponse.VisitSetDosageResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ForgetDoses operation middleware
+func (sh *strictHandler) ForgetDoses(w http.ResponseWriter, r *http.Request, params ForgetDosesParams) {
+	var request ForgetDosesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ForgetDoses(ctx, request.(ForgetDosesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ForgetDoses")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ForgetDosesResponseObject); ok {
+		if err := validResponse.VisitForgetDosesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RecordDose operation middleware
+func (sh *strictHandler) RecordDose(w http.ResponseWriter, r *http.Request) {
+	var request RecordDoseRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RecordDose(ctx, request.(RecordDoseRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RecordDose")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RecordDoseResponseObject); ok {
+		if err := validResponse.VisitRecordDoseResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ForgetDose operation middleware
+func (sh *strictHandler) ForgetDose(w http.ResponseWriter, r *http.Request, doseTime time.Time) {
+	var request ForgetDoseRequestObject
+
+	request.DoseTime = doseTime
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ForgetDose(ctx, request.(ForgetDoseRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ForgetDose")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ForgetDoseResponseObject); ok {
+		if err := validResponse.VisitForgetDoseResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// EditDose operation middleware
+func (sh *strictHandler) EditDose(w http.ResponseWriter, r *http.Request, doseTime time.Time) {
+	var request EditDoseRequestObject
+
+	request.DoseTime = doseTime
+
+	var body EditDoseJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.EditDose(ctx, request.(EditDoseRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "EditDose")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(EditDoseResponseObject); ok {
+		if err := validResponse.VisitEditDoseResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ExportDoses operation middleware
+func (sh *strictHandler) ExportDoses(w http.ResponseWriter, r *http.Request, params ExportDosesParams) {
+	var request ExportDosesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ExportDoses(ctx, request.(ExportDosesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ExportDoses")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ExportDosesResponseObject); ok {
+		if err := validResponse.VisitExportDosesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ImportDoses operation middleware
+func (sh *strictHandler) ImportDoses(w http.ResponseWriter, r *http.Request, params ImportDosesParams) {
+	var request ImportDosesRequestObject
+
+	request.Params = params
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+
+		var body ImportDosesJSONRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+			return
+		}
+		request.JSONBody = &body
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		request.Body = r.Body
+	}
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ImportDoses(ctx, request.(ImportDosesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ImportDoses")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ImportDosesResponseObject); ok {
+		if err := validResponse.VisitImportDosesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RevokeShareLink operation middleware
+func (sh *strictHandler) RevokeShareLink(w http.ResponseWriter, r *http.Request, params RevokeShareLinkParams) {
+	var request RevokeShareLinkRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RevokeShareLink(ctx, request.(RevokeShareLinkRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RevokeShareLink")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RevokeShareLinkResponseObject); ok {
+		if err := validResponse.VisitRevokeShareLinkResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ShareLinks operation middleware
+func (sh *strictHandler) ShareLinks(w http.ResponseWriter, r *http.Request) {
+	var request ShareLinksRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ShareLinks(ctx, request.(ShareLinksRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ShareLinks")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ShareLinksResponseObject); ok {
+		if err := validResponse.VisitShareLinksResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateShareLink operation middleware
+func (sh *strictHandler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
+	var request CreateShareLinkRequestObject
+
+	var body CreateShareLinkJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateShareLink(ctx, request.(CreateShareLinkRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateShareLink")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateShareLinkResponseObject); ok {
+		if err := validResponse.VisitCreateShareLinkResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ShareLinkAccesses operation middleware
+func (sh *strictHandler) ShareLinkAccesses(w http.ResponseWriter, r *http.Request, id int64) {
+	var request ShareLinkAccessesRequestObject
+
+	request.ID = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ShareLinkAccesses(ctx, request.(ShareLinkAccessesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ShareLinkAccesses")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ShareLinkAccessesResponseObject); ok {
+		if err := validResponse.VisitShareLinkAccessesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteCurrentUser operation middleware
+func (sh *strictHandler) DeleteCurrentUser(w http.ResponseWriter, r *http.Request) {
+	var request DeleteCurrentUserRequestObject
+
+	var body DeleteCurrentUserJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteCurrentUser(ctx, request.(DeleteCurrentUserRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteCurrentUser")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteCurrentUserResponseObject); ok {
+		if err := validResponse.VisitDeleteCurrentUserResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CurrentUser operation middleware
+func (sh *strictHandler) CurrentUser(w http.ResponseWriter, r *http.Request) {
+	var request CurrentUserRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CurrentUser(ctx, request.(CurrentUserRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CurrentUser")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CurrentUserResponseObject); ok {
+		if err := validResponse.VisitCurrentUserResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type Register200JSONResponse struct {
-	// Name The user's name
-	Name string `json:"name"`
+// UpdateCurrentUser operation middleware
+func (sh *strictHandler) UpdateCurrentUser(w http.ResponseWriter, r *http.Request) {
+	var request UpdateCurrentUserRequestObject
 
-	// Locale A locale identifier.
-	Locale Locale `json:"locale"`
+	var body UpdateCurrentUserJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
-	// Secret A secret and unique user identifier. This secret is generated once and never changes. It is used to both authenticate and identify a user, so it should be kept secret.
-	Secret UserSecret `json:"secret"`
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateCurrentUser(ctx, request.(UpdateCurrentUserRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateCurrentUser")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateCurrentUserResponseObject); ok {
+		if err := validResponse.VisitUpdateCurrentUserResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-func (response Register200JSONResponse) VisitRegisterResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+// DeleteDelegation operation middleware
+func (sh *strictHandler) DeleteDelegation(w http.ResponseWriter, r *http.Request, params DeleteDelegationParams) {
+	var request DeleteDelegationRequestObject
 
-	return json.NewEncoder(w).Encode(response)
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteDelegation(ctx, request.(DeleteDelegationRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteDelegation")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteDelegationResponseObject); ok {
+		if err := validResponse.VisitDeleteDelegationResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type RegisterdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
+// CurrentUserDelegations operation middleware
+func (sh *strictHandler) CurrentUserDelegations(w http.ResponseWriter, r *http.Request) {
+	var request CurrentUserDelegationsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CurrentUserDelegations(ctx, request.(CurrentUserDelegationsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CurrentUserDelegations")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CurrentUserDelegationsResponseObject); ok {
+		if err := validResponse.VisitCurrentUserDelegationsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-func (response RegisterdefaultJSONResponse) VisitRegisterResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+// InviteDelegate operation middleware
+func (sh *strictHandler) InviteDelegate(w http.ResponseWriter, r *http.Request) {
+	var request InviteDelegateRequestObject
 
-	return json.NewEncoder(w).Encode(response.Body)
+	var body InviteDelegateJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.InviteDelegate(ctx, request.(InviteDelegateRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "InviteDelegate")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(InviteDelegateResponseObject); ok {
+		if err := validResponse.VisitInviteDelegateResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-// StrictServerInterface represents all server handlers.
-type StrictServerInterface interface {
-	// Authenticate a user and obtain a session
-	// (POST /auth)
-	Auth(ctx context.Context, request AuthRequestObject) (AuthResponseObject, error)
-	// List all available delivery methods
-	// (GET /delivery-methods)
-	DeliveryMethods(ctx context.Context, request DeliveryMethodsRequestObject) (DeliveryMethodsResponseObject, error)
-	// Clear the user's dosage schedule
-	// (DELETE /dosage)
-	ClearDosage(ctx context.Context, request ClearDosageRequestObject) (ClearDosageResponseObject, error)
-	// Get the user's dosage and optionally their history
-	// (GET /dosage)
-	Dosage(ctx context.Context, request DosageRequestObject) (DosageResponseObject, error)
-	// Set the user's dosage
-	// (PUT /dosage)
-	SetDosage(ctx context.Context, request SetDosageRequestObject) (SetDosageResponseObject, error)
-	// Delete multiple dosages from the user's history
-	// (DELETE /dosage/dose)
-	ForgetDoses(ctx context.Context, request ForgetDosesRequestObject) (ForgetDosesResponseObject, error)
-	// Record a new dosage to the user's history
-	// (POST /dosage/dose)
-	RecordDose(ctx context.Context, request RecordDoseRequestObject) (RecordDoseResponseObject, error)
-	// Delete a dosage from the user's history
-	// (DELETE /dosage/dose/{doseTime})
-	ForgetDose(ctx context.Context, request ForgetDoseRequestObject) (ForgetDoseResponseObject, error)
-	// Update a dosage in the user's history
-	// (PUT /dosage/dose/{doseTime})
-	EditDose(ctx context.Context, request EditDoseRequestObject) (EditDoseResponseObject, error)
-	// Export the user's dosage history
-	// (GET /dosage/export-doses)
-	ExportDoses(ctx context.Context, request ExportDosesRequestObject) (ExportDosesResponseObject, error)
-	// Import a CSV file of dosage history
-	// (POST /dosage/import-doses)
-	ImportDoses(ctx context.Context, request ImportDosesRequestObject) (ImportDosesResponseObject, error)
-	// Get the current user
-	// (GET /me)
-	CurrentUser(ctx context.Context, request CurrentUserRequestObject) (CurrentUserResponseObject, error)
-	// Delete one of the current user's sessions
-	// (DELETE /me/sessions)
-	DeleteUserSession(ctx context.Context, request DeleteUserSessionRequestObject) (DeleteUserSessionResponseObject, error)
-	// List the current user's sessions
-	// (GET /me/sessions)
-	CurrentUserSessions(ctx context.Context, request CurrentUserSessionsRequestObject) (CurrentUserSessionsResponseObject, error)
-	// Get the server's supported notification methods
-	// (GET /notifications/methods)
-	SupportedNotificationMethods(ctx context.Context, request SupportedNotificationMethodsRequestObject) (SupportedNotificationMethodsResponseObject, error)
-	// Get the user's notification preferences
-	// (GET /notifications/preferences)
-	UserNotificationPreferences(ctx context.Context, request UserNotificationPreferencesRequestObject) (UserNotificationPreferencesResponseObject, error)
-	// Update the user's notification preferences
-	// (PUT /notifications/preferences)
-	UserUpdateNotificationPreferences(ctx context.Context, request UserUpdateNotificationPreferencesRequestObject) (UserUpdateNotificationPreferencesResponseObject, error)
-	// Send a test notification
-	// (POST /notifications/test)
-	SendTestNotification(ctx context.Context, request SendTestNotificationRequestObject) (SendTestNotificationResponseObject, error)
-	// Get the server's push notification information
-	// (GET /push-info)
-	WebPushInfo(ctx context.Context, request WebPushInfoRequestObject) (WebPushInfoResponseObject, error)
-	// Register a new account
-	// (POST /register)
-	Register(ctx context.Context, request RegisterRequestObject) (RegisterResponseObject, error)
+// AcceptDelegation operation middleware
+func (sh *strictHandler) AcceptDelegation(w http.ResponseWriter, r *http.Request) {
+	var request AcceptDelegationRequestObject
+
+	var body AcceptDelegationJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.AcceptDelegation(ctx, request.(AcceptDelegationRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "AcceptDelegation")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(AcceptDelegationResponseObject); ok {
+		if err := validResponse.VisitAcceptDelegationResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
-type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+// CancelCurrentUserDeletion operation middleware
+func (sh *strictHandler) CancelCurrentUserDeletion(w http.ResponseWriter, r *http.Request) {
+	var request CancelCurrentUserDeletionRequestObject
 
-type StrictHTTPServerOptions struct {
-	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
-	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CancelCurrentUserDeletion(ctx, request.(CancelCurrentUserDeletionRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CancelCurrentUserDeletion")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CancelCurrentUserDeletionResponseObject); ok {
+		if err := validResponse.VisitCancelCurrentUserDeletionResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteUserPasskey operation middleware
+func (sh *strictHandler) DeleteUserPasskey(w http.ResponseWriter, r *http.Request, params DeleteUserPasskeyParams) {
+	var request DeleteUserPasskeyRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteUserPasskey(ctx, request.(DeleteUserPasskeyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteUserPasskey")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteUserPasskeyResponseObject); ok {
+		if err := validResponse.VisitDeleteUserPasskeyResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CurrentUserPasskeys operation middleware
+func (sh *strictHandler) CurrentUserPasskeys(w http.ResponseWriter, r *http.Request) {
+	var request CurrentUserPasskeysRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CurrentUserPasskeys(ctx, request.(CurrentUserPasskeysRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CurrentUserPasskeys")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CurrentUserPasskeysResponseObject); ok {
+		if err := validResponse.VisitCurrentUserPasskeysResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
-		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		},
-		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		},
-	}}
-}
+// BeginPasskeyRegistration operation middleware
+func (sh *strictHandler) BeginPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	var request BeginPasskeyRegistrationRequestObject
 
-func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.BeginPasskeyRegistration(ctx, request.(BeginPasskeyRegistrationRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "BeginPasskeyRegistration")
+	}
 
-type strictHandler struct {
-	ssi         StrictServerInterface
-	middlewares []StrictMiddlewareFunc
-	options     StrictHTTPServerOptions
-}
+	response, err := handler(r.Context(), w, r, request)
 
-// Auth operation middleware
-func (sh *strictHandler) Auth(w http.ResponseWriter, r *http.Request, params AuthParams) {
-	var request AuthRequestObject
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(BeginPasskeyRegistrationResponseObject); ok {
+		if err := validResponse.VisitBeginPasskeyRegistrationResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
 
-	request.Params = params
+// FinishPasskeyRegistration operation middleware
+func (sh *strictHandler) FinishPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	var request FinishPasskeyRegistrationRequestObject
 
-	var body AuthJSONRequestBody
+	var body FinishPasskeyRegistrationJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
 		return
@@ -1849,18 +5633,18 @@ func (sh *strictHandler) Auth(w http.ResponseWriter, r *http.Request, params Aut
 	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.Auth(ctx, request.(AuthRequestObject))
+		return sh.ssi.FinishPasskeyRegistration(ctx, request.(FinishPasskeyRegistrationRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "Auth")
+		handler = middleware(handler, "FinishPasskeyRegistration")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(AuthResponseObject); ok {
-		if err := validResponse.VisitAuthResponse(w); err != nil {
+	} else if validResponse, ok := response.(FinishPasskeyRegistrationResponseObject); ok {
+		if err := validResponse.VisitFinishPasskeyRegistrationResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -1868,23 +5652,23 @@ func (sh *strictHandler) Auth(w http.ResponseWriter, r *http.Request, params Aut
 	}
 }
 
-// DeliveryMethods operation middleware
-func (sh *strictHandler) DeliveryMethods(w http.ResponseWriter, r *http.Request) {
-	var request DeliveryMethodsRequestObject
+// DeleteUserRecoveryCodes operation middleware
+func (sh *strictHandler) DeleteUserRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var request DeleteUserRecoveryCodesRequestObject
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.DeliveryMethods(ctx, request.(DeliveryMethodsRequestObject))
+		return sh.ssi.DeleteUserRecoveryCodes(ctx, request.(DeleteUserRecoveryCodesRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "DeliveryMethods")
+		handler = middleware(handler, "DeleteUserRecoveryCodes")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(DeliveryMethodsResponseObject); ok {
-		if err := validResponse.VisitDeliveryMethodsResponse(w); err != nil {
+	} else if validResponse, ok := response.(DeleteUserRecoveryCodesResponseObject); ok {
+		if err := validResponse.VisitDeleteUserRecoveryCodesResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -1892,23 +5676,23 @@ func (sh *strictHandler) DeliveryMethods(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-// ClearDosage operation middleware
-func (sh *strictHandler) ClearDosage(w http.ResponseWriter, r *http.Request) {
-	var request ClearDosageRequestObject
+// CurrentUserRecoveryCodes operation middleware
+func (sh *strictHandler) CurrentUserRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var request CurrentUserRecoveryCodesRequestObject
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.ClearDosage(ctx, request.(ClearDosageRequestObject))
+		return sh.ssi.CurrentUserRecoveryCodes(ctx, request.(CurrentUserRecoveryCodesRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "ClearDosage")
+		handler = middleware(handler, "CurrentUserRecoveryCodes")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(ClearDosageResponseObject); ok {
-		if err := validResponse.VisitClearDosageResponse(w); err != nil {
+	} else if validResponse, ok := response.(CurrentUserRecoveryCodesResponseObject); ok {
+		if err := validResponse.VisitCurrentUserRecoveryCodesResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -1916,25 +5700,23 @@ func (sh *strictHandler) ClearDosage(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Dosage operation middleware
-func (sh *strictHandler) Dosage(w http.ResponseWriter, r *http.Request, params DosageParams) {
-	var request DosageRequestObject
-
-	request.Params = params
+// GenerateUserRecoveryCodes operation middleware
+func (sh *strictHandler) GenerateUserRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var request GenerateUserRecoveryCodesRequestObject
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.Dosage(ctx, request.(DosageRequestObject))
+		return sh.ssi.GenerateUserRecoveryCodes(ctx, request.(GenerateUserRecoveryCodesRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "Dosage")
+		handler = middleware(handler, "GenerateUserRecoveryCodes")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(DosageResponseObject); ok {
-		if err := validResponse.VisitDosageResponse(w); err != nil {
+	} else if validResponse, ok := response.(GenerateUserRecoveryCodesResponseObject); ok {
+		if err := validResponse.VisitGenerateUserRecoveryCodesResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -1942,30 +5724,23 @@ func (sh *strictHandler) Dosage(w http.ResponseWriter, r *http.Request, params D
 	}
 }
 
-// SetDosage operation middleware
-func (sh *strictHandler) SetDosage(w http.ResponseWriter, r *http.Request) {
-	var request SetDosageRequestObject
-
-	var body SetDosageJSONRequestBody
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
-		return
-	}
-	request.Body = &body
+// RotateUserSecret operation middleware
+func (sh *strictHandler) RotateUserSecret(w http.ResponseWriter, r *http.Request) {
+	var request RotateUserSecretRequestObject
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.SetDosage(ctx, request.(SetDosageRequestObject))
+		return sh.ssi.RotateUserSecret(ctx, request.(RotateUserSecretRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "SetDosage")
+		handler = middleware(handler, "RotateUserSecret")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(SetDosageResponseObject); ok {
-		if err := validResponse.VisitSetDosageResponse(w); err != nil {
+	} else if validResponse, ok := response.(RotateUserSecretResponseObject); ok {
+		if err := validResponse.VisitRotateUserSecretResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -1973,25 +5748,25 @@ func (sh *strictHandler) SetDosage(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ForgetDoses operation middleware
-func (sh *strictHandler) ForgetDoses(w http.ResponseWriter, r *http.Request, params ForgetDosesParams) {
-	var request ForgetDosesRequestObject
+// DeleteUserSession operation middleware
+func (sh *strictHandler) DeleteUserSession(w http.ResponseWriter, r *http.Request, params DeleteUserSessionParams) {
+	var request DeleteUserSessionRequestObject
 
 	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.ForgetDoses(ctx, request.(ForgetDosesRequestObject))
+		return sh.ssi.DeleteUserSession(ctx, request.(DeleteUserSessionRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "ForgetDoses")
+		handler = middleware(handler, "DeleteUserSession")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(ForgetDosesResponseObject); ok {
-		if err := validResponse.VisitForgetDosesResponse(w); err != nil {
+	} else if validResponse, ok := response.(DeleteUserSessionResponseObject); ok {
+		if err := validResponse.VisitDeleteUserSessionResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -1999,23 +5774,23 @@ func (sh *strictHandler) ForgetDoses(w http.ResponseWriter, r *http.Request, par
 	}
 }
 
-// RecordDose operation middleware
-func (sh *strictHandler) RecordDose(w http.ResponseWriter, r *http.Request) {
-	var request RecordDoseRequestObject
+// CurrentUserSessions operation middleware
+func (sh *strictHandler) CurrentUserSessions(w http.ResponseWriter, r *http.Request) {
+	var request CurrentUserSessionsRequestObject
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.RecordDose(ctx, request.(RecordDoseRequestObject))
+		return sh.ssi.CurrentUserSessions(ctx, request.(CurrentUserSessionsRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "RecordDose")
+		handler = middleware(handler, "CurrentUserSessions")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(RecordDoseResponseObject); ok {
-		if err := validResponse.VisitRecordDoseResponse(w); err != nil {
+	} else if validResponse, ok := response.(CurrentUserSessionsResponseObject); ok {
+		if err := validResponse.VisitCurrentUserSessionsResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -2023,25 +5798,23 @@ func (sh *strictHandler) RecordDose(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ForgetDose operation middleware
-func (sh *strictHandler) ForgetDose(w http.ResponseWriter, r *http.Request, doseTime time.Time) {
-	var request ForgetDoseRequestObject
-
-	request.DoseTime = doseTime
+// DeleteOtherUserSessions operation middleware
+func (sh *strictHandler) DeleteOtherUserSessions(w http.ResponseWriter, r *http.Request) {
+	var request DeleteOtherUserSessionsRequestObject
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.ForgetDose(ctx, request.(ForgetDoseRequestObject))
+		return sh.ssi.DeleteOtherUserSessions(ctx, request.(DeleteOtherUserSessionsRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "ForgetDose")
+		handler = middleware(handler, "DeleteOtherUserSessions")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(ForgetDoseResponseObject); ok {
-		if err := validResponse.VisitForgetDoseResponse(w); err != nil {
+	} else if validResponse, ok := response.(DeleteOtherUserSessionsResponseObject); ok {
+		if err := validResponse.VisitDeleteOtherUserSessionsResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -2049,32 +5822,54 @@ func (sh *strictHandler) ForgetDose(w http.ResponseWriter, r *http.Request, dose
 	}
 }
 
-// EditDose operation middleware
-func (sh *strictHandler) EditDose(w http.ResponseWriter, r *http.Request, doseTime time.Time) {
-	var request EditDoseRequestObject
+// ExportTakeout operation middleware
+func (sh *strictHandler) ExportTakeout(w http.ResponseWriter, r *http.Request, params ExportTakeoutParams) {
+	var request ExportTakeoutRequestObject
 
-	request.DoseTime = doseTime
+	request.Params = params
 
-	var body EditDoseJSONRequestBody
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
-		return
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ExportTakeout(ctx, request.(ExportTakeoutRequestObject))
 	}
-	request.Body = &body
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ExportTakeout")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ExportTakeoutResponseObject); ok {
+		if err := validResponse.VisitExportTakeoutResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ImportTakeout operation middleware
+func (sh *strictHandler) ImportTakeout(w http.ResponseWriter, r *http.Request, params ImportTakeoutParams) {
+	var request ImportTakeoutRequestObject
+
+	request.Params = params
+	request.ContentType = r.Header.Get("Content-Type")
+
+	request.Body = r.Body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.EditDose(ctx, request.(EditDoseRequestObject))
+		return sh.ssi.ImportTakeout(ctx, request.(ImportTakeoutRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "EditDose")
+		handler = middleware(handler, "ImportTakeout")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(EditDoseResponseObject); ok {
-		if err := validResponse.VisitEditDoseResponse(w); err != nil {
+	} else if validResponse, ok := response.(ImportTakeoutResponseObject); ok {
+		if err := validResponse.VisitImportTakeoutResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -2082,25 +5877,25 @@ func (sh *strictHandler) EditDose(w http.ResponseWriter, r *http.Request, doseTi
 	}
 }
 
-// ExportDoses operation middleware
-func (sh *strictHandler) ExportDoses(w http.ResponseWriter, r *http.Request, params ExportDosesParams) {
-	var request ExportDosesRequestObject
+// DeleteUserToken operation middleware
+func (sh *strictHandler) DeleteUserToken(w http.ResponseWriter, r *http.Request, params DeleteUserTokenParams) {
+	var request DeleteUserTokenRequestObject
 
 	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.ExportDoses(ctx, request.(ExportDosesRequestObject))
+		return sh.ssi.DeleteUserToken(ctx, request.(DeleteUserTokenRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "ExportDoses")
+		handler = middleware(handler, "DeleteUserToken")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(ExportDosesResponseObject); ok {
-		if err := validResponse.VisitExportDosesResponse(w); err != nil {
+	} else if validResponse, ok := response.(DeleteUserTokenResponseObject); ok {
+		if err := validResponse.VisitDeleteUserTokenResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -2108,37 +5903,54 @@ func (sh *strictHandler) ExportDoses(w http.ResponseWriter, r *http.Request, par
 	}
 }
 
-// ImportDoses operation middleware
-func (sh *strictHandler) ImportDoses(w http.ResponseWriter, r *http.Request, params ImportDosesParams) {
-	var request ImportDosesRequestObject
+// CurrentUserTokens operation middleware
+func (sh *strictHandler) CurrentUserTokens(w http.ResponseWriter, r *http.Request) {
+	var request CurrentUserTokensRequestObject
 
-	request.Params = params
-	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CurrentUserTokens(ctx, request.(CurrentUserTokensRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CurrentUserTokens")
+	}
 
-		var body ImportDosesJSONRequestBody
-		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-			sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
-			return
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CurrentUserTokensResponseObject); ok {
+		if err := validResponse.VisitCurrentUserTokensResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
-		request.JSONBody = &body
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
 	}
-	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
-		request.Body = r.Body
+}
+
+// CreateUserToken operation middleware
+func (sh *strictHandler) CreateUserToken(w http.ResponseWriter, r *http.Request) {
+	var request CreateUserTokenRequestObject
+
+	var body CreateUserTokenJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
 	}
+	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.ImportDoses(ctx, request.(ImportDosesRequestObject))
+		return sh.ssi.CreateUserToken(ctx, request.(CreateUserTokenRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "ImportDoses")
+		handler = middleware(handler, "CreateUserToken")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(ImportDosesResponseObject); ok {
-		if err := validResponse.VisitImportDosesResponse(w); err != nil {
+	} else if validResponse, ok := response.(CreateUserTokenResponseObject); ok {
+		if err := validResponse.VisitCreateUserTokenResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -2146,23 +5958,25 @@ func (sh *strictHandler) ImportDoses(w http.ResponseWriter, r *http.Request, par
 	}
 }
 
-// CurrentUser operation middleware
-func (sh *strictHandler) CurrentUser(w http.ResponseWriter, r *http.Request) {
-	var request CurrentUserRequestObject
+// EmailConfirmPage operation middleware
+func (sh *strictHandler) EmailConfirmPage(w http.ResponseWriter, r *http.Request, params EmailConfirmPageParams) {
+	var request EmailConfirmPageRequestObject
+
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.CurrentUser(ctx, request.(CurrentUserRequestObject))
+		return sh.ssi.EmailConfirmPage(ctx, request.(EmailConfirmPageRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "CurrentUser")
+		handler = middleware(handler, "EmailConfirmPage")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(CurrentUserResponseObject); ok {
-		if err := validResponse.VisitCurrentUserResponse(w); err != nil {
+	} else if validResponse, ok := response.(EmailConfirmPageResponseObject); ok {
+		if err := validResponse.VisitEmailConfirmPageResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -2170,25 +5984,25 @@ func (sh *strictHandler) CurrentUser(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// DeleteUserSession operation middleware
-func (sh *strictHandler) DeleteUserSession(w http.ResponseWriter, r *http.Request, params DeleteUserSessionParams) {
-	var request DeleteUserSessionRequestObject
+// EmailConfirm operation middleware
+func (sh *strictHandler) EmailConfirm(w http.ResponseWriter, r *http.Request, params EmailConfirmParams) {
+	var request EmailConfirmRequestObject
 
 	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.DeleteUserSession(ctx, request.(DeleteUserSessionRequestObject))
+		return sh.ssi.EmailConfirm(ctx, request.(EmailConfirmRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "DeleteUserSession")
+		handler = middleware(handler, "EmailConfirm")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(DeleteUserSessionResponseObject); ok {
-		if err := validResponse.VisitDeleteUserSessionResponse(w); err != nil {
+	} else if validResponse, ok := response.(EmailConfirmResponseObject); ok {
+		if err := validResponse.VisitEmailConfirmResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -2196,23 +6010,51 @@ func (sh *strictHandler) DeleteUserSession(w http.ResponseWriter, r *http.Reques
 	}
 }
 
-// CurrentUserSessions operation middleware
-func (sh *strictHandler) CurrentUserSessions(w http.ResponseWriter, r *http.Request) {
-	var request CurrentUserSessionsRequestObject
+// EmailUnsubscribePage operation middleware
+func (sh *strictHandler) EmailUnsubscribePage(w http.ResponseWriter, r *http.Request, params EmailUnsubscribePageParams) {
+	var request EmailUnsubscribePageRequestObject
+
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.CurrentUserSessions(ctx, request.(CurrentUserSessionsRequestObject))
+		return sh.ssi.EmailUnsubscribePage(ctx, request.(EmailUnsubscribePageRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "CurrentUserSessions")
+		handler = middleware(handler, "EmailUnsubscribePage")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(CurrentUserSessionsResponseObject); ok {
-		if err := validResponse.VisitCurrentUserSessionsResponse(w); err != nil {
+	} else if validResponse, ok := response.(EmailUnsubscribePageResponseObject); ok {
+		if err := validResponse.VisitEmailUnsubscribePageResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// EmailUnsubscribe operation middleware
+func (sh *strictHandler) EmailUnsubscribe(w http.ResponseWriter, r *http.Request, params EmailUnsubscribeParams) {
+	var request EmailUnsubscribeRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.EmailUnsubscribe(ctx, request.(EmailUnsubscribeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "EmailUnsubscribe")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(EmailUnsubscribeResponseObject); ok {
+		if err := validResponse.VisitEmailUnsubscribeResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -2303,6 +6145,13 @@ func (sh *strictHandler) UserUpdateNotificationPreferences(w http.ResponseWriter
 func (sh *strictHandler) SendTestNotification(w http.ResponseWriter, r *http.Request) {
 	var request SendTestNotificationRequestObject
 
+	var body SendTestNotificationJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
 		return sh.ssi.SendTestNotification(ctx, request.(SendTestNotificationRequestObject))
 	}
@@ -2378,88 +6227,252 @@ func (sh *strictHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// OpenShareLink operation middleware
+func (sh *strictHandler) OpenShareLink(w http.ResponseWriter, r *http.Request, token string, params OpenShareLinkParams) {
+	var request OpenShareLinkRequestObject
+
+	request.Token = token
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.OpenShareLink(ctx, request.(OpenShareLinkRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "OpenShareLink")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(OpenShareLinkResponseObject); ok {
+		if err := validResponse.VisitOpenShareLinkResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9Rc/W4cN5J/FaL3gLWB1oysON7z/KdI2otySWx45ORw1kDmdNfMMOomOyRbylwwwL3D",
-	"veE9yaGK5Ex/cD4kS75dIEA83exisVj1qw8W9WeSqbJSEqQ1yejPZAE8B03//ABWL49OZxY0/szBZFpU",
-	"ViiZjJLLGbMLYFkhQFpmFqoucqbxC3qu4fcajGUcv2acZaAtF5LxUtXSMjVjVpTAXgjJDGRK5uZlyuxC",
-	"GOYYYPeiKNgUmAE7YO9mFiR9YfyoxmsmZq0phWFTEHLONLfAClGWpbCQD5I0MdkCSo6LmSldcpuMEiHt",
-	"NydJmpRCirIuk9FxmthlBe4VzEEnq9UqTTSYSkkDJJkLrZX+4J/gg0xJC9LiP3lVFSLjKKbhbwZl9Wdj",
-	"3n/RMEtGyV+GG6kP3VszJKputrasr9qrE/KOFyIfXMtklSYfuIUfBS3x/4ejBUeBg1zLm6R9LZN0hzLF",
-	"ZvWjh82hK5rc84MfntXGqvJnZcXMr4ke8zwX+IMX77WqQFvhdmrX6ppEfgJj+ByS3krdfEw2J2R2wS3p",
-	"XG1As4xLpu5Aa5EDuxd2MWAoHzX9DTLLbmFpGNdA45tkGKqZQbX0+uY+QBbOoRB3oJc/gV2oHNdRtVbV",
-	"YrFrmaes8ZssbQEs9xRZSSQbsxqrhZwnafLH0Vwd4cMjcyuqI1U5eR5VCg1BJyOra8BhSuf48/UqTUQe",
-	"m98slLbMEWYaKg0GpMUfMVbYFRo02jRKda4ANdwqGtsWBJsJKHITZ95z9WqVJpKX0OcL92RWFwXD1w+S",
-	"iyf9zSpNaimsidOmV4+he+Lw5fdaaMiT0SeUapjJL2YSUxJFGttTjkzJrNYaZLZFCLIup6CRUzBWqzlI",
-	"VnGbLcAw1MoFsKnKl4xbpmQGA/ZOFkumoYA7LgltO4vDvSMCjVUG7OzoS95T7D57XepWoZnt3fRcmS3r",
-	"zUlQ3vUgnTX4zwrF7YawE0x7a1Jair7jRZx4eMumYO8RBZEP1GCW86VpzZarelrArum+6WpCR15+lQ2e",
-	"tivG98JYpZfItbBQ7kXDc6S8WpPjWvNlj9rZ+Je4GM7GvzC30GABCI1/NUH4C/c9ygP+4GVV4Bzt1aW4",
-	"ttTyW5Cn1v3/3Wx2atNMlSVIey1JyZi9T18dH6cnxyfHR8evjo5fXR0fj+i//0zTbYNOrl6d7B30+hBK",
-	"3zYp9ZTSCQyiqKwMwU4JefAAwgU1KJWuDdOSY2T8K8anqnZOyIk4RcvkcrnTUN480gZrA/sw7JksEEHX",
-	"60ScNsWRGzGwe24YfdC2PW7hCIfuWsTrMBfp3QOnY2o22/gy1cLMmdIOIbuCNQ9n8ttDMSJILQYRFyUX",
-	"xbietsKItgryPNdgtvg6wO+ZH4IIbUDmkQhHeYm0x1PwzqsKuCYTWAD7fKU+O/ce8IM+aYmHnsQsbru/",
-	"b7p6itWarDqm1jzS2JBXoMJTLNcYGdjvsdy3jO4eBWFOUPYUVEdiOstFEZH36Tq0ZX5Mw/YBiQ3Y5Vwq",
-	"DTlCwCd6ZCaohc5sV2ninkVoS0ZAT8EAjXGxbcZJAJRqhSlm7ic6e1XVBbeQYzIGkn3yfNGcqhTWp1sH",
-	"+R2fY3Qcz4HhaHDPMuaef12AXeCeBzm55MkPX084VaoATrlUeHmmcogKKwxgmcqBzHpD/EVtoED9wMcu",
-	"LzYvY+pa+lwjAu7+lY/gpyFkpgn2KlmgGzP4H1XGi+iUBb1hIscQfSZA78wNklGCdjTw9JrbJMpKaUJN",
-	"Z4800IC+ExkOrLhdoBGfZIXIbkEPeFUN/WszxLG0oGZS1jeShuh4UbybJaNPj8jxJrF0Nojeo0UTxwZd",
-	"T+ckcfjMVzgesweD2rMNrMLbrYDVxdbdyUpHO2jkRvkazEw6Yv9pm37uE9Pa++WgxR3kbKZVGc972bS2",
-	"lDhPIeTOOUg2Xa5X3g+KysfytS9wscIWsM3d2+LhRF/1hE8zbKTfFzn67nFdoQmZqJkKQ6F1S5I+iNjU",
-	"I9CeQDPjCbVAGGRdIi/3MH1fmwVG4uRQJxGI6iYBTV7fa5gBpZdmux7/1bQ5rTYfDa7lBc8W7BaWqCs8",
-	"ohzcK/wdL2rAQT0dypSciXmt3S8HxCiDZQUDdmlZyZeoWlIUoTzoRZMrINaCjBhnFddWZHXBdZ+VSGi+",
-	"pQB1EBzFqlcIR4c6uyZ/ZyQC0wdJt62H5n39UDCy/0FnDiWKg3fTXHVNJLa0rplcedyN2CmqTcc8Rtfy",
-	"WjJ2xD7fQ5GpEm68+X1GlTKYRFnF/LsN7rAPwNHaRMaLYpkyYZkwSIi5qJCjzlowNmDOwM+ioRQyBx2d",
-	"xr3c4LqDFKHZQulSScon1pR4lmGmdIOryeJs00KXDT/hIWDJJEDu2LWKZQvIbv1MnupgLZTpTVWbxQ38",
-	"UQk0/AfNI7Sb4x6mDKkw09huJBCohulQYtEZSJRLVXesz4C1Qs4JwzbQ1drHBDWoLfMkTeLCS0iJ4wtG",
-	"KG9w10fEBrQffXu8ShPU73PA0OXyfHsF9PKccWNUJjBW3mQTOX24Ae2o+BDSgjt1sbgKMdqySeUeA/C6",
-	"yjnVV4U1EXIYq2um5OBadnS7dZay4DIvvIJLpir+ew1Mc5mrMhRz5yBB02p8pdBzYUQOKTOqibQLjkDL",
-	"7vmSFFFpDcgIQ4MiWXC5ZDMh56ArLag+PLiW7mTBFTlylJr7PEzsOPbcCMl+4Hd8TAtlwoyu5efPn38z",
-	"LNPLyqqB4/3jx8vzFy8HphAZvDhO2b++ZJ8/f24Vov729u0bePu317t8+tHbt37jL+VMxVDIbZYGW2sJ",
-	"eYhlvDTQpWVKWi4kJiEuqSUvE9TAH6Xd00kaGjHFfm4fp4A/aGdbJxF999Q47BnTzP8Oy5iGfscNvHl9",
-	"BBIzmTxIVGl2ivj8XT2bgQ4M4xsu2cXZ+fiUvT86+fYNq+ppITLy4R09dsslnaoNsc1rzBJR5yw4Q28w",
-	"6T+g/NJUkGEKkqeMF0WAV+PCyC0fsrI21s20APbL6Xs0us2ENBCdEqS0BUJmRZ0D4+yHX6+YEXPZtExS",
-	"UlMpmeOSKy3ukOVbWPrQFpd7OWY/v7tyW4vB+sXZ+fcbOSxVHZYNktTQmQm3fMD+rjQrlYbm/qfMALDr",
-	"5KPBKR3/xM+vzuVeJwdUF2J7PvHa2q3y9H1nP5bifVVrIQrZqVN3uznpIgF0LABxBhfW5WRg1Q/jdz+/",
-	"eDlgP3UkEtKCmaplzrgdsYW1lRkNhzncQYHKPijVf4mi4AOl50OQRx/Hw1xlZvgrTIen7y974cfQzdYz",
-	"lrwB4fvCmTXcr9Af5RSZbSmO+bePzt1e4RToo1zQI0rYUYvklt0vROYUuAX7RALMJi2z4RtyGr3xwQ/w",
-	"2ircCvIRLIcC7AbOplrd++zswLrlw04VMR+7heWW1MLZBx2pdiysp7ARaKztIl4C6+AFZBpsin7Q12EA",
-	"gYP5vJhduGmDsfwKU1LvvWlmdfLtmzzOwUVR4M+MZbW+A3YuZjMB//vf//M9FEXJZRNuveN1MOyGv/CW",
-	"R+U49vPl+ArXgNPpVwxapF+6s2kNpi4oYghZmGS1RM3XYDDacAosJDv9eXzJ/uPt4M2JP995WOLr15w6",
-	"4fcqU7vPvrzBNezN6wZi2xiM2XL+bdwrD2XxYkKmAeOYvWX+QOueY1hB3zyn6nubPZgtPz5Fzy3rgrJd",
-	"YZkEdI3+5XPxu60B4KrB36as2ORCSPvm9c7DYoTAghv70cCWGfBtfJswXH6uNX8TPavfKFOD61gZ9qOJ",
-	"9VKdNlNCZpbGQtnX2GJdwt3lqnxhduepSCjQ8N0nTT1j9h94Rratb0zwGbdLfENxWC0FZhe07kbp2bkq",
-	"P06YVr6RubqQU+1sweUcfdulbWZIU4V5RTPepKAv5E0ODChREeumtSlGeJX1sx5U/fZLfOrq9ypNDGS1",
-	"FnY5ppiV9n0KXIM+9c7LBbPJyD/ecItBkqMhfH7iy5qbSdmGnzvQDj2TY9w3VYHklUhGyTeD48GxZ5im",
-	"H94IOlwaNp3r8GbBF/yGy6VdYCKdcXkzVzcL0HBTqCIZ/blKk2FwuJUyJBnUZvr8Mkd9wLc4keYlWGoT",
-	"+7RNWxmfg1y3FvhMqeS34YDE96JRvRO/c61koXtmRHp5dIo0Wl2A3bB64vQdjP1O5csHtdG1bdWsbWCX",
-	"rTaspWtqnkDfxtoDfVmw1Zx4cnz8BZxbdQtyN6a7IfuSEjcqvoA27XGdZWDMrMZws1DzOYVbA9eLN+N1",
-	"sVWQ63UP2x2ZTUtKRp8maWLqsuR66dVugw5eu2TO1NQ1qIZl0gn6HJWSTDmZINFhOGw/8qV35GwOEe1u",
-	"d66Z5As36bAWnnYnQKTmulP0GqwWcAd5pEvhefbiR2Espfr8jouCT4te54lpbIPruAgboTZnQZig9Hfg",
-	"rACufZNcT/qv+yrekkWGH0Pe6PT4YhmsV02MRXqkcBvz2nWIdZecbtGysLwOihII/l6DXm4w0Fiu2/AX",
-	"MXEcwzBgCljrG7dcCdvpB3UaeZx0B/0HhFqrVRpnC2S+hylMoZ+HpcmTQudGJQ87DPKbFz+O9qrhOjlt",
-	"V0U2OX0I+tcFel92DR9gRLNKk8WmG/AhzIUmwp08tlv8qHrlW2TWO+ICdY0xG1X6QFBnhv9kTFqn1r8v",
-	"ZE4d01JZVml1J3LXatKoVeOyB3SKdCimdTrlnGBadvlvYCNWSb7BpwXFMhzaeLlELbWqI5Y6BtvAosfF",
-	"GIco0yHxwT7wM2CfBfjGMQHvBvjhpqkwjvJ/V3pOoqU89wAYRIJXogSTdAWVxvxtPAc3DfkYdg8afBMg",
-	"VWI2iYUvnh0KSD2HPXnw3oVyneft6TbvnAizsi6sqIrN4tc9Hn5Xd5uGzwQiRyfrqmkjndOQKZ0zziTc",
-	"B3tUU8xiQh01MrOrcAXgJOARplPUDP2sbV36QNOdu/bJL3IM+xut9+IWsrLexy5UfYgIJiqMA6xr+Gcw",
-	"iVXb0CKbtJYX3bLSGOKHdsgpz27JU9fu0IdVXNOZhuuO4zK/lt4wENd9j+WAXUpjgeepbxFhNX30abax",
-	"64m7TLTN7reYPWXcPavfafRP31z8JRb8HAjsjZiH9TzMeGN+7SIX/yy78Dine3C4tDWSq3K+QeQmfg2e",
-	"xl2HCZ5DYT4S7Y3CeGN/EMjAH5XS9oguxmzNly9o0BY/HrnI5e6aWMUc9aZ6hEBUyK31oNMsg8ruUUMv",
-	"vsTCH3aYmbtGI0rjUU97JgenPk+WkVFM3Y6WPfsmuIUpzIWkk3x/9/YfIm87gPGmI/9qid0DMiMM2oI2",
-	"PI7G2fiX5DF5TPNSVeOm7Zlb5NG5MJUyIpz779qpmSiAOo/dXTt3Pmz4XSiv4vtYGwIy/frk7X6IiV1S",
-	"fiqIutgAQDQh3YNOrnC/Qad4sfqyfCQ8OfIxeOJmKzyFLbxyPdtfB6Qmz5mYPoe5PGcZHMIlnYOurrho",
-	"3ey7buuHBSfasar1nxtA5c4ygHzb0WefIs9sTYmNUzfImWmgR6NapCyD32teoGr+Zc0P4bMGl8j6e0BK",
-	"s7x2AkMoR/gxMW47Zf8giuYiJvvQbc11DNza1u4MkXF341S45vzDDN4dh0ajjzPnZOh49gv16LB4kWZC",
-	"1/q1zo0mD/Mwweu65omngupQYWtSj5+zlDD05zBmV/XH5TNOHuHU5oAaEB3aPyQD6bczoAF5rvY3Nnxp",
-	"Guj9WpDIk+eDSq6DtObWNKbs71K615LGm2+f/+wr7P/jD72eVdB00vUg+aIVtHp9h/uOHP31Icj7N4vM",
-	"cxa0dlxkepj0XUdv9+rQM59BBkxaT26CGKNsNDaq+Tq6YVX7plR009BQtt2u+kp71pzyUeay9abXkzuO",
-	"PRNu35wt1SuUvitx7NqD5ywcbd2IXmxw46HjCWhH61QBmbbuZu8+So5ethSSEsb1VeuI1fgSFd36saIo",
-	"WPgDVkTRXfsXhrlz/zpbrA/cXPuW/3MKYdp5zXXO+JwLaSzTPKMWdndJ3iDRd+fvRuyyrAqgv5Rh15NQ",
-	"wW3y5EW3mFY+FWp1K3FfZAV9iLLgst141jsGmV+Bsa1L2Y84TpTOeummV7tH++mOFmUeruXJNrc7ZFHV",
-	"ZnEU2uWi8OxvYdCVn2eE4/Ucj3SY/esajQsVX81z7uRi905omAvj/zZcXBc/hBFP1aO356+GUJXSTUkt",
-	"DXsb3rb8oa6nr1T8s2aYz97aF1TEn4v6i6CR0LpNo91i+2mC7tHptMsga134/lozGg7bLby8EiRkN8b9",
-	"nKz+LwAA//8C36uNVVMAAA==",
+	"H4sIAAAAAAAC/+y9/3IbN7Iw+ioonlOV5BZF2d4ku/H9S2s5uzrHiV2WvDl1Y10TnGmSWA8HXAAjhZtS",
+	"1fcO3xt+T/JVdwMzmBkMf0iUkvOjKlWxODNAo9Hd6G70j19HmV6tdQmls6OXv46WIHMw9M/34Mzm5Gzu",
+	"wOCfOdjMqLVTuhy9HF3MhVuCyAoFpRN2qasiFwa/oN8N/KMC64TEr4UUGRgnVSnkSlelE3ounFqB+FKV",
+	"wkKmy9x+NRZuqaxgAMStKgoxA2HBTcTbuYOSvrD+reixUPPWlMqKGahyIYx0IAq1Wq2Ug3wyGo9stoSV",
+	"xMXMtVlJN3o5UqX7w4vReLRSpVpVq9HLZ+OR26yBH8ECzOju7m48WksjV+A8al6vpCpe6XKuzOpKf4ay",
+	"j6CrJQiHj8Tc6BVBWKjyMy5diow/lfiuABwMwVP43T8qMJvReFTKFQJBQ4zGI1ydMpCPXjpTQbwUD611",
+	"RpWLEcJK0H0obTVDgGawP4RV81ED7dEhvMOX7VqXFhibxmjz3v+CP2S6dFA6/KdcrwuVEaJO/241LaMZ",
+	"+V8NzEcvR/9y2hDxKT+1pzQqz9Zfd0QsqryRhconH8vR3Xj0Xjp4o4hifhuIlhLpF8qafIl4PyKGh3kz",
+	"Nat/+zR+9Y4m9/Dgh2dZhgx5DgUwLCkiyf1Tpt3KgvnCCslfIlWsjV6DcYp3c12ZBZy5AXpDrkdK85/j",
+	"BtAH+UR4kWLB3IAhPJRaLIzMQKzBKJ2PW1/eSitkYUDmGz+EkGXO4kFZejWrjIHS0awIaM31uXRwgr+O",
+	"xgn+aSj553o11/WLevZ3yBzSyqvKOr36UTs19wRBCJB5rvAPWbxrIWYbacSD/ADWygWMemTC84kynlC4",
+	"pXTMuxaMyGQp9A0Yo3IQt8otJwLRzjCLz7CxQhregXgYgWuzk4/lx5J3yRVA2FwxLPTRX7T42cEv7tTB",
+	"al1IB9dfLp1b25enp+vPi8lCT3K4OW298ZUI/7IEyIYArCyI6a+/iskHCwaliLi7m475p3Nt4z8/lMrZ",
+	"+DEU6gbM5gdwS51HD95I6/DbMxf9eKnKDMKTeJTKv0drpJ/e3oDJK//S7VJlS1ozrNZuI7QR/wSjxVyb",
+	"FPalgfILJ+RMV05IkWsLE3GuFmCdpQXLwupm1fwknklZIcWUf7+sVitpNlPaPcT5XEGRC0STHQuYLCbx",
+	"KIQveyVRit/dTSfivDIeNGIGPDIJhBkIpn4HOQ+NNDDN/euIGXwZ/4/M4TGD/6SfxbwqMxo3giF83MIe",
+	"Igsf4mcRpsc84EqVlQM7Fa4yCKMoq9UMDEoW/0io0mkh68En4o3Wa15OCRbBr2mKtqjUTsii0Ld8xve4",
+	"FGXbQqal21mWgfW7qEvmIZYy+rbEfy7kDTKCdksw0dOcx4SxcBp/UEbk0sm+MJRZBmsH+U55qMob5YCF",
+	"mv9mjKgsq6JADUeRwEMy24DbT5SNR7+caJODGb18/uxuTLDYnZKoQRcjB1GYGZAHLsJ/cjCkfyKpx9j9",
+	"kRSM1IwkNPS8sxcRutrQEHd6rHr8DQLwzd14pPLhg5BxI1QOJQoCMPESVem+/XrU0yDjncDhCbDXv6yV",
+	"AbsvUoFfTxBFvbSDkf3dHSq+xmjTO8TaAP20BOKAmjO+sMLASpU5GGbN3Is75EgSd5YOXt3aogaWmdYF",
+	"yDIG5o934xGNfXGexsjFedhyz5wsPOMJxLpyKEFYuP3Hydvy5M+wlMX85O186k2Lw/brDwGq/YiRXt2G",
+	"8q9Rv9AF7M+G7/Htrlqi8pEfp0FaDGjN7ekNjln6eqvMPKuFRpciJJ11AfEruRG5bk6WQCUoFl9+LE/E",
+	"1IDMpy+FBSbrXLNigbSjLYilsk6bjaA3M23wXSIk/mssIFeOXp9rswBHX+FKoESz7ecRjo9IoddH16k9",
+	"WOgT/yOK8klvldE7J2q11oZ409s5+AnqpipD9K6lW45ejuBFVqjsM5iJXK9P/WN7iu/SnnW2cStjBW0V",
+	"Pw4aLKERWb5F5aSHN8JoEmEhEGB4t48IhirSokhpb51aLRh7Z6aI/o6EMI0oVjTkZLQF/Sf2s1qf6DXr",
+	"yCdrjWxngtnY4pSUID4TdqmNEzywMLA2YKF0+EcKFHHlLYJbJNmFDioGvttRJ0nVspOtJ+ldMHxTomCO",
+	"grlzOO3GSyRqqlI5mx6bHt1n3BdJ4cEz+cUkZQBJ9O/xQyizTR+ov+pbodkzE8yPBeAR4A8DD6syzKoT",
+	"8RPA52JTHxUZGiriB13mciOcFpcV/QtZXBrgA0SX4YWVNqUqF6xHrnTplr2hEIy1gRulK8uv9AZDFM6V",
+	"sa4ZTzXwf2H52P2nLiFmqlsCHGUpz5uWLvj2yY0ki8biZ7xexuNoPPqBP/Z/X9co9hp/ktL5Udh1DyOh",
+	"k8w8IQXCJjT+i4BLqKA3YOQC3kgHP7CKnd7KlSw3tRKO6nUQ01CfqGyGi1swIBzZHLoUfvyJIFPE/w7S",
+	"FBvBhrq0+FqwwYeP2m9R9cMxXv+yhszBgBLWWAwMW8sA/sIKPEHzqgCRyaIAOi7a8G+H4psABRlVe4JA",
+	"az5gEpRt85izZFG8nY9e/rxDKeiw5N11VzLBL94KTh41DCC+xGeusiKvYBwU5sp6vwuvB+mBbNl76ZWM",
+	"htflwC5CmQeq5jebffTSg3jaTsQFOYngl6yorLq5BzR/qKG5dNIMqNsWH+0H0cEAvCD567Xl76Uq9iPt",
+	"SMMmSOb0pXCaHd+lO4Ti/hTDcOldmYdCQIx/6Myo1zujq8UyPSVYp1bSQS4smGqFfxuZK12IAm6gEEYt",
+	"lk7MYK4NtOmXZPeUxyZH0TRQi14pcnSwjZRJNABn8VRzbWqS/8KmjtNmi3U1K6L9ZRTdQ6EhIzyCdscx",
+	"7xc2DQ6X9eJ09WZ6DM3q+fOuRtDIojarxGzcEotdST1OHTNdiutzAR2CZAj0ldBMl6wRZ7CLVsE6oxdQ",
+	"irV02RL4vFmCmOl8I8i1k8FEvC2LjTBQwI0s6daos+vki8YBdsvuvKdAJ50FrdEduQB3KpeI14EBvcW0",
+	"Cn73mkTnhZYuSaGRBCJauJFFevDwVMzA3QKUzcGfy43dlyFqiduhrw6+/CojmJIKKK33r2wXItTKwWq3",
+	"/wpHvquHk8bITW+0V5d/S6Ph1eXfvJ+0r3PJRW2lIj7gF7lao03XWd2YRBMdoWeO//92Pj9z40yvVlC6",
+	"jyURmXC34+fPno1fPHvx7OTZ85Nnz6+ePXtJ//1/4/HQSy+unr/Y+dLX+4z0TTzSKGUkJgnxjA8G9NdC",
+	"Hm4PVKPedXmYlpwaxj/yXvPGI0DaiCw3Wxnl23vyYGVhl630SByISoiniR3+Pj/JbdDDDtc3vg5zEd0d",
+	"OJ3Q83ljM+uWzMRTk6mpg9h7KEXf7CsjAtZSIoJuui+rWbS67jEi89wkPViIBbrYFv4V4chrmSdux7TH",
+	"SPt9CkKQ6zXI2sKYXumpv7Hx8qO+O6/RQ7+kOK7cy8VIWnoMKgNVw0jvhvgIJPjGKdcGvwfyJAXUGsoc",
+	"/7nVddUZ2IpbqdghQ8qqD3fAS96zduwDBRkoW3uLgYiqhNtig8NBHgZlu7/Unfu32rZ3WignqtKpoom1",
+	"UFbMtb8aqknaghOzTXzVjCOrRakN4moJpajWuSTw1wbmQCoIUbgBmaMWETSqhDN7D02sS/iBQlEZ4hCB",
+	"hEPOSVUkiPisvmsW/p1IoAIONhEXfmlqLn6mn+w14oFl4d14xL8lxi4FnZ6kYdE7bAVkEj/li/Ywxby+",
+	"d1/rdVVIur5yiMufPVzXkV6OuNzrMPcRE53TfF88e/2ilMUO6sVZOBTEv97b2misVzqHJLLCCyLTOdQW",
+	"Bg/+ZWWhAGvpZw6asl+l2M1fuCcmqO/i+fdZ8HfSBDvjGMK4KSn6Bu2sS6/NJBbWmE1skZHnn1A9fPLT",
+	"m61Iq91qIx6+OFr7s73uN7vL9R8wFMk160wmlysKehJd8G01ucJdgh/v+DcI8cVNQjCwM/FAD1LwOKL/",
+	"KOEK4ac+tkFMeY5PnoKm7aNReYFTW96oMFhgXYG/tJN4q75uE/l+YCfDY5LABybxh2UM66Tvk6mj3VLH",
+	"rrKtBelS3MJMrCu7bA1bu6hQ5gRXGr1lI8UkvhTnA1mKv529uzjHoJzG0xQFP1lVZiCMdvSJrhy5O/jO",
+	"M5MWEvGX9XKEXEhVisoGIYGTUJTf9F1llxflXE8nfSl3uCvhm1o277+BV/g+3nX46J8BP4h/Oqj2dDW0",
+	"7S7ArnzANxtCjIC57vAcRXkuhuO6GCF9+EnNWfBVHW5D0YE4W8qyhGIivtdGeFsSlRwxJWVqGgZABivF",
+	"tKfp+ogaKaa3MMNNbX3B+9x6n6K7/gxW5WD9VUlYRVAFWZn/woaRePfGXivzPy4lQ/RJ5dMAwqclyMIt",
+	"p33liuPP+GVPpcF7OZPZ50Y3DVNqZgblxGeAtaUbGx59IngvLH3EsVafS31bw2JAOM9h0qIS2T+WPKCH",
+	"kOtf+Yu78eiTyndFJvAqJsnjuH0Gtc8QDCSdvJe3Uexfnwi3RhfupU/1x0y5StLM+IVNErAdi4XR1Rpy",
+	"3PjWG+EC9rVEkcX7u6qs80p5a9sJQMQi7jd/OMZdNOAqU/Lg07+8vhKn8RT2lF9Fz2/DdJbdE/SgJ1pz",
+	"DbQQYas1ns9ENgb+Tn5M4pFXBujsl7i2JhSwwzIraT4HUT795cRCZsC9pENgGvipw0YYI4HE70gdhzIz",
+	"m7Vj0wQ2YY6yWXL9BrGZj+JrWEcSG/sPNbEL/rASVYl7sxiIh0vQ9qB23De2OLTe2+p8GyE7dMEsECy9",
+	"RXN5YYXTmu8ZOTBRlUIKo2/Z84pWxcu+iae9ni5L4cC6fQxA2X9T2CrLANhT0XM0W8gqp24A3dKVAbvL",
+	"4ZwIAvW3M2FJu33IhbSuNvf6k7Hd4MUKvhtm6Ko0D70N+EMMy5C7iADATevudutSqiaL+92R4SSXFYXi",
+	"HAwHqlcPmP45pXggBe6I0amPWH5bzIDMYaS9GhVJGp9sDX7z3rC2eyFWWFJEWoPc1Vl+GDIid2nItZMk",
+	"B6NuIG8yQ3rR4mJWuSCTfMR5DmU4/Mkm6nHa6r5w7aIcClgfcnS64vBBEwalK2KNsY/ytBf6LHUk9g4l",
+	"fxalhdNcLS7r/JLDlNB/u3z7o7isz9aEjjcRr9gbUUfmK5KlBsrc07wFh84w8l2s2sP0D5jtak1n2/Zz",
+	"eHbjuII9k14Qcdw0oTxN25c2aS/sNhIoObCytSNpMrBJv4LikKgEPditBHGwZudpMaHZxW+9a3ybwxZY",
+	"V+mLHaIfS1LscCvI5OiLCOlNtRtZVBC2LqEshDwC9pkhJjZrILPaq0ylqqO6B9U4sZbGqawqpOmDkmCs",
+	"geSdvfwRqcyfu+uI9nf4JfN+bN39I4D2jcrrOnKaHBVpkNnmjGJmMkItuMkBztYybbAcZo9QvoHRIUzt",
+	"cPfQe/72kN3AgxpD/gaMu7Mfz5qwwNgdESIzzlZgVCZP32j76axcQAFkj4TjP+unbYXDzuuvSzRiyWZQ",
+	"cQQiutUpAWksPly9anz2sRhLzH1fnbAn7xKb05V3hO003oJ9GPyIOH1P/jVXP30OzYFcocncYwtuPOCO",
+	"4x1Slick0mYXXp2PYEH4sYUqrQNJl3Xs5OAH/rChDylnylhkjsbVoviYqa3PfcU0fn1OU1yc3/deo3OI",
+	"roaOnKuusG0dNgYyUDcQoaqzNxNxVjL58dG1Qr5K64Kt5feuNHprHDpgw0qSRHaU1Eom156LA3+mWPKq",
+	"CAGlOWSUQrkEAwLwnNtGvwdlWQp0gMZOLJy2ZcjG/rbKQF47C3fZ81feFZtQgxPQv0Rvh8CMi1soMr2C",
+	"xsff8KXwzxq1XrwHiRShMMB2MxbKCWVxIMHXzdIGA9wPN/GzhOiv5DT8sJ6lCR5farPSJUcih5F8DvAn",
+	"XE2WBpsW2hgjtYa1ESVAzuA6LbIlZJ/9TH7USY2U2ScUL58o9UqVi4PmUYbnqIVU6zaAIlp51DCda12y",
+	"RDPgA7HRVUetCWp5+D4e/xMbhvsDrEtgcGu0J9Q0Kz7Dmu1c5BbU7upsdZ4wwNK9MvKqH8fRC23qAP5k",
+	"gDtDgvttQ2zyRmhyhKmSHT1dQqAU9YEF+4CDbqA4z+IHoMXIoggeyFw6yeGufuT6ksc7IfjrNnfH45PS",
+	"1c4haLFYFAwZ/ZSm69F4NEiLKBEiVI/Goy10MAqaZ2LKDgbTKQ5ecp98g6Gs76S1nyGZt7DmR+Hor1PT",
+	"C72gSyzllvHRyxTHLtSEpr5n9mmYlFIi8/yQfMjDPWZDVwEBiIfkhyJ9fbBDQeKNC6y7aA7L0B7LqUzR",
+	"Ek8Weu9eQWX72esepMNTkoKBvTU10VPdqyVmd5QL2BHOL8VPMDur3LIUGRhY6XKToDD/ZCjzNDyPdjXc",
+	"DNB9Rsst77SYq1JZf63lP93lfGSiG9Di/EMcekE6mxbTUt6ohXTaTLLmvmLCuPvyK07DT7+zAPflV3Xt",
+	"gUyXXJlHTNfVrFDZv8NmKmpvzr29O50NjlDcLDa5v2Asct9A0RqMzygXJwV5J7l6jQ9KKut4t0iySGHB",
+	"2sa3gLgEE/vtlbPCZnoNlqsI3F/+MDT3Sn4/PDMS9sweZ6AGksdZHPiHB0uEb7eIQZ72qYRgg3nc/yML",
+	"vm/2FnyhEtJWVzXTWnowftYoErwu7wMj0p5rE9ta2+yeSxxsm/31h21C2IO5UxrH9uxgqu7FuZDW6kzJ",
+	"VvURtrWb5SbVZOTb4PkNB5ynq008SjtwE7m6P1whHRihy8nHsmPDtIqKLWWZF96QKYVey39UIIwsc70K",
+	"WccLKMHQanQZQ2FVDmMOYuhWMrrlLNdMGwMIiCDaVJSkusEjYwFmbRQlMk+4Do8BjpLPIQ+fh4kZYg+N",
+	"KsW/yRt5SQsVyr78WE6n07+jINqsnZ4w7B8+XJx/+dXEFiqDL5+NxZ++EtPptOVN+uN3330L3/3x621E",
+	"fPLdd37jMYxoOHAqvrnvBNv6M8cKVTIvttRnH9N0S8EiJfCWN6FNTqdisPppr02Zrkua+d/TquqfpYVv",
+	"vz6BMtOIZo9RbcQZ8sufq/kcTACYrR7x+tX55Zl4d/Lim28Fn5ntIC4mPF4u0VRlCWxZuSUSbob7RwZd",
+	"BGQdL4PerTVkKDbzMdkktbOQbuMGPmRNpPJxYRxaFk1IL6JkAI4bUmVWVDkIKf7tpyth1aKMOZOI1K41",
+	"hX6LtVE3CPJn2HjHGC734lL8+PaKtxaF4OtX539t8LDRVVi2D2NgNsEqORTytNIG4v0fCwsgPo4+UMwa",
+	"w0/w/MQ+t4+jZHj6Z9jsrhfSBNqh0y5FGdPmHizE0jkCsE51nNJM0zBkX7g0DgYWmQ02EW/Hcsomyfra",
+	"M2Q3E2IoKi2+b5F9bmqti0RRc/HmfVy02A6ToyjFvetCMnEa7yG//GoifuhselOaqkL7270UoaJYjpG7",
+	"yM+Tlf6nKgo50WZxCuXJh8vTXGf29CeYnZ69uzjtznbKsw14ky/Odx2bXQ8tlDltyGAaMz29d2Ti86DL",
+	"sf9OrWCLQiedV9qJ6WLi8zpc+5KUvrkNad+t98NRJyuncSvoGAy+jiCxZ0bf+nv8R9FjXxzOv8096bbY",
+	"2nbkGPJ8E/UqYmJpvLC6BPb3xOPUV8007VJSAsrF+bGqm6CjOL30esG2K0B7zJo4+Sq3TGdzdI4D8rcQ",
+	"rvjVGSPLm3PiNU8bBMVPMCPW3hmMsX7xzbd5GoLXRYF/ZiKrzA2IczWfK/g//+t//xWKYiXL+DT1ehWf",
+	"svz6l17qcA22Hy8ur3ANOJ15LqA19FfskTdgq4IUwnBLXWJ0nF6tDVgLeZPFcPbj5YX4j+8m377w+Z+H",
+	"hYf4NY8Z+dcpu3k4N9YLm0jWeNpAuf4eqOzAZiDtxMcWn+BZS3knW11u3pdaaOvaTjdxuZYZ+FJe0i75",
+	"5lJxdKTPjdorD6IF7vGzIdioSaEBH4TFr70PQUhf3y9lR8XO2bXRc1XAS19DilzP7T9ujXLgSwjnVQH1",
+	"Dy01NHzT/pFf3aciFS/vEdDGTpAk4iL/iByI29rXAxLGegIfCAoaXzBrW8RecxgG2IhEVjKHuGj0jti8",
+	"/d0tYZbjOlyOUzjrKoLvqTwzMUUc5n051CWf8mU0dBtB3dBNyqFxuZQGvldQJGP5PisuW0PXQ17YWPyC",
+	"801R3mIwBxe8I8ExfRmVUeIEaxTF3qigb3NhMFobr7N8Unz4KvwZ9CD/Og92IqaU3hZe7mTp2a3zMAYo",
+	"8S/9uQguqLCTIaFWr6GEPJKdoQ5fADak3dkG0286aXiN+UbofqPKz+mIufIzYxmRaoXVK2jVoPa567wb",
+	"Gv0opMYtNflc1APukqJNfUg10z+yAbGzBJLfH5qeNule1zN7C6lmcff1/WI5Ia6Wl54LGcV2OMWEde7t",
+	"wWx4cYsbE8XzUtp3Fz8OHwRSvLv4kcLaAHJ2KCEd14S9Vf5v83NHqHyISN3Pv9xMtrva1I3+fDiZ+8+S",
+	"DnTt7lUGzO5ZcuvhHLDdne3LGQEVMvKUW9NN+6Ro2GjwgECJNVQZFQ0t52C1djWZxYdEuka0tYfvVi2J",
+	"D091WBhZul25DuG0oVAIaTlMsfEwzGVh63xXZC9859bocrE91YHyTXT2GfK3ldsOQWvUocQK725XtkY7",
+	"j95BWbjvZBoPp8MwmF/7DNWzxWCxNHws5ILCTCiIhjakNy/XsznoOjwiiWazYrQNUmZ+Lp1MgzugtNDZ",
+	"OhHn9VNF9bL97isrCpg7gSneiRjq6HjfUdxti1YxQMw76wX8MZRq3KMmVFRJ6snO5BdPeyYTYyGWP+xZ",
+	"RLarMe7SL/xb+2z0oPY5FtU6lAXfQgV76Qdx3YotCsK3T3oM9VxD7aOnoYfWZqX4GSvt6cpdkAfgPXmy",
+	"0itgLxcuwQCSuL+mCqFt8AsO0OfemnX2rLFK7lGeAVI1H5natdlHv+YXac/RFt+3Oct4hObgGvKB5Oh9",
+	"czbrgEZaWIYXPr5EZFgf+x+VDddyyeXegLGDVy3+YaAsabKlugldOBpHdgcT3LQlMVuHqsLULZyPa2Ns",
+	"C5qSpAbWteKk96I2y6Whkum2lBfs6x40SfFtAoRd2a8hJVD1s/d8tiXfJVallRgP5HeVQpJzDXyOyY24",
+	"XW6O5bNfOre+dNJVA/T216urdyhSXGUjf2wPeu+iE/6Wtb63YIW7/SvdTTAHcisL8FU3u9VO531UDKQh",
+	"H17kY8gA4tIL7eoHB4H1IA/gakvtP362qzBDRzG2A1sba6XDecdNQL2F0k0j86l+pY7WZgadChXD50vV",
+	"htyMdl7LBtxY2CpbChmCl8tuBbtGf2tqrvkZmWECULHMa4ATU2K8KXKNDWwTHD2Wa7h6yFFi+yKuexVD",
+	"74oYXxG293M9evfJ9/VsDaV4n3hvEN7FbcbhKlQZ9Dt+nZCCV9IswO2RWNM6TVLSMEpzEmdFUX8gTQhZ",
+	"pPNpScE8NiTepTKB71fhI8W9b31WVPLOOqZJfxOqLDP7Y/PudriiCh8EUkgOvvDhCLzDlKzFr04jhD64",
+	"cDK1z8OuZSl3ZZS2IuzGOlj1N7Goy45tVWz5ra2OoZCdK/cobLBXR752Xz8uAkDhAO368NJ+9jKdE8Vn",
+	"cC9D5f45l14CiunrClF7+k4a1cq3TBQICQmWym1Jqtxi+6Qzwf1mphQqpJFLulpN37nhE7psrUr1j4qX",
+	"FpecYy+Lf0/ZKN7wlkvELJR1YEIaDiUqctEX20R80KBcQMzy0l0cRdkKR5MBuxr3tanURBk/4ZL4qgnj",
+	"oxlJVbZCiqW0S06dCSOE7Em68KpDMXrAaw+gopjNvW6YPVqPf0mKe/ZhjRSc2jPGLuu1nlX8rfFEkI+6",
+	"aQaIFEiB+iX9Rf6TqFjPU0gFuCXJgNEuxmBwFSL4dqkc2LXMOBffqNUKch8O6OpMhlAA/Dk9+PZrXLmR",
+	"mQNjKfZ+pw61nbUjCNtcPszZdR5q3fLHgrNe/ny4etWOeqJgxHpUj3jSob07YrCgwU7vco/NQ25JUzJq",
+	"QEt+F1IrmjenjWngA61qPhmLEI4qLdfw4DJTIZBuemjdDQTeQlYZ5TZUuYLpbgbSgDlLxgu9Vv7mpL44",
+	"b1oFT09Rdky57Uw62MK/t4JT+hu30Qch2G4ghk93rRMzJuJdakj+joRK/HE3n4NylTl6fdzIRhVF6HMF",
+	"O871c0tYUdGvs3YrM5m5OrJbOsl9kJsukBgh5/Mvt7WaY0VKukD0F+edytq+b2V3IZ3aYgzZrIAp3m3a",
+	"Ok+Ae+0V3JekCdvzSGu3R6wjbGlPmh9xY97SuqL5uRwafc4LqZtnk7OcqKahQDSJuaux8gHhvhxPI3FF",
+	"I4xrn8no2YjynqCUazV6OfrD5NnkmZfWRJ2nnzjwqFXx7fTTUi7lJ1luSFv+lMny00J/WoKBT4VG9rsb",
+	"j05DCNxac3HUem0XOQpzfNpu6f3zDv++brU7X8nPYed9AEndHLvuMehPIjxUTvgOYVtL7GtWMMC6P+t8",
+	"c1DH6fZRYmulY9tREqknPXcl/9xXatov+tIZrT7eL549ewDkbrhLeUsE7Sx1zG+lF9Ae2xcbw65tGBS3",
+	"WFAA5IQLBMxlVQwisl73abt5eSxoRy9/vh6PbOjuRWTX0bdYQM18Ap5fJi5QLshoxndG13eBpE99auXp",
+	"DBaqjAm8va4/42Obyn1s0kNDEVo/pK9g4KNiG/2P1RindyUThlI/3jsYcq+nbbg5MXIqJCoSUbpBgI5j",
+	"a9vcSovxWZ9vNK77gWS3Nfq7m126i2pmsOAgywUn4jwW8RAWonk627cHzTDuh6Xi9/S8g+j/kjJy73Tf",
+	"i/NY32lz3zR1O5G19MBthJbQHLemzEYj/49ofhLR3GawQyW18XHQw0L6jeZadbWLv91wKAxAtwjWF9Ft",
+	"/cjN/mN9mPpc9QRoCMn+r6z2ZD46fhvLtULTe8yGP/4PZz0lZ7WJeX/+Co2CTqLKV95P3yb8dndf+1C9",
+	"Yb/2Y605+zUYd+yHAWcU4E1Zv8PS42zQG2UdWZHyRqoCrcze1NE2cMRt2AjdVHMtwEF/B14VII1v8NfD",
+	"/td9um/hIsOPfTwVT3VEHLSdHz938zqu71pIonUk2sGFrxIYQodMbbjXoiNNpwFBHdFMkvUfFZ4itWAN",
+	"0SQNWQ5FtOS+UzhZ8b65utM1hZFX3wvfvSsm392N02BxcMs2oKDMHwmk66NK5Iao96zIqbd0I/HUEjq7",
+	"d6imcQaFYNtWF95S1x+AI9JfNr0QDwGuDnzbBmO7wWEcvlXvSBQWxdco7CL0n1DHUKHrv1+Xee0MXxt9",
+	"o3LIO4UWcNkTlIl7S8VOn0BGzHbOjpK5Olz9F3AJnqaDyN8EFptQhM6jcF8+X1cJPr8EF8nC+6k9+5Di",
+	"PkrLLuFrwf0eBO9laov23IXmiDptWjqmz6nvtVnQ5oDdTwzjgHThOOqiepzSGNLXrjbCsI27qoecF++D",
+	"CSXo9g6S66gc1wfvvp8xwPaI2x9nV3b2/pyAECvM4F0XDaLqmvSeKA7mzaQ5RqK4TuePrkkNZNrkQtKV",
+	"FY8s9Awd2q1giTYwvh2Jl/skN5XtZNuHe+e+tWbyc+59+Wh+Lhp/t9hFUGoymDxgL98nkOj0/Xexw9un",
+	"vwaGvGuzeWKPa3TjjsyMjlr3Y/020lMqf9G7lobKkXA4oizzj6VnSzzVvNk9ERdccGzsbxN9362f541U",
+	"uZ58LEfjQakzIHTo4ronc7aKnOM3ln2I/HiSE2S3CJFh7Q8WHalj/XWu/rNs4v10jr11zUE1mKIq8oT0",
+	"nBxHWwkT/Nb0xuEjDb2p8igijkO8T+qA/aQV+ZpeGtBh+nsS4tB1iECPqMsDSm6hAQcfZr+t3Q4q9tgf",
+	"OfjFnWb2JgomjX7qEd/13mbn0axhsmfalooH34Zzirz+VAJMz+ump7+5zbwH4LEW8mRG9QFW6d24oYb7",
+	"jYHt/O9jQ8bt/JnAaa2veJEn58qutVWhmta2nZqrAkoKPNLI7RzoZuVN8Jfj81RXQAT66xff7ZZQ76WD",
+	"N2qlHOSNnHpcCZcymV83siLpNzhctqlVW7alLycvVvcUbmpVA9wRbtIOCrdAAFfcJfRpRNz1YzoFHoPZ",
+	"HvNepE4N2qs7OBsqOzPA/GvhVO7wZJTX5XsFQr7viDJzFdl0TG6QCxvJnsjPp52Af1SyQNL8lxoeku7G",
+	"FyDzrda1EXnFCAMBpTMK7B55YQEV8SKud8nGGuqUaLy/NsRMK6R4dfk3wrZPKHyQvKAMTbvNynvvM7zb",
+	"Wc5NX1m8Ni01xbKCofZ1lHXKJju9zBGxNkTSYcCMNBwLnTLYcbqmkMke4qkJ9O6XduDDHYesxVNHb6C6",
+	"BsMyaVfpifvYcwxQN7X9wTp25B7A8RO34M1kyVuogQuVejOe5s6v2fv7X/el1zxGfwlYJ+bKWHc8fNOl",
+	"3yFoTvvMXhmg3AI5WKqnrygMFutp4r/Z5Z7rzGkTcSWnHNDwLL4p6D4EnjV3E00cfrbUFkqxBAN1mLt1",
+	"em3FrTYUy6DLDPBXn5YttOE0IiL3Pq/zgmNeP074wj71COIFat6xvR3Cj12F4LBqQA30Dy0DNJgLccaF",
+	"c0LR/7gcB/Vsu12GLBWuqN5bzlolq+VhRRI9F9/iIp6/ELlaKFenXQ5VF6ptNAqmHNe/1+1T6fyJS5FO",
+	"OA7agcFp//+fn518d/3rt+PnL+7+NQXsvtUN7k9CW2sa1OV0tlXOOb7GuJ9bKhbOB0TfuNgv3JDPF1Z8",
+	"eP/mCJlQSzAwuXdUz06NLlSxfawDmwVhS8XaHiXi9bbTX1V+d8p61RZfVqfIEtiHalW1LtX2xj6CKvWY",
+	"+gVj44FaRqPUPpZ2Ee7Vm5lwQw5W7pB4VrBN02f3vk2lr0YR5k2qjzK+AH0d5khJb6FBWOjH0K6MWzfn",
+	"97FyKKIpY4l7Axq1WDohb+WmDoJXJp0UZcfxkuntKBGHNJOgmFBO0tUS6vUEKUK5vBzpGXVV+MKKhZEZ",
+	"4LxKU9UmTc0EPlA/fLcMx063LLBcUMBdmeOPGRR1ypCvEo4JSOev37y+ei1C3hA+qbsHycLqUDPF3n/t",
+	"E/Fj6AAI3vaNWtNSMoEXxSuQJTkyxduAgghFjJ1oqSHxHomPKpMOd4zrFtXuxRKCg1fsSqUM8P+OGTvb",
+	"IDxjxJ57EtkpleI7wrCF2tTBUXmdX97EPxxLQPFmtpzjbfHRDzwdsDS7BPFIuP3AOcqHiPl4YUFfCdnk",
+	"pXahAUg+FlZ5IyjIGF8BSQZ95hHv0lqFtwfCs+KVpDZmLV2WSGDlu7iWrdg+fvzcjbno1fMCGr+9AdvO",
+	"3RavKTKWBkSE3chC8d3jDObaADXz4XlW3gZFfTthSzJ8x5Eou2iH53pqibAX1cZXty2aPRq38+JT3O4p",
+	"IB1m3k6T3aaGvEbrVkbnWbcVqLwBoQ1d2nsa08YfuJYyqDk/X5U3ysHQ2XNeD79fiNoO3TZRH7KBP1Kl",
+	"nfbydzS+lz58UKhIDcHxdv91mceqZ4cC4h0elvmdxB1lne3Aa1Nb7rSglHE79tIWN7m92bbJnNRlKLTH",
+	"H9VjhEET7qhGeJy3FvIkeQ6BGo/i9IyVweO7OQ/b9R3OztLvHW8WMjTWXNFk30sKi7DUOSNovwMxxrm2",
+	"9TWI90pRwJkrwXBlhaxQpcqULPn89rMGV6WcO5yQmir3SeOCXva7BEdTVmVd9Hg/4ggWa7K+7e/IS9Qi",
+	"56HkslQ7rbjCX11BAkoHhrae6UG4evuezHU0kNB2kOcoHEjH4kcmSroc0CUE/Fib5FHp5D4H8ymjeEu1",
+	"B3reOj2Pm3PYJwvPqW3q0LdlfTB4gZDy5a4UIjAu42ZbkQZUdnu8pTwhp2+iXb4AZ5upv7CRhU0CiE3s",
+	"fhXs3yw3cl8O3UHCTBGPRMNMTvEpUFNxXCFmHwpu2vSl9cp/B1innVsk+tkm6ZVjazrWXy2DAEGvkhVV",
+	"cAb1XSYJ3YLU046GUfPPYYl8NFSk4x1Xw3uV8Fyltb1B695vis/1tvv4HDuVM2rHmnWY3BTX2Oh2t08p",
+	"94jgd3Uph8fQ7vuN6Z9UtQ/IOrYPZ1i1rzfzHr6cd823j69Kh30/ih4dVv34SvQwfjvc9LBqNTLPH16l",
+	"pm6Lv6VQTQzxQ+vUvKcSh0am5eVvXq5G5r5u99G5kpDQG78biZz25HXJ5qCCNT2M/0aFYnp0/wh1YvYM",
+	"evBweNsyalIeLIvdNsRvWIFmL4m5SxHM88c6fpj4HkbpoQDGCZV42Zaf2igJcRETO3rQkdypMPMEtyvt",
+	"Ge9zMO9Y/kNCjg2suMX7PkG/zSqi3hildpw1ugG3T6RumO/gqi3tk7+BrCpp+sfa2HAh00yY2OMkDPt7",
+	"2f7iCx1zhdj2KGNhYF3IjDiu3HRcrksZ7mAGfSxL7mzrr2d84yh/u7/F0dKmxgDhI5NjLRP20iHbtY16",
+	"imTfnreHU11Tg/oxpUdAb2L/65NtL+nKBtcp178e1j7fE0m1g0j401CnCAHRJXDlfrbwbShlq+PK6O0Y",
+	"EVQ0W6EnQS+gJ+kgCRqjLrvOF7NEoViTdomuoxlA6c+12aZ2OyPtIxy6yFvFuiNPhFCWA9jFVevFfjh8",
+	"SHxnGzYR8K6dJ/+6FPcR6f5xwyG2CVVaVu6jathYP1p8O42dEpc2oHCIiJnQ9lMOLuuyWY/hQej3Dn5S",
+	"D0KNMMbIE3oS6k24h8Jy2Xz7BIGCfv+Pk4ZwdEQPeRKG8dvhglNZFCd8O7rNRUeVFWux3InXg1/If1tH",
+	"hm9rCz5YiTxkA/2/Hf0h0GurBcMXcSMHL2WG/IBUins73dyDZ9qH1tE5pznlhnY2YD1+RZfDgReOW99F",
+	"8cGdiAtKQ7W8r9w2R850xRN0G5mUlG1SN2CjnfaR93G3t+C993ojxz6+e3t5JSKIpnx8hrGQfSU7r6Yr",
+	"Wao5Eg0y71QwwDNubDHuepLaASjjbmWwced2eKhlQedJndrqfVwrcDKUrw9eaN6QsQDJyTaSGwxQMmQI",
+	"Oo1cZ52YqVTboyZx5+zdRR3tiTxhIJcZ0mJVFmCtmPqgs8aZYeP2PKl6Br4J4j7x390Oe63KBg01HKek",
+	"QSyt/6nWUdpv/0n8yy8nTpqBCgftJV0wrphnDtoQobgeDRuruAU+nG01Eb4LIFNivT/x+LhxvsdXI8cC",
+	"bpVtdYBM6hW9PR4lUegvMnt3j4eF1DM+W0dlrYjMVCnNJunl6m7SgQNsP05DR8aY8P57lhhI1Q3oHBG3",
+	"S13AtrjbIbMtxE00kr3G+2wjpn953RbcY7HW1qpZsekL+zEfEv3Dw0t7/qPukDM3YJdjCkVn8d4t1+il",
+	"OMWxawvUuo+EazjV68KINTOJH4fbk1JPQLzC7TQljVm1EcI/wUy8q+xS2GpWY8yO485pvq5rHfmK4zfl",
+	"XykbIIiJpbZt8eO7wLLgmIjXcf9BcrFw0NoMmg6EnGWQiFFaPUzCe0nY9Lc9VjGHowv3vWs7/D+7pIBH",
+	"wbguYsCo4Asw3wBmGi95GrVw2UuwPZ0bP9XgeLep4jW2lmz9PYg4L5H6nZcb4XJgOAKrUvuZ/le+Svhj",
+	"GP4+F/K3Mftp8qcMG0hafPcx/a/Cl08QQuCBZio4TiBBEg9PEFawN/531iTQ5eIEK5nnnoJjy4+cnGT+",
+	"cEKKfwX7etFg7KVlJWFt9MLIlaUGir2mC8GT0G1EppytG3Vhm6+hagIx9x6pmsCe2f68ZP92KkmdXyjJ",
+	"xRxe27sgwF531DRDnZNPWKtrrw5k5jNO00vjZ83FEC+g029u77oDONjOyxQPzu8nlLkrCw5PencWivl/",
+	"rhz3I58SdXr7QHPDve+hWr1oT6lZ96nXiwedW6FYFPsr62IRXuHzX9OIvvs3lSy21Fz6IhRgkWJNdUL5",
+	"CmtWORf1TzZcBNVr7eO6OBLNYzNZlmCsmGsUW8G8pGe5jlqL48brlptzRg0QE64cBPMVf/Uu2eogtVXN",
+	"K6fxAJ6ud7sJqM7Z0q2KNhfttOU5WpJrZMToarVaf6x2HJdLfStcAoJur/eI3mIi23Iy/iAxCLi3kq60",
+	"xCw1coQjlUvbmHHjuIgWVQCxvlM90kiL0reTwO97+9syJiz+6Sjg1cEbPiRoqtK7AGZwgLCJvmoJnrnW",
+	"jqM+aHR7L7HTDK7KxcGCJwZtOCuOqOVD8+r9JU40yG8idVrYejKJE2P54VLnPaz0DRwqd+pC3UM3H6x/",
+	"2Dq9XVnOeVGrdQErRIZ4//0r8adn3/xJ6BJOqBFts7S1T9M1ulrwjcwUDZKTaMdP3mnrYu/Jdir7/VNY",
+	"O+26mfgJZduHe5FWX77t6vx1Wa3ZFRx7VY/UBmyb7p2a7jDru67n0iL8R+4BFgL76sltwF8SjEN2KmLZ",
+	"wd1CCzjG3LvomyfarHjKe7lLhpvKP1rtjBai92lwNATjVqFeDWwYV1XYtm2P2WxgcO965u4nb6QdYexk",
+	"bwM//DAB1MV/Q1xdDg7MSlHxyyZtM8FovjYGfstZZVRsZIJ39MpGJUioVR/e/oS2VVzr0tvkYdpFJY2/",
+	"irFOULWoTJe5YoX9Y3n19vztS3ERzk/h6kmoScP10Rs1pKjy+IJuO9ds6+LwIM7pS0IH1g2HoV4CFxHB",
+	"t9rTtS8m0wEYmnzyUdHVlVBzHE2aBccdkVdvIs78N6FkJWrVVt5wzDr5NkmLQv2aW77NNvipv+ZsN4Rj",
+	"D5HPo9JzDsZpjd+ja9TxQsaVsrWnaIzflghyawlzqQrI6eL0nSQy9gtmINn2hD7KfFENGRXh7r9EgPse",
+	"XKu+eof7cQXWxcLgkQr0dKe5ok3zN/ePf3fQnT5cxO2+RMDt7+M17HHdspjow8qNreWdcvSW7zPK4omd",
+	"87S/vy33486nGHE7r68ruzxR5VwPajk/wQyv5S/wncfMjQpz3FPvxIW0NxRX5R2OT6aAboVi+04YygQE",
+	"M5w4+D68cawLl/RVB7JHCNgJQJFnZKdLnMb7/dwocB2v8dNF6l//5m2rA4n49I+tN/VU1zI//ZXcGHeD",
+	"nrb3dNj5wlFUB5uib1pNCtCH5hXGXAMX6isBbyqbNtpKl3y+8XkrnYPVmmOLQ78+1dSe/iLuYjARZ1Q5",
+	"4vkzocpMGwOZw5LW1BJDCqNvo+LUCj/JPkMean0a31dBJYI1366hPKgFwvb6yulqvS66JU3HLuwMqsSp",
+	"fQ3vbl1wNQ+RXL4AdzKa6D9OaJkn71Q5Onjmp+6s/0hHDGEgP5dO7jxkfGnzRBnq377rUYvhkYB3l7Pe",
+	"qlywHkEnGFN9ZQrcQOfW9uXpKbwgHySYiVyvT+VakUjld/jP67v/OwAhzlP2jgcBAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
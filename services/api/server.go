@@ -47,7 +47,10 @@ func NewServer(
 
 	requestValidatorMiddleware := newRequestValidator(swaggerAPI, openapi3filter.Options{
 		ExcludeResponseBody: true,
-		AuthenticationFunc:  inputs.Authenticator,
+		// Read-only properties are set by the server, but clients may send
+		// back what they got. The handlers ignore or check them.
+		ExcludeReadOnlyValidations: true,
+		AuthenticationFunc:         inputs.Authenticator,
 	})
 
 	handler := openapi.HandlerWithOptions(
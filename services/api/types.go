@@ -2,6 +2,7 @@ package api
 
 import (
 	"e2clicker.app/services/api/openapi"
+	"e2clicker.app/services/dosage"
 	"e2clicker.app/services/user"
 )
 
@@ -33,7 +34,11 @@ func maybeNil[T any](value T, valid bool) *T {
 }
 
 func convertUser(u user.User) openapi.User {
-	return openapi.User(u)
+	return openapi.User{
+		Name:    u.Name,
+		Locale:  u.Locale,
+		PurgeAt: maybeNil(u.PurgeAt, !u.PurgeAt.IsZero()),
+	}
 }
 
 func convertSession(s user.Session) openapi.Session {
@@ -45,6 +50,66 @@ func convertSession(s user.Session) openapi.Session {
 	}
 }
 
+func convertPasskey(p user.Passkey) openapi.Passkey {
+	return openapi.Passkey{
+		ID:        p.ID,
+		Name:      p.Name,
+		CreatedAt: p.CreatedAt,
+		LastUsed:  maybeNil(p.LastUsed, !p.LastUsed.IsZero()),
+	}
+}
+
+func convertPasskeyChallenge(c user.PasskeyChallenge) openapi.PasskeyChallenge {
+	return openapi.PasskeyChallenge{
+		CeremonyID: c.CeremonyID,
+		Options:    c.Options,
+	}
+}
+
+func convertPersonalToken(t user.PersonalToken) openapi.PersonalToken {
+	return openapi.PersonalToken{
+		ID:        t.ID,
+		Name:      t.Name,
+		Scopes:    t.Scopes,
+		CreatedAt: t.CreatedAt,
+		LastUsed:  maybeNil(t.LastUsed, !t.LastUsed.IsZero()),
+		ExpiresAt: maybeNil(t.ExpiresAt, !t.ExpiresAt.IsZero()),
+	}
+}
+
+func convertDelegation(d user.Delegation, viewer user.ID) openapi.Delegation {
+	role := openapi.Owner
+	if d.OwnerID != viewer {
+		role = openapi.Delegate
+	}
+	return openapi.Delegation{
+		ID:                  d.ID,
+		Role:                role,
+		OwnerID:             int64(d.OwnerID),
+		OwnerName:           d.OwnerName,
+		DelegateName:        maybeNil(d.DelegateName, !d.IsPending()),
+		Access:              d.Access,
+		MirrorNotifications: d.MirrorNotifications,
+		CreatedAt:           d.CreatedAt,
+		InviteExpiresAt:     maybeNil(d.InviteExpiresAt, d.IsPending()),
+		AcceptedAt:          maybeNil(d.AcceptedAt, !d.IsPending()),
+	}
+}
+
+func convertShareLink(l dosage.ShareLink) openapi.ShareLink {
+	return openapi.ShareLink{
+		ID:        l.ID,
+		Name:      l.Name,
+		Start:     l.Start,
+		End:       l.End,
+		Fields:    convertList(l.Fields, func(f dosage.ShareField) openapi.ShareField { return openapi.ShareField(f) }),
+		HasPIN:    l.HasPIN,
+		CreatedAt: l.CreatedAt,
+		ExpiresAt: l.ExpiresAt,
+		RevokedAt: maybeNil(l.RevokedAt, !l.RevokedAt.IsZero()),
+	}
+}
+
 func convertList[T any, U any](list []T, convert func(T) U) []U {
 	result := make([]U, len(list))
 	for i, item := range list {
@@ -166,10 +166,15 @@ func routeBodyContainsApplication(route *routers.Route) bool {
 		return false
 	}
 
-	for t := range route.Operation.RequestBody.Value.Content {
-		if strings.HasPrefix(t, "application/") {
-			return true
+	for t, media := range route.Operation.RequestBody.Value.Content {
+		if !strings.HasPrefix(t, "application/") {
+			continue
 		}
+		// Binary bodies, such as archives, have nothing to validate.
+		if media.Schema != nil && media.Schema.Value != nil && media.Schema.Value.Format == "binary" {
+			continue
+		}
+		return true
 	}
 
 	return false
@@ -0,0 +1,232 @@
+package dosage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"e2clicker.app/services/notification"
+	notificationapi "e2clicker.app/services/notification/openapi"
+	"e2clicker.app/services/user"
+	"go.uber.org/fx"
+)
+
+// digestCheckInterval is how often the digest service checks for users whose
+// digest is due.
+const digestCheckInterval = 15 * time.Minute
+
+// DosageDigestService sends weekly or monthly digests of their doses to users
+// who opted in to them.
+type DosageDigestService struct {
+	reminders   DosageReminderStorage
+	dosages     DosageStorage
+	doseHistory DoseHistoryStorage
+	notifs      *notification.UserNotificationService
+	logger      *slog.Logger
+}
+
+// NewDosageDigestService creates a new DosageDigestService.
+func NewDosageDigestService(
+	reminders DosageReminderStorage,
+	dosages DosageStorage,
+	doseHistory DoseHistoryStorage,
+	notifs *notification.UserNotificationService,
+	logger *slog.Logger,
+	lc fx.Lifecycle,
+) *DosageDigestService {
+	s := &DosageDigestService{
+		reminders:   reminders,
+		dosages:     dosages,
+		doseHistory: doseHistory,
+		notifs:      notifs,
+		logger:      logger,
+	}
+
+	fakectx, stop := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				s.run(fakectx)
+				close(done)
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stop()
+			<-done
+			return nil
+		},
+	})
+
+	return s
+}
+
+func (s *DosageDigestService) run(ctx context.Context) {
+	ticker := time.NewTicker(digestCheckInterval)
+	defer ticker.Stop()
+
+	now := time.Now()
+	for {
+		if err := s.sendDueDigests(ctx, now); err != nil {
+			s.logger.Error(
+				"DosageDigestService: error sending digests",
+				"err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case now = <-ticker.C:
+			// keep running
+		}
+	}
+}
+
+// sendDueDigests sends a digest to every user whose digest is due.
+func (s *DosageDigestService) sendDueDigests(ctx context.Context, now time.Time) error {
+	var methods []DeliveryMethod
+	var methodsLoaded bool
+
+	for userID, err := range s.notifs.UsersWithDigest(ctx) {
+		if err != nil {
+			return err
+		}
+
+		prefs, err := s.notifs.UserPreferences(ctx, userID)
+		if err != nil {
+			s.logger.ErrorContext(ctx,
+				"DosageDigestService: cannot get user preferences",
+				"err", err)
+			continue
+		}
+
+		start, end, due := prefs.DigestPeriod(now)
+		if !due {
+			continue
+		}
+
+		// Delivery methods are only needed for custom messages, so failing to
+		// get them shouldn't stop the digests.
+		if !methodsLoaded {
+			methods, err = s.dosages.DeliveryMethods(ctx)
+			if err != nil {
+				s.logger.WarnContext(ctx,
+					"DosageDigestService: cannot get delivery methods",
+					"err", err)
+			}
+			methodsLoaded = true
+		}
+
+		if err := s.sendDigest(ctx, userID, prefs.DigestFrequency, methods, start, end, now); err != nil {
+			s.logger.ErrorContext(ctx,
+				"DosageDigestService: error sending digest",
+				"err", err)
+		}
+	}
+
+	return nil
+}
+
+// sendDigest builds and sends the digest of the given period to a user. The
+// digest is marked as sent even if some of the user's channels failed, so
+// that the working ones don't get it again.
+func (s *DosageDigestService) sendDigest(
+	ctx context.Context, userID user.ID, frequency notification.DigestFrequency,
+	methods []DeliveryMethod, start, end, now time.Time) error {
+
+	dosage, err := s.dosages.Dosage(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("cannot get dosage: %w", err)
+	}
+	if dosage == nil {
+		// There is nothing to summarize without a schedule.
+		return s.notifs.MarkDigestSent(ctx, userID, now)
+	}
+
+	var doses []Dose
+	for dose, err := range s.doseHistory.DoseHistory(ctx, userID, start.Add(-LevelLookback), now) {
+		if err != nil {
+			return fmt.Errorf("cannot get dose history: %w", err)
+		}
+		doses = append(doses, dose)
+	}
+
+	sent, failed, err := s.reminders.ReminderAttempts(ctx, userID, start, end)
+	if err != nil {
+		return fmt.Errorf("cannot get reminder history: %w", err)
+	}
+
+	var lastDose *Dose
+	if len(doses) > 0 {
+		lastDose = &doses[len(doses)-1]
+	}
+
+	vars := MessageVariables(methods, *dosage, lastDose, now)
+	vars.Digest = BuildDigest(frequency, *dosage, doses, start, end)
+	vars.Digest.RemindersSent = sent
+	vars.Digest.RemindersFailed = failed
+
+	notifyErr := s.notifs.NotifyUser(ctx, userID, notificationapi.DigestMessage, vars)
+	if err := s.notifs.MarkDigestSent(ctx, userID, now); err != nil {
+		return errors.Join(notifyErr, fmt.Errorf("cannot mark digest as sent: %w", err))
+	}
+	return notifyErr
+}
+
+// BuildDigest summarizes the doses taken between start and end. doses must be
+// ordered by the time they were taken, and should include the doses before
+// start that affect the levels, see [LevelLookback], and every dose up to now,
+// so that the next dose can be estimated.
+//
+// The reminder counts of the summary are left for the caller to fill in.
+func BuildDigest(frequency notification.DigestFrequency, dosage Dosage, doses []Dose, start, end time.Time) notification.DigestSummary {
+	summary := notification.DigestSummary{
+		Frequency:   frequency,
+		PeriodStart: start,
+		PeriodEnd:   end,
+	}
+
+	interval := dosage.Interval.ToDuration()
+	if interval > 0 {
+		summary.DosesExpected = int(math.Round(float64(end.Sub(start)) / float64(interval)))
+	}
+
+	var late time.Duration
+	var lateCount int
+
+	for i, dose := range doses {
+		if dose.TakenAt.Before(start) || !dose.TakenAt.Before(end) {
+			continue
+		}
+		summary.DosesTaken++
+
+		// The first dose that the user ever recorded was never due.
+		if i == 0 {
+			continue
+		}
+		due := doses[i-1].TakenAt.Add(interval)
+		late += max(dose.TakenAt.Sub(due), 0)
+		lateCount++
+	}
+
+	if lateCount > 0 {
+		summary.AverageLateMinutes = int((late / time.Duration(lateCount)).Round(time.Minute) / time.Minute)
+	}
+
+	if len(doses) > 0 {
+		nextDoseAt := doses[len(doses)-1].TakenAt.Add(interval)
+		summary.NextDoseAt = &nextDoseAt
+
+		if level, ok := EstimateLevel(doses, nextDoseAt); ok {
+			summary.Trough = level
+			summary.TroughUnits = LevelUnits
+		}
+	}
+
+	return summary
+}
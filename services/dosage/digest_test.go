@@ -0,0 +1,55 @@
+package dosage
+
+import (
+	"testing"
+	"time"
+
+	notificationapi "e2clicker.app/services/notification/openapi"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestBuildDigest(t *testing.T) {
+	const day = 24 * time.Hour
+
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	end := start.Add(7 * day)
+
+	dosage := Dosage{
+		DeliveryMethod: "EV im",
+		Dose:           4,
+		Interval:       3.5,
+	}
+
+	dose := func(at time.Time) Dose {
+		return Dose{DeliveryMethod: dosage.DeliveryMethod, Dose: dosage.Dose, TakenAt: at}
+	}
+
+	doses := []Dose{
+		dose(start.Add(-3*day - 12*time.Hour)),
+		// Due at the start, taken 3 hours late.
+		dose(start.Add(3 * time.Hour)),
+		// Due 3.5 days after the previous one, taken 1 hour early.
+		dose(start.Add(3*day + 14*time.Hour)),
+		// After the period.
+		dose(end.Add(day)),
+	}
+
+	summary := BuildDigest(notificationapi.WeeklyDigest, dosage, doses, start, end)
+	assert.Equal(t, notificationapi.WeeklyDigest, summary.Frequency)
+	assert.Equal(t, start, summary.PeriodStart)
+	assert.Equal(t, end, summary.PeriodEnd)
+	assert.Equal(t, 2, summary.DosesTaken)
+	assert.Equal(t, 2, summary.DosesExpected)
+	assert.Equal(t, 90, summary.AverageLateMinutes)
+
+	nextDoseAt := end.Add(day).Add(dosage.Interval.ToDuration())
+	assert.Equal(t, &nextDoseAt, summary.NextDoseAt)
+	assert.Equal(t, LevelUnits, summary.TroughUnits)
+	assert.True(t, summary.Trough > 0, "trough is estimated")
+
+	// Without any doses, there is nothing to estimate.
+	summary = BuildDigest(notificationapi.MonthlyDigest, dosage, nil, start, end)
+	assert.Equal(t, 0, summary.DosesTaken)
+	assert.Zero(t, summary.NextDoseAt)
+	assert.Zero(t, summary.Trough)
+}
@@ -28,35 +28,35 @@ type DosageStorage interface {
 	DeliveryMethods(ctx context.Context) ([]DeliveryMethod, error)
 	// Dosage returns the dosage for a user.
 	// If the user has no schedule yet, this returns nil.
-	Dosage(ctx context.Context, secret user.Secret) (*Dosage, error)
+	Dosage(ctx context.Context, userID user.ID) (*Dosage, error)
 	// SetDosage sets the dosage for a user.
-	// The user secret is taken from the Schedule.
+	// The user userID is taken from the Schedule.
 	SetDosage(ctx context.Context, s Dosage) error
 	// ClearDosage clears the dosage for a user.
-	ClearDosage(ctx context.Context, secret user.Secret) error
+	ClearDosage(ctx context.Context, userID user.ID) error
 }
 
 // DoseHistoryStorage is a storage for dose history data.
 type DoseHistoryStorage interface {
 	// RecordDose records a single dose. The dose observation's ID is returned.
-	RecordDose(ctx context.Context, secret user.Secret, dose Dose) error
+	RecordDose(ctx context.Context, userID user.ID, dose Dose) error
 	// ImportDoses imports doses in bulk and returns the number of doses
 	// imported.
 	// This is a separate method from RecordDose to allow for more efficient
 	// bulk imports.
-	ImportDoses(ctx context.Context, secret user.Secret, doseSeq iter.Seq[Dose]) (int64, error)
+	ImportDoses(ctx context.Context, userID user.ID, doseSeq iter.Seq[Dose]) (int64, error)
 	// EditDose edits a dose by the previous takenAt time.
 	// All fields are updated.
-	EditDose(ctx context.Context, secret user.Secret, doseTime time.Time, dose Dose) error
+	EditDose(ctx context.Context, userID user.ID, doseTime time.Time, dose Dose) error
 	// ForgetDoses forgets the given doses.
-	ForgetDoses(ctx context.Context, secret user.Secret, doseTimes []time.Time) error
+	ForgetDoses(ctx context.Context, userID user.ID, doseTimes []time.Time) error
 	// DoseHistory returns the history of a dosage schedule. If end is zero, it
 	// is considered to be now. If begin is zero, it is considered to be
 	// since the beginning of time.
 	// The history is ordered by time taken, with the oldest dose first.
 	// If there's an error, the returned sequence will yield the error with a
 	// zero-value [Observation].
-	DoseHistory(ctx context.Context, secret user.Secret, begin, end time.Time) iter.Seq2[Dose, error]
+	DoseHistory(ctx context.Context, userID user.ID, begin, end time.Time) iter.Seq2[Dose, error]
 }
 
 // RecordedDosesResult is the result of recording doses.
@@ -71,8 +71,8 @@ type DeliveryMethod = openapi.DeliveryMethod
 
 // Dosage describes a dosage schedule.
 type Dosage struct {
-	// UserSecret is the secret of the user who the schedule is for.
-	UserSecret user.Secret
+	// UserID is the ID of the user who the schedule is for.
+	UserID user.ID
 	// DeliveryMethod is the method of delivery for the medication.
 	// Check the [delivery_methods] table.
 	DeliveryMethod string
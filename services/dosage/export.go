@@ -38,8 +38,8 @@ type ExporterService struct {
 	storage DoseHistoryStorage
 	logger  *slog.Logger
 
-	importLimiter *userlimit.UserRateLimiter[user.Secret]
-	exportLimiter *userlimit.UserRateLimiter[user.Secret]
+	importLimiter *userlimit.UserRateLimiter[user.ID]
+	exportLimiter *userlimit.UserRateLimiter[user.ID]
 }
 
 // NewExporterService creates a new CSVExporterService.
@@ -47,8 +47,8 @@ func NewExporterService(storage DoseHistoryStorage, lc fx.Lifecycle, logger *slo
 	s := &ExporterService{
 		storage:       storage,
 		logger:        logger,
-		importLimiter: userlimit.NewUserRateLimiter[user.Secret](rate.Every(15*time.Minute), 3),
-		exportLimiter: userlimit.NewUserRateLimiter[user.Secret](rate.Every(15*time.Minute), 3),
+		importLimiter: userlimit.NewUserRateLimiter[user.ID](rate.Every(15*time.Minute), 3),
+		exportLimiter: userlimit.NewUserRateLimiter[user.ID](rate.Every(15*time.Minute), 3),
 	}
 
 	stopCleanup := s.importLimiter.BeginCleanup()
@@ -72,8 +72,8 @@ type ExportDoseHistoryOptions struct {
 
 // ExportDoseHistory exports the dose history of the user as a CSV file into the
 // given writer. It returns the number of records exported.
-func (s *ExporterService) ExportDoseHistory(ctx context.Context, out io.Writer, secret user.Secret, o ExportDoseHistoryOptions) (int64, error) {
-	limit := s.exportLimiter.Reserve(secret)
+func (s *ExporterService) ExportDoseHistory(ctx context.Context, out io.Writer, userID user.ID, o ExportDoseHistoryOptions) (int64, error) {
+	limit := s.exportLimiter.Reserve(userID)
 	if err := userlimit.AsError(limit); err != nil {
 		return 0, err
 	}
@@ -81,7 +81,7 @@ func (s *ExporterService) ExportDoseHistory(ctx context.Context, out io.Writer,
 	var exported int64
 	var scanErrs []error
 	history := func(yield func(Dose) bool) {
-		for o, err := range s.storage.DoseHistory(ctx, secret, o.Begin, o.End) {
+		for o, err := range s.storage.DoseHistory(ctx, userID, o.Begin, o.End) {
 			if err != nil {
 				scanErrs = append(scanErrs, err)
 				continue
@@ -139,8 +139,8 @@ type ImportDoseHistoryResult struct {
 }
 
 // ImportDoseHistory imports dose history from a CSV file.
-func (s *ExporterService) ImportDoseHistory(ctx context.Context, in io.Reader, secret user.Secret, o ImportDoseHistoryOptions) (ImportDoseHistoryResult, error) {
-	limit := s.importLimiter.Reserve(secret)
+func (s *ExporterService) ImportDoseHistory(ctx context.Context, in io.Reader, userID user.ID, o ImportDoseHistoryOptions) (ImportDoseHistoryResult, error) {
+	limit := s.importLimiter.Reserve(userID)
 	if err := userlimit.AsError(limit); err != nil {
 		return ImportDoseHistoryResult{}, err
 	}
@@ -197,7 +197,7 @@ func (s *ExporterService) ImportDoseHistory(ctx context.Context, in io.Reader, s
 		return ImportDoseHistoryResult{}, publicerrors.Errorf("unsupported import format %q", o.Format)
 	}
 
-	succeeded, err := s.storage.ImportDoses(ctx, secret, doses)
+	succeeded, err := s.storage.ImportDoses(ctx, userID, doses)
 	r := ImportDoseHistoryResult{
 		Records:   records,
 		Succeeded: succeeded,
@@ -210,3 +210,11 @@ func (s *ExporterService) ImportDoseHistory(ctx context.Context, in io.Reader, s
 
 	return r, errors.Join(importErrors...)
 }
+
+// AccountPurging implements [user.AccountPurgeListener]. It forgets the
+// user's import and export rate limits.
+func (s *ExporterService) AccountPurging(ctx context.Context, userID user.ID) error {
+	s.importLimiter.Forget(userID)
+	s.exportLimiter.Forget(userID)
+	return nil
+}
@@ -0,0 +1,127 @@
+package dosage
+
+import (
+	"math"
+	"time"
+)
+
+// LevelUnits is the unit of the levels returned by [EstimateLevel].
+const LevelUnits = "pg/mL"
+
+// LevelLookback is how far back doses should be considered when estimating a
+// level. Doses older than this contribute a negligible amount for every
+// supported delivery method.
+const LevelLookback = 120 * 24 * time.Hour
+
+// pkParameters are the parameters of the three-compartment pharmacokinetic
+// model used to estimate serum estradiol levels.
+type pkParameters struct {
+	// d scales the dose into pg/mL.
+	d float64
+	// k1, k2 and k3 are the rate constants (per day) of the depot,
+	// intermediate and serum compartments.
+	k1, k2, k3 float64
+	// w is how long a patch is worn in days. It is only used for patches
+	// and is the default when the dose has no TakenOffAt.
+	w float64
+}
+
+// pkModels maps delivery method IDs (see the delivery_methods table) to their
+// model parameters. These mirror the PKParameters of estrannaise.js, which
+// the frontend uses to plot levels, so the two should be kept in sync.
+var pkModels = map[string]pkParameters{
+	"EB im":      {d: 1893.1, k1: 0.67, k2: 61.5, k3: 4.34},
+	"EV im":      {d: 478.0, k1: 0.236, k2: 4.85, k3: 1.24},
+	"EEn im":     {d: 191.4, k1: 0.119, k2: 0.601, k3: 0.402},
+	"EC im":      {d: 246.0, k1: 0.0825, k2: 3.57, k3: 0.669},
+	"EUn im":     {d: 471.5, k1: 0.01729, k2: 6.528, k3: 2.285},
+	"EUn casubq": {d: 16.15, k1: 0.046, k2: 0.022, k3: 0.101},
+	"patch tw":   {d: 16.792, k1: 0.283, k2: 5.592, k3: 4.3, w: 3.5},
+	"patch ow":   {d: 59.481, k1: 0.107, k2: 7.842, k3: 5.193, w: 7.0},
+}
+
+// EstimateLevel estimates the serum estradiol level at the given time from
+// the given doses, in [LevelUnits]. Doses taken after at are ignored.
+//
+// Since the model is linear, the level is the sum of each dose's
+// contribution. False is returned if none of the doses use a delivery method
+// that can be modeled.
+func EstimateLevel(doses []Dose, at time.Time) (float64, bool) {
+	var level float64
+	var ok bool
+
+	for _, dose := range doses {
+		p, known := pkModels[dose.DeliveryMethod]
+		if !known {
+			continue
+		}
+		ok = true
+
+		t := at.Sub(dose.TakenAt).Hours() / 24
+		if t < 0 {
+			continue
+		}
+
+		if p.w == 0 {
+			level += e2Curve3C(t, float64(dose.Dose), p, 0, 0)
+			continue
+		}
+
+		w := p.w
+		if dose.TakenOffAt != nil {
+			w = dose.TakenOffAt.Sub(dose.TakenAt).Hours() / 24
+		}
+		level += e2Patch3C(t, float64(dose.Dose), p, w)
+	}
+
+	return level, ok
+}
+
+// e2Curve3C returns the serum level t days after a dose that was entirely in
+// the depot compartment at t = 0. ds and d2 are the initial amounts in the
+// intermediate and serum compartments, already scaled by d.
+func e2Curve3C(t, dose float64, p pkParameters, ds, d2 float64) float64 {
+	if t < 0 {
+		return 0
+	}
+
+	k1, k2, k3 := p.k1, p.k2, p.k3
+	var level float64
+
+	if d2 > 0 {
+		level += d2 * math.Exp(-k3*t)
+	}
+
+	if ds > 0 {
+		if k2 == k3 {
+			level += ds * k2 * t * math.Exp(-k2*t)
+		} else {
+			level += ds * k2 / (k2 - k3) * (math.Exp(-k3*t) - math.Exp(-k2*t))
+		}
+	}
+
+	if dose > 0 {
+		// The rate constants of the supported models are all distinct, so
+		// the degenerate cases of the closed-form solution aren't handled.
+		level += dose * p.d * k1 * k2 * (0 +
+			math.Exp(-k1*t)/((k1-k2)*(k1-k3)) -
+			math.Exp(-k2*t)/((k1-k2)*(k2-k3)) +
+			math.Exp(-k3*t)/((k1-k3)*(k2-k3)))
+	}
+
+	return max(level, 0)
+}
+
+// e2Patch3C returns the serum level t days after a patch was put on and worn
+// for w days. Once the patch is removed, the depot is gone and only what was
+// already absorbed keeps contributing.
+func e2Patch3C(t, dose float64, p pkParameters, w float64) float64 {
+	if t <= w {
+		return e2Curve3C(t, dose, p, 0, 0)
+	}
+
+	k1, k2 := p.k1, p.k2
+	ds := dose * p.d * k1 / (k1 - k2) * (math.Exp(-k2*w) - math.Exp(-k1*w))
+	d2 := e2Curve3C(w, dose, p, 0, 0)
+	return e2Curve3C(t-w, 0, p, ds, d2)
+}
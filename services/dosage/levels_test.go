@@ -0,0 +1,65 @@
+package dosage
+
+import (
+	"testing"
+	"time"
+
+	"e2clicker.app/internal/ptr"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestEstimateLevel(t *testing.T) {
+	const day = 24 * time.Hour
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("unknown_method", func(t *testing.T) {
+		_, ok := EstimateLevel([]Dose{{DeliveryMethod: "pill", Dose: 2, TakenAt: now}}, now)
+		assert.False(t, ok)
+	})
+
+	t.Run("before_dose", func(t *testing.T) {
+		level, ok := EstimateLevel([]Dose{{DeliveryMethod: "EV im", Dose: 5, TakenAt: now}}, now.Add(-day))
+		assert.True(t, ok)
+		assert.Equal(t, 0.0, level)
+	})
+
+	t.Run("injection_rises_then_falls", func(t *testing.T) {
+		doses := []Dose{{DeliveryMethod: "EV im", Dose: 5, TakenAt: now}}
+
+		at := func(days float64) float64 {
+			level, _ := EstimateLevel(doses, now.Add(time.Duration(days*float64(day))))
+			return level
+		}
+
+		assert.Equal(t, 0.0, at(0))
+		assert.True(t, at(2) > at(0.25), "level should rise after injecting")
+		assert.True(t, at(14) < at(2), "level should fall after the peak")
+		assert.True(t, at(100) < 1, "level should be negligible long after")
+	})
+
+	t.Run("doses_add_up", func(t *testing.T) {
+		d1 := Dose{DeliveryMethod: "EC im", Dose: 5, TakenAt: now.Add(-7 * day)}
+		d2 := Dose{DeliveryMethod: "EC im", Dose: 5, TakenAt: now.Add(-14 * day)}
+
+		l1, _ := EstimateLevel([]Dose{d1}, now)
+		l2, _ := EstimateLevel([]Dose{d2}, now)
+		both, _ := EstimateLevel([]Dose{d1, d2}, now)
+		assert.Equal(t, l1+l2, both)
+	})
+
+	t.Run("patch_removed", func(t *testing.T) {
+		worn := Dose{DeliveryMethod: "patch tw", Dose: 100, TakenAt: now.Add(-4 * day)}
+		removed := worn
+		removed.TakenOffAt = ptr.To(now.Add(-3 * day))
+
+		lWorn, _ := EstimateLevel([]Dose{worn}, now)
+		lRemoved, _ := EstimateLevel([]Dose{removed}, now)
+		assert.True(t, lRemoved < lWorn, "removing a patch early should lower the level")
+
+		// The level must be continuous when the patch is taken off.
+		before, _ := EstimateLevel([]Dose{removed}, removed.TakenOffAt.Add(-time.Second))
+		after, _ := EstimateLevel([]Dose{removed}, removed.TakenOffAt.Add(time.Second))
+		assert.True(t, before-after < 0.01, "level should not jump at removal: %f -> %f", before, after)
+	})
+}
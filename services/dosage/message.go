@@ -0,0 +1,68 @@
+package dosage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"e2clicker.app/services/notification"
+	"e2clicker.app/services/user"
+)
+
+// MessageVariables returns the variables for custom notification messages
+// about the given dosage and last dose at the given time. The delivery methods
+// are used to look up the name and units of the dosage's delivery method.
+// lastDose may be nil if the user hasn't taken a dose yet.
+func MessageVariables(methods []DeliveryMethod, dosage Dosage, lastDose *Dose, now time.Time) notification.MessageVariables {
+	vars := notification.MessageVariables{
+		Dose:           dosage.Dose,
+		DeliveryMethod: dosage.DeliveryMethod,
+	}
+
+	for _, method := range methods {
+		if method.ID == dosage.DeliveryMethod {
+			vars.DeliveryMethod = method.Name
+			vars.Units = method.Units
+			break
+		}
+	}
+
+	if lastDose != nil {
+		vars.LastDoseAt = lastDose.TakenAt
+		vars.SinceLastDose = now.Sub(lastDose.TakenAt)
+		vars.DueAt = lastDose.TakenAt.Add(dosage.Interval.ToDuration())
+		if now.After(vars.DueAt) {
+			vars.Overdue = now.Sub(vars.DueAt)
+		}
+	}
+
+	return vars
+}
+
+// LoadMessageVariables is like [MessageVariables], but it loads the user's
+// dosage and last dose from storage. If the user has no dosage, only zero
+// values are returned.
+func LoadMessageVariables(ctx context.Context, dosages DosageStorage, doseHistory DoseHistoryStorage, userID user.ID, now time.Time) (notification.MessageVariables, error) {
+	dosage, err := dosages.Dosage(ctx, userID)
+	if err != nil {
+		return notification.MessageVariables{}, fmt.Errorf("cannot get dosage: %w", err)
+	}
+	if dosage == nil {
+		return notification.MessageVariables{}, nil
+	}
+
+	methods, err := dosages.DeliveryMethods(ctx)
+	if err != nil {
+		return notification.MessageVariables{}, fmt.Errorf("cannot get delivery methods: %w", err)
+	}
+
+	var lastDose *Dose
+	for dose, err := range doseHistory.DoseHistory(ctx, userID, now.Add(-LevelLookback), now) {
+		if err != nil {
+			return notification.MessageVariables{}, fmt.Errorf("cannot get dose history: %w", err)
+		}
+		lastDose = &dose
+	}
+
+	return MessageVariables(methods, *dosage, lastDose, now), nil
+}
@@ -0,0 +1,241 @@
+package dosage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"e2clicker.app/internal/ptr"
+	"e2clicker.app/services/notification"
+	"e2clicker.app/services/user"
+	"go.uber.org/fx"
+)
+
+// mqttEventTimeout bounds how long publishing a single user's dose event
+// and state may take once the request that caused it has returned.
+const mqttEventTimeout = time.Minute
+
+// DosageMQTTService publishes dose events and the dosage state (last and next
+// dose, estimated level) of users to their MQTT topics. Users without an MQTT
+// configuration are skipped.
+//
+// If the server has no MQTT broker configured, every method is a no-op.
+type DosageMQTTService struct {
+	mqtt        *notification.MQTTService
+	notifs      *notification.UserNotificationService
+	dosage      DosageStorage
+	doseHistory DoseHistoryStorage
+	logger      *slog.Logger
+}
+
+// DosageMQTTServiceConfig is the configuration for [DosageMQTTService].
+type DosageMQTTServiceConfig struct {
+	fx.In
+
+	MQTT        *notification.MQTTService `optional:"true"`
+	Notifs      *notification.UserNotificationService
+	Dosage      DosageStorage
+	DoseHistory DoseHistoryStorage
+	Logger      *slog.Logger
+	Lifecycle   fx.Lifecycle
+}
+
+// NewDosageMQTTService creates a new DosageMQTTService. If MQTT is available,
+// the retained state of every user with an MQTT configuration is refreshed
+// periodically in the background, since the estimated level changes over time.
+func NewDosageMQTTService(c DosageMQTTServiceConfig) *DosageMQTTService {
+	s := &DosageMQTTService{
+		mqtt:        c.MQTT,
+		notifs:      c.Notifs,
+		dosage:      c.Dosage,
+		doseHistory: c.DoseHistory,
+		logger:      c.Logger,
+	}
+
+	if s.mqtt == nil {
+		return s
+	}
+
+	fakectx, stop := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	c.Lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				s.run(fakectx)
+				close(done)
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stop()
+			<-done
+			return nil
+		},
+	})
+
+	return s
+}
+
+func (s *DosageMQTTService) run(ctx context.Context) {
+	ticker := time.NewTicker(s.mqtt.StateInterval())
+	defer ticker.Stop()
+
+	for {
+		s.logger.Debug("DosageMQTTService: refreshing states")
+
+		if err := s.refreshAll(ctx); err != nil {
+			s.logger.Error(
+				"DosageMQTTService: error refreshing states",
+				"err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *DosageMQTTService) refreshAll(ctx context.Context) error {
+	var errs []error
+	for userID, err := range s.notifs.UsersWithNotificationMethod(ctx, notification.MQTTMethod) {
+		if err != nil {
+			return err
+		}
+		if err := s.publishState(ctx, userID, time.Now()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DoseRecorded publishes the given dose to the user's MQTT topics and
+// refreshes their state. It returns immediately; publishing happens in the
+// background and errors are only logged.
+func (s *DosageMQTTService) DoseRecorded(ctx context.Context, userID user.ID, dose Dose) {
+	s.background(ctx, userID, func(ctx context.Context, configs []notification.MQTTNotificationConfig) error {
+		event := notification.MQTTDoseEvent{
+			DeliveryMethod: dose.DeliveryMethod,
+			Dose:           dose.Dose,
+			TakenAt:        dose.TakenAt,
+			TakenOffAt:     dose.TakenOffAt,
+		}
+
+		var errs []error
+		for _, config := range configs {
+			if err := s.mqtt.PublishDose(ctx, config, event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		if err := s.publishStateTo(ctx, userID, configs, time.Now()); err != nil {
+			errs = append(errs, err)
+		}
+
+		return errors.Join(errs...)
+	})
+}
+
+// DosesChanged refreshes the user's state after their dosage or dose history
+// was changed in a way that isn't a newly recorded dose. Like
+// [DosageMQTTService.DoseRecorded], it returns immediately.
+func (s *DosageMQTTService) DosesChanged(ctx context.Context, userID user.ID) {
+	s.background(ctx, userID, func(ctx context.Context, configs []notification.MQTTNotificationConfig) error {
+		return s.publishStateTo(ctx, userID, configs, time.Now())
+	})
+}
+
+func (s *DosageMQTTService) background(ctx context.Context, userID user.ID, f func(context.Context, []notification.MQTTNotificationConfig) error) {
+	if s.mqtt == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mqttEventTimeout)
+
+	go func() {
+		defer cancel()
+
+		configs, err := s.mqttConfigs(ctx, userID)
+		if err == nil && len(configs) > 0 {
+			err = f(ctx, configs)
+		}
+		if err != nil {
+			s.logger.ErrorContext(ctx,
+				"DosageMQTTService: error publishing to MQTT",
+				"err", err)
+		}
+	}()
+}
+
+func (s *DosageMQTTService) mqttConfigs(ctx context.Context, userID user.ID) ([]notification.MQTTNotificationConfig, error) {
+	configs, err := s.notifs.MQTTConfigs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get MQTT configs: %w", err)
+	}
+	return configs, nil
+}
+
+func (s *DosageMQTTService) publishState(ctx context.Context, userID user.ID, now time.Time) error {
+	configs, err := s.mqttConfigs(ctx, userID)
+	if err != nil || len(configs) == 0 {
+		return err
+	}
+	return s.publishStateTo(ctx, userID, configs, now)
+}
+
+func (s *DosageMQTTService) publishStateTo(ctx context.Context, userID user.ID, configs []notification.MQTTNotificationConfig, now time.Time) error {
+	state, err := s.state(ctx, userID, now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, config := range configs {
+		if err := s.mqtt.PublishState(ctx, config, state); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *DosageMQTTService) state(ctx context.Context, userID user.ID, now time.Time) (notification.MQTTState, error) {
+	state := notification.MQTTState{
+		LevelUnits: LevelUnits,
+		UpdatedAt:  now,
+	}
+
+	dosage, err := s.dosage.Dosage(ctx, userID)
+	if err != nil {
+		return state, fmt.Errorf("cannot get dosage: %w", err)
+	}
+
+	// Include doses taken up to a second from now, so that a dose that was
+	// just recorded with time.Now() is always included.
+	doses := make([]Dose, 0, 16)
+	for dose, err := range s.doseHistory.DoseHistory(ctx, userID, now.Add(-LevelLookback), now.Add(time.Second)) {
+		if err != nil {
+			return state, fmt.Errorf("cannot get dose history: %w", err)
+		}
+		doses = append(doses, dose)
+	}
+
+	if len(doses) > 0 {
+		last := doses[len(doses)-1]
+		state.LastDose = &last.TakenAt
+
+		if dosage != nil {
+			state.NextDose = ptr.To(last.TakenAt.Add(dosage.Interval.ToDuration()))
+		}
+
+		if level, ok := EstimateLevel(doses, now); ok {
+			state.EstimatedLevel = ptr.To(math.Round(level*10) / 10)
+		}
+	}
+
+	return state, nil
+}
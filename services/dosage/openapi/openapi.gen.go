@@ -7,6 +7,18 @@ import (
 	"time"
 )
 
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for ShareField.
+const (
+	Comments     ShareField = "comments"
+	CurrentLevel ShareField = "currentLevel"
+	Doses        ShareField = "doses"
+	Levels       ShareField = "levels"
+)
+
 // Defines values for ExportDosesParamsAccept.
 const (
 	ExportDosesParamsAcceptApplicationJSON ExportDosesParamsAccept = "application/json"
@@ -73,6 +85,83 @@ type Dose struct {
 	Comment *string `json:"comment,omitempty"`
 }
 
+// LevelSample An estimated level at a point in time.
+type LevelSample struct {
+	Time  time.Time `json:"time"`
+	Level float64   `json:"level"`
+}
+
+// ShareField A kind of data that a share link can show:
+// - `doses`: the doses taken within the shared range - `comments`: the comments of the shared doses - `levels`: the estimated levels within the shared range - `currentLevel`: the estimated level at the time the link is opened
+type ShareField string
+
+// ShareLink A link that shows some of a user's dosage data to anyone who has it.
+type ShareLink struct {
+	// ID The share link identifier
+	ID int64 `json:"id"`
+
+	// Name The name of the share link
+	Name string `json:"name"`
+
+	// Start The start of the shared time range
+	Start time.Time `json:"start"`
+
+	// End The end of the shared time range
+	End time.Time `json:"end"`
+
+	// Fields The kinds of data that are shared
+	Fields []ShareField `json:"fields"`
+
+	// HasPIN Whether a PIN is needed to open the link
+	HasPIN bool `json:"hasPIN"`
+
+	// CreatedAt The time the share link was created
+	CreatedAt time.Time `json:"createdAt"`
+
+	// ExpiresAt The time the share link expires
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// RevokedAt The time the share link was revoked, or null if it was not
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+// ShareLinkAccess An attempt to open a share link.
+type ShareLinkAccess struct {
+	// AccessedAt The time the share link was opened
+	AccessedAt time.Time `json:"accessedAt"`
+
+	// UserAgent The user agent that opened the share link, if any
+	UserAgent *string `json:"userAgent,omitempty"`
+
+	// Granted Whether the shared data was shown. This is false if the PIN was wrong.
+	Granted bool `json:"granted"`
+
+	// LockedOut Whether the PIN was wrong too many times in a row, so this attempt locked the share link, which revokes it.
+	LockedOut bool `json:"lockedOut"`
+}
+
+// SharedData The data that a share link shows. Data that isn't shared is left out.
+type SharedData struct {
+	// Start The start of the shared time range
+	Start time.Time `json:"start"`
+
+	// End The end of the shared time range
+	End time.Time `json:"end"`
+
+	// ExpiresAt The time the share link expires
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// LevelUnits The units of the estimated levels
+	LevelUnits string         `json:"levelUnits"`
+	Doses      *DosageHistory `json:"doses,omitempty"`
+
+	// Levels The estimated levels within the shared range, up to the time the link was opened
+	Levels *[]LevelSample `json:"levels,omitempty"`
+
+	// CurrentLevel The estimated level at the time the link was opened
+	CurrentLevel *float64 `json:"currentLevel,omitempty"`
+}
+
 // DosageParams defines parameters for Dosage.
 type DosageParams struct {
 	Start *time.Time `form:"start,omitempty" json:"start,omitempty"`
@@ -108,6 +197,42 @@ type ImportDosesParams struct {
 // ImportDosesParamsContentType defines parameters for ImportDoses.
 type ImportDosesParamsContentType string
 
+// RevokeShareLinkParams defines parameters for RevokeShareLink.
+type RevokeShareLinkParams struct {
+	// ID The identifier of the share link to revoke.
+	ID int64 `form:"id" json:"id"`
+}
+
+// CreateShareLinkJSONBody defines parameters for CreateShareLink.
+type CreateShareLinkJSONBody struct {
+	// End The end of the time range to share
+	End time.Time `json:"end"`
+
+	// ExpiresAt The time the share link expires
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// Fields The kinds of data to share
+	Fields []ShareField `json:"fields"`
+
+	// Name A name for the share link, e.g. who it is for
+	Name *string `json:"name,omitempty"`
+
+	// Pin A PIN of 6 to 12 digits that is needed to open the link. If not given, the link can be opened without one.
+	Pin *string `json:"pin,omitempty"`
+
+	// Start The start of the time range to share
+	Start time.Time `json:"start"`
+}
+
+// OpenShareLinkParams defines parameters for OpenShareLink.
+type OpenShareLinkParams struct {
+	// XSharePin The PIN of the share link, if it has one.
+	XSharePin *string `json:"X-Share-Pin,omitempty"`
+
+	// UserAgent The user agent of the client making the request.
+	UserAgent *string `json:"User-Agent,omitempty"`
+}
+
 // SetDosageJSONRequestBody defines body for SetDosage for application/json ContentType.
 type SetDosageJSONRequestBody = Dosage
 
@@ -116,3 +241,6 @@ type EditDoseJSONRequestBody = EditDoseJSONBody
 
 // ImportDosesJSONRequestBody defines body for ImportDoses for application/json ContentType.
 type ImportDosesJSONRequestBody = DosageHistory
+
+// CreateShareLinkJSONRequestBody defines body for CreateShareLink for application/json ContentType.
+type CreateShareLinkJSONRequestBody CreateShareLinkJSONBody
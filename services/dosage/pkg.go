@@ -3,6 +3,7 @@ package dosage
 import (
 	"log/slog"
 
+	"e2clicker.app/services/user"
 	"go.uber.org/fx"
 )
 
@@ -13,5 +14,12 @@ var Module = fx.Module("dosage",
 	fx.Provide(
 		NewExporterService,
 		NewDosageReminderService,
+		NewDosageDigestService,
+		NewDosageMQTTService,
+		NewShareService,
+		NewTakeoutService,
 	),
+	user.ProvideAccountPurgeListener[*ExporterService](),
+	user.ProvideAccountPurgeListener[*ShareService](),
+	user.ProvideAccountPurgeListener[*TakeoutService](),
 )
@@ -29,12 +29,21 @@ type DosageReminderStorage interface {
 	// RecordRemindedDoseAttempts records the reminded dose attempts.
 	// This is used to mark the reminder as sent or failed.
 	RecordRemindedDoseAttempts(ctx context.Context, remindedDoses []RemindedDoseAttempt) error
+
+	// ReminderAttempts returns the number of reminders that were sent to the
+	// user between begin and end, and the number of those that failed to be
+	// sent.
+	ReminderAttempts(ctx context.Context, userID user.ID, begin, end time.Time) (sent, failed int, err error)
+
+	// ReminderHistory returns every reminder that was sent to the user, or
+	// failed to be sent, with the oldest first.
+	ReminderHistory(ctx context.Context, userID user.ID) ([]RemindedDoseAttempt, error)
 }
 
 // DosageReminder is a reminder for a dosage.
 type DosageReminder struct {
-	// UserSecret is the secret of the user.
-	UserSecret user.Secret
+	// UserID is the ID of the user.
+	UserID user.ID
 	// Username is the username of the user.
 	Username string
 	// Dosage is the dosage information of the user.
@@ -52,8 +61,8 @@ type DosageReminder struct {
 
 // RemindedDoseAttempt is a dose that has been reminded.
 type RemindedDoseAttempt struct {
-	// UserSecret is the secret of the user.
-	UserSecret user.Secret
+	// UserID is the ID of the user.
+	UserID user.ID
 	// RemindedAt is the time when the reminder was sent.
 	RemindedAt time.Time
 	// RemindedDose is the dose that was reminded.
@@ -85,18 +94,21 @@ const (
 // DosageReminderService is a service for managing dosage reminders.
 type DosageReminderService struct {
 	storage DosageReminderStorage
+	dosages DosageStorage
 	notifs  *notification.UserNotificationService
 }
 
 // NewDosageReminderService creates a new DosageReminderService.
 func NewDosageReminderService(
 	storage DosageReminderStorage,
+	dosages DosageStorage,
 	notifs *notification.UserNotificationService,
 	slog *slog.Logger,
 	lc fx.Lifecycle,
 ) *DosageReminderService {
 	s := &DosageReminderService{
 		storage: storage,
+		dosages: dosages,
 		notifs:  notifs,
 	}
 
@@ -125,6 +137,8 @@ func (s *DosageReminderService) run(ctx context.Context, slog *slog.Logger) {
 	nextRun := time.NewTimer(0)
 	now := time.Now()
 
+	var methods []DeliveryMethod
+
 	for {
 		slog.Debug("DosageReminderService: running update cycle")
 
@@ -144,13 +158,26 @@ func (s *DosageReminderService) run(ctx context.Context, slog *slog.Logger) {
 			"DosageReminderService: scheduling next run",
 			"nextRun", tracked.nextRun)
 
+		// Delivery methods are only needed for custom messages, so failing to
+		// get them shouldn't stop the reminders.
+		if len(tracked.notifyingReminders) > 0 {
+			methods, err = s.dosages.DeliveryMethods(ctx)
+			if err != nil {
+				slog.Warn(
+					"DosageReminderService: cannot get delivery methods",
+					"err", err)
+			}
+		}
+
 		for _, r := range tracked.notifyingReminders {
+			vars := MessageVariables(methods, r.Dosage, &r.LastDose, now)
+
 			start := time.Now()
-			err := s.notifs.NotifyUser(ctx, r.UserSecret, notificationapi.ReminderMessage)
+			err := s.notifs.NotifyUser(ctx, r.UserID, notificationapi.ReminderMessage, vars)
 			taken := time.Since(start)
 
 			attempt := RemindedDoseAttempt{
-				UserSecret:   r.UserSecret,
+				UserID:       r.UserID,
 				RemindedAt:   now,
 				RemindedDose: r.LastDose.TakenAt,
 				ClearSnooze:  r.ClearSnooze,
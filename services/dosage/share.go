@@ -0,0 +1,516 @@
+package dosage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"e2clicker.app/internal/publicerrors"
+	"e2clicker.app/internal/userlimit"
+	"e2clicker.app/services/user"
+	"go.uber.org/fx"
+	"golang.org/x/time/rate"
+)
+
+func init() {
+	publicerrors.MarkValuesPublic(
+		ErrUnknownShareLink,
+		ErrIncorrectSharePIN,
+		ErrShareLinkLocked,
+		ErrInvalidSharePIN,
+		ErrInvalidShareRange,
+		ErrInvalidShareField,
+		ErrShareLinkExpiry,
+	)
+}
+
+var (
+	// ErrUnknownShareLink is returned when a share link doesn't exist, has
+	// expired or was revoked.
+	ErrUnknownShareLink = errors.New("unknown share link")
+	// ErrIncorrectSharePIN is returned when the PIN given to open a share link
+	// is wrong.
+	ErrIncorrectSharePIN = errors.New("incorrect share link PIN")
+	// ErrShareLinkLocked is returned when an incorrect PIN locked a share
+	// link, which revokes it.
+	ErrShareLinkLocked = errors.New("share link was locked after too many incorrect PINs")
+	// ErrInvalidSharePIN is returned when a share link is given a PIN that
+	// isn't 6 to 12 digits.
+	ErrInvalidSharePIN = errors.New("share link PIN must be 6 to 12 digits")
+	// ErrInvalidShareRange is returned when a share link's range doesn't
+	// start before it ends.
+	ErrInvalidShareRange = errors.New("share link range must start before it ends")
+	// ErrInvalidShareField is returned when a share link is given an unknown
+	// field.
+	ErrInvalidShareField = errors.New("invalid share field")
+	// ErrShareLinkExpiry is returned when a share link would already be
+	// expired.
+	ErrShareLinkExpiry = errors.New("share link would already be expired")
+)
+
+// maxShareLevelSamples is the maximum number of estimated levels in a share
+// link's level curve.
+const maxShareLevelSamples = 500
+
+// minShareLevelInterval is the minimum time between two estimated levels in a
+// share link's level curve.
+const minShareLevelInterval = time.Hour
+
+// maxSharePINAttempts is the number of incorrect PINs in a row after which a
+// share link is locked.
+const maxSharePINAttempts = 10
+
+// ShareLinkStorage stores share links and their access logs.
+type ShareLinkStorage interface {
+	// CreateShareLink creates a share link. The token and PIN are generated
+	// by [ShareService], which only gives the storage their hashes. pinHash
+	// is nil if the link has no PIN.
+	CreateShareLink(ctx context.Context, link ShareLink, tokenHash, pinHash []byte) (ShareLink, error)
+	// ShareLinks lists all share links of a user, including expired and
+	// revoked ones, with the newest first.
+	ShareLinks(ctx context.Context, userID user.ID) ([]ShareLink, error)
+	// ShareLinkByToken returns the share link whose token hashes to
+	// tokenHash along with the hash of its PIN, even if it has expired or was
+	// revoked. [ErrUnknownShareLink] is returned if there is no such link, or
+	// if the owner's account is scheduled to be deleted.
+	ShareLinkByToken(ctx context.Context, tokenHash []byte) (ShareLink, []byte, error)
+	// RevokeShareLink revokes a share link of a user.
+	// [ErrUnknownShareLink] is returned if the user has no such link.
+	RevokeShareLink(ctx context.Context, userID user.ID, linkID int64) error
+	// RecordShareLinkAccess adds an entry to the access log of a share link.
+	RecordShareLinkAccess(ctx context.Context, linkID int64, access ShareLinkAccess) error
+	// RecordIncorrectSharePIN adds an attempt with an incorrect PIN to the
+	// access log of a share link. If it makes maxAttempts incorrect PINs in a
+	// row, the link is revoked in the same transaction and the attempt is
+	// logged as having locked it. It returns true if the link was locked.
+	RecordIncorrectSharePIN(ctx context.Context, linkID int64, userAgent string, maxAttempts int) (bool, error)
+	// ShareLinkAccesses returns the access log of a share link of a user, with
+	// the newest access first.
+	ShareLinkAccesses(ctx context.Context, userID user.ID, linkID int64) ([]ShareLinkAccess, error)
+}
+
+// ShareField is a kind of data that a share link can include.
+type ShareField string
+
+const (
+	// ShareFieldDoses includes the doses taken within the shared range.
+	ShareFieldDoses ShareField = "doses"
+	// ShareFieldComments includes the comments of the shared doses. It has
+	// no effect without [ShareFieldDoses].
+	ShareFieldComments ShareField = "comments"
+	// ShareFieldLevels includes the estimated levels within the shared range.
+	ShareFieldLevels ShareField = "levels"
+	// ShareFieldCurrentLevel includes the estimated level at the time the
+	// link is opened, even if that is after the shared range.
+	ShareFieldCurrentLevel ShareField = "currentLevel"
+)
+
+// ShareFields is the list of all share fields.
+var ShareFields = []ShareField{
+	ShareFieldDoses,
+	ShareFieldComments,
+	ShareFieldLevels,
+	ShareFieldCurrentLevel,
+}
+
+// Validate returns an error if the share field is unknown.
+func (f ShareField) Validate() error {
+	if !slices.Contains(ShareFields, f) {
+		return fmt.Errorf("%w %q", ErrInvalidShareField, f)
+	}
+	return nil
+}
+
+// ShareLink is a public link that shows some of a user's data to anyone who
+// has it, such as their doctor or partner.
+type ShareLink struct {
+	// ID uniquely identifies the share link.
+	ID int64
+	// UserID is the ID of the user whose data is shared.
+	UserID user.ID
+	// Name is the name that the user gave the share link.
+	Name string
+	// Start and End are the range of data that is shared.
+	Start, End time.Time
+	// Fields are the kinds of data that are shared.
+	Fields []ShareField
+	// HasPIN is true if a PIN is needed to open the link.
+	HasPIN bool
+	// CreatedAt is the time that the share link was created.
+	CreatedAt time.Time
+	// ExpiresAt is the time that the share link expires.
+	ExpiresAt time.Time
+	// RevokedAt is the time that the share link was revoked.
+	// If zero, the share link was not revoked.
+	RevokedAt time.Time
+}
+
+// Shares returns true if the share link includes the given field.
+func (l ShareLink) Shares(field ShareField) bool {
+	return slices.Contains(l.Fields, field)
+}
+
+// IsActive returns true if the share link can still be opened.
+func (l ShareLink) IsActive(now time.Time) bool {
+	return l.RevokedAt.IsZero() && now.Before(l.ExpiresAt)
+}
+
+// ShareLinkWithToken is a share link along with the token in its URL, which
+// is only known when the link is created.
+type ShareLinkWithToken struct {
+	ShareLink
+	Token string
+}
+
+// ShareLinkAccess is an entry in the access log of a share link.
+type ShareLinkAccess struct {
+	// AccessedAt is the time that the share link was opened.
+	AccessedAt time.Time
+	// UserAgent is the user agent that opened the share link, if any.
+	UserAgent string
+	// Granted is true if the shared data was shown. It is false if the PIN
+	// was wrong.
+	Granted bool
+	// LockedOut is true if the PIN was wrong too many times in a row, so this
+	// attempt locked the share link.
+	LockedOut bool
+}
+
+// SharedData is the data that a share link shows. Data that isn't shared is
+// left empty.
+type SharedData struct {
+	// Start and End are the range of data that is shared.
+	Start, End time.Time
+	// ExpiresAt is the time that the share link expires.
+	ExpiresAt time.Time
+	// Doses are the doses taken within the shared range.
+	Doses []Dose
+	// Levels are the estimated levels within the shared range, in
+	// [LevelUnits].
+	Levels []LevelSample
+	// CurrentLevel is the estimated level at the time the link was opened, in
+	// [LevelUnits].
+	CurrentLevel *float64
+}
+
+// LevelSample is an estimated level at a point in time.
+type LevelSample struct {
+	Time  time.Time
+	Level float64
+}
+
+// ShareService manages share links and shows the data behind them.
+type ShareService struct {
+	links       ShareLinkStorage
+	doseHistory DoseHistoryStorage
+	logger      *slog.Logger
+
+	pinLimiter *userlimit.UserRateLimiter[int64]
+}
+
+// NewShareService creates a new ShareService.
+func NewShareService(links ShareLinkStorage, doseHistory DoseHistoryStorage, lc fx.Lifecycle, logger *slog.Logger) *ShareService {
+	s := &ShareService{
+		links:       links,
+		doseHistory: doseHistory,
+		logger:      logger,
+		// PINs are short, so guessing them must be slow.
+		pinLimiter: userlimit.NewUserRateLimiter[int64](rate.Every(time.Minute), 5),
+	}
+
+	stopCleanup := s.pinLimiter.BeginCleanup()
+	lc.Append(fx.StopHook(func(ctx context.Context) error {
+		stopCleanup()
+		return nil
+	}))
+
+	return s
+}
+
+// CreateShareLinkOptions are the options for creating a share link.
+type CreateShareLinkOptions struct {
+	Name       string
+	Start, End time.Time
+	Fields     []ShareField
+	ExpiresAt  time.Time
+	// PIN is needed to open the link if it's not empty.
+	PIN string
+}
+
+// CreateShareLink creates a share link to some of the user's data. The token
+// in its URL is only ever returned here.
+func (s *ShareService) CreateShareLink(ctx context.Context, userID user.ID, o CreateShareLinkOptions) (ShareLinkWithToken, error) {
+	if !o.Start.Before(o.End) {
+		return ShareLinkWithToken{}, ErrInvalidShareRange
+	}
+
+	if len(o.Fields) == 0 {
+		return ShareLinkWithToken{}, fmt.Errorf("%w: nothing would be shared", ErrInvalidShareField)
+	}
+	for _, f := range o.Fields {
+		if err := f.Validate(); err != nil {
+			return ShareLinkWithToken{}, err
+		}
+	}
+
+	if !o.ExpiresAt.After(time.Now()) {
+		return ShareLinkWithToken{}, ErrShareLinkExpiry
+	}
+
+	if o.PIN != "" && !validSharePIN(o.PIN) {
+		return ShareLinkWithToken{}, ErrInvalidSharePIN
+	}
+
+	if o.Name == "" {
+		o.Name = "Share link"
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return ShareLinkWithToken{}, err
+	}
+
+	var pinHash []byte
+	if o.PIN != "" {
+		pinHash = hashSharePIN(token, o.PIN)
+	}
+
+	link, err := s.links.CreateShareLink(ctx, ShareLink{
+		UserID:    userID,
+		Name:      o.Name,
+		Start:     o.Start,
+		End:       o.End,
+		Fields:    slices.Compact(slices.Sorted(slices.Values(o.Fields))),
+		ExpiresAt: o.ExpiresAt,
+	}, hashShareToken(token), pinHash)
+	if err != nil {
+		return ShareLinkWithToken{}, err
+	}
+
+	return ShareLinkWithToken{link, token}, nil
+}
+
+// ShareLinks lists the share links of the user.
+func (s *ShareService) ShareLinks(ctx context.Context, userID user.ID) ([]ShareLink, error) {
+	return s.links.ShareLinks(ctx, userID)
+}
+
+// RevokeShareLink revokes a share link of the user, after which it can no
+// longer be opened. Its access log is kept.
+func (s *ShareService) RevokeShareLink(ctx context.Context, userID user.ID, linkID int64) error {
+	return s.links.RevokeShareLink(ctx, userID, linkID)
+}
+
+// ShareLinkAccesses returns the access log of a share link of the user.
+func (s *ShareService) ShareLinkAccesses(ctx context.Context, userID user.ID, linkID int64) ([]ShareLinkAccess, error) {
+	return s.links.ShareLinkAccesses(ctx, userID, linkID)
+}
+
+// OpenShareLink returns the data that the share link with the given token
+// shows. pin is ignored if the link has no PIN. Every attempt is logged for
+// the owner to see.
+//
+// [ErrUnknownShareLink] is returned if the link doesn't exist, has expired or
+// was revoked, and [ErrIncorrectSharePIN] if the PIN is wrong. After
+// [maxSharePINAttempts] wrong PINs in a row, the link is revoked and
+// [ErrShareLinkLocked] is returned.
+func (s *ShareService) OpenShareLink(ctx context.Context, token, pin, userAgent string) (SharedData, error) {
+	link, pinHash, err := s.links.ShareLinkByToken(ctx, hashShareToken(token))
+	if err != nil {
+		return SharedData{}, err
+	}
+
+	now := time.Now()
+	if !link.IsActive(now) {
+		return SharedData{}, ErrUnknownShareLink
+	}
+
+	// Only wrong PINs are charged, so that viewers who know the PIN can
+	// reload the page freely. Every wrong PIN counts towards the lockout, even
+	// if the limit was already reached.
+	if pinHash != nil && !hmac.Equal(pinHash, hashSharePIN(token, pin)) {
+		locked, err := s.links.RecordIncorrectSharePIN(ctx, link.ID, userAgent, maxSharePINAttempts)
+		if err != nil {
+			return SharedData{}, fmt.Errorf("cannot record incorrect PIN: %w", err)
+		}
+		if locked {
+			s.logger.InfoContext(ctx,
+				"share link locked after too many incorrect PINs",
+				"link_id", link.ID)
+			return SharedData{}, ErrShareLinkLocked
+		}
+
+		limit := s.pinLimiter.Reserve(link.ID)
+		if err := userlimit.AsError(limit); err != nil {
+			return SharedData{}, err
+		}
+		return SharedData{}, ErrIncorrectSharePIN
+	}
+
+	data, err := s.sharedData(ctx, link, now)
+	if err != nil {
+		return SharedData{}, err
+	}
+
+	s.recordAccess(ctx, link, userAgent, true)
+	return data, nil
+}
+
+// AccountPurging implements [user.AccountPurgeListener]. It forgets the PIN
+// rate limits of the user's share links, which are deleted with the user.
+func (s *ShareService) AccountPurging(ctx context.Context, userID user.ID) error {
+	links, err := s.links.ShareLinks(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("cannot list share links: %w", err)
+	}
+	for _, link := range links {
+		s.pinLimiter.Forget(link.ID)
+	}
+	return nil
+}
+
+func (s *ShareService) recordAccess(ctx context.Context, link ShareLink, userAgent string, granted bool) {
+	err := s.links.RecordShareLinkAccess(ctx, link.ID, ShareLinkAccess{
+		UserAgent: userAgent,
+		Granted:   granted,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx,
+			"cannot record share link access",
+			"link_id", link.ID,
+			"err", err)
+	}
+}
+
+func (s *ShareService) sharedData(ctx context.Context, link ShareLink, now time.Time) (SharedData, error) {
+	data := SharedData{
+		Start:     link.Start,
+		End:       link.End,
+		ExpiresAt: link.ExpiresAt,
+	}
+
+	// Levels depend on doses from before the range, so fetch enough history
+	// for everything that is shared.
+	begin := link.Start
+	end := link.End
+	if link.Shares(ShareFieldLevels) {
+		begin = link.Start.Add(-LevelLookback)
+	}
+	if link.Shares(ShareFieldCurrentLevel) {
+		begin = earliest(begin, now.Add(-LevelLookback))
+		end = latest(end, now)
+	}
+
+	var doses []Dose
+	for dose, err := range s.doseHistory.DoseHistory(ctx, link.UserID, begin, end) {
+		if err != nil {
+			return SharedData{}, fmt.Errorf("cannot get dose history: %w", err)
+		}
+		doses = append(doses, dose)
+	}
+
+	if link.Shares(ShareFieldDoses) {
+		for _, dose := range doses {
+			if dose.TakenAt.Before(link.Start) || dose.TakenAt.After(link.End) {
+				continue
+			}
+			if !link.Shares(ShareFieldComments) {
+				dose.Comment = ""
+			}
+			data.Doses = append(data.Doses, dose)
+		}
+	}
+
+	if link.Shares(ShareFieldLevels) {
+		// Don't estimate levels past the present, since doses that weren't
+		// taken yet would be missing.
+		data.Levels = sampleLevels(doses, link.Start, earliest(link.End, now))
+	}
+
+	if link.Shares(ShareFieldCurrentLevel) {
+		if level, ok := EstimateLevel(doses, now); ok {
+			data.CurrentLevel = &level
+		}
+	}
+
+	return data, nil
+}
+
+// sampleLevels estimates the levels between start and end at regular
+// intervals. Nil is returned if none of the doses can be modeled.
+func sampleLevels(doses []Dose, start, end time.Time) []LevelSample {
+	if !start.Before(end) {
+		return nil
+	}
+
+	interval := max(end.Sub(start)/maxShareLevelSamples, minShareLevelInterval)
+
+	var samples []LevelSample
+	for t := start; !t.After(end); t = t.Add(interval) {
+		level, ok := EstimateLevel(doses, t)
+		if !ok {
+			return nil
+		}
+		samples = append(samples, LevelSample{Time: t, Level: level})
+	}
+
+	return samples
+}
+
+func generateShareToken() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("cannot generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// hashShareToken hashes the token of a share link. The token is random, so it
+// doesn't need a key.
+func hashShareToken(token string) []byte {
+	h := sha256.Sum256([]byte(token))
+	return h[:]
+}
+
+// hashSharePIN hashes the PIN of a share link, keyed with the link's token.
+// Only the token's hash is stored, so the PIN can't be guessed from the
+// database alone.
+func hashSharePIN(token, pin string) []byte {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(pin))
+	return mac.Sum(nil)
+}
+
+// validSharePIN returns true if the PIN is 6 to 12 digits.
+func validSharePIN(pin string) bool {
+	if len(pin) < 6 || len(pin) > 12 {
+		return false
+	}
+	for _, c := range pin {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
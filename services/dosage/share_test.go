@@ -0,0 +1,331 @@
+package dosage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"e2clicker.app/internal/userlimit"
+	"e2clicker.app/services/user"
+	"github.com/alecthomas/assert/v2"
+	"github.com/neilotoole/slogt"
+	"go.uber.org/fx/fxtest"
+	"golang.org/x/time/rate"
+)
+
+type fakeShareLinks struct {
+	links    []ShareLink
+	hashes   [][2][]byte // token hash, PIN hash
+	accesses map[int64][]ShareLinkAccess
+}
+
+func (f *fakeShareLinks) CreateShareLink(ctx context.Context, link ShareLink, tokenHash, pinHash []byte) (ShareLink, error) {
+	link.ID = int64(len(f.links) + 1)
+	link.HasPIN = pinHash != nil
+	link.CreatedAt = time.Now()
+	f.links = append(f.links, link)
+	f.hashes = append(f.hashes, [2][]byte{tokenHash, pinHash})
+	return link, nil
+}
+
+func (f *fakeShareLinks) ShareLinks(ctx context.Context, userID user.ID) ([]ShareLink, error) {
+	return f.links, nil
+}
+
+func (f *fakeShareLinks) ShareLinkByToken(ctx context.Context, tokenHash []byte) (ShareLink, []byte, error) {
+	for i, h := range f.hashes {
+		if bytes.Equal(h[0], tokenHash) {
+			return f.links[i], h[1], nil
+		}
+	}
+	return ShareLink{}, nil, ErrUnknownShareLink
+}
+
+func (f *fakeShareLinks) RevokeShareLink(ctx context.Context, userID user.ID, linkID int64) error {
+	for i := range f.links {
+		if f.links[i].ID == linkID && f.links[i].UserID == userID {
+			f.links[i].RevokedAt = time.Now()
+			return nil
+		}
+	}
+	return ErrUnknownShareLink
+}
+
+func (f *fakeShareLinks) RecordShareLinkAccess(ctx context.Context, linkID int64, access ShareLinkAccess) error {
+	if f.accesses == nil {
+		f.accesses = make(map[int64][]ShareLinkAccess)
+	}
+	f.accesses[linkID] = append(f.accesses[linkID], access)
+	return nil
+}
+
+func (f *fakeShareLinks) RecordIncorrectSharePIN(ctx context.Context, linkID int64, userAgent string, maxAttempts int) (bool, error) {
+	incorrect := 1
+	for _, a := range f.accesses[linkID] {
+		if a.Granted {
+			incorrect = 1
+		} else {
+			incorrect++
+		}
+	}
+
+	locked := incorrect >= maxAttempts
+	if locked {
+		for i := range f.links {
+			if f.links[i].ID == linkID {
+				f.links[i].RevokedAt = time.Now()
+			}
+		}
+	}
+
+	return locked, f.RecordShareLinkAccess(ctx, linkID, ShareLinkAccess{
+		UserAgent: userAgent,
+		LockedOut: locked,
+	})
+}
+
+func (f *fakeShareLinks) ShareLinkAccesses(ctx context.Context, userID user.ID, linkID int64) ([]ShareLinkAccess, error) {
+	return f.accesses[linkID], nil
+}
+
+type fakeDoseHistory struct {
+	DoseHistoryStorage
+	doses []Dose
+}
+
+func (f fakeDoseHistory) DoseHistory(ctx context.Context, userID user.ID, begin, end time.Time) iter.Seq2[Dose, error] {
+	return func(yield func(Dose, error) bool) {
+		for _, d := range f.doses {
+			if d.TakenAt.Before(begin) || d.TakenAt.After(end) {
+				continue
+			}
+			if !yield(d, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestShareService(t *testing.T) {
+	const day = 24 * time.Hour
+
+	ctx := context.Background()
+	now := time.Now()
+	userID := user.ID(1)
+
+	doses := fakeDoseHistory{doses: []Dose{
+		{DeliveryMethod: "EV im", Dose: 4, TakenAt: now.Add(-20 * day), Comment: "before"},
+		{DeliveryMethod: "EV im", Dose: 4, TakenAt: now.Add(-10 * day), Comment: "left thigh"},
+		{DeliveryMethod: "EV im", Dose: 4, TakenAt: now.Add(-3 * day), Comment: "right thigh"},
+	}}
+
+	newService := func(t *testing.T) (*ShareService, *fakeShareLinks) {
+		links := &fakeShareLinks{}
+		lc := fxtest.NewLifecycle(t)
+		s := NewShareService(links, doses, lc, slogt.New(t))
+		lc.RequireStart()
+		t.Cleanup(lc.RequireStop)
+		return s, links
+	}
+
+	t.Run("doses", func(t *testing.T) {
+		s, links := newService(t)
+
+		l, err := s.CreateShareLink(ctx, userID, CreateShareLinkOptions{
+			Start:     now.Add(-14 * day),
+			End:       now,
+			Fields:    []ShareField{ShareFieldDoses},
+			ExpiresAt: now.Add(day),
+		})
+		assert.NoError(t, err)
+		assert.NotZero(t, l.Token)
+		assert.Equal(t, "Share link", l.Name)
+
+		data, err := s.OpenShareLink(ctx, l.Token, "", "curl/8.0")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(data.Doses), "only doses within the range are shared")
+		assert.Equal(t, "", data.Doses[0].Comment, "comments are not shared")
+		assert.Zero(t, data.Levels)
+		assert.Zero(t, data.CurrentLevel)
+
+		assert.Equal(t,
+			[]ShareLinkAccess{{UserAgent: "curl/8.0", Granted: true}},
+			links.accesses[l.ID])
+	})
+
+	t.Run("levels", func(t *testing.T) {
+		s, _ := newService(t)
+
+		l, err := s.CreateShareLink(ctx, userID, CreateShareLinkOptions{
+			Start:     now.Add(-14 * day),
+			End:       now.Add(14 * day),
+			Fields:    []ShareField{ShareFieldDoses, ShareFieldComments, ShareFieldLevels, ShareFieldCurrentLevel},
+			ExpiresAt: now.Add(day),
+		})
+		assert.NoError(t, err)
+
+		data, err := s.OpenShareLink(ctx, l.Token, "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, "left thigh", data.Doses[0].Comment)
+		assert.NotZero(t, data.CurrentLevel)
+		assert.True(t, len(data.Levels) > 1, "levels are sampled")
+		assert.False(t, data.Levels[len(data.Levels)-1].Time.After(now),
+			"levels are not estimated past the present")
+	})
+
+	t.Run("pin", func(t *testing.T) {
+		s, links := newService(t)
+
+		_, err := s.CreateShareLink(ctx, userID, CreateShareLinkOptions{
+			Start:     now.Add(-day),
+			End:       now,
+			Fields:    []ShareField{ShareFieldDoses},
+			ExpiresAt: now.Add(day),
+			PIN:       "1234ab",
+		})
+		assert.IsError(t, err, ErrInvalidSharePIN)
+
+		_, err = s.CreateShareLink(ctx, userID, CreateShareLinkOptions{
+			Start:     now.Add(-day),
+			End:       now,
+			Fields:    []ShareField{ShareFieldDoses},
+			ExpiresAt: now.Add(day),
+			PIN:       "1234",
+		})
+		assert.IsError(t, err, ErrInvalidSharePIN)
+
+		l, err := s.CreateShareLink(ctx, userID, CreateShareLinkOptions{
+			Start:     now.Add(-day),
+			End:       now,
+			Fields:    []ShareField{ShareFieldDoses},
+			ExpiresAt: now.Add(day),
+			PIN:       "123456",
+		})
+		assert.NoError(t, err)
+		assert.True(t, l.HasPIN)
+
+		_, err = s.OpenShareLink(ctx, l.Token, "654321", "")
+		assert.IsError(t, err, ErrIncorrectSharePIN)
+
+		_, err = s.OpenShareLink(ctx, l.Token, "123456", "")
+		assert.NoError(t, err)
+
+		assert.Equal(t,
+			[]ShareLinkAccess{{Granted: false}, {Granted: true}},
+			links.accesses[l.ID])
+	})
+
+	t.Run("pin rate limit", func(t *testing.T) {
+		s, links := newService(t)
+
+		l, err := s.CreateShareLink(ctx, userID, CreateShareLinkOptions{
+			Start:     now.Add(-day),
+			End:       now,
+			Fields:    []ShareField{ShareFieldDoses},
+			ExpiresAt: now.Add(day),
+			PIN:       "123456",
+		})
+		assert.NoError(t, err)
+
+		for range 10 {
+			_, err = s.OpenShareLink(ctx, l.Token, "123456", "")
+			assert.NoError(t, err, "correct PINs are not limited")
+		}
+
+		var limitErr *userlimit.LimitExceededError
+		for range 5 {
+			_, err = s.OpenShareLink(ctx, l.Token, "000000", "")
+			assert.IsError(t, err, ErrIncorrectSharePIN)
+		}
+		_, err = s.OpenShareLink(ctx, l.Token, "000000", "")
+		assert.True(t, errors.As(err, &limitErr), "wrong PINs are limited")
+
+		_, err = s.OpenShareLink(ctx, l.Token, "123456", "")
+		assert.NoError(t, err)
+
+		accesses := links.accesses[l.ID]
+		assert.Equal(t, 10+6+1, len(accesses), "limited attempts are still logged")
+	})
+
+	t.Run("pin lockout", func(t *testing.T) {
+		s, links := newService(t)
+		// Don't let the rate limit get in the way of guessing.
+		s.pinLimiter = userlimit.NewUserRateLimiter[int64](rate.Inf, 0)
+
+		l, err := s.CreateShareLink(ctx, userID, CreateShareLinkOptions{
+			Start:     now.Add(-day),
+			End:       now,
+			Fields:    []ShareField{ShareFieldDoses},
+			ExpiresAt: now.Add(day),
+			PIN:       "123456",
+		})
+		assert.NoError(t, err)
+
+		for range maxSharePINAttempts - 1 {
+			_, err = s.OpenShareLink(ctx, l.Token, "000000", "")
+			assert.IsError(t, err, ErrIncorrectSharePIN)
+		}
+
+		_, err = s.OpenShareLink(ctx, l.Token, "000000", "")
+		assert.IsError(t, err, ErrShareLinkLocked)
+
+		_, err = s.OpenShareLink(ctx, l.Token, "123456", "")
+		assert.IsError(t, err, ErrUnknownShareLink, "the correct PIN no longer works")
+
+		accesses := links.accesses[l.ID]
+		assert.Equal(t, maxSharePINAttempts, len(accesses))
+		assert.False(t, accesses[len(accesses)-2].LockedOut)
+		assert.True(t, accesses[len(accesses)-1].LockedOut)
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		s, _ := newService(t)
+
+		l, err := s.CreateShareLink(ctx, userID, CreateShareLinkOptions{
+			Start:     now.Add(-day),
+			End:       now,
+			Fields:    []ShareField{ShareFieldDoses},
+			ExpiresAt: now.Add(day),
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, s.RevokeShareLink(ctx, userID, l.ID))
+
+		_, err = s.OpenShareLink(ctx, l.Token, "", "")
+		assert.IsError(t, err, ErrUnknownShareLink)
+
+		_, err = s.OpenShareLink(ctx, "nonexistent", "", "")
+		assert.IsError(t, err, ErrUnknownShareLink)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		s, _ := newService(t)
+
+		_, err := s.CreateShareLink(ctx, userID, CreateShareLinkOptions{
+			Start:     now,
+			End:       now.Add(-day),
+			Fields:    []ShareField{ShareFieldDoses},
+			ExpiresAt: now.Add(day),
+		})
+		assert.IsError(t, err, ErrInvalidShareRange)
+
+		_, err = s.CreateShareLink(ctx, userID, CreateShareLinkOptions{
+			Start:     now.Add(-day),
+			End:       now,
+			Fields:    []ShareField{"everything"},
+			ExpiresAt: now.Add(day),
+		})
+		assert.IsError(t, err, ErrInvalidShareField)
+
+		_, err = s.CreateShareLink(ctx, userID, CreateShareLinkOptions{
+			Start:     now.Add(-day),
+			End:       now,
+			Fields:    []ShareField{ShareFieldDoses},
+			ExpiresAt: now.Add(-time.Minute),
+		})
+		assert.IsError(t, err, ErrShareLinkExpiry)
+	})
+}
@@ -0,0 +1,696 @@
+package dosage
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
+
+	"e2clicker.app/internal/publicerrors"
+	"e2clicker.app/internal/userlimit"
+	"e2clicker.app/services/dosage/openapi"
+	"e2clicker.app/services/notification"
+	"e2clicker.app/services/user"
+	"go.uber.org/fx"
+	"golang.org/x/time/rate"
+)
+
+func init() {
+	publicerrors.MarkValuesPublic(
+		ErrTakeoutNotFreshAccount,
+		ErrTakeoutInvalid,
+		ErrTakeoutTooLarge,
+	)
+}
+
+var (
+	// ErrTakeoutNotFreshAccount is returned when a takeout is imported into
+	// an account that already has a dosage schedule or doses.
+	ErrTakeoutNotFreshAccount = errors.New("takeouts can only be restored into a fresh account without a dosage or doses")
+	// ErrTakeoutInvalid is returned when the imported archive is not a
+	// takeout or is damaged.
+	ErrTakeoutInvalid = errors.New("invalid takeout archive")
+	// ErrTakeoutTooLarge is returned when the imported archive is larger than
+	// [MaxTakeoutSize].
+	ErrTakeoutTooLarge = errors.New("takeout archive is too large")
+)
+
+// TakeoutFormat is the archive format of a takeout.
+type TakeoutFormat string
+
+const (
+	TakeoutZip TakeoutFormat = "application/zip"
+	TakeoutTar TakeoutFormat = "application/x-tar"
+)
+
+// AsMIME returns itself.
+func (f TakeoutFormat) AsMIME() string { return string(f) }
+
+const (
+	// TakeoutManifestFormat identifies the manifest of a takeout archive.
+	TakeoutManifestFormat = "e2clicker-takeout"
+	// TakeoutVersion is the version of the takeout archives that are
+	// exported. It is bumped whenever a file changes in a way that older
+	// servers can't restore.
+	TakeoutVersion = 1
+	// MaxTakeoutSize is the largest takeout archive that can be imported.
+	MaxTakeoutSize = 64 << 20 // 64 MiB
+)
+
+// Names of the files within a takeout archive.
+const (
+	takeoutManifestFile            = "manifest.json"
+	takeoutProfileFile             = "profile.json"
+	takeoutDosageFile              = "dosage.json"
+	takeoutDosesFile               = "doses.json"
+	takeoutNotificationPrefsFile   = "notification-preferences.json"
+	takeoutNotificationHistoryFile = "notification-history.json"
+	takeoutSessionsFile            = "sessions.json"
+)
+
+// TakeoutManifest describes a takeout archive. It is always the first file in
+// the archive.
+type TakeoutManifest struct {
+	// Format is always [TakeoutManifestFormat].
+	Format string `json:"format"`
+	// Version is the [TakeoutVersion] that the archive was exported with.
+	Version int `json:"version"`
+	// ExportedAt is the time that the archive was exported.
+	ExportedAt time.Time `json:"exportedAt"`
+	// IncludesCredentials is true if the notification preferences contain
+	// the credentials of the user's notification configs. Otherwise, they're
+	// redacted.
+	IncludesCredentials bool `json:"includesCredentials"`
+	// Files lists the other files in the archive.
+	Files []string `json:"files"`
+}
+
+// TakeoutProfile is the profile.json file of a takeout.
+type TakeoutProfile struct {
+	Name   string      `json:"name"`
+	Locale user.Locale `json:"locale"`
+}
+
+// TakeoutReminder is a single entry in the notification-history.json file of
+// a takeout.
+type TakeoutReminder struct {
+	SentAt    time.Time `json:"sentAt"`
+	DoseDueAt time.Time `json:"doseDueAt"`
+	Error     *string   `json:"error,omitempty"`
+}
+
+// TakeoutSession is a single entry in the sessions.json file of a takeout.
+// Sessions are only exported for the user's information and are never
+// restored.
+type TakeoutSession struct {
+	CreatedAt time.Time `json:"createdAt"`
+	LastUsed  time.Time `json:"lastUsed"`
+	UserAgent string    `json:"userAgent,omitempty"`
+}
+
+// TakeoutStorage restores takeouts.
+type TakeoutStorage interface {
+	// ImportTakeout restores a takeout into the user's account within a single
+	// transaction, so that either all of it is restored or none of it is.
+	// [ErrTakeoutNotFreshAccount] is returned if the user already has a
+	// dosage schedule or doses. It returns the number of doses that were
+	// restored.
+	ImportTakeout(ctx context.Context, userID user.ID, t TakeoutImport) (int64, error)
+}
+
+// TakeoutImport is the validated contents of a takeout that are restored by
+// [TakeoutStorage.ImportTakeout].
+type TakeoutImport struct {
+	// Name is the user's new name. If empty, the name is left as it is.
+	Name string
+	// Locale is the user's new locale. If empty, the locale is left as it is.
+	Locale user.Locale
+	// Dosage is the user's dosage schedule, if they had one.
+	Dosage *Dosage
+	// Doses are the doses that the user took.
+	Doses []Dose
+	// NotificationPreferences replace the user's notification preferences.
+	NotificationPreferences notification.UserPreferences
+	// RemindedDoseAttempts are the reminders that were sent to the user.
+	RemindedDoseAttempts []RemindedDoseAttempt
+}
+
+// TakeoutService exports a user's whole account into an archive and restores
+// it into a fresh account, possibly on another server.
+type TakeoutService struct {
+	users       *user.UserService
+	notifs      *notification.UserNotificationService
+	dosages     DosageStorage
+	doseHistory DoseHistoryStorage
+	reminders   DosageReminderStorage
+	takeouts    TakeoutStorage
+	logger      *slog.Logger
+
+	limiter *userlimit.UserRateLimiter[user.ID]
+}
+
+// NewTakeoutService creates a new TakeoutService.
+func NewTakeoutService(
+	users *user.UserService,
+	notifs *notification.UserNotificationService,
+	dosages DosageStorage,
+	doseHistory DoseHistoryStorage,
+	reminders DosageReminderStorage,
+	takeouts TakeoutStorage,
+	lc fx.Lifecycle,
+	logger *slog.Logger,
+) *TakeoutService {
+	s := &TakeoutService{
+		users:       users,
+		notifs:      notifs,
+		dosages:     dosages,
+		doseHistory: doseHistory,
+		reminders:   reminders,
+		takeouts:    takeouts,
+		logger:      logger,
+		// Both exporting and importing go through the whole account.
+		limiter: userlimit.NewUserRateLimiter[user.ID](rate.Every(time.Hour), 3),
+	}
+
+	stopCleanup := s.limiter.BeginCleanup()
+	lc.Append(fx.StopHook(func(ctx context.Context) error {
+		stopCleanup()
+		return nil
+	}))
+
+	return s
+}
+
+// ExportTakeoutOptions are options for exporting a takeout.
+type ExportTakeoutOptions struct {
+	Format TakeoutFormat
+	// IncludeCredentials includes the credentials of the user's notification
+	// configs, such as API tokens, instead of redacting them. Configs with
+	// redacted credentials are skipped when the takeout is restored.
+	IncludeCredentials bool
+}
+
+// ExportTakeout writes the whole account of the user into out as an archive.
+// It contains the user's profile, dosage schedule, dose history, notification
+// preferences and history and the metadata of their sessions, along with a
+// [TakeoutManifest].
+func (s *TakeoutService) ExportTakeout(ctx context.Context, out io.Writer, userID user.ID, o ExportTakeoutOptions) error {
+	var aw takeoutArchiveWriter
+	switch o.Format {
+	case TakeoutZip:
+		aw = &takeoutZipWriter{zip.NewWriter(out)}
+	case TakeoutTar:
+		aw = &takeoutTarWriter{tar.NewWriter(out)}
+	default:
+		return publicerrors.Errorf("unsupported takeout format %q", o.Format)
+	}
+
+	limit := s.limiter.Reserve(userID)
+	if err := userlimit.AsError(limit); err != nil {
+		return err
+	}
+
+	files, err := s.exportFiles(ctx, userID, o)
+	if err != nil {
+		limit.Cancel()
+		return err
+	}
+
+	manifest := TakeoutManifest{
+		Format:              TakeoutManifestFormat,
+		Version:             TakeoutVersion,
+		ExportedAt:          time.Now().UTC(),
+		IncludesCredentials: o.IncludeCredentials,
+	}
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, f.name)
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal manifest: %w", err)
+	}
+
+	now := manifest.ExportedAt
+	if err := aw.WriteFile(takeoutManifestFile, manifestJSON, now); err != nil {
+		return fmt.Errorf("cannot write %s: %w", takeoutManifestFile, err)
+	}
+	for _, f := range files {
+		if err := aw.WriteFile(f.name, f.data, now); err != nil {
+			return fmt.Errorf("cannot write %s: %w", f.name, err)
+		}
+	}
+
+	return aw.Close()
+}
+
+type takeoutFile struct {
+	name string
+	data []byte
+}
+
+func (s *TakeoutService) exportFiles(ctx context.Context, userID user.ID, o ExportTakeoutOptions) ([]takeoutFile, error) {
+	u, err := s.users.User(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get user: %w", err)
+	}
+
+	d, err := s.dosages.Dosage(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get dosage: %w", err)
+	}
+
+	doses := make([]openapi.Dose, 0, 64)
+	for dose, err := range s.doseHistory.DoseHistory(ctx, userID, time.Time{}, time.Time{}) {
+		if err != nil {
+			return nil, fmt.Errorf("cannot get dose history: %w", err)
+		}
+		doses = append(doses, dose.ToOpenAPI())
+	}
+
+	prefs, err := s.notifs.ExportUserPreferences(ctx, userID, o.IncludeCredentials)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get notification preferences: %w", err)
+	}
+
+	reminded, err := s.reminders.ReminderHistory(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get notification history: %w", err)
+	}
+
+	sessions, err := s.users.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get sessions: %w", err)
+	}
+
+	var dosage *openapi.Dosage
+	if d != nil {
+		dosage = &openapi.Dosage{
+			DeliveryMethod: d.DeliveryMethod,
+			Dose:           d.Dose,
+			Interval:       float64(d.Interval),
+			Concurrence:    d.Concurrence,
+		}
+	}
+
+	history := make([]TakeoutReminder, len(reminded))
+	for i, r := range reminded {
+		history[i] = TakeoutReminder{
+			SentAt:    r.RemindedAt,
+			DoseDueAt: r.RemindedDose,
+			Error:     r.ErrorReason,
+		}
+	}
+
+	sessionsMeta := make([]TakeoutSession, len(sessions))
+	for i, session := range sessions {
+		sessionsMeta[i] = TakeoutSession{
+			CreatedAt: session.CreatedAt,
+			LastUsed:  session.LastUsed,
+			UserAgent: session.UserAgent,
+		}
+	}
+
+	contents := []struct {
+		name string
+		v    any
+	}{
+		{takeoutProfileFile, TakeoutProfile{Name: u.Name, Locale: u.Locale}},
+		{takeoutDosageFile, dosage},
+		{takeoutDosesFile, doses},
+		{takeoutNotificationPrefsFile, prefs},
+		{takeoutNotificationHistoryFile, history},
+		{takeoutSessionsFile, sessionsMeta},
+	}
+
+	files := make([]takeoutFile, len(contents))
+	for i, c := range contents {
+		b, err := json.MarshalIndent(c.v, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal %s: %w", c.name, err)
+		}
+		files[i] = takeoutFile{c.name, b}
+	}
+
+	return files, nil
+}
+
+// ImportTakeoutOptions are options for importing a takeout.
+type ImportTakeoutOptions struct {
+	Format TakeoutFormat
+}
+
+// ImportTakeoutResult is the result of importing a takeout.
+type ImportTakeoutResult struct {
+	// Manifest is the manifest of the imported takeout.
+	Manifest TakeoutManifest
+	// Doses is the number of doses that were restored.
+	Doses int64
+	// SkippedNotificationConfigs is the number of notification configs that
+	// couldn't be restored on this server, see
+	// [notification.UserNotificationService.PrepareImport].
+	SkippedNotificationConfigs int
+}
+
+// ImportTakeout restores a takeout that was exported by [ExportTakeout],
+// possibly on another server, into the user's account. Either all of it is
+// restored or, if anything fails, none of it is.
+// [ErrTakeoutNotFreshAccount] is returned if the user already has a dosage
+// schedule or doses, since they would clash with the restored ones.
+//
+// Sessions are not restored; the user stays logged in with the account they
+// imported into.
+func (s *TakeoutService) ImportTakeout(ctx context.Context, in io.Reader, userID user.ID, o ImportTakeoutOptions) (ImportTakeoutResult, error) {
+	switch o.Format {
+	case TakeoutZip, TakeoutTar:
+	default:
+		return ImportTakeoutResult{}, publicerrors.Errorf("unsupported takeout format %q", o.Format)
+	}
+
+	// Failed imports are charged as well, since reading the archive is most
+	// of the work.
+	limit := s.limiter.Reserve(userID)
+	if err := userlimit.AsError(limit); err != nil {
+		return ImportTakeoutResult{}, err
+	}
+
+	return s.importTakeout(ctx, in, userID, o)
+}
+
+func (s *TakeoutService) importTakeout(ctx context.Context, in io.Reader, userID user.ID, o ImportTakeoutOptions) (ImportTakeoutResult, error) {
+	if err := s.checkFreshAccount(ctx, userID); err != nil {
+		return ImportTakeoutResult{}, err
+	}
+
+	files, err := readTakeoutArchive(in, o.Format)
+	if err != nil {
+		return ImportTakeoutResult{}, err
+	}
+
+	var manifest TakeoutManifest
+	if err := unmarshalTakeoutFile(files, takeoutManifestFile, &manifest); err != nil {
+		return ImportTakeoutResult{}, err
+	}
+	if manifest.Format != TakeoutManifestFormat {
+		return ImportTakeoutResult{}, fmt.Errorf("%w: unknown format %q", ErrTakeoutInvalid, manifest.Format)
+	}
+	if manifest.Version < 1 || manifest.Version > TakeoutVersion {
+		return ImportTakeoutResult{}, publicerrors.Errorf(
+			"takeout version %d is not supported by this server, which supports up to version %d",
+			manifest.Version, TakeoutVersion)
+	}
+
+	var profile TakeoutProfile
+	var dosage *openapi.Dosage
+	var doses []openapi.Dose
+	var prefs notification.UserPreferences
+	var history []TakeoutReminder
+
+	for _, f := range []struct {
+		name string
+		v    any
+	}{
+		{takeoutProfileFile, &profile},
+		{takeoutDosageFile, &dosage},
+		{takeoutDosesFile, &doses},
+		{takeoutNotificationPrefsFile, &prefs},
+		{takeoutNotificationHistoryFile, &history},
+	} {
+		if err := unmarshalTakeoutFile(files, f.name, f.v); err != nil {
+			return ImportTakeoutResult{}, err
+		}
+	}
+
+	r := ImportTakeoutResult{Manifest: manifest}
+
+	// Validate everything before anything is written, so that a bad takeout
+	// is rejected as a whole.
+	t := TakeoutImport{
+		Locale: profile.Locale,
+		Doses:  make([]Dose, len(doses)),
+	}
+
+	if profile.Name != "" {
+		t.Name, err = user.ParseName(profile.Name)
+		if err != nil {
+			return r, err
+		}
+	}
+	if profile.Locale != "" {
+		if err := profile.Locale.Validate(); err != nil {
+			return r, fmt.Errorf("%w %q", user.ErrInvalidLocale, profile.Locale)
+		}
+	}
+
+	if dosage != nil {
+		methods, err := s.dosages.DeliveryMethods(ctx)
+		if err != nil {
+			return r, fmt.Errorf("cannot get delivery methods: %w", err)
+		}
+		if !slices.ContainsFunc(methods, func(m DeliveryMethod) bool { return m.ID == dosage.DeliveryMethod }) {
+			return r, publicerrors.Errorf("unknown delivery method %q in takeout", dosage.DeliveryMethod)
+		}
+
+		t.Dosage = &Dosage{
+			UserID:         userID,
+			DeliveryMethod: dosage.DeliveryMethod,
+			Dose:           dosage.Dose,
+			Interval:       Days(dosage.Interval),
+			Concurrence:    dosage.Concurrence,
+		}
+	}
+
+	for i, d := range doses {
+		t.Doses[i] = doseFromOpenAPI(d)
+	}
+
+	imported, err := s.notifs.PrepareImport(ctx, userID, prefs)
+	if err != nil {
+		return r, fmt.Errorf("cannot restore notification preferences: %w", err)
+	}
+	t.NotificationPreferences = imported.Preferences
+	r.SkippedNotificationConfigs = imported.Skipped
+
+	for _, h := range history {
+		t.RemindedDoseAttempts = append(t.RemindedDoseAttempts, RemindedDoseAttempt{
+			UserID:       userID,
+			RemindedAt:   h.SentAt,
+			RemindedDose: h.DoseDueAt,
+			ErrorReason:  h.Error,
+		})
+	}
+
+	r.Doses, err = s.takeouts.ImportTakeout(ctx, userID, t)
+	if err != nil {
+		return r, fmt.Errorf("cannot restore takeout: %w", err)
+	}
+
+	// The takeout is restored by now, so failing to send the confirmation
+	// emails is only logged. The user can add the addresses again to get new
+	// ones.
+	if err := s.notifs.FinishImport(ctx, userID, imported); err != nil {
+		s.logger.WarnContext(ctx,
+			"TakeoutService: cannot send email confirmations for restored takeout",
+			"user_id", userID,
+			"err", err)
+	}
+
+	s.logger.InfoContext(ctx,
+		"TakeoutService: restored takeout",
+		"user_id", userID,
+		"takeout_version", manifest.Version,
+		"takeout_exported_at", manifest.ExportedAt,
+		"doses", r.Doses,
+		"skipped_notification_configs", r.SkippedNotificationConfigs)
+
+	return r, nil
+}
+
+func (s *TakeoutService) checkFreshAccount(ctx context.Context, userID user.ID) error {
+	d, err := s.dosages.Dosage(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("cannot get dosage: %w", err)
+	}
+	if d != nil {
+		return ErrTakeoutNotFreshAccount
+	}
+
+	for _, err := range s.doseHistory.DoseHistory(ctx, userID, time.Time{}, time.Time{}) {
+		if err != nil {
+			return fmt.Errorf("cannot get dose history: %w", err)
+		}
+		return ErrTakeoutNotFreshAccount
+	}
+
+	return nil
+}
+
+// AccountPurging implements [user.AccountPurgeListener]. It forgets the
+// user's takeout rate limit.
+func (s *TakeoutService) AccountPurging(ctx context.Context, userID user.ID) error {
+	s.limiter.Forget(userID)
+	return nil
+}
+
+type takeoutArchiveWriter interface {
+	WriteFile(name string, data []byte, modTime time.Time) error
+	Close() error
+}
+
+type takeoutZipWriter struct{ w *zip.Writer }
+
+func (w *takeoutZipWriter) WriteFile(name string, data []byte, modTime time.Time) error {
+	f, err := w.w.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+func (w *takeoutZipWriter) Close() error { return w.w.Close() }
+
+type takeoutTarWriter struct{ w *tar.Writer }
+
+func (w *takeoutTarWriter) WriteFile(name string, data []byte, modTime time.Time) error {
+	if err := w.w.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := w.w.Write(data)
+	return err
+}
+
+func (w *takeoutTarWriter) Close() error { return w.w.Close() }
+
+// readTakeoutArchive reads every file in the archive into memory. Both the
+// archive and the files within it are limited to [MaxTakeoutSize] in total.
+func readTakeoutArchive(in io.Reader, format TakeoutFormat) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	var total int64
+
+	readFile := func(name string, r io.Reader) error {
+		if _, ok := files[name]; ok {
+			return fmt.Errorf("%w: duplicate file %q", ErrTakeoutInvalid, name)
+		}
+		b, err := io.ReadAll(io.LimitReader(r, MaxTakeoutSize-total+1))
+		if err != nil {
+			if errors.Is(err, ErrTakeoutTooLarge) {
+				return err
+			}
+			return fmt.Errorf("%w: cannot read %q: %v", ErrTakeoutInvalid, name, err)
+		}
+		total += int64(len(b))
+		if total > MaxTakeoutSize {
+			return ErrTakeoutTooLarge
+		}
+		files[name] = b
+		return nil
+	}
+
+	switch format {
+	case TakeoutZip:
+		// Zip archives keep their index at the end, so the whole archive has
+		// to be read first.
+		b, err := io.ReadAll(&takeoutLimitReader{in, MaxTakeoutSize})
+		if err != nil {
+			if errors.Is(err, ErrTakeoutTooLarge) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("cannot read takeout: %w", err)
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrTakeoutInvalid, err)
+		}
+
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("%w: cannot open %q: %v", ErrTakeoutInvalid, f.Name, err)
+			}
+			err = readFile(f.Name, rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	case TakeoutTar:
+		tr := tar.NewReader(&takeoutLimitReader{in, MaxTakeoutSize})
+		for {
+			h, err := tr.Next()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if errors.Is(err, ErrTakeoutTooLarge) {
+					return nil, err
+				}
+				return nil, fmt.Errorf("%w: %v", ErrTakeoutInvalid, err)
+			}
+			if h.Typeflag != tar.TypeReg {
+				continue
+			}
+			if err := readFile(h.Name, tr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// takeoutLimitReader is like [io.LimitedReader], except that it fails with
+// [ErrTakeoutTooLarge] once the limit is exceeded rather than ending early,
+// which would otherwise look like a truncated archive.
+type takeoutLimitReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *takeoutLimitReader) Read(p []byte) (int, error) {
+	if r.n < 0 {
+		return 0, ErrTakeoutTooLarge
+	}
+	if int64(len(p)) > r.n+1 {
+		p = p[:r.n+1]
+	}
+	n, err := r.r.Read(p)
+	r.n -= int64(n)
+	if r.n < 0 {
+		return n, ErrTakeoutTooLarge
+	}
+	return n, err
+}
+
+func unmarshalTakeoutFile(files map[string][]byte, name string, v any) error {
+	b, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%w: missing %s", ErrTakeoutInvalid, name)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%w: cannot parse %s: %v", ErrTakeoutInvalid, name, err)
+	}
+	return nil
+}
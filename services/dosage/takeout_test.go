@@ -0,0 +1,252 @@
+package dosage
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"e2clicker.app/internal/userlimit"
+	"e2clicker.app/services/dosage/openapi"
+	"e2clicker.app/services/notification"
+	"e2clicker.app/services/user"
+	"github.com/alecthomas/assert/v2"
+	"github.com/neilotoole/slogt"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestTakeoutArchive(t *testing.T) {
+	files := []takeoutFile{
+		{takeoutManifestFile, []byte(`{"format":"e2clicker-takeout","version":1}`)},
+		{takeoutProfileFile, []byte(`{"name":"Lily","locale":"en"}`)},
+	}
+
+	for _, format := range []TakeoutFormat{TakeoutZip, TakeoutTar} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			writeTakeoutArchive(t, &buf, format, files)
+
+			read, err := readTakeoutArchive(&buf, format)
+			assert.NoError(t, err)
+			assert.Equal(t, len(files), len(read))
+			for _, f := range files {
+				assert.Equal(t, f.data, read[f.name])
+			}
+		})
+	}
+
+	t.Run("duplicate file", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeTakeoutArchive(t, &buf, TakeoutTar, append(files, files[0]))
+
+		_, err := readTakeoutArchive(&buf, TakeoutTar)
+		assert.IsError(t, err, ErrTakeoutInvalid)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeTakeoutArchive(t, &buf, TakeoutTar, []takeoutFile{
+			{takeoutDosesFile, make([]byte, MaxTakeoutSize+1)},
+		})
+
+		_, err := readTakeoutArchive(&buf, TakeoutTar)
+		assert.IsError(t, err, ErrTakeoutTooLarge)
+	})
+
+	t.Run("not an archive", func(t *testing.T) {
+		_, err := readTakeoutArchive(bytes.NewReader([]byte("hello")), TakeoutZip)
+		assert.IsError(t, err, ErrTakeoutInvalid)
+	})
+}
+
+type fakeDosages struct {
+	DosageStorage
+	dosage *Dosage
+}
+
+func (f fakeDosages) Dosage(ctx context.Context, userID user.ID) (*Dosage, error) {
+	return f.dosage, nil
+}
+
+type fakeTakeouts struct {
+	imported []TakeoutImport
+	err      error
+}
+
+func (f *fakeTakeouts) ImportTakeout(ctx context.Context, userID user.ID, t TakeoutImport) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	f.imported = append(f.imported, t)
+	return int64(len(t.Doses)), nil
+}
+
+func TestTakeoutServiceImport(t *testing.T) {
+	ctx := context.Background()
+	userID := user.ID(1)
+
+	newServiceWithStorage := func(t *testing.T, dosages fakeDosages, takeouts TakeoutStorage) *TakeoutService {
+		logger := slogt.New(t)
+		lc := fxtest.NewLifecycle(t)
+		t.Cleanup(func() { lc.RequireStop() })
+
+		notifications, err := notification.NewNotificationService(notification.NotificationServiceConfig{}, logger)
+		assert.NoError(t, err)
+		notifs := notification.NewUserNotificationService(notification.UserNotificationServiceConfig{
+			NotificationService: notifications,
+			Logger:              logger,
+			Lifecycle:           lc,
+		})
+
+		// The dose history and reminders are only written through takeouts, so
+		// any other write panics.
+		return NewTakeoutService(nil, notifs, dosages, fakeDoseHistory{}, nil, takeouts, lc, logger)
+	}
+	newService := func(t *testing.T, dosages fakeDosages) *TakeoutService {
+		return newServiceWithStorage(t, dosages, &fakeTakeouts{})
+	}
+
+	manifest := func(version int) []byte {
+		b, err := json.Marshal(TakeoutManifest{
+			Format:     TakeoutManifestFormat,
+			Version:    version,
+			ExportedAt: time.Now(),
+		})
+		assert.NoError(t, err)
+		return b
+	}
+
+	t.Run("not fresh", func(t *testing.T) {
+		s := newService(t, fakeDosages{dosage: &Dosage{UserID: userID}})
+
+		var buf bytes.Buffer
+		writeTakeoutArchive(t, &buf, TakeoutZip, []takeoutFile{
+			{takeoutManifestFile, manifest(TakeoutVersion)},
+		})
+
+		_, err := s.ImportTakeout(ctx, &buf, userID, ImportTakeoutOptions{Format: TakeoutZip})
+		assert.IsError(t, err, ErrTakeoutNotFreshAccount)
+	})
+
+	t.Run("missing manifest", func(t *testing.T) {
+		s := newService(t, fakeDosages{})
+
+		var buf bytes.Buffer
+		writeTakeoutArchive(t, &buf, TakeoutZip, []takeoutFile{
+			{takeoutProfileFile, []byte(`{}`)},
+		})
+
+		_, err := s.ImportTakeout(ctx, &buf, userID, ImportTakeoutOptions{Format: TakeoutZip})
+		assert.IsError(t, err, ErrTakeoutInvalid)
+	})
+
+	t.Run("failed imports are limited", func(t *testing.T) {
+		s := newService(t, fakeDosages{})
+
+		for range 3 {
+			_, err := s.ImportTakeout(ctx, strings.NewReader("not an archive"), userID, ImportTakeoutOptions{Format: TakeoutZip})
+			assert.IsError(t, err, ErrTakeoutInvalid)
+		}
+
+		var limitErr *userlimit.LimitExceededError
+		_, err := s.ImportTakeout(ctx, strings.NewReader("not an archive"), userID, ImportTakeoutOptions{Format: TakeoutZip})
+		assert.True(t, errors.As(err, &limitErr), "the limit was reached")
+	})
+
+	t.Run("newer version", func(t *testing.T) {
+		s := newService(t, fakeDosages{})
+
+		var buf bytes.Buffer
+		writeTakeoutArchive(t, &buf, TakeoutZip, []takeoutFile{
+			{takeoutManifestFile, manifest(TakeoutVersion + 1)},
+		})
+
+		_, err := s.ImportTakeout(ctx, &buf, userID, ImportTakeoutOptions{Format: TakeoutZip})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not supported")
+	})
+
+	t.Run("storage fails", func(t *testing.T) {
+		doses, err := json.Marshal([]openapi.Dose{
+			{DeliveryMethod: "EV im", Dose: 4, TakenAt: time.Now().Add(-24 * time.Hour)},
+			{DeliveryMethod: "EV im", Dose: 4, TakenAt: time.Now()},
+		})
+		assert.NoError(t, err)
+
+		files := []takeoutFile{
+			{takeoutManifestFile, manifest(TakeoutVersion)},
+			{takeoutProfileFile, []byte(`{"name":"Lily","locale":"en"}`)},
+			{takeoutDosageFile, []byte(`null`)},
+			{takeoutDosesFile, doses},
+			{takeoutNotificationPrefsFile, []byte(`{}`)},
+			{takeoutNotificationHistoryFile, []byte(`[{"sentAt":"2024-01-01T00:00:00Z","doseDueAt":"2024-01-01T00:00:00Z"}]`)},
+		}
+
+		takeouts := &fakeTakeouts{err: errors.New("connection reset")}
+		s := newServiceWithStorage(t, fakeDosages{}, takeouts)
+
+		var buf bytes.Buffer
+		writeTakeoutArchive(t, &buf, TakeoutZip, files)
+
+		_, err = s.ImportTakeout(ctx, &buf, userID, ImportTakeoutOptions{Format: TakeoutZip})
+		assert.IsError(t, err, takeouts.err)
+		assert.Zero(t, takeouts.imported, "nothing is written")
+
+		// The account is still fresh, so the import can be retried.
+		takeouts.err = nil
+		buf.Reset()
+		writeTakeoutArchive(t, &buf, TakeoutZip, files)
+
+		r, err := s.ImportTakeout(ctx, &buf, userID, ImportTakeoutOptions{Format: TakeoutZip})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), r.Doses)
+		assert.Equal(t, 1, len(takeouts.imported), "everything is written at once")
+
+		imported := takeouts.imported[0]
+		assert.Equal(t, "Lily", imported.Name)
+		assert.Equal(t, user.Locale("en"), imported.Locale)
+		assert.Equal(t, 2, len(imported.Doses))
+		assert.Equal(t, 1, len(imported.RemindedDoseAttempts))
+	})
+
+	t.Run("invalid profile", func(t *testing.T) {
+		takeouts := &fakeTakeouts{}
+		s := newServiceWithStorage(t, fakeDosages{}, takeouts)
+
+		var buf bytes.Buffer
+		writeTakeoutArchive(t, &buf, TakeoutZip, []takeoutFile{
+			{takeoutManifestFile, manifest(TakeoutVersion)},
+			{takeoutProfileFile, []byte(`{"name":"Lily","locale":"whatever lol"}`)},
+			{takeoutDosageFile, []byte(`null`)},
+			{takeoutDosesFile, []byte(`[]`)},
+			{takeoutNotificationPrefsFile, []byte(`{}`)},
+			{takeoutNotificationHistoryFile, []byte(`[]`)},
+		})
+
+		_, err := s.ImportTakeout(ctx, &buf, userID, ImportTakeoutOptions{Format: TakeoutZip})
+		assert.IsError(t, err, user.ErrInvalidLocale)
+		assert.Zero(t, takeouts.imported, "nothing is written")
+	})
+}
+
+func writeTakeoutArchive(t *testing.T, buf *bytes.Buffer, format TakeoutFormat, files []takeoutFile) {
+	t.Helper()
+
+	var aw takeoutArchiveWriter
+	switch format {
+	case TakeoutZip:
+		aw = &takeoutZipWriter{zip.NewWriter(buf)}
+	case TakeoutTar:
+		aw = &takeoutTarWriter{tar.NewWriter(buf)}
+	}
+
+	for _, f := range files {
+		assert.NoError(t, aw.WriteFile(f.name, f.data, time.Now()))
+	}
+	assert.NoError(t, aw.Close())
+}
@@ -0,0 +1,303 @@
+package notification
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"e2clicker.app/services/user"
+
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
+)
+
+// sealedCredentialPrefix marks a credential that has been encrypted with
+// [credentialKeys]. It is followed by the key ID, the wrapped data key and the
+// encrypted credential, separated by colons.
+const sealedCredentialPrefix = "enc:v2:"
+
+// credentialSchemaKeyword marks the properties of a config schema that hold
+// credentials, which are encrypted at rest.
+const credentialSchemaKeyword = "x-secret"
+
+var (
+	errCredentialKeysMissing = errors.New("credential is encrypted but no credential keys are configured")
+	errCredentialKeyUnknown  = errors.New("credential is encrypted with an unknown key")
+	errCredentialMalformed   = errors.New("malformed encrypted credential")
+)
+
+// credentialKeys encrypts credentials in notification configs using envelope
+// encryption: every credential is encrypted with its own random data key, and
+// the data key is encrypted (wrapped) with a server key. The ID of the server
+// key is stored alongside, so server keys can be rotated.
+type credentialKeys struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// newCredentialKeys loads the credential keys from the config. It returns nil
+// if no keys are configured.
+func newCredentialKeys(config e2clickermodule.Notification) (*credentialKeys, error) {
+	if config.CredentialKeys == nil {
+		return nil, nil
+	}
+
+	var c *e2clickermodule.CredentialKeysSubmodule
+
+	switch value := config.CredentialKeys.Value.(type) {
+	case e2clickermodule.CredentialKeysSubmodule:
+		c = &value
+	case e2clickermodule.CredentialKeysPath:
+		b, err := os.ReadFile(string(value))
+		if err != nil {
+			return nil, fmt.Errorf("cannot read credential keys file at %s: %w", value, err)
+		}
+		c = new(e2clickermodule.CredentialKeysSubmodule)
+		if err := json.Unmarshal(b, c); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal credential keys at %s: %w", value, err)
+		}
+	default:
+		panic("unreachable")
+	}
+
+	k := &credentialKeys{
+		primary: c.Primary,
+		keys:    make(map[string]cipher.AEAD, len(c.Keys)),
+	}
+
+	for id, key := range c.Keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid credential key ID %q: must be non-empty and not contain ':'", id)
+		}
+
+		b, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credential key %q: %w", id, err)
+		}
+		if len(b) != 32 {
+			return nil, fmt.Errorf("invalid credential key %q: must be 32 bytes, got %d", id, len(b))
+		}
+
+		aead, err := newAESGCM(b)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credential key %q: %w", id, err)
+		}
+		k.keys[id] = aead
+	}
+
+	if _, ok := k.keys[k.primary]; !ok {
+		return nil, fmt.Errorf("primary credential key %q is not in the keys", k.primary)
+	}
+
+	return k, nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts a credential with a new data key that is wrapped with the
+// primary key. ad is authenticated along with the credential, so that it
+// cannot be moved somewhere else.
+func (k *credentialKeys) seal(plaintext string, ad credentialAD) (string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+
+	data, err := newAESGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext := sealAEAD(data, []byte(plaintext), []byte(ad.String()))
+	wrapped := sealAEAD(k.keys[k.primary], dataKey, []byte(k.primary))
+
+	return sealedCredentialPrefix + k.primary +
+		":" + base64.RawURLEncoding.EncodeToString(wrapped) +
+		":" + base64.RawURLEncoding.EncodeToString(ciphertext), nil
+}
+
+// open decrypts a credential sealed by [credentialKeys.seal]. Credentials that
+// were stored before encryption was enabled are returned as they are.
+func (k *credentialKeys) open(sealed string, ad credentialAD) (string, error) {
+	keyID, wrapped, ciphertext, ok := parseSealedCredential(sealed)
+	if !ok {
+		if strings.HasPrefix(sealed, sealedCredentialPrefix) {
+			return "", errCredentialMalformed
+		}
+		return sealed, nil
+	}
+
+	if k == nil {
+		return "", errCredentialKeysMissing
+	}
+
+	key, ok := k.keys[keyID]
+	if !ok {
+		return "", errCredentialKeyUnknown
+	}
+
+	dataKey, err := openAEAD(key, wrapped, []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("cannot unwrap data key: %w", err)
+	}
+
+	data, err := newAESGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := openAEAD(data, ciphertext, []byte(ad.String()))
+	if err != nil {
+		return "", fmt.Errorf("cannot decrypt credential: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// needsReseal returns true if the credential is in plain text or encrypted
+// with a key other than the primary one.
+func (k *credentialKeys) needsReseal(sealed string) bool {
+	if k == nil {
+		return false
+	}
+	keyID, _, _, ok := parseSealedCredential(sealed)
+	return !ok || keyID != k.primary
+}
+
+func parseSealedCredential(s string) (keyID string, wrapped, ciphertext []byte, ok bool) {
+	rest, ok := strings.CutPrefix(s, sealedCredentialPrefix)
+	if !ok {
+		return "", nil, nil, false
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 {
+		return "", nil, nil, false
+	}
+
+	wrapped, err1 := base64.RawURLEncoding.DecodeString(parts[1])
+	ciphertext, err2 := base64.RawURLEncoding.DecodeString(parts[2])
+	if err1 != nil || err2 != nil {
+		return "", nil, nil, false
+	}
+
+	return parts[0], wrapped, ciphertext, true
+}
+
+func sealAEAD(aead cipher.AEAD, plaintext, ad []byte) []byte {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		panic(err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, ad)
+}
+
+func openAEAD(aead cipher.AEAD, b, ad []byte) ([]byte, error) {
+	if len(b) < aead.NonceSize() {
+		return nil, errCredentialMalformed
+	}
+	nonce, ciphertext := b[:aead.NonceSize()], b[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, ad)
+}
+
+// credentialFields returns the paths of the properties that are marked as
+// credentials in a config schema.
+func credentialFields(schema json.RawMessage) ([][]string, error) {
+	type schemaNode struct {
+		Secret     bool                       `json:"x-secret"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+
+	var walk func(raw json.RawMessage, path []string) ([][]string, error)
+	walk = func(raw json.RawMessage, path []string) ([][]string, error) {
+		var node schemaNode
+		if err := json.Unmarshal(raw, &node); err != nil {
+			return nil, err
+		}
+		if node.Secret {
+			return [][]string{path}, nil
+		}
+		var fields [][]string
+		for name, prop := range node.Properties {
+			f, err := walk(prop, append(path[:len(path):len(path)], name))
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, f...)
+		}
+		return fields, nil
+	}
+
+	return walk(schema, nil)
+}
+
+// mapCredentials calls f with every credential field of a config that is set
+// to a string. The config is only re-encoded if f changes any of them.
+func mapCredentials(config json.RawMessage, fields [][]string, f func(path []string, value string) (string, error)) (json.RawMessage, error) {
+	if len(fields) == 0 {
+		return config, nil
+	}
+
+	d := json.NewDecoder(bytes.NewReader(config))
+	d.UseNumber()
+
+	var v map[string]any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var changed bool
+	for _, path := range fields {
+		obj := v
+		for _, name := range path[:len(path)-1] {
+			obj, _ = obj[name].(map[string]any)
+		}
+
+		name := path[len(path)-1]
+		value, ok := obj[name].(string)
+		if !ok || value == "" {
+			continue
+		}
+
+		newValue, err := f(path, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", strings.Join(path, "."), err)
+		}
+		if newValue != value {
+			obj[name] = newValue
+			changed = true
+		}
+	}
+
+	if !changed {
+		return config, nil
+	}
+	return json.Marshal(v)
+}
+
+// credentialAD is what a credential is bound to when it is sealed: the field
+// at path of the config with the given ID and method that belongs to the user.
+// A sealed credential that is copied anywhere else can't be opened.
+type credentialAD struct {
+	userID   user.ID
+	configID string
+	method   string
+	path     []string
+}
+
+// String returns the additional data that the credential is sealed with.
+func (ad credentialAD) String() string {
+	return fmt.Sprintf("%d:%s:%s:%s", ad.userID, ad.configID, ad.method, strings.Join(ad.path, "."))
+}
@@ -0,0 +1,191 @@
+package notification
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
+	"e2clicker.app/services/user"
+	"github.com/alecthomas/assert/v2"
+	"github.com/neilotoole/slogt"
+)
+
+func testCredentialKeysConfig(primary string, ids ...string) e2clickermodule.Notification {
+	keys := make(map[string]string, len(ids))
+	for i, id := range ids {
+		keys[id] = base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(rune('a'+i)), 32)))
+	}
+	return e2clickermodule.Notification{
+		CredentialKeys: &e2clickermodule.CredentialKeysJSON{
+			Value: e2clickermodule.CredentialKeysSubmodule{
+				Primary: primary,
+				Keys:    keys,
+			},
+		},
+	}
+}
+
+func TestCredentialKeys(t *testing.T) {
+	k, err := newCredentialKeys(testCredentialKeysConfig("new", "old", "new"))
+	assert.NoError(t, err)
+
+	ad := credentialAD{1, "abc", GotifyMethod, []string{"token"}}
+
+	sealed, err := k.seal("hunter2", ad)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sealed, sealedCredentialPrefix+"new:"))
+	assert.NotContains(t, sealed, "hunter2")
+	assert.False(t, k.needsReseal(sealed))
+
+	plaintext, err := k.open(sealed, ad)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", plaintext)
+
+	// The credential can't be moved to another field, config or user.
+	for _, other := range []credentialAD{
+		{1, "abc", PushoverMethod, []string{"token"}},
+		{1, "abc", GotifyMethod, []string{"base_url"}},
+		{1, "def", GotifyMethod, []string{"token"}},
+		{2, "abc", GotifyMethod, []string{"token"}},
+	} {
+		_, err = k.open(sealed, other)
+		assert.Error(t, err)
+	}
+
+	// Credentials from before encryption are returned as they are.
+	plaintext, err = k.open("hunter2", ad)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", plaintext)
+	assert.True(t, k.needsReseal("hunter2"))
+
+	// Credentials sealed with an old key can still be opened.
+	old, err := newCredentialKeys(testCredentialKeysConfig("old", "old"))
+	assert.NoError(t, err)
+
+	sealed, err = old.seal("hunter2", ad)
+	assert.NoError(t, err)
+	assert.True(t, k.needsReseal(sealed))
+
+	plaintext, err = k.open(sealed, ad)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", plaintext)
+
+	// Removing the key makes the credential unusable.
+	removed, err := newCredentialKeys(testCredentialKeysConfig("new", "new"))
+	assert.NoError(t, err)
+	_, err = removed.open(sealed, ad)
+	assert.IsError(t, err, errCredentialKeyUnknown)
+
+	var none *credentialKeys
+	_, err = none.open(sealed, ad)
+	assert.IsError(t, err, errCredentialKeysMissing)
+}
+
+func TestNewCredentialKeysInvalid(t *testing.T) {
+	_, err := newCredentialKeys(testCredentialKeysConfig("missing", "a"))
+	assert.Error(t, err)
+
+	_, err = newCredentialKeys(testCredentialKeysConfig("a:b", "a:b"))
+	assert.Error(t, err)
+
+	config := testCredentialKeysConfig("a", "a")
+	config.CredentialKeys.Value = e2clickermodule.CredentialKeysSubmodule{
+		Primary: "a",
+		Keys:    map[string]string{"a": base64.StdEncoding.EncodeToString([]byte("short"))},
+	}
+	_, err = newCredentialKeys(config)
+	assert.Error(t, err)
+}
+
+func TestCredentialFields(t *testing.T) {
+	schema, err := configSchemas.ReadFile("schemas/webPush.json")
+	assert.NoError(t, err)
+
+	fields, err := credentialFields(schema)
+	assert.NoError(t, err)
+	slices.SortFunc(fields, slices.Compare)
+	assert.Equal(t, [][]string{{"keys", "auth"}, {"keys", "p256dh"}}, fields)
+}
+
+type recordingGotifyNotifier struct{ sent *[]GotifyNotificationConfig }
+
+func (n recordingGotifyNotifier) Notify(ctx context.Context, _ Notification, c GotifyNotificationConfig) error {
+	*n.sent = append(*n.sent, c)
+	return nil
+}
+
+func TestNotificationServiceCredentials(t *testing.T) {
+	var sent []GotifyNotificationConfig
+	gotify, err := NewNotifier[GotifyNotificationConfig](GotifyMethod, recordingGotifyNotifier{&sent})
+	assert.NoError(t, err)
+
+	newService := func(config e2clickermodule.Notification) *NotificationService {
+		service, err := NewNotificationService(NotificationServiceConfig{
+			Config:    config,
+			Notifiers: []Notifier{gotify},
+		}, slogt.New(t))
+		assert.NoError(t, err)
+		return service
+	}
+
+	ctx := context.Background()
+	userID := user.ID(1)
+	plain := mustConfigs(t, map[string]any{
+		"gotify": []any{map[string]any{"base_url": "https://gotify.example.com", "token": "hunter2"}},
+	})
+
+	// Without keys, credentials stay in plain text.
+	service := newService(e2clickermodule.Notification{})
+	configs, err := service.ValidateConfigs(ctx, userID, plain, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, string(configs[0].Config), "hunter2")
+
+	// Plain text configs stored before keys were configured are resealed.
+	service = newService(testCredentialKeysConfig("old", "old"))
+	resealed, changed, err := service.resealConfigs(userID, configs)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.NotContains(t, string(resealed[0].Config), "hunter2")
+
+	_, changed, err = service.resealConfigs(userID, resealed)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// After a rotation, the old key is still used to read the configs, and
+	// they are resealed with the new key.
+	service = newService(testCredentialKeysConfig("new", "old", "new"))
+	assert.NoError(t, service.Notify(ctx, userID, Notification{}, resealed))
+	assert.Equal(t, "hunter2", sent[0].Token)
+
+	rotated, changed, err := service.resealConfigs(userID, resealed)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Contains(t, string(rotated[0].Config), sealedCredentialPrefix+"new:")
+
+	// Encrypted configs that are sent back are accepted and kept encrypted.
+	configs, err = service.ValidateConfigs(ctx, userID, rotated, rotated)
+	assert.NoError(t, err)
+	var stored GotifyNotificationConfig
+	assert.NoError(t, json.Unmarshal(configs[0].Config, &stored))
+	assert.True(t, strings.HasPrefix(stored.Token, sealedCredentialPrefix))
+	assert.Equal(t, "https://gotify.example.com", stored.BaseURL)
+
+	sent = nil
+	assert.NoError(t, service.Notify(ctx, userID, Notification{}, configs))
+	assert.Equal(t, "hunter2", sent[0].Token)
+
+	// Another user can't take over the encrypted credentials.
+	_, err = service.ValidateConfigs(ctx, userID+1, configs, nil)
+	assert.Error(t, err)
+	assert.Error(t, service.Notify(ctx, userID+1, Notification{}, configs))
+
+	// Neither can another config of the same user.
+	moved := slices.Clone(configs)
+	moved[0].ID = "other"
+	_, err = service.ValidateConfigs(ctx, userID, moved, configs)
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+package notification
+
+import (
+	"time"
+
+	"e2clicker.app/services/notification/openapi"
+)
+
+// DigestFrequency is how often a user gets a digest of their doses.
+type DigestFrequency = openapi.DigestFrequency
+
+// DigestSummary is the summary that a digest notification is about.
+type DigestSummary = openapi.DigestSummary
+
+// digestHour is the hour of the day, in the user's time zone, that digests
+// are sent at once their period has ended.
+const digestHour = 9
+
+// validDigestFrequency returns true if f is a known frequency or empty.
+func validDigestFrequency(f DigestFrequency) bool {
+	switch f {
+	case "", openapi.WeeklyDigest, openapi.MonthlyDigest:
+		return true
+	default:
+		return false
+	}
+}
+
+// DigestPeriod returns the last full period that the user's digest covers as
+// of now, in the user's time zone. Weekly periods start on Monday and monthly
+// periods on the first of the month.
+//
+// due is true if the digest for the period should be sent now: digests are
+// enabled, it is past [digestHour] on the day that the period ended, and no
+// digest has been sent since.
+func (p UserPreferences) DigestPeriod(now time.Time) (start, end time.Time, due bool) {
+	now = now.In(p.Location())
+	y, m, d := now.Date()
+
+	switch p.DigestFrequency {
+	case openapi.WeeklyDigest:
+		daysSinceMonday := (int(now.Weekday()) + 6) % 7
+		end = time.Date(y, m, d-daysSinceMonday, 0, 0, 0, 0, now.Location())
+		start = end.AddDate(0, 0, -7)
+	case openapi.MonthlyDigest:
+		end = time.Date(y, m, 1, 0, 0, 0, 0, now.Location())
+		start = end.AddDate(0, -1, 0)
+	default:
+		return time.Time{}, time.Time{}, false
+	}
+
+	sendAt := time.Date(end.Year(), end.Month(), end.Day(), digestHour, 0, 0, 0, end.Location())
+	due = !now.Before(sendAt) && (p.LastDigestAt == nil || p.LastDigestAt.Before(end))
+	return start, end, due
+}
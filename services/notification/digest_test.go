@@ -0,0 +1,55 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"e2clicker.app/services/notification/openapi"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestDigestPeriod(t *testing.T) {
+	tz, err := time.LoadLocation("America/Los_Angeles")
+	assert.NoError(t, err)
+
+	// Wednesday, March 13, 2024, 10:00 in Los Angeles.
+	now := time.Date(2024, 3, 13, 10, 0, 0, 0, tz)
+
+	prefs := UserPreferences{Timezone: "America/Los_Angeles"}
+	_, _, due := prefs.DigestPeriod(now)
+	assert.False(t, due, "digests are off by default")
+
+	prefs.DigestFrequency = openapi.WeeklyDigest
+	start, end, due := prefs.DigestPeriod(now)
+	assert.True(t, due)
+	assert.Equal(t, time.Date(2024, 3, 4, 0, 0, 0, 0, tz), start)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, tz), end)
+
+	sentAt := time.Date(2024, 3, 11, 9, 5, 0, 0, tz)
+	prefs.LastDigestAt = &sentAt
+	_, _, due = prefs.DigestPeriod(now)
+	assert.False(t, due, "the digest was already sent")
+
+	// Digests are sent in the morning after the period ends.
+	_, _, due = prefs.DigestPeriod(time.Date(2024, 3, 18, 8, 0, 0, 0, tz))
+	assert.False(t, due)
+	_, _, due = prefs.DigestPeriod(time.Date(2024, 3, 18, 9, 0, 0, 0, tz))
+	assert.True(t, due)
+
+	prefs.DigestFrequency = openapi.MonthlyDigest
+	start, end, due = prefs.DigestPeriod(now)
+	assert.False(t, due, "the last digest was sent after February ended")
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, tz), start)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, tz), end)
+
+	_, _, due = prefs.DigestPeriod(time.Date(2024, 4, 1, 9, 0, 0, 0, tz))
+	assert.True(t, due)
+}
+
+func TestUserPreferencesValidateDigestFrequency(t *testing.T) {
+	prefs := UserPreferences{DigestFrequency: "daily"}
+	assert.IsError(t, prefs.Validate(), ErrUnknownDigestFrequency)
+
+	prefs.DigestFrequency = openapi.MonthlyDigest
+	assert.NoError(t, prefs.Validate())
+}
@@ -0,0 +1,174 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+// emailsFS contains the built-in email templates. Each notification type can
+// have a {type}.html.tmpl and {type}.txt.tmpl template that defines the
+// "content" block of layout.html.tmpl and layout.txt.tmpl. Types without
+// their own templates use default.html.tmpl and default.txt.tmpl.
+//
+//go:embed emails
+var emailsFS embed.FS
+
+const (
+	emailLayoutName  = "layout"
+	emailDefaultName = "default"
+	emailHTMLSuffix  = ".html.tmpl"
+	emailTextSuffix  = ".txt.tmpl"
+)
+
+// emailTemplateData is the data that email templates are executed with.
+type emailTemplateData struct {
+	Notification
+	// Address is the email address that the email is sent to.
+	Address string
+	// LogoCID is the Content-ID of the embedded logo image.
+	LogoCID string
+	// AccountURL links to the user's account settings, if known.
+	AccountURL string
+	// DashboardURL links to the user's dashboard, if known.
+	DashboardURL string
+	// UnsubscribeURL links to the page that unsubscribes Address, if known.
+	UnsubscribeURL string
+	// ConfirmURL links to the page that confirms Address. It is only set for
+	// confirmation emails.
+	ConfirmURL string
+}
+
+// emailTemplates holds the parsed email templates by name, which is either a
+// notification type, "default" or the name of an email that isn't a
+// notification, such as "email_confirmation".
+type emailTemplates struct {
+	html map[string]*htmltemplate.Template
+	text map[string]*texttemplate.Template
+}
+
+// loadEmailTemplates parses the built-in email templates. If overrideDir is
+// not empty, templates in it take precedence over the built-in ones with the
+// same file name.
+func loadEmailTemplates(overrideDir string) (*emailTemplates, error) {
+	builtin, err := fs.Sub(emailsFS, "emails")
+	if err != nil {
+		return nil, err
+	}
+
+	fsys := builtin
+	names := make(map[string]struct{})
+
+	if err := addEmailTemplateNames(builtin, names); err != nil {
+		return nil, err
+	}
+
+	if overrideDir != "" {
+		dir := os.DirFS(overrideDir)
+		if err := addEmailTemplateNames(dir, names); err != nil {
+			return nil, fmt.Errorf("cannot read email templates directory: %w", err)
+		}
+		fsys = overlayFS{upper: dir, lower: builtin}
+	}
+
+	htmlLayout, err := htmltemplate.ParseFS(fsys, emailLayoutName+emailHTMLSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse HTML email layout: %w", err)
+	}
+
+	textLayout, err := texttemplate.ParseFS(fsys, emailLayoutName+emailTextSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse text email layout: %w", err)
+	}
+
+	t := &emailTemplates{
+		html: make(map[string]*htmltemplate.Template, len(names)),
+		text: make(map[string]*texttemplate.Template, len(names)),
+	}
+
+	for name := range names {
+		html := htmltemplate.Must(htmlLayout.Clone())
+		if _, err := html.ParseFS(fsys, name+emailHTMLSuffix); err != nil {
+			return nil, fmt.Errorf("cannot parse HTML email template %q: %w", name, err)
+		}
+
+		text := texttemplate.Must(textLayout.Clone())
+		if _, err := text.ParseFS(fsys, name+emailTextSuffix); err != nil {
+			return nil, fmt.Errorf("cannot parse text email template %q: %w", name, err)
+		}
+
+		t.html[name] = html
+		t.text[name] = text
+	}
+
+	if t.html[emailDefaultName] == nil {
+		return nil, errors.New("missing default email template")
+	}
+
+	return t, nil
+}
+
+// addEmailTemplateNames adds the names of all templates in fsys that have
+// both an HTML and a text variant, excluding the layout.
+func addEmailTemplateNames(fsys fs.FS, names map[string]struct{}) error {
+	htmls, err := fs.Glob(fsys, "*"+emailHTMLSuffix)
+	if err != nil {
+		return err
+	}
+	for _, file := range htmls {
+		name := strings.TrimSuffix(file, emailHTMLSuffix)
+		if name == emailLayoutName {
+			continue
+		}
+		if _, err := fs.Stat(fsys, name+emailTextSuffix); err != nil {
+			return fmt.Errorf("email template %q has no text variant: %w", name, err)
+		}
+		names[name] = struct{}{}
+	}
+	return nil
+}
+
+// render renders the text and HTML bodies of an email using the template with
+// the given name.
+func (t *emailTemplates) render(name string, data emailTemplateData) (text, html string, err error) {
+	if t.html[name] == nil {
+		name = emailDefaultName
+	}
+
+	var textBuf, htmlBuf bytes.Buffer
+
+	if err := t.text[name].ExecuteTemplate(&textBuf, emailLayoutName+emailTextSuffix, data); err != nil {
+		return "", "", fmt.Errorf("cannot render text email %q: %w", name, err)
+	}
+
+	if err := t.html[name].ExecuteTemplate(&htmlBuf, emailLayoutName+emailHTMLSuffix, data); err != nil {
+		return "", "", fmt.Errorf("cannot render HTML email %q: %w", name, err)
+	}
+
+	return textBuf.String(), htmlBuf.String(), nil
+}
+
+// overlayFS is a file system that opens files from upper, falling back to
+// lower if they don't exist there.
+type overlayFS struct {
+	upper fs.FS
+	lower fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	f, err := o.upper.Open(name)
+	if err == nil {
+		return f, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return o.lower.Open(name)
+}
@@ -0,0 +1,99 @@
+package notification
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"e2clicker.app/services/user"
+)
+
+// ErrInvalidEmailToken is returned when the token of an email link cannot be
+// opened, e.g. because it was tampered with or is for something else.
+var ErrInvalidEmailToken = errors.New("invalid email link")
+
+// emailTokenPurpose describes what an email token may be used for.
+// It is authenticated along with the token, so a token for one purpose can't
+// be used for another.
+type emailTokenPurpose string
+
+const (
+	emailTokenUnsubscribe emailTokenPurpose = "unsubscribe"
+	emailTokenConfirm     emailTokenPurpose = "confirm"
+)
+
+// emailToken is the content of a token in an email link. It is sealed so that
+// it can't be forged for another user.
+type emailToken struct {
+	UserID  user.ID `json:"u"`
+	Address string  `json:"a"`
+	// ExpiresAt is the Unix time after which the token is no longer valid.
+	// Zero means the token never expires.
+	ExpiresAt int64 `json:"e,omitempty"`
+}
+
+// emailTokenSealer seals and opens email tokens using AES-GCM.
+type emailTokenSealer struct {
+	aead cipher.AEAD
+}
+
+func newEmailTokenSealer(key string) (*emailTokenSealer, error) {
+	k := sha256.Sum256([]byte(key))
+
+	block, err := aes.NewCipher(k[:])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &emailTokenSealer{aead: aead}, nil
+}
+
+// seal returns the URL-safe sealed token.
+func (s *emailTokenSealer) seal(purpose emailTokenPurpose, t emailToken) (string, error) {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, s.aead.NonceSize(), s.aead.NonceSize()+len(b)+s.aead.Overhead())
+	rand.Read(nonce)
+
+	sealed := s.aead.Seal(nonce, nonce, b, []byte(purpose))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// open opens a token that was sealed for the given purpose.
+func (s *emailTokenSealer) open(purpose emailTokenPurpose, token string) (emailToken, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sealed) < s.aead.NonceSize() {
+		return emailToken{}, ErrInvalidEmailToken
+	}
+
+	nonce, sealed := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+
+	b, err := s.aead.Open(nil, nonce, sealed, []byte(purpose))
+	if err != nil {
+		return emailToken{}, ErrInvalidEmailToken
+	}
+
+	var t emailToken
+	if err := json.Unmarshal(b, &t); err != nil {
+		return emailToken{}, ErrInvalidEmailToken
+	}
+
+	if t.ExpiresAt != 0 && time.Now().Unix() > t.ExpiresAt {
+		return emailToken{}, ErrInvalidEmailToken
+	}
+
+	return t, nil
+}
@@ -1,6 +1,7 @@
 package notification
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -8,20 +9,56 @@ import (
 	"time"
 
 	"e2clicker.app/internal/publicerrors"
+	"e2clicker.app/services/user"
 )
 
 func init() {
 	publicerrors.MarkTypePublic[UnknownServiceError]()
-	publicerrors.MarkTypePublic[HTTPUnknownStatusError]()
+	publicerrors.MarkTypePublicWithStringer(func(ctx context.Context, err HTTPUnknownStatusError, outer string) string {
+		if outer != "" {
+			return outer + ": " + err.publicError()
+		}
+		return err.publicError()
+	})
+	publicerrors.MarkTypePublic[HTTPRateLimitedError]()
 	publicerrors.MarkTypePublic[ConfigError]()
 	publicerrors.MarkTypePublic[WebPushSubscriptionExpired]()
+	publicerrors.MarkTypePublic[MessageTemplateError]()
 	publicerrors.MarkValuesPublic(ErrWebPushNotAvailable)
+	publicerrors.MarkValuesPublic(ErrUnknownVAPIDKey)
 	publicerrors.MarkValuesPublic(ErrUnknownNotificationType)
+	publicerrors.MarkValuesPublic(ErrInvalidEmailToken)
+	publicerrors.MarkValuesPublic(ErrEmailNotAvailable)
+	publicerrors.MarkValuesPublic(ErrEmailConfirmationNotAvailable)
+	publicerrors.MarkValuesPublic(ErrUnknownDigestFrequency)
+	publicerrors.MarkValuesPublic(ErrNotificationConfigNotFound)
+	publicerrors.MarkValuesPublic(ErrTestMethodRequired)
+	publicerrors.MarkValuesPublic(ErrUnsavedEmailTest)
 }
 
 // ErrUnknownNotificationType is returned when the notification type is unknown.
 var ErrUnknownNotificationType = errors.New("unknown notification type")
 
+// ErrUnknownTimezone is returned when the user's time zone is not a known IANA
+// time zone. It is the same error as [user.ErrUnknownTimezone].
+var ErrUnknownTimezone = user.ErrUnknownTimezone
+
+// ErrUnknownDigestFrequency is returned when the user's digest frequency is
+// not one of the known frequencies.
+var ErrUnknownDigestFrequency = errors.New("unknown digest frequency")
+
+// ErrNotificationConfigNotFound is returned when a test notification targets
+// notification configs that the user doesn't have.
+var ErrNotificationConfigNotFound = errors.New("notification config not found")
+
+// ErrTestMethodRequired is returned when an unsaved config is tested without
+// its method.
+var ErrTestMethodRequired = errors.New("method is required to test an unsaved config")
+
+// ErrUnsavedEmailTest is returned when an unsaved email config is tested.
+// Email addresses must be confirmed before they receive anything.
+var ErrUnsavedEmailTest = errors.New("email addresses must be saved and confirmed before they can be tested")
+
 // UnknownServiceError is returned when an unknown service is requested.
 type UnknownServiceError struct {
 	Service string `json:"service"`
@@ -56,8 +93,10 @@ type HTTPUnknownStatusError struct {
 	// StatusCode is the HTTP status code of the API response.
 	StatusCode int `json:"statusCode"`
 	// Body is the body of the API response.
-	// It is truncated to [HTTPErrorMaxBodySize] bytes.
-	Body string `json:"body"`
+	// It is truncated to [HTTPErrorMaxBodySize] bytes. It is only logged and
+	// never shown to the user, since configs such as Gotify's can point at
+	// any server.
+	Body string `json:"-"`
 }
 
 const HTTPErrorMaxBodySize = 1024
@@ -66,6 +105,12 @@ func (e HTTPUnknownStatusError) Error() string {
 	return fmt.Sprintf("unknown HTTP status code %d returned: %s", e.StatusCode, e.Body)
 }
 
+// publicError is the error message that is shown to the user, which leaves
+// out the body.
+func (e HTTPUnknownStatusError) publicError() string {
+	return fmt.Sprintf("unknown HTTP status code %d returned", e.StatusCode)
+}
+
 func consumeHTTPUnknownStatusError(resp *http.Response) error {
 	limReader := io.LimitReader(resp.Body, HTTPErrorMaxBodySize)
 	body, _ := io.ReadAll(limReader)
@@ -75,6 +120,17 @@ func consumeHTTPUnknownStatusError(resp *http.Response) error {
 	}
 }
 
+// HTTPRateLimitedError is returned when an API rate limits a notification for
+// longer than the notifier is willing to wait.
+type HTTPRateLimitedError struct {
+	// RetryAfter is how long the API asked us to wait before retrying.
+	RetryAfter time.Duration `json:"retryAfter"`
+}
+
+func (e HTTPRateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited by API, retry after %s", e.RetryAfter)
+}
+
 // WebPushSubscriptionExpired is returned when a WebPush subscription has
 // expired.
 type WebPushSubscriptionExpired struct {
@@ -87,3 +143,15 @@ func (e WebPushSubscriptionExpired) Error() string {
 
 // ErrWebPushNotAvailable is returned when WebPush is not available.
 var ErrWebPushNotAvailable = fmt.Errorf("WebPush is not available")
+
+// ErrUnknownVAPIDKey is returned when a push subscription was created with a
+// VAPID key that the server no longer has. The client needs to subscribe
+// again.
+var ErrUnknownVAPIDKey = errors.New("push subscription was created with an unknown VAPID key")
+
+// ErrEmailNotAvailable is returned when email is not available.
+var ErrEmailNotAvailable = fmt.Errorf("email is not available")
+
+// ErrEmailConfirmationNotAvailable is returned when a new email address is
+// added but the server cannot send confirmation links.
+var ErrEmailConfirmationNotAvailable = fmt.Errorf("email addresses cannot be confirmed on this server")
@@ -0,0 +1,319 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"e2clicker.app/services/notification/openapi"
+)
+
+// MessageVariables are the variables that custom notification messages can
+// use in their templates. Fields that aren't relevant to a notification are
+// left as zero values.
+type MessageVariables struct {
+	// Username is the name of the user.
+	Username string
+	// Dose is the amount of the user's scheduled dose.
+	Dose float32
+	// Units is the units of Dose, e.g. "mg".
+	Units string
+	// DeliveryMethod is the full name of the delivery method of the dose.
+	DeliveryMethod string
+	// LastDoseAt is when the user last took a dose.
+	LastDoseAt time.Time
+	// SinceLastDose is how long ago the user last took a dose.
+	SinceLastDose time.Duration
+	// DueAt is when the next dose is due.
+	DueAt time.Time
+	// Overdue is how long ago the next dose was due. It is zero if the dose
+	// isn't due yet.
+	Overdue time.Duration
+	// Digest is the summary of a digest notification. It is only set for
+	// [openapi.DigestMessage].
+	Digest openapi.DigestSummary
+}
+
+// In returns a copy of the variables with all times converted to loc.
+func (v MessageVariables) In(loc *time.Location) MessageVariables {
+	if !v.LastDoseAt.IsZero() {
+		v.LastDoseAt = v.LastDoseAt.In(loc)
+	}
+	if !v.DueAt.IsZero() {
+		v.DueAt = v.DueAt.In(loc)
+	}
+	if v.Digest.Frequency != "" {
+		v.Digest.PeriodStart = v.Digest.PeriodStart.In(loc)
+		v.Digest.PeriodEnd = v.Digest.PeriodEnd.In(loc)
+		if v.Digest.NextDoseAt != nil {
+			nextDoseAt := v.Digest.NextDoseAt.In(loc)
+			v.Digest.NextDoseAt = &nextDoseAt
+		}
+	}
+	return v
+}
+
+const (
+	// maxMessageTemplateSize is the maximum size of a message template.
+	maxMessageTemplateSize = 2048
+	// maxMessageSize is the maximum size of a rendered message.
+	maxMessageSize = 4096
+)
+
+// messageFuncs is the only set of functions that message templates can use,
+// besides the allowed builtins in [messageBuiltins].
+var messageFuncs = template.FuncMap{
+	"duration": formatMessageDuration,
+	"minutes":  func(n int) time.Duration { return time.Duration(n) * time.Minute },
+	"time":     formatMessageTime("15:04"),
+	"date":     formatMessageTime("Mon, Jan 2"),
+	"datetime": formatMessageTime("Mon, Jan 2 15:04"),
+	"lower":    strings.ToLower,
+	"upper":    strings.ToUpper,
+}
+
+// messageBuiltins are the builtin template functions that message templates
+// are allowed to use.
+var messageBuiltins = map[string]bool{
+	"and":     true,
+	"or":      true,
+	"not":     true,
+	"eq":      true,
+	"ne":      true,
+	"lt":      true,
+	"le":      true,
+	"gt":      true,
+	"ge":      true,
+	"print":   true,
+	"printf":  true,
+	"println": true,
+}
+
+// MessageTemplateError is returned when a custom notification message is not
+// a valid template.
+type MessageTemplateError struct {
+	// Type is the notification type of the message.
+	Type string `json:"type"`
+	// Field is either "title" or "message".
+	Field string `json:"field"`
+	err   error
+}
+
+func (e MessageTemplateError) Error() string {
+	return fmt.Sprintf("invalid %s template for %s: %v", e.Field, e.Type, e.err)
+}
+
+func (e MessageTemplateError) Unwrap() error {
+	return e.err
+}
+
+// ValidateCustomNotifications checks that every custom notification message
+// is a valid template that renders without errors.
+func ValidateCustomNotifications(custom openapi.CustomNotifications) error {
+	var errs []error
+	for t, msg := range custom {
+		if _, err := renderMessageTemplate(msg.Title, MessageVariables{}); err != nil {
+			errs = append(errs, MessageTemplateError{t, "title", err})
+		}
+		if _, err := renderMessageTemplate(msg.Message, MessageVariables{}); err != nil {
+			errs = append(errs, MessageTemplateError{t, "message", err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// renderMessage renders the title and message templates of msg.
+func renderMessage(msg openapi.NotificationMessage, vars MessageVariables) (openapi.NotificationMessage, error) {
+	title, err := renderMessageTemplate(msg.Title, vars)
+	if err != nil {
+		return openapi.NotificationMessage{}, fmt.Errorf("cannot render title: %w", err)
+	}
+	message, err := renderMessageTemplate(msg.Message, vars)
+	if err != nil {
+		return openapi.NotificationMessage{}, fmt.Errorf("cannot render message: %w", err)
+	}
+	return openapi.NotificationMessage{
+		Title:   title,
+		Message: message,
+	}, nil
+}
+
+func renderMessageTemplate(text string, vars MessageVariables) (string, error) {
+	tmpl, err := parseMessageTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&limitedWriter{w: &b, n: maxMessageSize}, vars); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// parseMessageTemplate parses a message template and checks that it only
+// uses the allowed subset of the template language. Loops and nested
+// templates are rejected so that rendering is always cheap.
+func parseMessageTemplate(text string) (*template.Template, error) {
+	if len(text) > maxMessageTemplateSize {
+		return nil, fmt.Errorf("template is longer than %d bytes", maxMessageTemplateSize)
+	}
+
+	tmpl, err := template.New("message").Funcs(messageFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tmpl.Templates()) > 1 {
+		return nil, errors.New("defining templates is not allowed")
+	}
+
+	if tmpl.Tree != nil {
+		if err := checkMessageNode(tmpl.Tree.Root); err != nil {
+			return nil, err
+		}
+	}
+
+	return tmpl, nil
+}
+
+func checkMessageNode(node parse.Node) error {
+	switch node := node.(type) {
+	case nil:
+		return nil
+	case *parse.ListNode:
+		if node == nil {
+			return nil
+		}
+		for _, n := range node.Nodes {
+			if err := checkMessageNode(n); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.TextNode, *parse.CommentNode,
+		*parse.FieldNode, *parse.VariableNode, *parse.DotNode,
+		*parse.NilNode, *parse.BoolNode, *parse.NumberNode, *parse.StringNode:
+		return nil
+	case *parse.ActionNode:
+		return checkMessageNode(node.Pipe)
+	case *parse.IfNode:
+		return checkMessageBranch(&node.BranchNode)
+	case *parse.WithNode:
+		return checkMessageBranch(&node.BranchNode)
+	case *parse.PipeNode:
+		if node == nil {
+			return nil
+		}
+		for _, cmd := range node.Cmds {
+			if err := checkMessageNode(cmd); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.CommandNode:
+		for _, arg := range node.Args {
+			if err := checkMessageNode(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.ChainNode:
+		return checkMessageNode(node.Node)
+	case *parse.IdentifierNode:
+		if _, ok := messageFuncs[node.Ident]; ok || messageBuiltins[node.Ident] {
+			return nil
+		}
+		return fmt.Errorf("function %q is not allowed", node.Ident)
+	case *parse.RangeNode:
+		return errors.New("range is not allowed")
+	case *parse.TemplateNode:
+		return errors.New("template is not allowed")
+	default:
+		return fmt.Errorf("%s is not allowed", node)
+	}
+}
+
+func checkMessageBranch(node *parse.BranchNode) error {
+	if err := checkMessageNode(node.Pipe); err != nil {
+		return err
+	}
+	if err := checkMessageNode(node.List); err != nil {
+		return err
+	}
+	return checkMessageNode(node.ElseList)
+}
+
+// limitedWriter writes to w until n bytes have been written, after which it
+// returns an error, which stops template execution.
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+func (w *limitedWriter) Write(b []byte) (int, error) {
+	if len(b) > w.n {
+		return 0, fmt.Errorf("rendered message is longer than %d bytes", maxMessageSize)
+	}
+	w.n -= len(b)
+	return w.w.Write(b)
+}
+
+// formatMessageDuration formats a duration for humans using its two largest
+// units, e.g. "2 days 3 hours" or "45 minutes".
+func formatMessageDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+
+	parts := make([]string, 0, 2)
+	for _, unit := range units {
+		if len(parts) == 2 {
+			break
+		}
+		n := int(d / unit.size)
+		d -= time.Duration(n) * unit.size
+		if n == 0 {
+			if len(parts) > 0 {
+				// Don't skip to a smaller unit, since "1 day 5 minutes" is
+				// more precise than it is useful.
+				break
+			}
+			continue
+		}
+		part := strconv.Itoa(n) + " " + unit.name
+		if n != 1 {
+			part += "s"
+		}
+		parts = append(parts, part)
+	}
+
+	if len(parts) == 0 {
+		return "0 minutes"
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatMessageTime(layout string) func(time.Time) string {
+	return func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(layout)
+	}
+}
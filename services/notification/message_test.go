@@ -0,0 +1,111 @@
+package notification
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"e2clicker.app/services/notification/openapi"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestRenderMessage(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	assert.NoError(t, err)
+
+	lastDose := time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+	vars := MessageVariables{
+		Username:       "Pastel Cat",
+		Dose:           4,
+		Units:          "mg",
+		DeliveryMethod: "Estradiol Valerate, Intramuscular",
+		LastDoseAt:     lastDose,
+		SinceLastDose:  7*24*time.Hour + 2*time.Hour + 30*time.Minute,
+		DueAt:          lastDose.Add(7 * 24 * time.Hour),
+		Overdue:        2*time.Hour + 30*time.Minute,
+	}
+
+	msg, err := renderMessage(openapi.NotificationMessage{
+		Title:   "Hi {{ .Username }}!",
+		Message: "Take {{ .Dose }}{{ .Units }} of {{ .DeliveryMethod }}, due at {{ time .DueAt }}{{ if .Overdue }} ({{ duration .Overdue }} ago){{ end }}.",
+	}, vars.In(la))
+	assert.NoError(t, err)
+	assert.Equal(t, "Hi Pastel Cat!", msg.Title)
+	assert.Equal(t, "Take 4mg of Estradiol Valerate, Intramuscular, due at 12:00 (2 hours 30 minutes ago).", msg.Message)
+}
+
+func TestParseMessageTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{"plain text", "Don't forget your dose!", ""},
+		{"variables", "{{ .Username }}: {{ printf \"%.1f\" .Dose }}", ""},
+		{"with", "{{ with .Units }}{{ upper . }}{{ end }}", ""},
+		{"method", "{{ .DueAt.Format \"Jan 2\" }}", ""},
+		{"range", "{{ range 1000000000 }}x{{ end }}", "range is not allowed"},
+		{"define", `{{ define "x" }}{{ end }}`, "defining templates is not allowed"},
+		{"call", "{{ call .Username }}", `function "call" is not allowed`},
+		{"unknown function", "{{ exec }}", `function "exec" not defined`},
+		{"too long", strings.Repeat("a", maxMessageTemplateSize+1), "template is longer"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseMessageTemplate(test.template)
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCustomNotifications(t *testing.T) {
+	err := ValidateCustomNotifications(openapi.CustomNotifications{
+		string(openapi.ReminderMessage): {
+			Title:   "Reminder",
+			Message: "Take {{ .Doze }}",
+		},
+	})
+	var templateErr MessageTemplateError
+	assert.True(t, errors.As(err, &templateErr))
+	assert.Equal(t, "message", templateErr.Field)
+
+	err = ValidateCustomNotifications(openapi.CustomNotifications{
+		string(openapi.ReminderMessage): {
+			Title:   "Reminder",
+			Message: "Take {{ .Dose }}{{ .Units }}",
+		},
+	})
+	assert.NoError(t, err)
+}
+
+func TestRenderMessageTooLong(t *testing.T) {
+	_, err := renderMessageTemplate(
+		strings.Repeat("{{ .Username }}", 100),
+		MessageVariables{Username: strings.Repeat("a", 100)})
+	assert.Error(t, err)
+}
+
+func TestFormatMessageDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0 minutes"},
+		{30 * time.Second, "0 minutes"},
+		{time.Minute, "1 minute"},
+		{90 * time.Minute, "1 hour 30 minutes"},
+		{49 * time.Hour, "2 days 1 hour"},
+		{24*time.Hour + 5*time.Minute, "1 day"},
+		{-2 * time.Hour, "2 hours"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, formatMessageDuration(test.in), test.in.String())
+	}
+}
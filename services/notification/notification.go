@@ -2,42 +2,122 @@ package notification
 
 import (
 	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
 
 	"e2clicker.app/services/notification/openapi"
+	"e2clicker.app/services/user"
+	"golang.org/x/text/language"
 )
 
 // Notification describes a notification message to be sent to the user.
 type Notification = openapi.Notification
 
-// LoadNotification loads a notification message of the given type.
-func LoadNotification(ctx context.Context, t openapi.NotificationType) (openapi.NotificationMessage, error) {
-	switch t {
-	case openapi.WelcomeMessage:
-		return openapi.NotificationMessage{
-			Title:   "Welcome! 😄🌈❤️",
-			Message: "e2clicker can send you notifications to remind you now!",
-		}, nil
-	case openapi.ReminderMessage:
-		return openapi.NotificationMessage{
-			Title:   "Reminder!",
-			Message: "Don't forget to take your hormone dose!",
-		}, nil
-	case openapi.AccountNoticeMessage:
-		return openapi.NotificationMessage{
-			Title:   "Account Notice",
-			Message: "Please check your e2clicker account.",
-		}, nil
-	case openapi.WebPushExpiringMessage:
-		return openapi.NotificationMessage{
-			Title:   "Notifications will stop working soon 😟",
-			Message: "Your browser's push subscription is expiring soon. You need to refresh it!",
-		}, nil
-	case openapi.TestMessage:
-		return openapi.NotificationMessage{
-			Title:   "Test Message",
-			Message: "This is a test message to check your notification settings.",
-		}, nil
-	default:
+// localesFS contains the translations of the notification messages, one JSON
+// file per language. See locales/README.md.
+//
+//go:embed locales/*.json
+var localesFS embed.FS
+
+// fallbackLanguage is the language that every other language is checked
+// against and that is used when nothing else matches.
+var fallbackLanguage = language.English
+
+// localeMessages maps notification types to their messages in one language.
+type localeMessages map[openapi.NotificationType]openapi.NotificationMessage
+
+// messageCatalog holds the notification messages in every language.
+type messageCatalog struct {
+	// tags is the list of languages in the catalog. The first one is always
+	// [fallbackLanguage].
+	tags     []language.Tag
+	messages []localeMessages
+	matcher  language.Matcher
+}
+
+var loadMessageCatalog = sync.OnceValues(func() (*messageCatalog, error) {
+	return parseMessageCatalog(localesFS, "locales")
+})
+
+func parseMessageCatalog(fsys fs.FS, dir string) (*messageCatalog, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &messageCatalog{}
+	for _, file := range files {
+		tag, err := language.Parse(strings.TrimSuffix(path.Base(file), ".json"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid locale file name %q: %w", file, err)
+		}
+
+		b, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		var messages localeMessages
+		if err := json.Unmarshal(b, &messages); err != nil {
+			return nil, fmt.Errorf("cannot parse locale file %q: %w", file, err)
+		}
+
+		if tag == fallbackLanguage {
+			c.tags = slices.Insert(c.tags, 0, tag)
+			c.messages = slices.Insert(c.messages, 0, messages)
+		} else {
+			c.tags = append(c.tags, tag)
+			c.messages = append(c.messages, messages)
+		}
+	}
+
+	if len(c.tags) == 0 || c.tags[0] != fallbackLanguage {
+		return nil, fmt.Errorf("missing %s locale", fallbackLanguage)
+	}
+
+	c.matcher = language.NewMatcher(c.tags)
+	return c, nil
+}
+
+// message returns the message of type t in the language that best matches
+// the given tags.
+func (c *messageCatalog) message(t openapi.NotificationType, tags []language.Tag) (openapi.NotificationMessage, bool) {
+	_, i, _ := c.matcher.Match(tags...)
+	if msg, ok := c.messages[i][t]; ok {
+		return msg, true
+	}
+	msg, ok := c.messages[0][t]
+	return msg, ok
+}
+
+// isKnownNotificationType returns true if t has a message in the catalog.
+func isKnownNotificationType(t openapi.NotificationType) bool {
+	catalog, err := loadMessageCatalog()
+	if err != nil {
+		return false
+	}
+	_, ok := catalog.messages[0][t]
+	return ok
+}
+
+// LoadNotification loads a notification message of the given type in the
+// language that best matches the given locale.
+func LoadNotification(ctx context.Context, t openapi.NotificationType, locale user.Locale) (openapi.NotificationMessage, error) {
+	catalog, err := loadMessageCatalog()
+	if err != nil {
+		return openapi.NotificationMessage{}, fmt.Errorf("cannot load notification messages: %w", err)
+	}
+
+	msg, ok := catalog.message(t, locale.Tags())
+	if !ok {
 		return openapi.NotificationMessage{}, ErrUnknownNotificationType
 	}
+
+	return msg, nil
 }
@@ -0,0 +1,126 @@
+package notification
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"e2clicker.app/services/notification/openapi"
+	"e2clicker.app/services/user"
+	"github.com/alecthomas/assert/v2"
+)
+
+var allNotificationTypes = []openapi.NotificationType{
+	openapi.WelcomeMessage,
+	openapi.ReminderMessage,
+	openapi.AccountNoticeMessage,
+	openapi.WebPushExpiringMessage,
+	openapi.TestMessage,
+	openapi.SubscriptionPausedMessage,
+	openapi.DigestMessage,
+	openapi.AccountDeletedMessage,
+}
+
+func TestMessageCatalogKeys(t *testing.T) {
+	catalog, err := loadMessageCatalog()
+	assert.NoError(t, err)
+
+	fallback := catalog.messages[0]
+	for _, nt := range allNotificationTypes {
+		msg, ok := fallback[nt]
+		assert.True(t, ok, "%s: missing %s", fallbackLanguage, nt)
+		assert.NotZero(t, msg.Title, "%s: empty title for %s", fallbackLanguage, nt)
+		assert.NotZero(t, msg.Message, "%s: empty message for %s", fallbackLanguage, nt)
+	}
+
+	wantKeys := slices.Sorted(maps.Keys(fallback))
+
+	for i, tag := range catalog.tags[1:] {
+		messages := catalog.messages[i+1]
+		t.Run(tag.String(), func(t *testing.T) {
+			for _, nt := range wantKeys {
+				msg, ok := messages[nt]
+				assert.True(t, ok, "missing %s", nt)
+				assert.NotZero(t, msg.Title, "empty title for %s", nt)
+				assert.NotZero(t, msg.Message, "empty message for %s", nt)
+				_, err := renderMessage(msg, testDigestVariables())
+				assert.NoError(t, err, "invalid template for %s", nt)
+			}
+			for nt := range messages {
+				_, ok := fallback[nt]
+				assert.True(t, ok, "unknown notification type %s", nt)
+			}
+		})
+	}
+}
+
+func TestLoadNotification(t *testing.T) {
+	tests := []struct {
+		locale user.Locale
+		want   string
+	}{
+		{"", "Reminder!"},
+		{"en-US", "Reminder!"},
+		{"es-MX,es;q=0.9,en;q=0.8", "¡Recordatorio!"},
+		{"fr-CA", "Rappel !"},
+		{"ja,de;q=0.5", "Erinnerung!"},
+		{"xx-invalid", "Reminder!"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.locale), func(t *testing.T) {
+			msg, err := LoadNotification(context.Background(), openapi.ReminderMessage, test.locale)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, msg.Title)
+		})
+	}
+
+	_, err := LoadNotification(context.Background(), "nonexistent", "")
+	assert.IsError(t, err, ErrUnknownNotificationType)
+}
+
+func testDigestVariables() MessageVariables {
+	nextDoseAt := time.Date(2024, 3, 14, 21, 0, 0, 0, time.UTC)
+	return MessageVariables{
+		Username: "Pastel Cat",
+		Digest: openapi.DigestSummary{
+			Frequency:          openapi.WeeklyDigest,
+			PeriodStart:        time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:          time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+			DosesTaken:         2,
+			DosesExpected:      2,
+			AverageLateMinutes: 180,
+			RemindersSent:      2,
+			NextDoseAt:         &nextDoseAt,
+			Trough:             212.4,
+			TroughUnits:        "pg/mL",
+		},
+	}
+}
+
+func TestDigestMessage(t *testing.T) {
+	msg, err := LoadNotification(context.Background(), openapi.DigestMessage, "en")
+	assert.NoError(t, err)
+
+	rendered, err := renderMessage(msg, testDigestVariables())
+	assert.NoError(t, err)
+	assert.Equal(t, "Your weekly e2clicker summary", rendered.Title)
+	assert.Equal(t,
+		"Last week you took 2/2 doses, 3 hours late on average. "+
+			"Your next dose is due Thu, Mar 14 21:00. "+
+			"Your estimated trough level is 212 pg/mL.",
+		rendered.Message)
+
+	vars := testDigestVariables()
+	vars.Digest.Frequency = openapi.MonthlyDigest
+	vars.Digest.AverageLateMinutes = 0
+	vars.Digest.NextDoseAt = nil
+	vars.Digest.Trough = 0
+
+	rendered, err = renderMessage(msg, vars)
+	assert.NoError(t, err)
+	assert.Equal(t, "Your monthly e2clicker summary", rendered.Title)
+	assert.Equal(t, "Last month you took 2/2 doses, all on time.", rendered.Message)
+}
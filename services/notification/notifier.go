@@ -1,114 +1,599 @@
 package notification
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 
-	"e2clicker.app/internal/validating"
+	"e2clicker.app/internal/publicerrors"
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
 	"e2clicker.app/services/notification/openapi"
+	"e2clicker.app/services/user"
 	"go.uber.org/fx"
 )
 
-// NotificationConfigs contains all the configurations for a notification.
-type NotificationConfigs struct {
-	Gotify   []GotifyNotificationConfig   `json:"gotify,omitempty"`
-	Pushover []PushoverNotificationConfig `json:"pushover,omitempty"`
-	WebPush  []openapi.PushSubscription   `json:"webPush,omitempty"`
-	Email    []EmailNotificationConfig    `json:"email,omitempty"`
+// NotificationConfig is the configuration of a single notification channel,
+// tagged with the name of the [Notifier] method that it's for.
+type NotificationConfig struct {
+	// ID identifies the config. It is assigned when the config is first saved.
+	ID     string          `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Config json.RawMessage `json:"config"`
+	// Health tracks whether notifications are being delivered to the config.
+	// It is nil until a notification is sent.
+	Health *NotificationHealth `json:"health,omitempty"`
 }
 
-// NotificationMethodSupports lists the supported notification services.
-type NotificationMethodSupports struct {
-	Gotify   bool `json:"gotify"`
-	Pushover bool `json:"pushover"`
-	WebPush  bool `json:"webPush"`
-	Email    bool `json:"email"`
+// NotificationHealth is the delivery health of a [NotificationConfig].
+type NotificationHealth = openapi.NotificationHealth
+
+// newNotificationConfigID generates a new random ID for a
+// [NotificationConfig].
+func newNotificationConfigID() string {
+	var b [6]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Keys of the server-managed properties that are added to each config object
+// in the API.
+const (
+	apiConfigIDKey     = "_id"
+	apiConfigHealthKey = "_health"
+)
+
+// NotificationConfigs contains all the notification channels of a user.
+//
+// It is stored as a list of [NotificationConfig] entries. The older format,
+// an object of config lists keyed by method, is still accepted when decoding.
+type NotificationConfigs []NotificationConfig
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (c *NotificationConfigs) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '{' {
+		var grouped map[string][]json.RawMessage
+		if err := json.Unmarshal(b, &grouped); err != nil {
+			return err
+		}
+		*c = GroupedNotificationConfigs(grouped)
+		return nil
+	}
+	return json.Unmarshal(b, (*[]NotificationConfig)(c))
+}
+
+// GroupedNotificationConfigs creates [NotificationConfigs] from config lists
+// keyed by method, which is the format used by the API. Methods are sorted by
+// name. The ID of each config is taken from its "_id" property, and its
+// "_health" property is ignored, since the health is managed by the server.
+func GroupedNotificationConfigs(grouped map[string][]json.RawMessage) NotificationConfigs {
+	var c NotificationConfigs
+	for _, method := range slices.Sorted(maps.Keys(grouped)) {
+		for _, config := range grouped[method] {
+			entry := NotificationConfig{Method: method, Config: config}
+
+			var props map[string]json.RawMessage
+			if json.Unmarshal(config, &props) == nil {
+				_, hasID := props[apiConfigIDKey]
+				_, hasHealth := props[apiConfigHealthKey]
+				if hasID || hasHealth {
+					json.Unmarshal(props[apiConfigIDKey], &entry.ID)
+					delete(props, apiConfigIDKey)
+					delete(props, apiConfigHealthKey)
+					entry.Config, _ = json.Marshal(props)
+				}
+			}
+
+			c = append(c, entry)
+		}
+	}
+	return c
+}
+
+// Grouped returns the configs as lists keyed by method, with the ID and health
+// of each config added as the "_id" and "_health" properties. It is the
+// inverse of [GroupedNotificationConfigs].
+func (c NotificationConfigs) Grouped() map[string][]json.RawMessage {
+	grouped := make(map[string][]json.RawMessage)
+	for _, config := range c {
+		b := config.Config
+
+		var props map[string]json.RawMessage
+		if json.Unmarshal(b, &props) == nil && (config.ID != "" || config.Health != nil) {
+			if config.ID != "" {
+				props[apiConfigIDKey], _ = json.Marshal(config.ID)
+			}
+			if config.Health != nil {
+				props[apiConfigHealthKey], _ = json.Marshal(config.Health)
+			}
+			b, _ = json.Marshal(props)
+		}
+
+		grouped[config.Method] = append(grouped[config.Method], b)
+	}
+	return grouped
 }
 
 // IsEmpty returns true if the notification configs are empty.
 func (c NotificationConfigs) IsEmpty() bool {
-	return len(c.Gotify) == 0 && len(c.Pushover) == 0 && len(c.WebPush) == 0 && len(c.Email) == 0
+	return len(c) == 0
 }
 
-// NotificationService is a collection of NotificationServices.
-// It implements the [Notifier] interface.
+// Has returns true if there is at least one config for the given method.
+func (c NotificationConfigs) Has(method string) bool {
+	return slices.ContainsFunc(c, func(config NotificationConfig) bool {
+		return config.Method == method
+	})
+}
+
+// ConfigsOf decodes the configs of the given method into ConfigT. It is meant
+// for code that deals with a particular method, such as email confirmation.
+func ConfigsOf[ConfigT any](c NotificationConfigs, method string) ([]ConfigT, error) {
+	var configs []ConfigT
+	for _, config := range c {
+		if config.Method != method {
+			continue
+		}
+		var v ConfigT
+		if err := json.Unmarshal(config.Config, &v); err != nil {
+			return nil, ConfigError{method, err}
+		}
+		configs = append(configs, v)
+	}
+	return configs, nil
+}
+
+// UpdateConfigsOf calls f with every config of the given method decoded into
+// ConfigT. Changes that f makes to the config are saved, and the config is
+// removed if f returns false. The ID and health of each config are kept.
+func UpdateConfigsOf[ConfigT any](c *NotificationConfigs, method string, f func(*ConfigT) (keep bool)) error {
+	updated := make(NotificationConfigs, 0, len(*c))
+	for _, config := range *c {
+		if config.Method != method {
+			updated = append(updated, config)
+			continue
+		}
+
+		var v ConfigT
+		if err := json.Unmarshal(config.Config, &v); err != nil {
+			return ConfigError{method, err}
+		}
+		if !f(&v) {
+			continue
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("cannot marshal %s config: %w", method, err)
+		}
+		config.Config = b
+		updated = append(updated, config)
+	}
+	*c = updated
+	return nil
+}
+
+// Unpaused returns the configs that have not been paused.
+func (c NotificationConfigs) Unpaused() NotificationConfigs {
+	return slices.DeleteFunc(slices.Clone(c), func(config NotificationConfig) bool {
+		return config.Health != nil && config.Health.Paused
+	})
+}
+
+// Healthy returns the configs that are not paused and whose last notification
+// did not fail.
+func (c NotificationConfigs) Healthy() NotificationConfigs {
+	return slices.DeleteFunc(slices.Clone(c), func(config NotificationConfig) bool {
+		return config.Health != nil && (config.Health.Paused || config.Health.ConsecutiveFailures > 0)
+	})
+}
+
+// recordResults updates the health of the configs from the results of sending
+// a notification. Configs that fail threshold times in a row are paused, and
+// configs that succeed are resumed. The configs that were paused by this call
+// are returned.
+func (c NotificationConfigs) recordResults(results []NotifyResult, threshold int, now time.Time) (paused []NotificationConfig) {
+	for _, result := range results {
+		if result.Config.ID == "" || errors.Is(result.Err, ErrNotificationSkipped) {
+			continue
+		}
+
+		ix := slices.IndexFunc(c, func(config NotificationConfig) bool {
+			return config.ID == result.Config.ID
+		})
+		if ix == -1 {
+			// The config was removed while the notification was being sent.
+			continue
+		}
+
+		var health NotificationHealth
+		if c[ix].Health != nil {
+			health = *c[ix].Health
+		}
+
+		if result.Err == nil {
+			health.LastSuccessAt = &now
+			health.ConsecutiveFailures = 0
+			health.Paused = false
+		} else {
+			health.LastErrorAt = &now
+			health.LastError = truncateString(deliveryError(result.Err), maxHealthErrorLength)
+			health.ConsecutiveFailures++
+			if health.ConsecutiveFailures >= threshold && !health.Paused {
+				health.Paused = true
+				paused = append(paused, c[ix])
+			}
+		}
+
+		c[ix].Health = &health
+	}
+	return paused
+}
+
+// deliveryError returns the message of an error that a notifier returned as
+// it is shown to the user. The body of unexpected HTTP responses is left out.
+func deliveryError(err error) string {
+	var statusErr HTTPUnknownStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.publicError()
+	}
+	return err.Error()
+}
+
+// maxHealthErrorLength is the maximum length of [NotificationHealth.LastError]
+// in runes.
+const maxHealthErrorLength = 512
+
+// NotificationRoute restricts the channels that a type of notification is sent
+// to. See [NotificationConfigs.Routed].
+type NotificationRoute = openapi.NotificationRoute
+
+// Routed returns only the configs that notifications following the given
+// route are sent to. If the route lists devices, only the web push
+// subscriptions of those devices are included.
+func (c NotificationConfigs) Routed(route NotificationRoute) NotificationConfigs {
+	var routed NotificationConfigs
+	for _, config := range c {
+		if !slices.Contains(route.Methods, config.Method) {
+			continue
+		}
+		if config.Method == WebPushMethod && route.Devices != nil {
+			var sub struct {
+				DeviceID openapi.PushDeviceID `json:"deviceID"`
+			}
+			if err := json.Unmarshal(config.Config, &sub); err != nil ||
+				!slices.Contains(route.Devices, sub.DeviceID) {
+				continue
+			}
+		}
+		routed = append(routed, config)
+	}
+	return routed
+}
+
+// NotificationService sends notifications through the registered [Notifier]s.
+//
+// Credentials in notification configs, which are the properties marked with
+// "x-secret" in the config schemas, are encrypted at rest if credential keys
+// are configured. They are only decrypted by this service right before a
+// config is validated or used.
 type NotificationService struct {
-	services     NotificationServiceConfig
-	servicesAttr slog.Attr
-	logger       *slog.Logger
+	notifiers   map[string]Notifier
+	credentials *credentialKeys
+	// credentialFields maps methods to the paths of their credential fields.
+	credentialFields map[string][][]string
+	logger           *slog.Logger
 }
 
 // NotificationServiceConfig is the configuration for the notification service.
 type NotificationServiceConfig struct {
 	fx.In
-	Gotify   *GotifyService   `optional:"true"`
-	Pushover *PushoverService `optional:"true"`
-	WebPush  *WebPushService  `optional:"true"`
-	Email    *EmailService    `optional:"true"`
+	Config    e2clickermodule.Notification
+	Notifiers []Notifier `group:"notifiers"`
 }
 
 // NewNotificationService creates a new notification service.
-func NewNotificationService(s NotificationServiceConfig, logger *slog.Logger) *NotificationService {
-	return &NotificationService{
-		services: s,
-		logger:   logger,
-		servicesAttr: slog.Group(
-			"services",
-			"gotify", s.Gotify != nil,
-			"pushover", s.Pushover != nil,
-			"webPush", s.WebPush != nil,
-			"email", s.Email != nil,
-		),
-	}
-}
-
-// Notify sends a notification to all the services.
-func (m *NotificationService) Notify(ctx context.Context, n Notification, c NotificationConfigs) error {
-	return errors.Join(slices.Concat(
-		callNotify(ctx, "gotify", n, c.Gotify, m.services.Gotify),
-		callNotify(ctx, "pushover", n, c.Pushover, m.services.Pushover),
-		callNotify(ctx, "webPush", n, c.WebPush, m.services.WebPush),
-		callNotify(ctx, "email", n, c.Email, m.services.Email),
-	)...)
-}
-
-// Supports returns the supported notification services.
-func (m *NotificationService) Supports() NotificationMethodSupports {
-	return NotificationMethodSupports{
-		Gotify:   m.services.Gotify != nil,
-		Pushover: m.services.Pushover != nil,
-		WebPush:  m.services.WebPush != nil,
-		Email:    m.services.Email != nil,
-	}
-}
-
-func callNotify[
-	ConfigT any,
-	NotifierT interface {
-		Notify(context.Context, Notification, ConfigT) error
-	},
-](
-	ctx context.Context,
-	name string,
-	notification Notification,
-	configs []ConfigT,
-	notifier *NotifierT,
-) (errs []error) {
-	if notifier == nil {
-		// errs = append(errs, UnknownServiceError{name})
-		return
-	}
-	for _, c := range configs {
-		if err := validating.ShouldValidate(ctx, c); err != nil {
-			errs = append(errs, ConfigError{name, err})
+func NewNotificationService(s NotificationServiceConfig, logger *slog.Logger) (*NotificationService, error) {
+	credentials, err := newCredentialKeys(s.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &NotificationService{
+		notifiers:        make(map[string]Notifier, len(s.Notifiers)),
+		credentials:      credentials,
+		credentialFields: make(map[string][][]string, len(s.Notifiers)),
+		logger:           logger,
+	}
+	for _, n := range s.Notifiers {
+		if _, ok := m.notifiers[n.Method()]; ok {
+			return nil, fmt.Errorf("notification method %q is registered twice", n.Method())
+		}
+		m.notifiers[n.Method()] = n
+
+		fields, err := credentialFields(n.ConfigSchema())
+		if err != nil {
+			return nil, fmt.Errorf("invalid config schema for %s: %w", n.Method(), err)
+		}
+		if len(fields) > 0 {
+			m.credentialFields[n.Method()] = fields
+		}
+	}
+
+	if credentials == nil {
+		logger.Warn("no credential keys configured, notification credentials are stored in plain text")
+	}
+
+	logger.Debug(
+		"notification methods registered",
+		"methods", slices.Sorted(maps.Keys(m.notifiers)))
+
+	return m, nil
+}
+
+// NotifyResult is the result of sending a notification to a single config.
+type NotifyResult struct {
+	Config NotificationConfig
+	// Err is the error that the notifier returned, if any. It is
+	// [ErrNotificationSkipped] if the config was skipped.
+	Err error
+}
+
+// Send sends a notification to all the configs of the user and returns the
+// result of each config. Configs of methods that are not available are left
+// out.
+func (m *NotificationService) Send(ctx context.Context, userID user.ID, n Notification, c NotificationConfigs) []NotifyResult {
+	ctx = withRecipient(ctx, userID)
+	results := make([]NotifyResult, 0, len(c))
+	for _, config := range c {
+		notifier, ok := m.notifiers[config.Method]
+		if !ok {
 			continue
 		}
-		if err := (*notifier).Notify(ctx, notification, c); err != nil {
-			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		result := NotifyResult{Config: config}
+		if opened, err := m.openConfig(userID, config); err != nil {
+			result.Err = err
+		} else {
+			result.Err = notifier.Send(ctx, n, opened.Config)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Notify sends a notification to all the configs of the user. Configs of
+// methods that are not available are skipped.
+func (m *NotificationService) Notify(ctx context.Context, userID user.ID, n Notification, c NotificationConfigs) error {
+	return joinNotifyErrors(m.Send(ctx, userID, n, c))
+}
+
+// joinNotifyErrors joins the errors of the results, prefixed by their method.
+func joinNotifyErrors(results []NotifyResult) error {
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil && !errors.Is(result.Err, ErrNotificationSkipped) {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Config.Method, result.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Notifier returns the notifier of the given method, if it's available.
+func (m *NotificationService) Notifier(method string) (Notifier, bool) {
+	n, ok := m.notifiers[method]
+	return n, ok
+}
+
+// Notifiers returns all available notifiers, sorted by method name.
+func (m *NotificationService) Notifiers() []Notifier {
+	notifiers := slices.Collect(maps.Values(m.notifiers))
+	slices.SortFunc(notifiers, func(a, b Notifier) int {
+		return strings.Compare(a.Method(), b.Method())
+	})
+	return notifiers
+}
+
+// ValidateConfigs validates every config of the user using its notifier and
+// returns the normalized configs with their credentials encrypted. Credentials
+// that are already encrypted are accepted if they were encrypted for the same
+// user and config, so clients can send back the configs they got. Configs of
+// methods that are not available are rejected, unless they are unchanged from
+// the existing configs.
+//
+// Configs with the ID of an existing config of the same method keep their ID
+// and health. Other configs are given a new ID.
+func (m *NotificationService) ValidateConfigs(ctx context.Context, userID user.ID, c, existing NotificationConfigs) (NotificationConfigs, error) {
+	validated := make(NotificationConfigs, len(c))
+	for i, config := range c {
+		// Credentials are bound to the ID that the config was sent with,
+		// which may be replaced below.
+		opened, err := m.openConfig(userID, config)
+
+		ix := slices.IndexFunc(existing, func(old NotificationConfig) bool {
+			return config.ID != "" && old.ID == config.ID && old.Method == config.Method
+		})
+		if ix != -1 {
+			config.Health = existing[ix].Health
+		} else {
+			config.ID = newNotificationConfigID()
+			config.Health = nil
+		}
+
+		notifier, ok := m.notifiers[config.Method]
+		if !ok {
+			ix := slices.IndexFunc(existing, func(old NotificationConfig) bool {
+				return old.Method == config.Method && bytes.Equal(old.Config, config.Config)
+			})
+			if ix == -1 {
+				return nil, UnknownServiceError{config.Method}
+			}
+			validated[i] = existing[ix]
+			continue
+		}
+		if err != nil {
+			return nil, publicerrors.Errorf("invalid %s config: %w", config.Method, err)
+		}
+		config.Config, err = notifier.ValidateConfig(ctx, opened.Config)
+		if err != nil {
+			return nil, publicerrors.Errorf("invalid %s config: %w", config.Method, err)
+		}
+		validated[i], err = m.sealConfig(userID, config)
+		if err != nil {
+			return nil, err
 		}
 	}
-	return
+	return validated, nil
+}
+
+// openConfig decrypts the credentials of a config of the user.
+func (m *NotificationService) openConfig(userID user.ID, c NotificationConfig) (NotificationConfig, error) {
+	b, err := mapCredentials(c.Config, m.credentialFields[c.Method], func(path []string, value string) (string, error) {
+		return m.credentials.open(value, credentialAD{userID, c.ID, c.Method, path})
+	})
+	if err != nil {
+		return c, ConfigError{c.Method, err}
+	}
+	c.Config = b
+	return c, nil
+}
+
+// openConfigsOf decodes the configs of the given method into ConfigT with
+// their credentials decrypted, like [ConfigsOf].
+func openConfigsOf[ConfigT any](m *NotificationService, userID user.ID, c NotificationConfigs, method string) ([]ConfigT, error) {
+	var opened NotificationConfigs
+	for _, config := range c {
+		if config.Method != method {
+			continue
+		}
+		config, err := m.openConfig(userID, config)
+		if err != nil {
+			return nil, err
+		}
+		opened = append(opened, config)
+	}
+	return ConfigsOf[ConfigT](opened, method)
+}
+
+// sealConfig encrypts the credentials of a config of the user that is in
+// plain text. It does nothing if no credential keys are configured.
+func (m *NotificationService) sealConfig(userID user.ID, c NotificationConfig) (NotificationConfig, error) {
+	if m.credentials == nil {
+		return c, nil
+	}
+	b, err := mapCredentials(c.Config, m.credentialFields[c.Method], func(path []string, value string) (string, error) {
+		return m.credentials.seal(value, credentialAD{userID, c.ID, c.Method, path})
+	})
+	if err != nil {
+		return c, fmt.Errorf("cannot encrypt %s config: %w", c.Method, err)
+	}
+	c.Config = b
+	return c, nil
+}
+
+// resealConfigs encrypts the credentials of the user's configs that are still
+// in plain text or encrypted with an old key with the primary key. It reports
+// whether anything was changed.
+func (m *NotificationService) resealConfigs(userID user.ID, c NotificationConfigs) (NotificationConfigs, bool, error) {
+	var changed bool
+	resealed := make(NotificationConfigs, len(c))
+	for i, config := range c {
+		b, err := mapCredentials(config.Config, m.credentialFields[config.Method], func(path []string, value string) (string, error) {
+			if !m.credentials.needsReseal(value) {
+				return value, nil
+			}
+			ad := credentialAD{userID, config.ID, config.Method, path}
+			plaintext, err := m.credentials.open(value, ad)
+			if err != nil {
+				return "", err
+			}
+			changed = true
+			return m.credentials.seal(plaintext, ad)
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("cannot re-encrypt %s config: %w", config.Method, err)
+		}
+		config.Config = b
+		resealed[i] = config
+	}
+	return resealed, changed, nil
+}
+
+// RedactedCredential replaces the credentials of configs that are exported
+// without them, see [NotificationService.ExportConfigs].
+const RedactedCredential = "REDACTED"
+
+// ExportConfigs returns the user's configs as they are exported for the user
+// to take to another server. Their credentials are decrypted if
+// includeCredentials is true, since the other server can't decrypt them, and
+// replaced with [RedactedCredential] otherwise. Health is left out, since it
+// only applies to this server.
+func (m *NotificationService) ExportConfigs(userID user.ID, c NotificationConfigs, includeCredentials bool) (NotificationConfigs, error) {
+	exported := make(NotificationConfigs, len(c))
+	for i, config := range c {
+		b, err := mapCredentials(config.Config, m.credentialFields[config.Method], func(path []string, value string) (string, error) {
+			if !includeCredentials {
+				return RedactedCredential, nil
+			}
+			return m.credentials.open(value, credentialAD{userID, config.ID, config.Method, path})
+		})
+		if err != nil {
+			return nil, ConfigError{config.Method, err}
+		}
+		config.Config = b
+		config.Health = nil
+		exported[i] = config
+	}
+	return exported, nil
+}
+
+// importable returns true if a config exported from another server can be
+// used on this one. Configs of methods that aren't available here and configs
+// whose credentials were redacted can't be.
+func (m *NotificationService) importable(c NotificationConfig) bool {
+	if _, ok := m.notifiers[c.Method]; !ok {
+		return false
+	}
+	var redacted bool
+	mapCredentials(c.Config, m.credentialFields[c.Method], func(path []string, value string) (string, error) {
+		redacted = redacted || value == RedactedCredential
+		return value, nil
+	})
+	return !redacted
+}
+
+// credentialMethods returns the methods whose configs have credentials.
+func (m *NotificationService) credentialMethods() []string {
+	return slices.Sorted(maps.Keys(m.credentialFields))
+}
+
+// validateRoutes checks that routes only use available methods.
+func (m *NotificationService) validateRoutes(routes openapi.NotificationRoutes) error {
+	for t, route := range routes {
+		for _, method := range route.Methods {
+			if _, ok := m.notifiers[method]; !ok {
+				return publicerrors.Errorf("invalid route for %q: %w", t, UnknownServiceError{method})
+			}
+		}
+	}
+	return nil
+}
+
+// truncateString truncates s to at most n runes. An ellipsis is added if the
+// string is truncated.
+func truncateString(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n-1]) + "…"
 }
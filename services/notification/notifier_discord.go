@@ -0,0 +1,149 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"e2clicker.app/internal/validating"
+
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
+)
+
+// DiscordNotificationConfig is a user configuration for the Discord webhook
+// service.
+type DiscordNotificationConfig struct {
+	// WebhookURL is the URL of the Discord webhook, which looks like
+	// https://discord.com/api/webhooks/{id}/{token}.
+	WebhookURL string `json:"webhook_url"`
+	// Username overrides the default username of the webhook.
+	Username string `json:"username,omitempty"`
+	// AvatarURL overrides the default avatar of the webhook.
+	AvatarURL string `json:"avatar_url,omitempty"`
+	// Color is the color of the embed as a 0xRRGGBB integer.
+	Color int `json:"color,omitempty"`
+}
+
+// discordWebhookHosts are the only hosts that webhook URLs may point to, so
+// that users can't make the server send requests anywhere else.
+var discordWebhookHosts = []string{
+	"discord.com",
+	"discordapp.com",
+	"ptb.discord.com",
+	"canary.discord.com",
+}
+
+var _ validating.Validator = (*DiscordNotificationConfig)(nil)
+
+// Validate checks that the configuration is valid.
+func (c *DiscordNotificationConfig) Validate() error {
+	u, err := url.Parse(c.WebhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if u.Scheme != "https" {
+		return errors.New("webhook URL must use https")
+	}
+	if !slices.Contains(discordWebhookHosts, u.Host) {
+		return fmt.Errorf("webhook URL has unknown Discord host %q", u.Host)
+	}
+	if !strings.HasPrefix(u.Path, "/api/webhooks/") {
+		return errors.New("webhook URL is not a Discord webhook")
+	}
+	if c.AvatarURL != "" {
+		if u, err := url.Parse(c.AvatarURL); err != nil || u.Scheme != "https" {
+			return errors.New("avatar URL must be a valid https URL")
+		}
+	}
+	if c.Color < 0 || c.Color > 0xFFFFFF {
+		return errors.New("color must be between 0x000000 and 0xFFFFFF")
+	}
+	return nil
+}
+
+// DiscordService is a service for sending notifications via Discord webhooks.
+type DiscordService struct {
+	http *http.Client
+}
+
+// NewDiscordService creates a new Discord webhook service.
+func NewDiscordService(config e2clickermodule.Notification) (*DiscordService, error) {
+	timeout, err := time.ParseDuration(config.ClientTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client timeout %q: %w", config.ClientTimeout, err)
+	}
+	return &DiscordService{http: &http.Client{Timeout: timeout}}, nil
+}
+
+func (s DiscordService) Notify(ctx context.Context, n Notification, config DiscordNotificationConfig) error {
+	if err := config.Validate(); err != nil {
+		return ConfigError{err: err}
+	}
+
+	// https://discord.com/developers/docs/resources/webhook#execute-webhook
+	type discordEmbed struct {
+		Title       string `json:"title,omitempty"`
+		Description string `json:"description,omitempty"`
+		Color       int    `json:"color,omitempty"`
+	}
+
+	type discordWebhookMessage struct {
+		Username  string         `json:"username,omitempty"`
+		AvatarURL string         `json:"avatar_url,omitempty"`
+		Embeds    []discordEmbed `json:"embeds"`
+		// AllowedMentions is set to an empty object to prevent the message
+		// from pinging anyone.
+		AllowedMentions struct {
+			Parse []string `json:"parse"`
+		} `json:"allowed_mentions"`
+	}
+
+	msg := discordWebhookMessage{
+		Username:  config.Username,
+		AvatarURL: config.AvatarURL,
+		Embeds: []discordEmbed{{
+			Title:       truncateString(n.Message.Title, 256),
+			Description: truncateString(n.Message.Message, 4096),
+			Color:       config.Color,
+		}},
+	}
+	msg.AllowedMentions.Parse = []string{}
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	r, err := doRateLimited(ctx, s.http,
+		func() (*http.Request, error) {
+			req, err := http.NewRequestWithContext(ctx, "POST", config.WebhookURL, bytes.NewReader(b))
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Content-Type", "application/json")
+			return req, nil
+		},
+		func(r *http.Response) time.Duration {
+			// Discord gives a more precise X-RateLimit-Reset-After header.
+			// https://discord.com/developers/docs/topics/rate-limits
+			return parseRetryAfter(r.Header, "X-RateLimit-Reset-After", "Retry-After")
+		},
+	)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		return consumeHTTPUnknownStatusError(r)
+	}
+
+	return nil
+}
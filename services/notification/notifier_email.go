@@ -1,14 +1,20 @@
 package notification
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
+	"e2clicker.app"
 	"e2clicker.app/services/notification/openapi"
+	"e2clicker.app/services/user"
 	"go.uber.org/fx"
 
 	// Choose to use a deprecated library. The only other choices (xhit,
@@ -19,15 +25,42 @@ import (
 )
 
 // EmailNotificationConfig is a user configuration for the email service.
-type EmailNotificationConfig = openapi.EmailSubscription
+type EmailNotificationConfig struct {
+	// Address is the email address to send notifications to.
+	Address string `json:"address"`
+	// Name is the name used along with Address in the To field.
+	Name string `json:"name,omitempty"`
+	// Pending is true if Address hasn't been confirmed by following the link
+	// in the confirmation email yet. Pending addresses don't receive
+	// notifications.
+	//
+	// Addresses that were added before confirmation was required don't have
+	// this set and are treated as confirmed.
+	Pending bool `json:"pending,omitempty"`
+}
+
+// IsPending implements [pendingConfig].
+func (c EmailNotificationConfig) IsPending() bool { return c.Pending }
 
 // EmailService is a service for sending notifications via email.
 type EmailService struct {
-	dialer *mail.Dialer
-	config *e2clickermodule.EmailSubmodule
-	logger *slog.Logger
+	sender    *smtpSender
+	config    *e2clickermodule.EmailSubmodule
+	templates *emailTemplates
+	tokens    *emailTokenSealer // nil if no token key
+	logger    *slog.Logger
 }
 
+const (
+	// emailLogoCID is the Content-ID of the logo embedded into HTML emails.
+	emailLogoCID = "logo.png"
+	// emailConfirmationTemplate is the name of the confirmation email template.
+	emailConfirmationTemplate = "email_confirmation"
+	// emailConfirmationTTL is how long the link in a confirmation email is
+	// valid for.
+	emailConfirmationTTL = 7 * 24 * time.Hour
+)
+
 // NewEmailService creates a new email service.
 func NewEmailService(config e2clickermodule.Notification, logger *slog.Logger, lc fx.Lifecycle) (*EmailService, error) {
 	if config.Email == nil {
@@ -52,6 +85,24 @@ func NewEmailService(config e2clickermodule.Notification, logger *slog.Logger, l
 		panic("unreachable")
 	}
 
+	var templatesDir string
+	if mailConfig.TemplatesDir != nil {
+		templatesDir = *mailConfig.TemplatesDir
+	}
+
+	templates, err := loadEmailTemplates(templatesDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load email templates: %w", err)
+	}
+
+	var tokens *emailTokenSealer
+	if mailConfig.TokenKey != "" {
+		tokens, err = newEmailTokenSealer(mailConfig.TokenKey)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create email token sealer: %w", err)
+		}
+	}
+
 	logger = logger.With(
 		"notifier", "email",
 		"email.host", mailConfig.SMTP.Host,
@@ -72,38 +123,144 @@ func NewEmailService(config e2clickermodule.Notification, logger *slog.Logger, l
 	dialer.Timeout = 30 * time.Second
 	dialer.RetryFailure = true
 
+	senderConfig := smtpSenderConfig{
+		MaxConnections: cmp.Or(mailConfig.SMTP.MaxConnections, 2),
+		IdleTimeout:    30 * time.Second,
+		QueueSize:      cmp.Or(mailConfig.SMTP.QueueSize, 64),
+	}
+	if mailConfig.SMTP.IdleTimeout != "" {
+		senderConfig.IdleTimeout, err = time.ParseDuration(mailConfig.SMTP.IdleTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SMTP idle timeout %q: %w", mailConfig.SMTP.IdleTimeout, err)
+		}
+	}
+	if senderConfig.MaxConnections < 1 || senderConfig.QueueSize < 1 || senderConfig.IdleTimeout <= 0 {
+		return nil, fmt.Errorf("invalid SMTP connection settings")
+	}
+
+	sender := newSMTPSender(dialer, senderConfig, logger)
+	lc.Append(fx.StartStopHook(sender.start, sender.close))
+
 	return &EmailService{
-		dialer: dialer,
-		config: mailConfig,
-		logger: logger,
+		sender:    sender,
+		config:    mailConfig,
+		templates: templates,
+		tokens:    tokens,
+		logger:    logger,
 	}, nil
 }
 
 func (s EmailService) Notify(ctx context.Context, n Notification, config EmailNotificationConfig) error {
-	optstr := func(opt *string) string {
-		if opt == nil {
-			return ""
+	data := s.templateData(n, config)
+
+	if data.AccountURL != "" && s.tokens != nil {
+		if userID, ok := recipientFromContext(ctx); ok {
+			token, err := s.tokens.seal(emailTokenUnsubscribe, emailToken{
+				UserID:  userID,
+				Address: config.Address,
+			})
+			if err != nil {
+				return fmt.Errorf("cannot create unsubscribe token: %w", err)
+			}
+			data.UnsubscribeURL = s.apiURL("/notifications/email/unsubscribe", token)
 		}
-		return *opt
+	}
+
+	return s.send(ctx, string(n.Type), data, config)
+}
+
+// CanConfirm returns true if the service can send confirmation emails, which
+// requires both a base URL and a token key.
+func (s EmailService) CanConfirm() bool {
+	return s.config.BaseURL != "" && s.tokens != nil
+}
+
+// SendConfirmation sends an email with a link that confirms the address in
+// the given config for the given user.
+func (s EmailService) SendConfirmation(ctx context.Context, userID user.ID, username string, config EmailNotificationConfig) error {
+	if !s.CanConfirm() {
+		return ErrEmailConfirmationNotAvailable
+	}
+
+	token, err := s.tokens.seal(emailTokenConfirm, emailToken{
+		UserID:    userID,
+		Address:   config.Address,
+		ExpiresAt: time.Now().Add(emailConfirmationTTL).Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create confirmation token: %w", err)
+	}
+
+	data := s.templateData(Notification{
+		Message: openapi.NotificationMessage{
+			Title: "Confirm your email address",
+			Message: "Someone, hopefully you, added this email address to their e2clicker account " +
+				"to receive notifications. Please confirm that you want to receive them.",
+		},
+		Username: username,
+	}, config)
+	data.ConfirmURL = s.apiURL("/notifications/email/confirm", token)
+
+	return s.send(ctx, emailConfirmationTemplate, data, config)
+}
+
+// Confirm opens a confirmation token from an email link and returns the user
+// and email address that it was made for.
+func (s EmailService) Confirm(token string) (user.ID, string, error) {
+	return s.openToken(emailTokenConfirm, token)
+}
+
+func (s EmailService) templateData(n Notification, config EmailNotificationConfig) emailTemplateData {
+	data := emailTemplateData{
+		Notification: n,
+		Address:      config.Address,
+		LogoCID:      emailLogoCID,
+	}
+	if s.config.BaseURL != "" {
+		base := strings.TrimSuffix(s.config.BaseURL, "/")
+		data.AccountURL = base + "/settings"
+		data.DashboardURL = base + "/dashboard"
+	}
+	return data
+}
+
+// apiURL returns the URL of the given API path with the token as its query.
+func (s EmailService) apiURL(path, token string) string {
+	return strings.TrimSuffix(s.config.BaseURL, "/") + "/api" + path + "?token=" + url.QueryEscape(token)
+}
+
+func (s EmailService) send(ctx context.Context, template string, data emailTemplateData, config EmailNotificationConfig) error {
+	text, html, err := s.templates.render(template, data)
+	if err != nil {
+		return err
 	}
 
 	msg := mail.NewMessage()
 	msg.SetHeader("From", s.config.From)
-	msg.SetAddressHeader("To", string(config.Address), optstr(config.Name))
-	msg.SetHeader("Subject", n.Message.Title)
-	msg.SetBody("text/plain", n.Message.Message)
-	// TODO: text/html support
+	msg.SetAddressHeader("To", config.Address, config.Name)
+	msg.SetHeader("Subject", data.Message.Title)
+	if data.UnsubscribeURL != "" {
+		// https://www.rfc-editor.org/rfc/rfc8058
+		msg.SetHeader("List-Unsubscribe", "<"+data.UnsubscribeURL+">")
+		msg.SetHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+	msg.SetBody("text/plain", text)
+	msg.AddAlternative("text/html", html)
+	msg.Embed(emailLogoCID, mail.SetCopyFunc(func(w io.Writer) error {
+		_, err := w.Write(e2clicker.LogoPNG)
+		return err
+	}))
 
 	s.logger.Debug(
 		"sending email",
 		"from", s.config.From,
-		"notification", n.Type)
+		"template", template)
 
-	if err := s.dialer.DialAndSend(msg); err != nil {
+	if err := s.sender.send(ctx, msg); err != nil {
 		s.logger.Error(
 			"failed to send email",
 			"from", s.config.From,
-			"notification", n.Type,
+			"template", template,
 			"err", err)
 
 		return fmt.Errorf("cannot send email: %w", err)
@@ -112,6 +269,23 @@ func (s EmailService) Notify(ctx context.Context, n Notification, config EmailNo
 	return nil
 }
 
+// Unsubscribe opens an unsubscribe token from an email link and returns the
+// user and email address that it was made for.
+func (s EmailService) Unsubscribe(token string) (user.ID, string, error) {
+	return s.openToken(emailTokenUnsubscribe, token)
+}
+
+func (s EmailService) openToken(purpose emailTokenPurpose, token string) (user.ID, string, error) {
+	if s.tokens == nil {
+		return 0, "", ErrInvalidEmailToken
+	}
+	t, err := s.tokens.open(purpose, token)
+	if err != nil {
+		return 0, "", err
+	}
+	return t.UserID, t.Address, nil
+}
+
 func stringifyEmails[T ~string](emails []T) []string {
 	result := make([]string, 0, len(emails))
 	for _, email := range emails {
@@ -0,0 +1,175 @@
+package notification
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"e2clicker.app/services/notification/openapi"
+	"github.com/alecthomas/assert/v2"
+	"github.com/neilotoole/slogt"
+)
+
+func TestEmailTemplates(t *testing.T) {
+	data := emailTemplateData{
+		Notification: Notification{
+			Type: openapi.ReminderMessage,
+			Message: openapi.NotificationMessage{
+				Title:   "Reminder!",
+				Message: "Don't forget to take your <hormone> dose!",
+			},
+			Username: "Pastel Cat",
+		},
+		Address:        "cat@example.com",
+		LogoCID:        emailLogoCID,
+		UnsubscribeURL: "https://e2clicker.app/api/notifications/email/unsubscribe?token=abc",
+	}
+
+	t.Run("builtin", func(t *testing.T) {
+		templates, err := loadEmailTemplates("")
+		assert.NoError(t, err)
+
+		text, html, err := templates.render(string(data.Type), data)
+		assert.NoError(t, err)
+
+		assert.Contains(t, text, "Don't forget to take your <hormone> dose!")
+		assert.Contains(t, text, data.UnsubscribeURL)
+		assert.Contains(t, html, "&lt;hormone&gt;")
+		assert.Contains(t, html, "cid:"+emailLogoCID)
+	})
+
+	t.Run("override", func(t *testing.T) {
+		dir := t.TempDir()
+		err := os.WriteFile(
+			filepath.Join(dir, "reminder_message.txt.tmpl"),
+			[]byte(`{{ define "content" }}custom: {{ .Message.Message }}{{ end }}`),
+			0644)
+		assert.NoError(t, err)
+
+		templates, err := loadEmailTemplates(dir)
+		assert.NoError(t, err)
+
+		text, _, err := templates.render(string(data.Type), data)
+		assert.NoError(t, err)
+		assert.Contains(t, text, "custom: Don't forget")
+	})
+
+	t.Run("default", func(t *testing.T) {
+		templates, err := loadEmailTemplates("")
+		assert.NoError(t, err)
+
+		data := data
+		data.Type = openapi.TestMessage
+
+		_, _, err = templates.render(string(data.Type), data)
+		assert.NoError(t, err)
+	})
+
+	t.Run("digest", func(t *testing.T) {
+		templates, err := loadEmailTemplates("")
+		assert.NoError(t, err)
+
+		vars := testDigestVariables()
+
+		data := data
+		data.Type = openapi.DigestMessage
+		data.Digest = &vars.Digest
+		data.DashboardURL = "https://e2clicker.app/dashboard"
+
+		text, html, err := templates.render(string(data.Type), data)
+		assert.NoError(t, err)
+		assert.Contains(t, text, "Mon, Mar 4 to Sun, Mar 10")
+		assert.Contains(t, text, "2 of 2")
+		assert.Contains(t, text, "Thu, Mar 14 21:00")
+		assert.Contains(t, html, "212 pg/mL")
+		assert.Contains(t, html, `href="`+data.DashboardURL+`"`)
+	})
+
+	t.Run("confirmation", func(t *testing.T) {
+		templates, err := loadEmailTemplates("")
+		assert.NoError(t, err)
+
+		data := data
+		data.ConfirmURL = "https://e2clicker.app/api/notifications/email/confirm?token=abc"
+
+		text, html, err := templates.render(emailConfirmationTemplate, data)
+		assert.NoError(t, err)
+		assert.Contains(t, text, data.ConfirmURL)
+		assert.Contains(t, html, `href="`+data.ConfirmURL+`"`)
+	})
+}
+
+func TestEmailTokenSealer(t *testing.T) {
+	sealer, err := newEmailTokenSealer("hunter2")
+	assert.NoError(t, err)
+
+	token, err := sealer.seal(emailTokenUnsubscribe, emailToken{
+		UserID:  42,
+		Address: "cat@example.com",
+	})
+	assert.NoError(t, err)
+
+	opened, err := sealer.open(emailTokenUnsubscribe, token)
+	assert.NoError(t, err)
+	assert.Equal(t, "cat@example.com", opened.Address)
+	assert.Equal(t, 42, opened.UserID)
+
+	_, err = sealer.open("other", token)
+	assert.IsError(t, err, ErrInvalidEmailToken)
+
+	other, err := newEmailTokenSealer("hunter3")
+	assert.NoError(t, err)
+	_, err = other.open(emailTokenUnsubscribe, token)
+	assert.IsError(t, err, ErrInvalidEmailToken)
+
+	_, err = sealer.open(emailTokenUnsubscribe, token[:len(token)-2])
+	assert.IsError(t, err, ErrInvalidEmailToken)
+}
+
+func TestEmailTokenExpiry(t *testing.T) {
+	sealer, err := newEmailTokenSealer("hunter2")
+	assert.NoError(t, err)
+
+	token, err := sealer.seal(emailTokenConfirm, emailToken{
+		UserID:    42,
+		Address:   "cat@example.com",
+		ExpiresAt: time.Now().Add(-time.Minute).Unix(),
+	})
+	assert.NoError(t, err)
+
+	_, err = sealer.open(emailTokenConfirm, token)
+	assert.IsError(t, err, ErrInvalidEmailToken)
+}
+
+type recordingEmailNotifier struct{ sent *[]string }
+
+func (n recordingEmailNotifier) Notify(ctx context.Context, _ Notification, c EmailNotificationConfig) error {
+	*n.sent = append(*n.sent, c.Address)
+	return nil
+}
+
+func TestNotifySkipsPending(t *testing.T) {
+	var sent []string
+	notifier, err := NewNotifier[EmailNotificationConfig](EmailMethod, recordingEmailNotifier{&sent})
+	assert.NoError(t, err)
+
+	service, err := NewNotificationService(NotificationServiceConfig{
+		Notifiers: []Notifier{notifier},
+	}, slogt.New(t))
+	assert.NoError(t, err)
+
+	configs := mustConfigs(t, map[string]any{
+		"email": []any{
+			map[string]any{"address": "confirmed@example.com"},
+			map[string]any{"address": "pending@example.com", "pending": true},
+		},
+	})
+
+	results := service.Send(context.Background(), 1, Notification{}, configs)
+	assert.Equal(t, []string{"confirmed@example.com"}, sent)
+	assert.NoError(t, results[0].Err)
+	assert.IsError(t, results[1].Err, ErrNotificationSkipped)
+	assert.NoError(t, joinNotifyErrors(results))
+}
@@ -0,0 +1,382 @@
+package notification
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"e2clicker.app/internal/validating"
+	"go.uber.org/fx"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
+)
+
+// MQTTNotificationConfig is a user configuration for the MQTT service.
+// Messages for the user are published under {topicPrefix}/{TopicID}/.
+type MQTTNotificationConfig struct {
+	// TopicID is the topic segment that identifies the user. Anyone with
+	// access to the broker can subscribe to it, so it is always generated by
+	// the server, see [assignMQTTTopicIDs].
+	TopicID string `json:"topic_id"`
+	// HomeAssistant enables Home Assistant MQTT discovery for this user.
+	HomeAssistant bool `json:"home_assistant,omitempty"`
+}
+
+const maxMQTTTopicIDLength = 64
+
+var _ validating.Validator = (*MQTTNotificationConfig)(nil)
+
+// SetDefaults generates a random topic ID if there is none.
+func (c *MQTTNotificationConfig) SetDefaults() {
+	if c.TopicID == "" {
+		c.TopicID = newMQTTTopicID()
+	}
+}
+
+// Validate checks that the configuration is valid.
+func (c *MQTTNotificationConfig) Validate() error {
+	if c.TopicID == "" {
+		return errors.New("missing topic ID")
+	}
+	if len(c.TopicID) > maxMQTTTopicIDLength {
+		return fmt.Errorf("topic ID must be at most %d bytes long", maxMQTTTopicIDLength)
+	}
+	if strings.ContainsAny(c.TopicID, "/+#$\x00") {
+		return errors.New("topic ID must not contain '/', '+', '#', '$' or NUL")
+	}
+	return nil
+}
+
+// newMQTTTopicID generates a new random topic ID for
+// [MQTTNotificationConfig]. It has 128 bits of randomness, so it is unique
+// among all users for all practical purposes.
+func newMQTTTopicID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b[:]))
+}
+
+// assignMQTTTopicIDs makes sure that every topic ID in configs was generated
+// by the server, so that users can't pick a topic ID that is easy to guess or
+// that belongs to someone else. Topic IDs that the user already has in
+// existing are kept, and every other topic ID, including one that appears
+// twice, is replaced with a new one.
+func assignMQTTTopicIDs(configs, existing []MQTTNotificationConfig) {
+	seen := make(map[string]bool, len(configs))
+	for i := range configs {
+		c := &configs[i]
+		known := slices.ContainsFunc(existing, func(old MQTTNotificationConfig) bool {
+			return old.TopicID == c.TopicID
+		})
+		if c.TopicID == "" || !known || seen[c.TopicID] {
+			c.TopicID = newMQTTTopicID()
+		}
+		seen[c.TopicID] = true
+	}
+}
+
+// MQTTTopic is the last segment of a per-user MQTT topic.
+type MQTTTopic string
+
+const (
+	// MQTTNotificationTopic receives every notification sent to the user,
+	// including dose reminders.
+	MQTTNotificationTopic MQTTTopic = "notification"
+	// MQTTDoseTopic receives an [MQTTDoseEvent] every time the user records a
+	// dose.
+	MQTTDoseTopic MQTTTopic = "dose"
+	// MQTTStateTopic holds the retained [MQTTState] of the user.
+	MQTTStateTopic MQTTTopic = "state"
+)
+
+// MQTTNotificationEvent is the payload published to [MQTTNotificationTopic].
+type MQTTNotificationEvent struct {
+	Type    string    `json:"type"`
+	Title   string    `json:"title"`
+	Message string    `json:"message"`
+	SentAt  time.Time `json:"sentAt"`
+}
+
+// MQTTDoseEvent is the payload published to [MQTTDoseTopic].
+type MQTTDoseEvent struct {
+	DeliveryMethod string     `json:"deliveryMethod"`
+	Dose           float32    `json:"dose"`
+	TakenAt        time.Time  `json:"takenAt"`
+	TakenOffAt     *time.Time `json:"takenOffAt,omitempty"`
+}
+
+// MQTTState is the payload retained in [MQTTStateTopic].
+type MQTTState struct {
+	// LastDose is the time of the last recorded dose, if any.
+	LastDose *time.Time `json:"lastDose"`
+	// NextDose is when the next dose is due, if the user has a dosage.
+	NextDose *time.Time `json:"nextDose"`
+	// EstimatedLevel is the estimated serum estradiol level in
+	// [MQTTState.LevelUnits], if it can be estimated.
+	EstimatedLevel *float64 `json:"estimatedLevel"`
+	// LevelUnits is the unit of EstimatedLevel.
+	LevelUnits string `json:"levelUnits"`
+	// UpdatedAt is when this state was computed.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+const (
+	mqttOnline  = "online"
+	mqttOffline = "offline"
+
+	mqttDisconnectQuiesce = 250 // ms
+	mqttPublishTimeout    = 10 * time.Second
+)
+
+// MQTTService is a service for publishing notifications and dosage events
+// to an MQTT broker.
+type MQTTService struct {
+	client        mqtt.Client
+	config        *e2clickermodule.MQTTSubmodule
+	stateInterval time.Duration
+	logger        *slog.Logger
+}
+
+// NewMQTTService creates a new MQTT service.
+// The connection is established in the background once the application
+// starts, and it is automatically re-established if it is lost.
+func NewMQTTService(config e2clickermodule.Notification, logger *slog.Logger, lc fx.Lifecycle) (*MQTTService, error) {
+	if config.MQTT == nil {
+		return nil, nil
+	}
+
+	var mqttConfig *e2clickermodule.MQTTSubmodule
+
+	switch value := config.MQTT.Value.(type) {
+	case e2clickermodule.MQTTSubmodule:
+		mqttConfig = &value
+	case e2clickermodule.MQTTPath:
+		b, err := os.ReadFile(string(value))
+		if err != nil {
+			return nil, fmt.Errorf("cannot read MQTT config file at %s: %w", value, err)
+		}
+		mqttConfig = new(e2clickermodule.MQTTSubmodule)
+		if err := json.Unmarshal(b, mqttConfig); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal MQTT config at %s: %w", value, err)
+		}
+	default:
+		panic("unreachable")
+	}
+
+	if mqttConfig.Qos < 0 || mqttConfig.Qos > 2 {
+		return nil, fmt.Errorf("invalid MQTT QoS %d", mqttConfig.Qos)
+	}
+
+	stateInterval, err := time.ParseDuration(mqttConfig.StateInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MQTT state interval %q: %w", mqttConfig.StateInterval, err)
+	}
+
+	logger = logger.With(
+		"notifier", "mqtt",
+		"mqtt.broker", mqttConfig.Broker,
+		"mqtt.clientID", mqttConfig.ClientID,
+	)
+
+	s := &MQTTService{
+		config:        mqttConfig,
+		stateInterval: stateInterval,
+		logger:        logger,
+	}
+
+	statusTopic := s.topic("status")
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(mqttConfig.Broker).
+		SetClientID(mqttConfig.ClientID).
+		SetUsername(mqttConfig.Username).
+		SetPassword(mqttConfig.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetWill(statusTopic, mqttOffline, byte(mqttConfig.Qos), true).
+		SetOnConnectHandler(func(c mqtt.Client) {
+			logger.Info("connected to MQTT broker")
+			c.Publish(statusTopic, byte(mqttConfig.Qos), true, mqttOnline)
+		}).
+		SetConnectionLostHandler(func(c mqtt.Client, err error) {
+			logger.Warn("lost connection to MQTT broker", "err", err)
+		})
+
+	s.client = mqtt.NewClient(opts)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			// With ConnectRetry, this token only completes once connected, so
+			// don't wait on it and let the broker come up on its own time.
+			s.client.Connect()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if s.client.IsConnected() {
+				t := s.client.Publish(statusTopic, byte(mqttConfig.Qos), true, mqttOffline)
+				waitMQTTToken(ctx, t)
+			}
+			s.client.Disconnect(mqttDisconnectQuiesce)
+			return nil
+		},
+	})
+
+	return s, nil
+}
+
+// StateInterval returns how often the retained state of each user should be
+// refreshed.
+func (s MQTTService) StateInterval() time.Duration {
+	return s.stateInterval
+}
+
+func (s MQTTService) Notify(ctx context.Context, n Notification, config MQTTNotificationConfig) error {
+	return s.publish(ctx, config, MQTTNotificationTopic, false, MQTTNotificationEvent{
+		Type:    string(n.Type),
+		Title:   n.Message.Title,
+		Message: n.Message.Message,
+		SentAt:  time.Now(),
+	})
+}
+
+// PublishDose publishes a dose event to the user's dose topic.
+func (s MQTTService) PublishDose(ctx context.Context, config MQTTNotificationConfig, dose MQTTDoseEvent) error {
+	return s.publish(ctx, config, MQTTDoseTopic, false, dose)
+}
+
+// PublishState publishes the retained state of the user. If the user has
+// Home Assistant discovery enabled, the discovery payloads are also
+// (re)published.
+func (s MQTTService) PublishState(ctx context.Context, config MQTTNotificationConfig, state MQTTState) error {
+	if err := s.publish(ctx, config, MQTTStateTopic, true, state); err != nil {
+		return err
+	}
+	if config.HomeAssistant && s.config.HomeAssistantPrefix != "" {
+		if err := s.publishDiscovery(ctx, config); err != nil {
+			return fmt.Errorf("cannot publish Home Assistant discovery: %w", err)
+		}
+	}
+	return nil
+}
+
+// https://www.home-assistant.io/integrations/sensor.mqtt/
+type homeAssistantSensor struct {
+	Name              string              `json:"name"`
+	UniqueID          string              `json:"unique_id"`
+	ObjectID          string              `json:"object_id"`
+	StateTopic        string              `json:"state_topic"`
+	ValueTemplate     string              `json:"value_template"`
+	DeviceClass       string              `json:"device_class,omitempty"`
+	StateClass        string              `json:"state_class,omitempty"`
+	UnitOfMeasurement string              `json:"unit_of_measurement,omitempty"`
+	Icon              string              `json:"icon,omitempty"`
+	AvailabilityTopic string              `json:"availability_topic"`
+	Device            homeAssistantDevice `json:"device"`
+}
+
+type homeAssistantDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+}
+
+func (s MQTTService) publishDiscovery(ctx context.Context, config MQTTNotificationConfig) error {
+	nodeID := "e2clicker_" + config.TopicID
+	device := homeAssistantDevice{
+		Identifiers:  []string{nodeID},
+		Name:         "e2clicker",
+		Manufacturer: "e2clicker",
+	}
+
+	sensors := map[string]homeAssistantSensor{
+		"last_dose": {
+			Name:          "Last dose",
+			DeviceClass:   "timestamp",
+			ValueTemplate: "{{ value_json.lastDose }}",
+			Icon:          "mdi:needle",
+		},
+		"next_dose": {
+			Name:          "Next dose",
+			DeviceClass:   "timestamp",
+			ValueTemplate: "{{ value_json.nextDose }}",
+			Icon:          "mdi:calendar-clock",
+		},
+		"estimated_level": {
+			Name:              "Estimated estradiol level",
+			StateClass:        "measurement",
+			UnitOfMeasurement: "pg/mL",
+			ValueTemplate:     "{{ value_json.estimatedLevel }}",
+			Icon:              "mdi:chart-bell-curve",
+		},
+	}
+
+	for id, sensor := range sensors {
+		sensor.UniqueID = nodeID + "_" + id
+		sensor.ObjectID = nodeID + "_" + id
+		sensor.StateTopic = s.userTopic(config, MQTTStateTopic)
+		sensor.AvailabilityTopic = s.topic("status")
+		sensor.Device = device
+
+		topic := s.config.HomeAssistantPrefix + "/sensor/" + nodeID + "/" + id + "/config"
+		if err := s.publishJSON(ctx, topic, true, sensor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s MQTTService) publish(ctx context.Context, config MQTTNotificationConfig, topic MQTTTopic, retained bool, v any) error {
+	if err := config.Validate(); err != nil {
+		return ConfigError{err: err}
+	}
+	return s.publishJSON(ctx, s.userTopic(config, topic), retained, v)
+}
+
+func (s MQTTService) publishJSON(ctx context.Context, topic string, retained bool, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal MQTT payload: %w", err)
+	}
+
+	// Bound the wait, since publishes are queued while the broker is
+	// unreachable.
+	ctx, cancel := context.WithTimeout(ctx, mqttPublishTimeout)
+	defer cancel()
+
+	t := s.client.Publish(topic, byte(s.config.Qos), retained, b)
+	if err := waitMQTTToken(ctx, t); err != nil {
+		return fmt.Errorf("cannot publish to %s: %w", topic, err)
+	}
+
+	return nil
+}
+
+func (s MQTTService) topic(name string) string {
+	return s.config.TopicPrefix + "/" + name
+}
+
+func (s MQTTService) userTopic(config MQTTNotificationConfig, topic MQTTTopic) string {
+	return s.config.TopicPrefix + "/" + config.TopicID + "/" + string(topic)
+}
+
+func waitMQTTToken(ctx context.Context, t mqtt.Token) error {
+	select {
+	case <-t.Done():
+		return t.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
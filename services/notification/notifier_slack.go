@@ -0,0 +1,145 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"e2clicker.app/internal/validating"
+
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
+)
+
+// SlackNotificationConfig is a user configuration for the Slack incoming
+// webhook service.
+type SlackNotificationConfig struct {
+	// WebhookURL is the URL of the Slack incoming webhook, which looks like
+	// https://hooks.slack.com/services/T000/B000/XXXX.
+	WebhookURL string `json:"webhook_url"`
+}
+
+// slackWebhookHosts are the only hosts that webhook URLs may point to, so that
+// users can't make the server send requests anywhere else.
+var slackWebhookHosts = []string{
+	"hooks.slack.com",
+	"hooks.slack-gov.com",
+}
+
+var _ validating.Validator = (*SlackNotificationConfig)(nil)
+
+// Validate checks that the configuration is valid.
+func (c *SlackNotificationConfig) Validate() error {
+	u, err := url.Parse(c.WebhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if u.Scheme != "https" {
+		return errors.New("webhook URL must use https")
+	}
+	if !slices.Contains(slackWebhookHosts, u.Host) {
+		return fmt.Errorf("webhook URL has unknown Slack host %q", u.Host)
+	}
+	if !strings.HasPrefix(u.Path, "/services/") {
+		return errors.New("webhook URL is not a Slack webhook")
+	}
+	return nil
+}
+
+// SlackService is a service for sending notifications via Slack incoming
+// webhooks.
+type SlackService struct {
+	http *http.Client
+}
+
+// NewSlackService creates a new Slack incoming webhook service.
+func NewSlackService(config e2clickermodule.Notification) (*SlackService, error) {
+	timeout, err := time.ParseDuration(config.ClientTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client timeout %q: %w", config.ClientTimeout, err)
+	}
+	return &SlackService{http: &http.Client{Timeout: timeout}}, nil
+}
+
+func (s SlackService) Notify(ctx context.Context, n Notification, config SlackNotificationConfig) error {
+	if err := config.Validate(); err != nil {
+		return ConfigError{err: err}
+	}
+
+	// https://api.slack.com/reference/block-kit/blocks
+	type slackText struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+
+	type slackBlock struct {
+		Type string    `json:"type"`
+		Text slackText `json:"text"`
+	}
+
+	type slackWebhookMessage struct {
+		// Text is the fallback text used in notifications.
+		Text   string       `json:"text"`
+		Blocks []slackBlock `json:"blocks"`
+	}
+
+	b, err := json.Marshal(slackWebhookMessage{
+		Text: n.Message.Title + ": " + n.Message.Message,
+		Blocks: []slackBlock{
+			{
+				Type: "header",
+				Text: slackText{Type: "plain_text", Text: truncateString(n.Message.Title, 150)},
+			},
+			{
+				Type: "section",
+				Text: slackText{Type: "mrkdwn", Text: truncateString(escapeSlackText(n.Message.Message), 3000)},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	r, err := doRateLimited(ctx, s.http,
+		func() (*http.Request, error) {
+			req, err := http.NewRequestWithContext(ctx, "POST", config.WebhookURL, bytes.NewReader(b))
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Content-Type", "application/json")
+			return req, nil
+		},
+		func(r *http.Response) time.Duration {
+			// https://api.slack.com/apis/rate-limits
+			return parseRetryAfter(r.Header, "Retry-After")
+		},
+	)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		return consumeHTTPUnknownStatusError(r)
+	}
+
+	return nil
+}
+
+var slackTextEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+)
+
+// escapeSlackText escapes the control characters in Slack's mrkdwn format.
+// See https://api.slack.com/reference/surfaces/formatting#escaping.
+func escapeSlackText(s string) string {
+	return slackTextEscaper.Replace(s)
+}
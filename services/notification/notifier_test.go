@@ -0,0 +1,438 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"e2clicker.app/services/notification/openapi"
+	"github.com/alecthomas/assert/v2"
+	"github.com/neilotoole/slogt"
+)
+
+func mustConfigs(t *testing.T, grouped map[string]any) NotificationConfigs {
+	t.Helper()
+	b, err := json.Marshal(grouped)
+	assert.NoError(t, err)
+	var c NotificationConfigs
+	assert.NoError(t, json.Unmarshal(b, &c))
+	return c
+}
+
+func TestNotificationConfigsLegacyFormat(t *testing.T) {
+	var prefs UserPreferences
+	err := json.Unmarshal([]byte(`{
+		"notificationConfigs": {
+			"mqtt": [{"topic_id":"cat"}],
+			"email": [{"address":"cat@example.com"}, {"address":"dog@example.com"}]
+		}
+	}`), &prefs)
+	assert.NoError(t, err)
+
+	assert.Equal(t, NotificationConfigs{
+		{Method: EmailMethod, Config: json.RawMessage(`{"address":"cat@example.com"}`)},
+		{Method: EmailMethod, Config: json.RawMessage(`{"address":"dog@example.com"}`)},
+		{Method: MQTTMethod, Config: json.RawMessage(`{"topic_id":"cat"}`)},
+	}, prefs.NotificationConfigs)
+
+	// The new format is written back and reads the same.
+	b, err := json.Marshal(prefs)
+	assert.NoError(t, err)
+
+	var again UserPreferences
+	assert.NoError(t, json.Unmarshal(b, &again))
+	assert.Equal(t, prefs.NotificationConfigs, again.NotificationConfigs)
+
+	mqtt, err := ConfigsOf[MQTTNotificationConfig](again.NotificationConfigs, MQTTMethod)
+	assert.NoError(t, err)
+	assert.Equal(t, []MQTTNotificationConfig{{TopicID: "cat"}}, mqtt)
+}
+
+func TestUpdateConfigsOf(t *testing.T) {
+	c := mustConfigs(t, map[string]any{
+		"discord": []any{map[string]any{"webhook_url": "https://discord.com/api/webhooks/1/a"}},
+		"email": []any{
+			map[string]any{"address": "cat@example.com", "pending": true},
+			map[string]any{"address": "dog@example.com"},
+		},
+		"mqtt": []any{map[string]any{"topic_id": "cat"}},
+	})
+	for i := range c {
+		c[i].ID = fmt.Sprint(i)
+	}
+
+	err := UpdateConfigsOf(&c, EmailMethod, func(c *EmailNotificationConfig) bool {
+		c.Pending = false
+		return c.Address != "dog@example.com"
+	})
+	assert.NoError(t, err)
+
+	ids := make([]string, len(c))
+	for i, config := range c {
+		ids[i] = config.ID
+	}
+	assert.Equal(t, []string{"0", "1", "3"}, ids)
+
+	emails, err := ConfigsOf[EmailNotificationConfig](c, EmailMethod)
+	assert.NoError(t, err)
+	assert.Equal(t, []EmailNotificationConfig{{Address: "cat@example.com"}}, emails)
+}
+
+func TestNotificationConfigsGrouped(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NotificationConfigs{
+		{
+			ID:     "abc",
+			Method: MQTTMethod,
+			Config: json.RawMessage(`{"topic_id":"cat"}`),
+			Health: &NotificationHealth{LastSuccessAt: &now},
+		},
+		{
+			Method: EmailMethod,
+			Config: json.RawMessage(`{"address":"cat@example.com"}`),
+		},
+	}
+
+	grouped := c.Grouped()
+	assert.Equal(t, `{"_health":{"lastSuccessAt":"2024-01-01T00:00:00Z","consecutiveFailures":0,"paused":false},"_id":"abc","topic_id":"cat"}`, string(grouped[MQTTMethod][0]))
+	assert.Equal(t, `{"address":"cat@example.com"}`, string(grouped[EmailMethod][0]))
+
+	// The health sent back by clients is ignored.
+	again := GroupedNotificationConfigs(grouped)
+	assert.Equal(t, NotificationConfigs{
+		{Method: EmailMethod, Config: json.RawMessage(`{"address":"cat@example.com"}`)},
+		{ID: "abc", Method: MQTTMethod, Config: json.RawMessage(`{"topic_id":"cat"}`)},
+	}, again)
+}
+
+func TestNotificationConfigsRecordResults(t *testing.T) {
+	c := NotificationConfigs{
+		{ID: "a", Method: GotifyMethod},
+		{ID: "b", Method: EmailMethod},
+	}
+
+	failed := errors.New("connection refused")
+	results := []NotifyResult{
+		{Config: c[0], Err: failed},
+		{Config: c[1], Err: ErrNotificationSkipped},
+	}
+
+	now := time.Now()
+	assert.Zero(t, c.recordResults(results, 2, now))
+	assert.Equal(t, 1, c[0].Health.ConsecutiveFailures)
+	assert.Equal(t, "connection refused", c[0].Health.LastError)
+	assert.False(t, c[0].Health.Paused)
+	assert.Zero(t, c[1].Health, "skipped configs are not tracked")
+	assert.Equal(t, c[1:], c.Healthy(), "failing configs are not healthy")
+
+	paused := c.recordResults(results, 2, now)
+	assert.Equal(t, 1, len(paused))
+	assert.Equal(t, "a", paused[0].ID)
+	assert.True(t, c[0].Health.Paused)
+	assert.Equal(t, NotificationConfigs{c[1]}, c.Unpaused())
+
+	// Configs are only paused once.
+	assert.Zero(t, c.recordResults(results, 2, now))
+	assert.Equal(t, 3, c[0].Health.ConsecutiveFailures)
+
+	// A success resumes the config and keeps the last error around.
+	assert.Zero(t, c.recordResults([]NotifyResult{{Config: c[0]}}, 2, now))
+	assert.False(t, c[0].Health.Paused)
+	assert.Equal(t, 0, c[0].Health.ConsecutiveFailures)
+	assert.Equal(t, &now, c[0].Health.LastSuccessAt)
+	assert.Equal(t, "connection refused", c[0].Health.LastError)
+	assert.Equal(t, c, c.Healthy())
+}
+
+func TestNotificationServiceValidateConfigs(t *testing.T) {
+	mqtt, err := NewNotifier[MQTTNotificationConfig](MQTTMethod, MQTTService{})
+	assert.NoError(t, err)
+
+	service, err := NewNotificationService(NotificationServiceConfig{
+		Notifiers: []Notifier{mqtt},
+	}, slogt.New(t))
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	validated, err := service.ValidateConfigs(ctx, 1, mustConfigs(t, map[string]any{
+		"mqtt": []any{map[string]any{"home_assistant": true, "junk": 1}},
+	}), nil)
+	assert.NoError(t, err)
+
+	configs, err := ConfigsOf[MQTTNotificationConfig](validated, MQTTMethod)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(configs))
+	assert.NotZero(t, configs[0].TopicID, "topic ID is generated")
+	assert.True(t, configs[0].HomeAssistant)
+	assert.NotContains(t, string(validated[0].Config), "junk")
+
+	_, err = service.ValidateConfigs(ctx, 1, mustConfigs(t, map[string]any{
+		"mqtt": []any{map[string]any{"topic_id": "a/b"}},
+	}), nil)
+	var configErr ConfigError
+	assert.True(t, errors.As(err, &configErr))
+	assert.Equal(t, MQTTMethod, configErr.Service)
+
+	// Methods that are not available are rejected unless the user already
+	// had the exact same config.
+	carrierPigeon := mustConfigs(t, map[string]any{
+		"carrierPigeon": []any{map[string]any{"coop": "north"}},
+	})
+	_, err = service.ValidateConfigs(ctx, 1, carrierPigeon, nil)
+	assert.IsError(t, err, UnknownServiceError{"carrierPigeon"})
+
+	validated, err = service.ValidateConfigs(ctx, 1, carrierPigeon, carrierPigeon)
+	assert.NoError(t, err)
+	assert.Equal(t, carrierPigeon, validated)
+}
+
+func TestNotificationServiceValidateConfigsKeepsHealth(t *testing.T) {
+	mqtt, err := NewNotifier[MQTTNotificationConfig](MQTTMethod, MQTTService{})
+	assert.NoError(t, err)
+
+	service, err := NewNotificationService(NotificationServiceConfig{
+		Notifiers: []Notifier{mqtt},
+	}, slogt.New(t))
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	existing, err := service.ValidateConfigs(ctx, 1, mustConfigs(t, map[string]any{
+		"mqtt": []any{map[string]any{"topic_id": "cat"}},
+	}), nil)
+	assert.NoError(t, err)
+	assert.NotZero(t, existing[0].ID)
+	existing[0].Health = &NotificationHealth{ConsecutiveFailures: 3}
+
+	// Configs sent back with their ID keep their health, even if they were
+	// edited. New configs get a new ID.
+	validated, err := service.ValidateConfigs(ctx, 1, NotificationConfigs{
+		{ID: existing[0].ID, Method: MQTTMethod, Config: json.RawMessage(`{"topic_id":"dog"}`)},
+		{Method: MQTTMethod, Config: json.RawMessage(`{"topic_id":"bird"}`)},
+		{ID: "made-up", Method: MQTTMethod, Config: json.RawMessage(`{"topic_id":"fish"}`)},
+	}, existing)
+	assert.NoError(t, err)
+
+	assert.Equal(t, existing[0].ID, validated[0].ID)
+	assert.Equal(t, existing[0].Health, validated[0].Health)
+	for _, config := range validated[1:] {
+		assert.NotZero(t, config.ID)
+		assert.NotEqual(t, "made-up", config.ID)
+		assert.Zero(t, config.Health)
+	}
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) Notify(context.Context, Notification, map[string]any) error { return nil }
+
+func TestNotifierSchemas(t *testing.T) {
+	methods := []string{
+		GotifyMethod,
+		PushoverMethod,
+		WebPushMethod,
+		EmailMethod,
+		DiscordMethod,
+		SlackMethod,
+		MQTTMethod,
+	}
+	for _, method := range methods {
+		t.Run(method, func(t *testing.T) {
+			n, err := NewNotifier[map[string]any](method, nopNotifier{})
+			assert.NoError(t, err)
+			assert.True(t, json.Valid(n.ConfigSchema()), "schema is not valid JSON")
+		})
+	}
+}
+
+func TestUserPreferencesConfigsFor(t *testing.T) {
+	prefs := UserPreferences{
+		NotificationConfigs: mustConfigs(t, map[string]any{
+			"webPush": []any{
+				map[string]any{"deviceID": "phone"},
+				map[string]any{"deviceID": "laptop"},
+			},
+			"email": []any{map[string]any{"address": "cat@example.com"}},
+			"mqtt":  []any{map[string]any{"topic_id": "cat"}},
+		}),
+		Routes: openapi.NotificationRoutes{
+			string(openapi.ReminderMessage): {
+				Methods: []string{WebPushMethod, MQTTMethod},
+				Devices: []openapi.PushDeviceID{"phone"},
+			},
+			string(openapi.AccountNoticeMessage): {
+				Methods: []string{EmailMethod},
+			},
+			string(openapi.WelcomeMessage): {
+				Methods: []string{},
+			},
+		},
+	}
+
+	reminder := prefs.ConfigsFor(openapi.ReminderMessage)
+	webPush, err := ConfigsOf[WebPushNotificationConfig](reminder, WebPushMethod)
+	assert.NoError(t, err)
+	assert.Equal(t, []WebPushNotificationConfig{{DeviceID: "phone"}}, webPush)
+	assert.True(t, reminder.Has(MQTTMethod))
+	assert.False(t, reminder.Has(EmailMethod))
+
+	notice := prefs.ConfigsFor(openapi.AccountNoticeMessage)
+	assert.True(t, notice.Has(EmailMethod))
+	assert.False(t, notice.Has(WebPushMethod))
+
+	assert.True(t, prefs.ConfigsFor(openapi.WelcomeMessage).IsEmpty())
+
+	// Types without a route go everywhere.
+	assert.Equal(t, prefs.NotificationConfigs, prefs.ConfigsFor(openapi.TestMessage))
+
+	// The original configs are untouched.
+	assert.Equal(t, 4, len(prefs.NotificationConfigs))
+}
+
+func TestUserPreferencesValidateRoutes(t *testing.T) {
+	prefs := UserPreferences{
+		Routes: openapi.NotificationRoutes{
+			"nonexistent_message": {
+				Methods: []string{EmailMethod},
+			},
+		},
+	}
+	assert.IsError(t, prefs.Validate(), ErrUnknownNotificationType)
+
+	prefs.Routes = openapi.NotificationRoutes{
+		string(openapi.ReminderMessage): {
+			Methods: []string{EmailMethod},
+		},
+	}
+	assert.NoError(t, prefs.Validate())
+
+	service, err := NewNotificationService(NotificationServiceConfig{}, slogt.New(t))
+	assert.NoError(t, err)
+	assert.IsError(t, service.validateRoutes(prefs.Routes), UnknownServiceError{EmailMethod})
+}
+
+func TestDiscordNotificationConfigValidate(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://discord.com/api/webhooks/123/abc", true},
+		{"https://canary.discord.com/api/webhooks/123/abc", true},
+		{"http://discord.com/api/webhooks/123/abc", false},
+		{"https://discord.com/channels/123/abc", false},
+		{"https://discord.com:8443/api/webhooks/123/abc", false},
+		{"https://discord.com:443/api/webhooks/123/abc", false},
+		{"https://discord.com.example.com/api/webhooks/123/abc", false},
+		{"https://localhost/api/webhooks/123/abc", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			c := DiscordNotificationConfig{WebhookURL: test.url}
+			if test.valid {
+				assert.NoError(t, c.Validate())
+			} else {
+				assert.Error(t, c.Validate())
+			}
+		})
+	}
+}
+
+func TestSlackNotificationConfigValidate(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://hooks.slack.com/services/T000/B000/XXXX", true},
+		{"https://hooks.slack-gov.com/services/T000/B000/XXXX", true},
+		{"http://hooks.slack.com/services/T000/B000/XXXX", false},
+		{"https://hooks.slack.com/api/T000/B000/XXXX", false},
+		{"https://hooks.slack.com:8443/services/T000/B000/XXXX", false},
+		{"https://example.com/services/T000/B000/XXXX", false},
+		{"https://hooks.slack.com.example.com/services/T000", false},
+		{"https://localhost/services/T000/B000/XXXX", false},
+		{"https://10.0.0.1/services/T000/B000/XXXX", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			c := SlackNotificationConfig{WebhookURL: test.url}
+			if test.valid {
+				assert.NoError(t, c.Validate())
+			} else {
+				assert.Error(t, c.Validate())
+			}
+		})
+	}
+}
+
+func TestAssignMQTTTopicIDs(t *testing.T) {
+	existing := []MQTTNotificationConfig{{TopicID: "known"}}
+	configs := []MQTTNotificationConfig{
+		{TopicID: "known"},
+		{TopicID: "known"},
+		{TopicID: "chosen-by-client"},
+		{},
+	}
+
+	assignMQTTTopicIDs(configs, existing)
+
+	assert.Equal(t, "known", configs[0].TopicID)
+	seen := map[string]bool{"known": true}
+	for _, c := range configs[1:] {
+		assert.NoError(t, c.Validate())
+		assert.False(t, seen[c.TopicID], "topic ID %q is reused", c.TopicID)
+		assert.NotEqual(t, "chosen-by-client", c.TopicID)
+		seen[c.TopicID] = true
+	}
+}
+
+func TestTestNotificationResult(t *testing.T) {
+	ctx := context.Background()
+	config := NotificationConfig{ID: "abc", Method: GotifyMethod}
+
+	result := testNotificationResult(ctx, NotifyResult{Config: config})
+	assert.Equal(t, openapi.TestNotificationResult{
+		Method: GotifyMethod,
+		ID:     "abc",
+		Status: openapi.TestNotificationSent,
+	}, result)
+
+	result = testNotificationResult(ctx, NotifyResult{Config: config, Err: ErrNotificationSkipped})
+	assert.Equal(t, openapi.TestNotificationSkipped, result.Status)
+
+	result = testNotificationResult(ctx, NotifyResult{
+		Config: config,
+		Err: fmt.Errorf("cannot send: %w", HTTPUnknownStatusError{
+			StatusCode: 401,
+			Body:       strings.Repeat("unauthorized ", 100),
+		}),
+	})
+	assert.Equal(t, openapi.TestNotificationFailed, result.Status)
+	assert.Equal(t, 401, result.HTTPStatus)
+	assert.Contains(t, result.Error, "401")
+	// The response body could be from any server, so it's never returned.
+	assert.NotContains(t, result.Error, "unauthorized")
+
+	// Errors that aren't public are hidden.
+	result = testNotificationResult(ctx, NotifyResult{Config: config, Err: errors.New("secret database error")})
+	assert.Equal(t, openapi.TestNotificationFailed, result.Status)
+	assert.NotContains(t, result.Error, "database")
+	assert.Zero(t, result.HTTPStatus)
+
+	// Unsaved configs only say whether they failed, not how.
+	result = unsavedTestNotificationResult(NotifyResult{
+		Config: config,
+		Err:    fmt.Errorf("cannot send: %w", HTTPUnknownStatusError{StatusCode: 401}),
+	})
+	assert.Equal(t, openapi.TestNotificationResult{
+		Method: GotifyMethod,
+		Status: openapi.TestNotificationFailed,
+		Error:  "cannot deliver the notification",
+	}, result)
+}
@@ -27,6 +27,73 @@ func convertSubscription(subscription openapi.PushSubscription) *webpush.Subscri
 	}
 }
 
+// webPushDelivery describes how a push service should deliver a notification
+// of a particular type.
+type webPushDelivery struct {
+	// TTL is how long the push service keeps the notification around while the
+	// device is offline. Zero means the notification is dropped if it cannot
+	// be delivered right away.
+	TTL time.Duration
+	// Urgency is how eagerly the device should wake up for the notification.
+	Urgency webpush.Urgency
+	// Topic collapses pending notifications of the same topic, so that only
+	// the latest one is delivered once the device is back online.
+	Topic string
+}
+
+// defaultWebPushDelivery is used for notification types that are not in
+// [webPushDeliveries].
+var defaultWebPushDelivery = webPushDelivery{
+	TTL:     24 * time.Hour,
+	Urgency: webpush.UrgencyNormal,
+}
+
+// webPushDeliveries is the delivery of each notification type.
+var webPushDeliveries = map[openapi.NotificationType]webPushDelivery{
+	// Reminders replace each other, since only the latest one matters. They
+	// stop being useful once the next dose is due anyway.
+	openapi.ReminderMessage: {
+		TTL:     12 * time.Hour,
+		Urgency: webpush.UrgencyHigh,
+		Topic:   "reminder",
+	},
+	// Test notifications are only useful while the user is looking.
+	openapi.TestMessage: {
+		TTL:     5 * time.Minute,
+		Urgency: webpush.UrgencyLow,
+	},
+	openapi.DigestMessage: {
+		TTL:     7 * 24 * time.Hour,
+		Urgency: webpush.UrgencyLow,
+		Topic:   "digest",
+	},
+	openapi.WebPushExpiringMessage: {
+		TTL:     7 * 24 * time.Hour,
+		Urgency: webpush.UrgencyNormal,
+		Topic:   "web-push-expiring",
+	},
+	openapi.SubscriptionPausedMessage: {
+		TTL:     7 * 24 * time.Hour,
+		Urgency: webpush.UrgencyNormal,
+	},
+	openapi.AccountNoticeMessage: {
+		TTL:     7 * 24 * time.Hour,
+		Urgency: webpush.UrgencyNormal,
+	},
+	openapi.AccountDeletedMessage: {
+		TTL:     7 * 24 * time.Hour,
+		Urgency: webpush.UrgencyNormal,
+	},
+}
+
+// webPushDeliveryFor returns the delivery of the given notification type.
+func webPushDeliveryFor(t openapi.NotificationType) webPushDelivery {
+	if d, ok := webPushDeliveries[t]; ok {
+		return d
+	}
+	return defaultWebPushDelivery
+}
+
 // WebPushService is a service for sending notifications via the Push API.
 type WebPushService struct {
 	http   *http.Client
@@ -62,33 +129,65 @@ func NewWebPushSevice(config e2clickermodule.Notification) (*WebPushService, err
 		panic("unreachable")
 	}
 
+	if _, ok := keys.PreviousKeys[keys.ID]; ok {
+		return nil, fmt.Errorf("VAPID key %q is both the current and a previous key", keys.ID)
+	}
+
 	return &WebPushService{
 		http:   &http.Client{Timeout: timeout},
 		config: keys,
 	}, nil
 }
 
-// VAPIDPublicKey returns the VAPID public key.
+// VAPIDPublicKey returns the current VAPID public key.
 func (s WebPushService) VAPIDPublicKey() string {
 	return s.config.PublicKey
 }
 
+// VAPIDKeyID returns the ID of the current VAPID key.
+func (s WebPushService) VAPIDKeyID() string {
+	return s.config.ID
+}
+
+// vapidKey returns the VAPID key pair with the given ID, which is either the
+// current key or one of the previous keys.
+func (s WebPushService) vapidKey(id string) (privateKey, publicKey string, ok bool) {
+	if id == s.config.ID {
+		return s.config.PrivateKey, s.config.PublicKey, true
+	}
+	k, ok := s.config.PreviousKeys[id]
+	return k.PrivateKey, k.PublicKey, ok
+}
+
 func (s WebPushService) Notify(ctx context.Context, n Notification, config WebPushNotificationConfig) error {
 	if !config.ExpirationTime.IsZero() && config.ExpirationTime.Before(time.Now()) {
 		return &WebPushSubscriptionExpired{config.ExpirationTime}
 	}
 
+	privateKey, publicKey, ok := s.vapidKey(config.KeyID)
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownVAPIDKey, config.KeyID)
+	}
+
+	// Ask the client to move to the current key. The subscription keeps
+	// working until then.
+	n.Resubscribe = config.KeyID != s.config.ID
+
 	m, err := json.Marshal(n)
 	if err != nil {
 		return fmt.Errorf("cannot marshal notification: %w", err)
 	}
 
+	delivery := webPushDeliveryFor(n.Type)
+
 	opts := &webpush.Options{
 		HTTPClient:      s.http,
-		Urgency:         webpush.UrgencyHigh,
+		TTL:             int(delivery.TTL / time.Second),
+		Urgency:         delivery.Urgency,
+		Topic:           delivery.Topic,
 		Subscriber:      n.Username,
-		VAPIDPublicKey:  s.config.PublicKey,
-		VAPIDPrivateKey: s.config.PrivateKey,
+		VAPIDPublicKey:  publicKey,
+		VAPIDPrivateKey: privateKey,
 	}
 
 	resp, err := webpush.SendNotificationWithContext(ctx, m, convertSubscription(config), opts)
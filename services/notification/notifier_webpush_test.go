@@ -0,0 +1,163 @@
+package notification
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"e2clicker.app/services/notification/openapi"
+	"github.com/SherClockHolmes/webpush-go"
+	"github.com/alecthomas/assert/v2"
+
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
+)
+
+func TestWebPushHeaders(t *testing.T) {
+	var headers http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	vapidPrivate, vapidPublic, err := webpush.GenerateVAPIDKeys()
+	assert.NoError(t, err)
+
+	s := &WebPushService{
+		http: srv.Client(),
+		config: &e2clickermodule.WebPushSubmodule{
+			PrivateKey: vapidPrivate,
+			PublicKey:  vapidPublic,
+		},
+	}
+
+	config := testPushSubscription(t, srv.URL)
+
+	tests := []struct {
+		typ     openapi.NotificationType
+		ttl     string
+		urgency string
+		topic   string
+	}{
+		{openapi.ReminderMessage, "43200", "high", "reminder"},
+		{openapi.TestMessage, "300", "low", ""},
+		{openapi.DigestMessage, "604800", "low", "digest"},
+		{openapi.WelcomeMessage, "86400", "normal", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.typ), func(t *testing.T) {
+			headers = nil
+
+			n := Notification{
+				Type:     test.typ,
+				Message:  openapi.NotificationMessage{Title: "Title", Message: "Message"},
+				Username: "Pastel Cat",
+			}
+			err := s.Notify(context.Background(), n, config)
+			assert.NoError(t, err)
+
+			assert.NotZero(t, headers)
+			assert.Equal(t, test.ttl, headers.Get("TTL"))
+			assert.Equal(t, test.urgency, headers.Get("Urgency"))
+			assert.Equal(t, test.topic, headers.Get("Topic"))
+		})
+	}
+}
+
+func TestWebPushDeliveryTopics(t *testing.T) {
+	// RFC 8030 limits topics to 32 characters of the URL-safe base64 alphabet.
+	for typ, d := range webPushDeliveries {
+		assert.True(t, len(d.Topic) <= 32, "topic of %s is too long", typ)
+		for _, r := range d.Topic {
+			ok := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_'
+			assert.True(t, ok, "topic of %s has invalid character %q", typ, r)
+		}
+	}
+}
+
+func TestWebPushVAPIDKeys(t *testing.T) {
+	var authorization string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	oldPrivate, oldPublic, err := webpush.GenerateVAPIDKeys()
+	assert.NoError(t, err)
+	newPrivate, newPublic, err := webpush.GenerateVAPIDKeys()
+	assert.NoError(t, err)
+
+	config := &e2clickermodule.WebPushSubmodule{
+		ID:         "new",
+		PrivateKey: newPrivate,
+		PublicKey:  newPublic,
+	}
+	config.PreviousKeys = map[string]struct {
+		PrivateKey string `json:"privateKey"`
+		PublicKey  string `json:"publicKey"`
+	}{
+		"": {PrivateKey: oldPrivate, PublicKey: oldPublic},
+	}
+
+	s := &WebPushService{http: srv.Client(), config: config}
+	assert.Equal(t, "new", s.VAPIDKeyID())
+	assert.Equal(t, newPublic, s.VAPIDPublicKey())
+
+	n := Notification{
+		Type:     openapi.TestMessage,
+		Message:  openapi.NotificationMessage{Title: "Title", Message: "Message"},
+		Username: "Pastel Cat",
+	}
+
+	tests := []struct {
+		name   string
+		keyID  string
+		key    string
+		hasErr error
+	}{
+		{"current", "new", newPublic, nil},
+		{"previous", "", oldPublic, nil},
+		{"unknown", "gone", "", ErrUnknownVAPIDKey},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			authorization = ""
+
+			sub := testPushSubscription(t, srv.URL)
+			sub.KeyID = test.keyID
+
+			err := s.Notify(context.Background(), n, sub)
+			if test.hasErr != nil {
+				assert.True(t, errors.Is(err, test.hasErr), "unexpected error: %v", err)
+				assert.Equal(t, "", authorization)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Contains(t, authorization, "k="+test.key)
+		})
+	}
+}
+
+func testPushSubscription(t *testing.T, endpoint string) WebPushNotificationConfig {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	auth := make([]byte, 16)
+	_, err = rand.Read(auth)
+	assert.NoError(t, err)
+
+	var config WebPushNotificationConfig
+	config.DeviceID = "test"
+	config.Endpoint = endpoint
+	config.Keys.P256Dh = base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes())
+	config.Keys.Auth = base64.RawURLEncoding.EncodeToString(auth)
+	return config
+}
@@ -4,18 +4,39 @@
 package openapi
 
 import (
+	"encoding/json"
 	"time"
 
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
 // Defines values for NotificationType.
 const (
-	AccountNoticeMessage   NotificationType = "account_notice_message"
-	ReminderMessage        NotificationType = "reminder_message"
-	TestMessage            NotificationType = "test_message"
-	WebPushExpiringMessage NotificationType = "web_push_expiring_message"
-	WelcomeMessage         NotificationType = "welcome_message"
+	AccountDeletedMessage     NotificationType = "account_deleted_message"
+	AccountNoticeMessage      NotificationType = "account_notice_message"
+	DigestMessage             NotificationType = "digest_message"
+	ReminderMessage           NotificationType = "reminder_message"
+	SubscriptionPausedMessage NotificationType = "subscription_paused_message"
+	TestMessage               NotificationType = "test_message"
+	WebPushExpiringMessage    NotificationType = "web_push_expiring_message"
+	WelcomeMessage            NotificationType = "welcome_message"
+)
+
+// Defines values for DigestFrequency.
+const (
+	MonthlyDigest DigestFrequency = "monthly"
+	WeeklyDigest  DigestFrequency = "weekly"
+)
+
+// Defines values for TestNotificationStatus.
+const (
+	TestNotificationFailed  TestNotificationStatus = "failed"
+	TestNotificationSent    TestNotificationStatus = "sent"
+	TestNotificationSkipped TestNotificationStatus = "skipped"
 )
 
 // PushDeviceID A short ID associated with the device that the push subscription is for This is used to identify the device when updating its push subscription later on.
@@ -33,11 +54,58 @@ type PushDeviceID = string
 //   - `web_push_expiring_message` is sent to notify the user that their
 //     web push subscription is expiring.
 //   - `test_message` is sent to test your notification settings.
+//   - `subscription_paused_message` is sent to notify the user that one
+//     of their notification configs kept failing and has been paused.
+//   - `digest_message` is the weekly or monthly summary of the user's
+//     doses, if they opted in to it.
+//   - `account_deleted_message` is sent to confirm that the user's
+//     account and all of its data were deleted. It is the last
+//     notification that the user gets.
 type NotificationType string
 
 // CustomNotifications Custom notifications that the user can override with. The object keys are the notification types.
+//
+// The title and message are Go [text/template](https://pkg.go.dev/text/template) templates. They can use `{{ .Username }}`, `{{ .Dose }}`, `{{ .Units }}`, `{{ .DeliveryMethod }}`, `{{ .LastDoseAt }}`, `{{ .SinceLastDose }}`, `{{ .DueAt }}` and `{{ .Overdue }}`, which are empty or zero for notifications that aren't about a dose. Digests can also use `{{ .Digest }}`, which is a `DigestSummary` with Go field names, e.g. `{{ .Digest.DosesTaken }}`. Durations and times can be formatted with the `duration`, `time`, `date` and `datetime` functions, e.g. `{{ duration .Overdue }}` or `{{ time .DueAt }}`, and `minutes` turns a number of minutes into a duration. Loops and nested templates are not allowed.
 type CustomNotifications map[string]NotificationMessage
 
+// DigestFrequency How often the user gets a digest of their doses. Weekly digests cover Monday to Sunday and are sent on Monday morning, and monthly digests cover the previous month and are sent on its first morning, in the user's time zone.
+type DigestFrequency string
+
+// DigestSummary A summary of the user's doses over a week or a month.
+type DigestSummary struct {
+	Frequency DigestFrequency `json:"frequency"`
+
+	// PeriodStart The start of the period that the digest covers.
+	PeriodStart time.Time `json:"periodStart"`
+
+	// PeriodEnd The end of the period that the digest covers. It is exclusive.
+	PeriodEnd time.Time `json:"periodEnd"`
+
+	// DosesTaken The number of doses taken in the period.
+	DosesTaken int `json:"dosesTaken"`
+
+	// DosesExpected The number of doses that the user's schedule called for in the period.
+	DosesExpected int `json:"dosesExpected"`
+
+	// AverageLateMinutes How many minutes late the doses in the period were taken on average. Doses taken early count as on time.
+	AverageLateMinutes int `json:"averageLateMinutes"`
+
+	// RemindersSent The number of dose reminders that were sent in the period.
+	RemindersSent int `json:"remindersSent"`
+
+	// RemindersFailed The number of dose reminders that failed to be sent in the period.
+	RemindersFailed int `json:"remindersFailed"`
+
+	// NextDoseAt When the next dose is due, if the user has taken any dose.
+	NextDoseAt *time.Time `json:"nextDoseAt,omitempty"`
+
+	// Trough The estimated serum estradiol level right before the next dose, in `troughUnits`. It is omitted if it can't be estimated for the user's delivery method.
+	Trough float64 `json:"trough,omitempty"`
+
+	// TroughUnits The units of `trough`, e.g. `pg/mL`.
+	TroughUnits string `json:"troughUnits,omitempty"`
+}
+
 // EmailSubscription defines model for EmailSubscription.
 type EmailSubscription struct {
 	// Address The email address to send the notification to. This email address will appear in the `To` field of the email.
@@ -45,6 +113,9 @@ type EmailSubscription struct {
 
 	// Name The name of the user to send the email to. This name will be used with the email address in the `To` field.
 	Name *string `json:"name,omitempty"`
+
+	// Pending Whether the email address is waiting to be confirmed. A confirmation link is sent to every newly added address, and no notifications are sent to it until the link is followed. This is set by the server and ignored when updating preferences.
+	Pending bool `json:"pending,omitempty"`
 }
 
 // Notification defines model for Notification.
@@ -58,6 +129,13 @@ type Notification struct {
 	//   - `web_push_expiring_message` is sent to notify the user that their
 	//     web push subscription is expiring.
 	//   - `test_message` is sent to test your notification settings.
+	//   - `subscription_paused_message` is sent to notify the user that one
+	//     of their notification configs kept failing and has been paused.
+	//   - `digest_message` is the weekly or monthly summary of the user's
+	//     doses, if they opted in to it.
+	//   - `account_deleted_message` is sent to confirm that the user's
+	//     account and all of its data were deleted. It is the last
+	//     notification that the user gets.
 	Type NotificationType `json:"type"`
 
 	// Message The message of the notification.
@@ -65,6 +143,40 @@ type Notification struct {
 
 	// Username The username of the user to send the notification to.
 	Username string `json:"username"`
+
+	// Digest The summary that a `digest_message` notification is about. It is only set for digests.
+	Digest *DigestSummary `json:"digest,omitempty"`
+
+	// Resubscribe This is only set on web push notifications. It is true if the push subscription was created with a VAPID key that the server has since rotated out, in which case the client should subscribe again using the key from `PushInfo`.
+	Resubscribe bool `json:"resubscribe,omitempty"`
+}
+
+// NotificationConfig The config of a single notification channel. For example, an `email` config is an `EmailSubscription`, and a `webPush` config is a `PushSubscription`.
+//
+// Besides the properties in the method's config schema, every config has an `_id` and a `_health` set by the server. The `_id` should be sent back with the config so that it keeps its health. Configs without a known `_id` are treated as new.
+type NotificationConfig = json.RawMessage
+
+// NotificationConfigs The user's notification channels, grouped by notification method. Each config must follow the config schema of its method, as returned by `GET /notifications/methods`. The configs of methods that the server does not support are rejected.
+//
+// Credentials, which are the properties marked with `x-secret: true` in the config schema, may be stored encrypted. They are then returned encrypted and can be sent back as they are to keep them unchanged.
+type NotificationConfigs map[string][]NotificationConfig
+
+// NotificationHealth Whether notifications are being delivered to a notification config. A config that fails too many times in a row is paused: no notifications other than test notifications are sent to it until a test notification succeeds.
+type NotificationHealth struct {
+	// LastSuccessAt The last time a notification was delivered.
+	LastSuccessAt *time.Time `json:"lastSuccessAt,omitempty"`
+
+	// LastErrorAt The last time a notification failed to be delivered.
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"`
+
+	// LastError The error of the last failed notification.
+	LastError string `json:"lastError,omitempty"`
+
+	// ConsecutiveFailures The number of notifications that failed in a row.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+
+	// Paused Whether the config is paused because it failed too many times in a row.
+	Paused bool `json:"paused"`
 }
 
 // NotificationMessage The message of the notification. This is derived from the notification type but can be overridden by the user.
@@ -76,23 +188,55 @@ type NotificationMessage struct {
 	Message string `json:"message"`
 }
 
-// NotificationMethodSupports A list of notification methods that the server supports.
-type NotificationMethodSupports = []string
+// NotificationMethod A notification method that the server supports.
+type NotificationMethod struct {
+	// Name The name of the method. This is the key of the method's configs in `NotificationConfigs`.
+	Name string `json:"name"`
+
+	// ConfigSchema The JSON Schema of the method's config. Clients can use it to render the settings form of the method.
+	ConfigSchema json.RawMessage `json:"configSchema"`
+}
+
+// NotificationMethods A list of notification methods that the server supports.
+type NotificationMethods = []NotificationMethod
 
 // NotificationPreferences The user's notification preferences.
 // Each key is a notification type and the value is the notification configuration for that type. It may be nil if the server does not support a particular notification type.
 type NotificationPreferences struct {
 	CustomNotifications CustomNotifications `json:"customNotifications,omitempty"`
-	NotificationConfigs struct {
-		Email   *[]EmailSubscription `json:"email,omitempty"`
-		WebPush *[]PushSubscription  `json:"webPush,omitempty"`
-	} `json:"notificationConfigs"`
+
+	// DigestFrequency How often the user gets a `digest_message`. Digests are off if this is not set.
+	DigestFrequency DigestFrequency `json:"digestFrequency,omitempty"`
+
+	// NotificationConfigs The user's notification channels, grouped by notification method. Each config must follow the config schema of its method, as returned by `GET /notifications/methods`. The configs of methods that the server does not support are rejected.
+	//
+	// Credentials, which are the properties marked with `x-secret: true` in the config schema, may be stored encrypted. They are then returned encrypted and can be sent back as they are to keep them unchanged.
+	NotificationConfigs NotificationConfigs `json:"notificationConfigs"`
+	Routes              NotificationRoutes  `json:"routes,omitempty"`
+
+	// Timezone The IANA time zone of the user, e.g. `America/Los_Angeles`. Times in custom notification messages are shown in this time zone. If empty, UTC is used.
+	Timezone string `json:"timezone,omitempty"`
 }
 
+// NotificationRoute The channels that a type of notification is sent to.
+type NotificationRoute struct {
+	// Methods The notification methods that receive this type of notification. An empty list mutes the notification type.
+	Methods []string `json:"methods"`
+
+	// Devices If set, web push notifications of this type are only sent to these devices instead of every device. This only matters if `webPush` is in `methods`.
+	Devices []PushDeviceID `json:"devices,omitempty"`
+}
+
+// NotificationRoutes Routing rules that decide where each type of notification is sent. The object keys are the notification types. Types without a rule are sent to every configured channel.
+type NotificationRoutes map[string]NotificationRoute
+
 // PushInfo This is returned by the server and contains information that the client would need to subscribe to push notifications.
 type PushInfo struct {
 	// ApplicationServerKey A Base64-encoded string or ArrayBuffer containing an ECDSA P-256 public key that the push server will use to authenticate your application server. If specified, all messages from your application server must use the VAPID authentication scheme, and include a JWT signed with the corresponding private key. This key IS NOT the same ECDH key that you use to encrypt the data. For more information, see "Using VAPID with WebPush".
 	ApplicationServerKey string `json:"applicationServerKey"`
+
+	// KeyID The ID of the VAPID key in `applicationServerKey`. Clients should store it in the `keyID` of the push subscription that they create with the key.
+	KeyID string `json:"keyID,omitempty"`
 }
 
 // PushSubscription The configuration for a push notification subscription.
@@ -118,6 +262,82 @@ type PushSubscription struct {
 		// Auth An authentication secret, as described in Message Encryption for Web Push.
 		Auth string `json:"auth"`
 	} `json:"keys"`
+
+	// KeyID The ID of the VAPID key that the subscription was created with, as returned in `PushInfo`. Subscriptions without one were created with the key that had no ID.
+	KeyID string `json:"keyID,omitempty"`
+}
+
+// TestNotificationResult The result of sending a test notification to a single config.
+type TestNotificationResult struct {
+	// Method The method of the config.
+	Method string `json:"method"`
+
+	// ID The `_id` of the config. It is omitted for unsaved configs.
+	ID string `json:"id,omitempty"`
+
+	// Status Whether the notification was delivered:
+	//   - `sent` if it was delivered.
+	//   - `skipped` if the config can't receive notifications yet, such as
+	//     an email address that isn't confirmed.
+	//   - `failed` if it couldn't be delivered. `error` says why.
+	Status TestNotificationStatus `json:"status"`
+
+	// Error The error message if the notification failed. For unsaved configs, it doesn't say why.
+	Error string `json:"error,omitempty"`
+
+	// HTTPStatus The HTTP status code that the notification service responded with, if it responded with one that wasn't expected. It is omitted for unsaved configs.
+	HTTPStatus int `json:"httpStatus,omitempty"`
+}
+
+// TestNotificationStatus Whether the notification was delivered:
+//   - `sent` if it was delivered.
+//   - `skipped` if the config can't receive notifications yet, such as
+//     an email address that isn't confirmed.
+//   - `failed` if it couldn't be delivered. `error` says why.
+type TestNotificationStatus string
+
+// TestNotificationTarget The notification configs that a test notification is sent to. All configs are used if nothing is set.
+type TestNotificationTarget struct {
+	// Method Only send the notification to configs of this method. It is required if `config` is set.
+	Method string `json:"method,omitempty"`
+
+	// ID Only send the notification to the config with this `_id`.
+	ID string `json:"id,omitempty"`
+
+	// Config The config of a single notification channel. For example, an `email` config is an `EmailSubscription`, and a `webPush` config is a `PushSubscription`.
+	//
+	// Besides the properties in the method's config schema, every config has an `_id` and a `_health` set by the server. The `_id` should be sent back with the config so that it keeps its health. Configs without a known `_id` are treated as new.
+	Config *NotificationConfig `json:"config,omitempty"`
+}
+
+// EmailConfirmToken defines model for EmailConfirmToken.
+type EmailConfirmToken = string
+
+// EmailUnsubscribeToken defines model for EmailUnsubscribeToken.
+type EmailUnsubscribeToken = string
+
+// EmailConfirmPageParams defines parameters for EmailConfirmPage.
+type EmailConfirmPageParams struct {
+	// Token The token from the link of a confirmation email.
+	Token EmailConfirmToken `form:"token" json:"token"`
+}
+
+// EmailConfirmParams defines parameters for EmailConfirm.
+type EmailConfirmParams struct {
+	// Token The token from the link of a confirmation email.
+	Token EmailConfirmToken `form:"token" json:"token"`
+}
+
+// EmailUnsubscribePageParams defines parameters for EmailUnsubscribePage.
+type EmailUnsubscribePageParams struct {
+	// Token The token from the unsubscribe link of an email.
+	Token EmailUnsubscribeToken `form:"token" json:"token"`
+}
+
+// EmailUnsubscribeParams defines parameters for EmailUnsubscribe.
+type EmailUnsubscribeParams struct {
+	// Token The token from the unsubscribe link of an email.
+	Token EmailUnsubscribeToken `form:"token" json:"token"`
 }
 
 // UserUpdateNotificationPreferencesJSONBody defines parameters for UserUpdateNotificationPreferences.
@@ -127,11 +347,22 @@ type UserUpdateNotificationPreferencesJSONBody struct {
 	// TODO: Implement this field.
 	Current             *NotificationPreferences `json:"_current,omitempty"`
 	CustomNotifications CustomNotifications      `json:"customNotifications,omitempty"`
-	NotificationConfigs struct {
-		Email   *[]EmailSubscription `json:"email,omitempty"`
-		WebPush *[]PushSubscription  `json:"webPush,omitempty"`
-	} `json:"notificationConfigs"`
+
+	// DigestFrequency How often the user gets a `digest_message`. Digests are off if this is not set.
+	DigestFrequency DigestFrequency `json:"digestFrequency,omitempty"`
+
+	// NotificationConfigs The user's notification channels, grouped by notification method. Each config must follow the config schema of its method, as returned by `GET /notifications/methods`. The configs of methods that the server does not support are rejected.
+	//
+	// Credentials, which are the properties marked with `x-secret: true` in the config schema, may be stored encrypted. They are then returned encrypted and can be sent back as they are to keep them unchanged.
+	NotificationConfigs NotificationConfigs `json:"notificationConfigs"`
+	Routes              NotificationRoutes  `json:"routes,omitempty"`
+
+	// Timezone The IANA time zone of the user, e.g. `America/Los_Angeles`. Times in custom notification messages are shown in this time zone. If empty, UTC is used.
+	Timezone string `json:"timezone,omitempty"`
 }
 
 // UserUpdateNotificationPreferencesJSONRequestBody defines body for UserUpdateNotificationPreferences for application/json ContentType.
 type UserUpdateNotificationPreferencesJSONRequestBody UserUpdateNotificationPreferencesJSONBody
+
+// SendTestNotificationJSONRequestBody defines body for SendTestNotification for application/json ContentType.
+type SendTestNotificationJSONRequestBody = TestNotificationTarget
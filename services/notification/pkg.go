@@ -4,6 +4,7 @@ package notification
 import (
 	"log/slog"
 
+	"e2clicker.app/services/user"
 	"go.uber.org/fx"
 )
 
@@ -18,5 +19,16 @@ var Module = fx.Module("notification",
 		NewPushoverService,
 		NewWebPushSevice,
 		NewEmailService,
+		NewDiscordService,
+		NewSlackService,
+		NewMQTTService,
 	),
+	ProvideNotifier[GotifyNotificationConfig, GotifyService](GotifyMethod),
+	ProvideNotifier[PushoverNotificationConfig, PushoverService](PushoverMethod),
+	ProvideNotifier[WebPushNotificationConfig, WebPushService](WebPushMethod),
+	ProvideNotifier[EmailNotificationConfig, EmailService](EmailMethod),
+	ProvideNotifier[DiscordNotificationConfig, DiscordService](DiscordMethod),
+	ProvideNotifier[SlackNotificationConfig, SlackService](SlackMethod),
+	ProvideNotifier[MQTTNotificationConfig, MQTTService](MQTTMethod),
+	user.ProvideAccountPurgeListener[*UserNotificationService](),
 )
@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxRateLimitWait is the longest that a notifier will wait for a rate
+	// limit to reset before giving up and returning [HTTPRateLimitedError].
+	maxRateLimitWait = 15 * time.Second
+	// maxRateLimitRetries is the maximum number of times a request is retried
+	// after being rate limited.
+	maxRateLimitRetries = 2
+)
+
+// doRateLimited sends the request created by newRequest. If the server responds
+// with 429 Too Many Requests, retryAfter is called to determine how long to
+// wait before retrying. If the wait is short enough, the request is retried;
+// otherwise, an [HTTPRateLimitedError] is returned.
+//
+// newRequest is called once per attempt, since a request body cannot be sent
+// twice.
+func doRateLimited(
+	ctx context.Context,
+	client *http.Client,
+	newRequest func() (*http.Request, error),
+	retryAfter func(*http.Response) time.Duration,
+) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		r, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send notification: %w", err)
+		}
+
+		if r.StatusCode != http.StatusTooManyRequests {
+			return r, nil
+		}
+
+		wait := retryAfter(r)
+		r.Body.Close()
+
+		if attempt >= maxRateLimitRetries || wait > maxRateLimitWait {
+			return nil, HTTPRateLimitedError{RetryAfter: wait}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// parseRetryAfter parses the first of the given headers that is present in h
+// as a duration. The header values may either be a number of seconds, which
+// can be fractional, or an HTTP date as allowed by the standard Retry-After
+// header. If none of the headers are present or valid, then
+// [maxRateLimitWait] is returned.
+func parseRetryAfter(h http.Header, keys ...string) time.Duration {
+	for _, key := range keys {
+		v := h.Get(key)
+		if v == "" {
+			continue
+		}
+		if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+			return time.Duration(secs * float64(time.Second))
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return max(time.Until(t), 0)
+		}
+	}
+	return maxRateLimitWait
+}
@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestDoRateLimited(t *testing.T) {
+	newServer := func(t *testing.T, retryAfter string, limitedTimes int) (*httptest.Server, *int) {
+		var calls int
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls <= limitedTimes {
+				w.Header().Set("Retry-After", retryAfter)
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+		return srv, &calls
+	}
+
+	send := func(ctx context.Context, srv *httptest.Server) (*http.Response, error) {
+		return doRateLimited(ctx, srv.Client(),
+			func() (*http.Request, error) {
+				return http.NewRequestWithContext(ctx, "POST", srv.URL, nil)
+			},
+			func(r *http.Response) time.Duration {
+				return parseRetryAfter(r.Header, "Retry-After")
+			},
+		)
+	}
+
+	t.Run("retries", func(t *testing.T) {
+		srv, calls := newServer(t, "0.01", 2)
+
+		r, err := send(context.Background(), srv)
+		assert.NoError(t, err)
+		r.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, r.StatusCode)
+		assert.Equal(t, 3, *calls)
+	})
+
+	t.Run("too_long", func(t *testing.T) {
+		srv, calls := newServer(t, "3600", 1)
+
+		_, err := send(context.Background(), srv)
+		assert.Equal(t, error(HTTPRateLimitedError{RetryAfter: time.Hour}), err)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("too_many", func(t *testing.T) {
+		srv, calls := newServer(t, "0", 10)
+
+		_, err := send(context.Background(), srv)
+		assert.IsError(t, err, HTTPRateLimitedError{})
+		assert.Equal(t, maxRateLimitRetries+1, *calls)
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	h := http.Header{}
+	assert.Equal(t, maxRateLimitWait, parseRetryAfter(h, "Retry-After"))
+
+	h.Set("Retry-After", "2")
+	assert.Equal(t, 2*time.Second, parseRetryAfter(h, "Retry-After"))
+
+	h.Set("X-RateLimit-Reset-After", "1.5")
+	assert.Equal(t, 1500*time.Millisecond, parseRetryAfter(h, "X-RateLimit-Reset-After", "Retry-After"))
+}
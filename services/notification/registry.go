@@ -0,0 +1,162 @@
+package notification
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"e2clicker.app/internal/validating"
+	"go.uber.org/fx"
+)
+
+// Names of the notification methods that come with e2clicker. A method's name
+// is also the key of its configs in the notification preferences API.
+const (
+	GotifyMethod   = "gotify"
+	PushoverMethod = "pushover"
+	WebPushMethod  = "webPush"
+	EmailMethod    = "email"
+	DiscordMethod  = "discord"
+	SlackMethod    = "slack"
+	MQTTMethod     = "mqtt"
+)
+
+// Notifier is a notification method that users can configure, such as email
+// or a Discord webhook. Notifiers are registered into the "notifiers" fx group,
+// usually with [ProvideNotifier], and are looked up by [NotificationService]
+// using their method name.
+type Notifier interface {
+	// Method returns the name of the notification method, e.g. "email".
+	Method() string
+	// ConfigSchema returns the JSON Schema of the method's configuration.
+	// Clients use it to render the settings form of the method.
+	ConfigSchema() json.RawMessage
+	// ValidateConfig checks the given configuration and returns it in its
+	// normalized form, with unknown fields dropped and defaults filled in.
+	ValidateConfig(ctx context.Context, config json.RawMessage) (json.RawMessage, error)
+	// Send sends the notification using the given configuration.
+	Send(ctx context.Context, n Notification, config json.RawMessage) error
+}
+
+// ErrNotificationSkipped is returned by [Notifier.Send] if a config is not
+// meant to receive notifications yet, such as an email address that has not
+// been confirmed. It does not count as a failure.
+var ErrNotificationSkipped = errors.New("notification skipped")
+
+// configSchemas contains the JSON Schema of each notification method, named
+// after the method.
+//
+//go:embed schemas/*.json
+var configSchemas embed.FS
+
+// notifyFunc is the Notify method of a typed notification service.
+type notifyFunc[ConfigT any] interface {
+	Notify(context.Context, Notification, ConfigT) error
+}
+
+// configDefaulter is implemented by configurations that have defaults which
+// are filled in before validation.
+type configDefaulter interface {
+	SetDefaults()
+}
+
+// pendingConfig is implemented by configurations that can be held pending
+// until the user confirms them, such as email addresses. Notifications are not
+// sent to pending configurations; see [ErrNotificationSkipped].
+type pendingConfig interface {
+	IsPending() bool
+}
+
+// typedNotifier adapts a notification service that takes a concrete
+// configuration type into a [Notifier].
+type typedNotifier[ConfigT any] struct {
+	method string
+	schema json.RawMessage
+	notify func(context.Context, Notification, ConfigT) error
+}
+
+// NewNotifier creates a [Notifier] for the given method out of a service that
+// sends notifications using configurations of type ConfigT. The method must
+// have a JSON Schema in the schemas directory.
+//
+// Configurations are decoded from JSON into ConfigT. If *ConfigT implements
+// [validating.Validator] or [validating.ContextValidator], it is validated
+// before it's used.
+func NewNotifier[ConfigT any](method string, service notifyFunc[ConfigT]) (Notifier, error) {
+	schema, err := configSchemas.ReadFile("schemas/" + method + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("missing config schema for %s: %w", method, err)
+	}
+	return typedNotifier[ConfigT]{
+		method: method,
+		schema: schema,
+		notify: service.Notify,
+	}, nil
+}
+
+// ProvideNotifier registers the service S as the [Notifier] of the given
+// method. The service is optional: if it's not provided or is nil because it
+// has not been configured, the method is not registered.
+func ProvideNotifier[ConfigT any, S notifyFunc[ConfigT]](method string) fx.Option {
+	return fx.Provide(fx.Annotate(
+		func(s *S) ([]Notifier, error) {
+			if s == nil {
+				return nil, nil
+			}
+			n, err := NewNotifier[ConfigT](method, *s)
+			if err != nil {
+				return nil, err
+			}
+			return []Notifier{n}, nil
+		},
+		fx.ParamTags(`optional:"true"`),
+		fx.ResultTags(`group:"notifiers,flatten"`),
+	))
+}
+
+func (n typedNotifier[ConfigT]) Method() string {
+	return n.method
+}
+
+func (n typedNotifier[ConfigT]) ConfigSchema() json.RawMessage {
+	return n.schema
+}
+
+func (n typedNotifier[ConfigT]) ValidateConfig(ctx context.Context, config json.RawMessage) (json.RawMessage, error) {
+	c, err := n.parse(ctx, config, true)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal %s config: %w", n.method, err)
+	}
+	return b, nil
+}
+
+func (n typedNotifier[ConfigT]) Send(ctx context.Context, notification Notification, config json.RawMessage) error {
+	c, err := n.parse(ctx, config, false)
+	if err != nil {
+		return err
+	}
+	if p, ok := any(c).(pendingConfig); ok && p.IsPending() {
+		return ErrNotificationSkipped
+	}
+	return n.notify(ctx, notification, c)
+}
+
+func (n typedNotifier[ConfigT]) parse(ctx context.Context, config json.RawMessage, defaults bool) (ConfigT, error) {
+	var c ConfigT
+	if err := json.Unmarshal(config, &c); err != nil {
+		return c, ConfigError{n.method, err}
+	}
+	if d, ok := any(&c).(configDefaulter); ok && defaults {
+		d.SetDefaults()
+	}
+	if err := validating.ShouldValidate(ctx, &c); err != nil {
+		return c, ConfigError{n.method, err}
+	}
+	return c, nil
+}
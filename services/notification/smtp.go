@@ -0,0 +1,199 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gopkg.in/mail.v2"
+)
+
+// errSMTPSenderClosed is returned when an email is sent after the SMTP sender
+// was stopped.
+var errSMTPSenderClosed = errors.New("SMTP sender is closed")
+
+// smtpDialer dials a new SMTP connection. It is implemented by [mail.Dialer].
+type smtpDialer interface {
+	Dial() (mail.SendCloser, error)
+}
+
+// smtpSenderConfig is the configuration for [smtpSender].
+type smtpSenderConfig struct {
+	// MaxConnections is the number of connections that may be open at once.
+	// Each connection has its own worker.
+	MaxConnections int
+	// IdleTimeout is how long a connection may be unused before it's closed.
+	IdleTimeout time.Duration
+	// QueueSize is how many messages may wait to be sent before
+	// [smtpSender.send] blocks.
+	QueueSize int
+}
+
+// smtpSender sends emails over long-lived SMTP connections. Messages are
+// queued and picked up by a fixed number of workers, each of which keeps its
+// connection open until it has been idle for a while.
+type smtpSender struct {
+	dialer smtpDialer
+	config smtpSenderConfig
+	logger *slog.Logger
+
+	queue  chan smtpJob
+	stop   context.CancelFunc
+	stopCh <-chan struct{}
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+type smtpJob struct {
+	ctx  context.Context
+	msg  *mail.Message
+	done chan error
+}
+
+func newSMTPSender(dialer smtpDialer, config smtpSenderConfig, logger *slog.Logger) *smtpSender {
+	ctx, stop := context.WithCancel(context.Background())
+	return &smtpSender{
+		dialer: dialer,
+		config: config,
+		logger: logger,
+		queue:  make(chan smtpJob, config.QueueSize),
+		stop:   stop,
+		stopCh: ctx.Done(),
+	}
+}
+
+// start starts the workers.
+func (s *smtpSender) start() {
+	for range s.config.MaxConnections {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.work()
+		}()
+	}
+}
+
+// close stops the workers and waits for them to close their connections.
+// Messages that are still queued fail with [errSMTPSenderClosed], while
+// messages that are being sent are allowed to finish.
+func (s *smtpSender) close() {
+	// Stopping first also unblocks senders waiting for room in the queue, so
+	// that the lock can be taken.
+	s.stop()
+
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	for {
+		select {
+		case job := <-s.queue:
+			job.done <- errSMTPSenderClosed
+		default:
+			return
+		}
+	}
+}
+
+// send queues msg and waits for it to be sent. If ctx is canceled before the
+// message is sent, ctx.Err() is returned and the message is dropped, unless a
+// worker is already in the middle of sending it.
+func (s *smtpSender) send(ctx context.Context, msg *mail.Message) error {
+	job := smtpJob{
+		ctx:  ctx,
+		msg:  msg,
+		done: make(chan error, 1),
+	}
+
+	if err := s.enqueue(ctx, job); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-job.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *smtpSender) enqueue(ctx context.Context, job smtpJob) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return errSMTPSenderClosed
+	}
+
+	select {
+	case s.queue <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.stopCh:
+		return errSMTPSenderClosed
+	}
+}
+
+func (s *smtpSender) work() {
+	var conn mail.SendCloser
+	closeConn := func() {
+		if conn != nil {
+			if err := conn.Close(); err != nil {
+				s.logger.Debug(
+					"error closing idle SMTP connection",
+					"err", err)
+			}
+			conn = nil
+		}
+	}
+	defer closeConn()
+
+	idle := time.NewTimer(s.config.IdleTimeout)
+	idle.Stop()
+	defer idle.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+
+		case <-idle.C:
+			closeConn()
+
+		case job := <-s.queue:
+			idle.Stop()
+
+			if err := job.ctx.Err(); err != nil {
+				job.done <- err
+				continue
+			}
+
+			if conn == nil {
+				c, err := s.dialer.Dial()
+				if err != nil {
+					job.done <- err
+					continue
+				}
+				conn = c
+			}
+
+			if err := mail.Send(conn, job.msg); err != nil {
+				// The connection may be left in the middle of a transaction or
+				// be broken altogether, so don't reuse it.
+				closeConn()
+				job.done <- err
+				continue
+			}
+
+			job.done <- nil
+			idle.Reset(s.config.IdleTimeout)
+		}
+	}
+}
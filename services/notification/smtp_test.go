@@ -0,0 +1,229 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/neilotoole/slogt"
+	"gopkg.in/mail.v2"
+)
+
+// fakeSMTPServer is a minimal SMTP server that accepts every message.
+type fakeSMTPServer struct {
+	addr string
+
+	mu       sync.Mutex
+	conns    int
+	messages []string
+	// dataDelay delays the response to the end of DATA.
+	dataDelay time.Duration
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	s := &fakeSMTPServer{addr: l.Addr().String()}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns++
+			s.mu.Unlock()
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(format string, args ...any) {
+		fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+
+	reply("220 localhost fake SMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		cmd, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch strings.ToUpper(cmd) {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL", "RCPT", "RSET", "NOOP":
+			reply("250 OK")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+
+			s.mu.Lock()
+			s.messages = append(s.messages, data.String())
+			delay := s.dataDelay
+			s.mu.Unlock()
+
+			time.Sleep(delay)
+			reply("250 OK")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) stats() (conns, messages int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conns, len(s.messages)
+}
+
+func (s *fakeSMTPServer) dialer(t *testing.T) *mail.Dialer {
+	host, portStr, err := net.SplitHostPort(s.addr)
+	assert.NoError(t, err)
+
+	port, err := strconv.Atoi(portStr)
+	assert.NoError(t, err)
+
+	d := mail.NewDialer(host, port, "", "")
+	d.StartTLSPolicy = mail.NoStartTLS
+	return d
+}
+
+func newTestMessage(i int) *mail.Message {
+	msg := mail.NewMessage()
+	msg.SetHeader("From", "e2clicker@example.com")
+	msg.SetHeader("To", "cat@example.com")
+	msg.SetHeader("Subject", "Message "+strconv.Itoa(i))
+	msg.SetBody("text/plain", "Hello!")
+	return msg
+}
+
+func TestSMTPSender(t *testing.T) {
+	t.Run("reuses connection", func(t *testing.T) {
+		server := newFakeSMTPServer(t)
+		sender := newSMTPSender(server.dialer(t), smtpSenderConfig{
+			MaxConnections: 1,
+			IdleTimeout:    time.Minute,
+			QueueSize:      4,
+		}, slogt.New(t))
+		sender.start()
+		defer sender.close()
+
+		for i := range 5 {
+			assert.NoError(t, sender.send(context.Background(), newTestMessage(i)))
+		}
+
+		conns, messages := server.stats()
+		assert.Equal(t, 1, conns)
+		assert.Equal(t, 5, messages)
+	})
+
+	t.Run("closes idle connection", func(t *testing.T) {
+		server := newFakeSMTPServer(t)
+		sender := newSMTPSender(server.dialer(t), smtpSenderConfig{
+			MaxConnections: 1,
+			IdleTimeout:    50 * time.Millisecond,
+			QueueSize:      4,
+		}, slogt.New(t))
+		sender.start()
+		defer sender.close()
+
+		assert.NoError(t, sender.send(context.Background(), newTestMessage(0)))
+		time.Sleep(200 * time.Millisecond)
+		assert.NoError(t, sender.send(context.Background(), newTestMessage(1)))
+
+		conns, messages := server.stats()
+		assert.Equal(t, 2, conns)
+		assert.Equal(t, 2, messages)
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		server := newFakeSMTPServer(t)
+		server.dataDelay = 500 * time.Millisecond
+
+		sender := newSMTPSender(server.dialer(t), smtpSenderConfig{
+			MaxConnections: 1,
+			IdleTimeout:    time.Minute,
+			QueueSize:      1,
+		}, slogt.New(t))
+		sender.start()
+		defer sender.close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := sender.send(ctx, newTestMessage(0))
+		assert.IsError(t, err, context.DeadlineExceeded)
+		assert.True(t, time.Since(start) < 400*time.Millisecond, "send should not wait for the server")
+	})
+
+	t.Run("bounded queue", func(t *testing.T) {
+		server := newFakeSMTPServer(t)
+		server.dataDelay = 300 * time.Millisecond
+
+		sender := newSMTPSender(server.dialer(t), smtpSenderConfig{
+			MaxConnections: 1,
+			IdleTimeout:    time.Minute,
+			QueueSize:      1,
+		}, slogt.New(t))
+		sender.start()
+		defer sender.close()
+
+		// The first message is picked up by the worker and the second one
+		// fills the queue, so the third one has to wait for room.
+		go sender.send(context.Background(), newTestMessage(0))
+		time.Sleep(50 * time.Millisecond)
+		go sender.send(context.Background(), newTestMessage(1))
+		time.Sleep(50 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := sender.enqueue(ctx, smtpJob{ctx: ctx, msg: newTestMessage(2), done: make(chan error, 1)})
+		assert.IsError(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		server := newFakeSMTPServer(t)
+		sender := newSMTPSender(server.dialer(t), smtpSenderConfig{
+			MaxConnections: 2,
+			IdleTimeout:    time.Minute,
+			QueueSize:      4,
+		}, slogt.New(t))
+		sender.start()
+		sender.close()
+
+		err := sender.send(context.Background(), newTestMessage(0))
+		assert.IsError(t, err, errSMTPSenderClosed)
+	})
+}
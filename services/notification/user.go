@@ -2,14 +2,23 @@ package notification
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 	"log/slog"
+	"slices"
+	"strings"
+	"time"
 
+	"e2clicker.app/internal/publicerrors"
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
 	"e2clicker.app/services/notification/openapi"
 	"e2clicker.app/services/user"
 	"go.uber.org/fx"
+	"libdb.so/ctxt"
 )
 
 // UserPreferences is the preferences of a user.
@@ -17,15 +26,75 @@ import (
 type UserPreferences struct {
 	NotificationConfigs NotificationConfigs         `json:"notificationConfigs"`
 	CustomNotifications openapi.CustomNotifications `json:"customNotifications,omitempty"`
+	// Timezone is the IANA time zone that times in custom notifications are
+	// shown in. An empty string means UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// Routes maps notification types to the channels that they're sent to.
+	// Types without a route are sent to every channel.
+	Routes openapi.NotificationRoutes `json:"routes,omitempty"`
+	// DigestFrequency is how often the user gets a digest of their doses.
+	// Digests are off if it is empty.
+	DigestFrequency DigestFrequency `json:"digestFrequency,omitempty"`
+	// LastDigestAt is when the user was last sent a digest. It is managed by
+	// the server and kept when the preferences are set.
+	LastDigestAt *time.Time `json:"lastDigestAt,omitempty"`
+}
+
+// Location returns the time zone of the user. It falls back to UTC if the
+// time zone is not set or unknown.
+func (p UserPreferences) Location() *time.Location {
+	if p.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Validate checks that the time zone, digest frequency, custom notifications
+// and routes are valid. The methods used by routes are checked when the
+// preferences are set, since they depend on the notifiers that are available.
+func (p UserPreferences) Validate() error {
+	if err := user.ValidateTimezone(p.Timezone); err != nil {
+		return err
+	}
+	if !validDigestFrequency(p.DigestFrequency) {
+		return ErrUnknownDigestFrequency
+	}
+	for t := range p.Routes {
+		if !isKnownNotificationType(openapi.NotificationType(t)) {
+			return publicerrors.Errorf("invalid route for %q: %w", t, ErrUnknownNotificationType)
+		}
+	}
+	return ValidateCustomNotifications(p.CustomNotifications)
+}
+
+// ConfigsFor returns the notification configs that notifications of the given
+// type are sent to, following the user's routes.
+func (p UserPreferences) ConfigsFor(t openapi.NotificationType) NotificationConfigs {
+	if route, ok := p.Routes[string(t)]; ok {
+		return p.NotificationConfigs.Routed(route)
+	}
+	return p.NotificationConfigs
 }
 
 type UserNotificationStorage interface {
 	// UserPreferences returns the preferences of a user.
-	UserPreferences(ctx context.Context, userSecret user.Secret) (UserPreferences, error)
+	UserPreferences(ctx context.Context, userID user.ID) (UserPreferences, error)
 	// SetUserPreferencesTx sets the preferences of a user inside a transaction.
 	// The function set is called with the current preferences and should modify
-	// the given preferences, all within the same transaction.
-	SetUserPreferencesTx(ctx context.Context, userSecret user.Secret, set func(*UserPreferences) error) error
+	// the given preferences, all within the same transaction. Concurrent calls
+	// for the same user wait for each other, so no update is lost.
+	SetUserPreferencesTx(ctx context.Context, userID user.ID, set func(*UserPreferences) error) error
+	// UsersWithNotificationMethod returns the IDs of all users that have
+	// at least one configuration for the given notification method, e.g.
+	// [MQTTMethod].
+	UsersWithNotificationMethod(ctx context.Context, method string) iter.Seq2[user.ID, error]
+	// UsersWithDigest returns the IDs of all users that have a digest
+	// frequency set.
+	UsersWithDigest(ctx context.Context) iter.Seq2[user.ID, error]
 }
 
 // UserNotificationService is a service that sends notifications to users.
@@ -33,7 +102,10 @@ type UserNotificationService struct {
 	userNotifications UserNotificationStorage
 	users             *user.UserService
 	notification      *NotificationService
+	email             *EmailService
+	webPush           *WebPushService
 	logger            *slog.Logger
+	failureThreshold  int
 }
 
 // UserNotificationServiceConfig is the configuration for the user notification
@@ -45,58 +117,625 @@ type UserNotificationServiceConfig struct {
 	*NotificationService
 	*user.UserService
 	*slog.Logger
+	fx.Lifecycle
+
+	Config  e2clickermodule.Notification
+	Email   *EmailService   `optional:"true"`
+	WebPush *WebPushService `optional:"true"`
 }
 
 // NewUserNotificationService creates a new user notification service.
+//
+// Notification configs that fail to receive notifications
+// [e2clickermodule.Notification.FailureThreshold] times in a row are paused
+// until a test notification succeeds.
+//
+// When the server starts, credentials in users' notification configs that are
+// still in plain text or encrypted with an old key are re-encrypted with the
+// primary credential key in the background.
 func NewUserNotificationService(s UserNotificationServiceConfig) *UserNotificationService {
-	return &UserNotificationService{
+	us := &UserNotificationService{
 		userNotifications: s.UserNotificationStorage,
 		users:             s.UserService,
 		notification:      s.NotificationService,
+		email:             s.Email,
+		webPush:           s.WebPush,
 		logger:            s.Logger,
+		failureThreshold:  cmp.Or(s.Config.FailureThreshold, 10),
 	}
+
+	if us.notification.credentials == nil {
+		return us
+	}
+
+	fakectx, stop := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	s.Lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				defer close(done)
+				if err := us.resealCredentials(fakectx); err != nil {
+					us.logger.Error(
+						"cannot re-encrypt notification credentials",
+						"err", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stop()
+			<-done
+			return nil
+		},
+	})
+
+	return us
 }
 
-// NotifyUser sends a notification to a user.
-func (s *UserNotificationService) NotifyUser(ctx context.Context, secret user.Secret, t openapi.NotificationType) error {
-	prefs, err := s.userNotifications.UserPreferences(ctx, secret)
+// resealCredentials re-encrypts the credentials of every user that has some in
+// plain text or encrypted with an old key.
+func (s *UserNotificationService) resealCredentials(ctx context.Context) error {
+	var resealed int
+	var errs []error
+
+	for _, method := range s.notification.credentialMethods() {
+		for userID, err := range s.userNotifications.UsersWithNotificationMethod(ctx, method) {
+			if err != nil {
+				return err
+			}
+
+			prefs, err := s.userNotifications.UserPreferences(ctx, userID)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			_, changed, err := s.notification.resealConfigs(userID, prefs.NotificationConfigs)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+
+			err = s.userNotifications.SetUserPreferencesTx(ctx, userID, func(p *UserPreferences) error {
+				configs, _, err := s.notification.resealConfigs(userID, p.NotificationConfigs)
+				if err != nil {
+					return err
+				}
+				p.NotificationConfigs = configs
+				return nil
+			})
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			resealed++
+		}
+	}
+
+	if resealed > 0 {
+		s.logger.Info(
+			"re-encrypted notification credentials",
+			"users", resealed)
+	}
+
+	return errors.Join(errs...)
+}
+
+// NotifyUser sends a notification to a user. The variables are used to render
+// the message, which is the user's custom message for the notification type
+// if they have one. The username is filled in automatically, and times are
+// shown in the user's time zone.
+//
+// Paused configs are skipped, except for test notifications, which resume the
+// configs that they are delivered to. The health of every config that the
+// notification is sent to is recorded.
+func (s *UserNotificationService) NotifyUser(ctx context.Context, userID user.ID, t openapi.NotificationType, vars MessageVariables) error {
+	prefs, err := s.userNotifications.UserPreferences(ctx, userID)
 	if err != nil {
 		return err
 	}
 
-	if prefs.NotificationConfigs.IsEmpty() {
-		return nil
+	configs := prefs.ConfigsFor(t)
+	if t != openapi.TestMessage {
+		configs = configs.Unpaused()
+	}
+
+	results, err := s.sendToConfigs(ctx, userID, prefs, t, vars, configs)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(mirroredNotificationTypes, t) {
+		s.mirrorToDelegates(ctx, userID, prefs, t, vars)
+	}
+
+	return joinNotifyErrors(results)
+}
+
+// mirroredNotificationTypes are the types of notifications that are also sent
+// to the delegates of a user who asked for them, see [user.Delegation].
+var mirroredNotificationTypes = []openapi.NotificationType{
+	openapi.ReminderMessage,
+	openapi.DigestMessage,
+}
+
+// mirrorToDelegates sends the owner's notification to the owner's delegates
+// who asked for it. The notification is rendered for the owner, so it carries
+// the owner's name, but it goes to the delegates' own configs. Failures are
+// only logged, since they don't concern the owner.
+func (s *UserNotificationService) mirrorToDelegates(ctx context.Context, ownerID user.ID, prefs UserPreferences, t openapi.NotificationType, vars MessageVariables) {
+	delegates, err := s.users.MirroringDelegates(ctx, ownerID)
+	if err != nil {
+		s.logger.ErrorContext(ctx,
+			"cannot get delegates to mirror notification to",
+			"notification", t,
+			"err", err)
+		return
+	}
+	if len(delegates) == 0 {
+		return
+	}
+
+	n, err := s.renderNotification(ctx, ownerID, prefs, t, vars)
+	if err != nil {
+		s.logger.ErrorContext(ctx,
+			"cannot render notification to mirror to delegates",
+			"notification", t,
+			"err", err)
+		return
+	}
+
+	for _, delegateID := range delegates {
+		delegatePrefs, err := s.userNotifications.UserPreferences(ctx, delegateID)
+		if err != nil {
+			s.logger.ErrorContext(ctx,
+				"cannot get delegate preferences to mirror notification",
+				"notification", t,
+				"delegate_id", delegateID,
+				"err", err)
+			continue
+		}
+
+		configs := delegatePrefs.ConfigsFor(t).Unpaused()
+		if configs.IsEmpty() {
+			continue
+		}
+
+		if err := joinNotifyErrors(s.deliver(ctx, delegateID, n, configs)); err != nil {
+			s.logger.WarnContext(ctx,
+				"cannot mirror notification to delegate",
+				"notification", t,
+				"delegate_id", delegateID,
+				"err", err)
+		}
+	}
+}
+
+// TestNotificationTarget selects the notification configs that a test
+// notification is sent to.
+type TestNotificationTarget = openapi.TestNotificationTarget
+
+// SendTestNotification sends a test notification to the configs selected by
+// the target and returns the result of each config. Failing to deliver the
+// notification to a config is not an error; it is reported in its result.
+//
+// If the target has an unsaved config, it is validated and the notification
+// is sent to it alone. Its health is not recorded, and its result only says
+// whether it was delivered, since the config may point at any server.
+func (s *UserNotificationService) SendTestNotification(ctx context.Context, userID user.ID, target TestNotificationTarget, vars MessageVariables) ([]openapi.TestNotificationResult, error) {
+	prefs, err := s.userNotifications.UserPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var configs NotificationConfigs
+	var unsaved bool
+
+	switch {
+	case target.Config != nil:
+		if target.Method == "" {
+			return nil, ErrTestMethodRequired
+		}
+		if target.Method == EmailMethod {
+			return nil, ErrUnsavedEmailTest
+		}
+
+		config := GroupedNotificationConfigs(map[string][]json.RawMessage{
+			target.Method: {*target.Config},
+		})
+		// Without existing configs, the config is given a new ID, so it
+		// doesn't take over the health of the saved config that it may be an
+		// edit of. Its credentials may still be the saved config's, which are
+		// only opened with the ID that it was sent with.
+		configs, err = s.notification.ValidateConfigs(ctx, userID, config, nil)
+		if err != nil {
+			return nil, err
+		}
+		unsaved = true
+
+	default:
+		configs = slices.DeleteFunc(slices.Clone(prefs.NotificationConfigs), func(c NotificationConfig) bool {
+			return (target.Method != "" && c.Method != target.Method) ||
+				(target.ID != "" && c.ID != target.ID)
+		})
+		if configs.IsEmpty() && (target.Method != "" || target.ID != "") {
+			return nil, ErrNotificationConfigNotFound
+		}
+	}
+
+	results, err := s.sendToConfigs(ctx, userID, prefs, openapi.TestMessage, vars, configs)
+	if err != nil {
+		return nil, err
+	}
+
+	testResults := make([]openapi.TestNotificationResult, len(results))
+	for i, r := range results {
+		if unsaved {
+			testResults[i] = unsavedTestNotificationResult(r)
+		} else {
+			testResults[i] = testNotificationResult(ctx, r)
+		}
+	}
+	return testResults, nil
+}
+
+func testNotificationResult(ctx context.Context, r NotifyResult) openapi.TestNotificationResult {
+	result := openapi.TestNotificationResult{
+		Method: r.Config.Method,
+		ID:     r.Config.ID,
+		Status: openapi.TestNotificationSent,
+	}
+
+	switch {
+	case r.Err == nil:
+	case errors.Is(r.Err, ErrNotificationSkipped):
+		result.Status = openapi.TestNotificationSkipped
+	default:
+		result.Status = openapi.TestNotificationFailed
+		result.Error = publicerrors.String(ctx, r.Err, "cannot deliver the notification")
+
+		// Only the status is returned, not the response body.
+		var statusErr HTTPUnknownStatusError
+		if errors.As(r.Err, &statusErr) {
+			result.HTTPStatus = statusErr.StatusCode
+		}
+	}
+
+	return result
+}
+
+// unsavedTestNotificationResult is like [testNotificationResult], but leaves
+// out the ID and the reason of a failure. Otherwise, the status codes and
+// errors of any server could be probed through unsaved configs.
+func unsavedTestNotificationResult(r NotifyResult) openapi.TestNotificationResult {
+	result := openapi.TestNotificationResult{
+		Method: r.Config.Method,
+		Status: openapi.TestNotificationSent,
 	}
+	switch {
+	case r.Err == nil:
+	case errors.Is(r.Err, ErrNotificationSkipped):
+		result.Status = openapi.TestNotificationSkipped
+	default:
+		result.Status = openapi.TestNotificationFailed
+		result.Error = "cannot deliver the notification"
+	}
+	return result
+}
 
-	u, err := s.users.User(ctx, secret)
+// sendToConfigs sends a notification of the given type to the configs and
+// records the results in the user's notification health. If any config is
+// paused as a result, the user is told about it.
+func (s *UserNotificationService) sendToConfigs(ctx context.Context, userID user.ID, prefs UserPreferences, t openapi.NotificationType, vars MessageVariables, configs NotificationConfigs) ([]NotifyResult, error) {
+	if configs.IsEmpty() {
+		return nil, nil
+	}
+
+	n, err := s.renderNotification(ctx, userID, prefs, t, vars)
 	if err != nil {
-		return fmt.Errorf("failed to get user for notification: %w", err)
+		return nil, err
+	}
+
+	return s.deliver(ctx, userID, n, configs), nil
+}
+
+// renderNotification renders a notification of the given type for the user,
+// using their custom message if they have one.
+func (s *UserNotificationService) renderNotification(ctx context.Context, userID user.ID, prefs UserPreferences, t openapi.NotificationType, vars MessageVariables) (Notification, error) {
+	u, err := s.users.User(ctx, userID)
+	if err != nil {
+		return Notification{}, fmt.Errorf("failed to get user for notification: %w", err)
 	}
 
 	n := Notification{
 		Type:     t,
 		Username: u.Name,
 	}
+
+	vars.Username = u.Name
+	vars = vars.In(prefs.Location())
+	if vars.Digest.Frequency != "" {
+		n.Digest = &vars.Digest
+	}
+
 	if custom, ok := prefs.CustomNotifications[string(t)]; ok {
-		n.Message = custom
-	} else {
-		n.Message, err = LoadNotification(ctx, t)
+		n.Message, err = renderMessage(custom, vars)
 		if err != nil {
-			return err
+			s.logger.WarnContext(ctx,
+				"cannot render custom notification, using the default message",
+				"notification", t,
+				"err", err)
+		}
+	}
+
+	if n.Message == (openapi.NotificationMessage{}) {
+		msg, err := LoadNotification(ctx, t, u.Locale)
+		if err != nil {
+			return Notification{}, err
 		}
+		n.Message, err = renderMessage(msg, vars)
+		if err != nil {
+			return Notification{}, fmt.Errorf("cannot render %s message: %w", t, err)
+		}
+	}
+
+	return n, nil
+}
+
+// deliver sends the notification to the configs of the recipient and records
+// the results in the recipient's notification health. If any config is paused
+// as a result, the recipient is told about it.
+func (s *UserNotificationService) deliver(ctx context.Context, recipientID user.ID, n Notification, configs NotificationConfigs) []NotifyResult {
+	results := s.notification.Send(ctx, recipientID, n, configs)
+
+	var paused []NotificationConfig
+	err := s.userNotifications.SetUserPreferencesTx(ctx, recipientID, func(p *UserPreferences) error {
+		paused = p.NotificationConfigs.recordResults(results, s.failureThreshold, time.Now())
+		return nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx,
+			"cannot record notification health",
+			"notification", n.Type,
+			"err", err)
+	}
+
+	if len(paused) > 0 {
+		s.notifyPaused(ctx, recipientID, paused)
+	}
+
+	return results
+}
+
+// notifyPaused tells the user that some of their configs were paused. The
+// message is only sent to configs that are known to work, so that it doesn't
+// get lost in the broken ones.
+func (s *UserNotificationService) notifyPaused(ctx context.Context, userID user.ID, paused []NotificationConfig) {
+	for _, config := range paused {
+		s.logger.InfoContext(ctx,
+			"paused failing notification config",
+			"method", config.Method,
+			"config_id", config.ID)
+	}
+
+	prefs, err := s.userNotifications.UserPreferences(ctx, userID)
+	if err != nil {
+		s.logger.ErrorContext(ctx,
+			"cannot get preferences to notify about paused configs",
+			"err", err)
+		return
+	}
+
+	configs := prefs.ConfigsFor(openapi.SubscriptionPausedMessage).Healthy()
+	if configs.IsEmpty() {
+		return
+	}
+
+	results, err := s.sendToConfigs(ctx, userID, prefs, openapi.SubscriptionPausedMessage, MessageVariables{}, configs)
+	if err == nil {
+		err = joinNotifyErrors(results)
+	}
+	if err != nil {
+		s.logger.WarnContext(ctx,
+			"cannot notify user about paused configs",
+			"err", err)
 	}
+}
 
-	return s.notification.Notify(ctx, n, prefs.NotificationConfigs)
+// AccountPurging implements [user.AccountPurgeListener]. It confirms to the
+// user that their account was deleted, which is the last notification that
+// they get.
+func (s *UserNotificationService) AccountPurging(ctx context.Context, userID user.ID) error {
+	return s.NotifyUser(ctx, userID, openapi.AccountDeletedMessage, MessageVariables{})
+}
+
+// recipient is the user that a notification is being sent to.
+type recipient struct {
+	userID user.ID
+}
+
+// withRecipient returns a context that carries the user that a notification
+// is being sent to, for notifiers that need to link back to the user.
+func withRecipient(ctx context.Context, userID user.ID) context.Context {
+	return ctxt.With(ctx, recipient{userID})
+}
+
+// recipientFromContext returns the user that a notification is being sent to,
+// if it's known.
+func recipientFromContext(ctx context.Context) (user.ID, bool) {
+	r, ok := ctxt.From[recipient](ctx)
+	return r.userID, ok
 }
 
 // UserPreferences returns the preferences of a user.
-func (s *UserNotificationService) UserPreferences(ctx context.Context, secret user.Secret) (UserPreferences, error) {
-	return s.userNotifications.UserPreferences(ctx, secret)
+func (s *UserNotificationService) UserPreferences(ctx context.Context, userID user.ID) (UserPreferences, error) {
+	return s.userNotifications.UserPreferences(ctx, userID)
+}
+
+// MQTTConfigs returns the MQTT configs of a user with their topic IDs
+// decrypted.
+func (s *UserNotificationService) MQTTConfigs(ctx context.Context, userID user.ID) ([]MQTTNotificationConfig, error) {
+	p, err := s.userNotifications.UserPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return openConfigsOf[MQTTNotificationConfig](s.notification, userID, p.NotificationConfigs, MQTTMethod)
+}
+
+// UsersWithNotificationMethod returns the IDs of all users that have the
+// given notification method configured.
+func (s *UserNotificationService) UsersWithNotificationMethod(ctx context.Context, method string) iter.Seq2[user.ID, error] {
+	return s.userNotifications.UsersWithNotificationMethod(ctx, method)
+}
+
+// UsersWithDigest returns the IDs of all users that have digests enabled.
+func (s *UserNotificationService) UsersWithDigest(ctx context.Context) iter.Seq2[user.ID, error] {
+	return s.userNotifications.UsersWithDigest(ctx)
+}
+
+// MarkDigestSent records that the user was sent a digest at the given time,
+// so that [UserPreferences.DigestPeriod] doesn't consider it due again.
+func (s *UserNotificationService) MarkDigestSent(ctx context.Context, userID user.ID, at time.Time) error {
+	return s.userNotifications.SetUserPreferencesTx(ctx, userID, func(p *UserPreferences) error {
+		p.LastDigestAt = &at
+		return nil
+	})
+}
+
+// ExportUserPreferences returns the preferences of a user as they are
+// exported for the user to take to another server, see
+// [NotificationService.ExportConfigs].
+func (s *UserNotificationService) ExportUserPreferences(ctx context.Context, userID user.ID, includeCredentials bool) (UserPreferences, error) {
+	prefs, err := s.userNotifications.UserPreferences(ctx, userID)
+	if err != nil {
+		return UserPreferences{}, err
+	}
+
+	prefs.NotificationConfigs, err = s.notification.ExportConfigs(userID, prefs.NotificationConfigs, includeCredentials)
+	if err != nil {
+		return UserPreferences{}, err
+	}
+
+	return prefs, nil
+}
+
+// ImportedPreferences are preferences exported from another server by
+// [UserNotificationService.ExportUserPreferences] that are ready to be
+// stored, see [UserNotificationService.PrepareImport].
+type ImportedPreferences struct {
+	// Preferences are the preferences to store.
+	Preferences UserPreferences
+	// Skipped is the number of notification configs that couldn't be
+	// imported.
+	Skipped int
+
+	// pending are the email addresses that have to be confirmed once the
+	// preferences are stored.
+	pending []EmailNotificationConfig
+}
+
+// PrepareImport validates preferences exported from another server by
+// [UserNotificationService.ExportUserPreferences] so that they can replace the
+// user's preferences. Configs that can't work on this server are skipped:
+// those of methods that aren't available, those whose credentials were
+// redacted, Web Push subscriptions, which are bound to the other server's
+// keys, and email addresses if they can't be confirmed. MQTT configs get new
+// topic IDs.
+//
+// Nothing is stored; the caller stores the prepared preferences, e.g. along
+// with the rest of a takeout, and then calls
+// [UserNotificationService.FinishImport]. Like
+// [UserNotificationService.SetUserPreferences], email addresses have to be
+// confirmed again.
+func (s *UserNotificationService) PrepareImport(ctx context.Context, userID user.ID, prefs UserPreferences) (ImportedPreferences, error) {
+	canConfirm := s.email != nil && s.email.CanConfirm()
+
+	// MQTT topic IDs are set by the server, so imported configs are given new
+	// ones. This also keeps configs whose topic IDs were redacted.
+	configs := slices.Clone(prefs.NotificationConfigs)
+	err := UpdateConfigsOf(&configs, MQTTMethod, func(c *MQTTNotificationConfig) bool {
+		c.TopicID = newMQTTTopicID()
+		return true
+	})
+	if err != nil {
+		return ImportedPreferences{}, err
+	}
+
+	configs = slices.DeleteFunc(configs, func(c NotificationConfig) bool {
+		return c.Method == WebPushMethod ||
+			(c.Method == EmailMethod && !canConfirm) ||
+			!s.notification.importable(c)
+	})
+	skipped := len(prefs.NotificationConfigs) - len(configs)
+
+	for i := range configs {
+		configs[i].ID = ""
+		configs[i].Health = nil
+	}
+	prefs.NotificationConfigs = configs
+
+	if len(prefs.Routes) > 0 {
+		routes := make(openapi.NotificationRoutes, len(prefs.Routes))
+		for t, route := range prefs.Routes {
+			route.Methods = slices.DeleteFunc(slices.Clone(route.Methods), func(method string) bool {
+				_, ok := s.notification.Notifier(method)
+				return !ok
+			})
+			route.Devices = nil
+			routes[t] = route
+		}
+		prefs.Routes = routes
+	}
+
+	prefs.LastDigestAt = nil
+
+	if err := prefs.Validate(); err != nil {
+		return ImportedPreferences{}, err
+	}
+	if err := s.notification.validateRoutes(prefs.Routes); err != nil {
+		return ImportedPreferences{}, err
+	}
+
+	configs, err = s.notification.ValidateConfigs(ctx, userID, prefs.NotificationConfigs, nil)
+	if err != nil {
+		return ImportedPreferences{}, err
+	}
+
+	var pending []EmailNotificationConfig
+	err = UpdateConfigsOf(&configs, EmailMethod, func(c *EmailNotificationConfig) bool {
+		c.Pending = true
+		pending = append(pending, *c)
+		return true
+	})
+	if err != nil {
+		return ImportedPreferences{}, err
+	}
+	prefs.NotificationConfigs = configs
+
+	return ImportedPreferences{
+		Preferences: prefs,
+		Skipped:     skipped,
+		pending:     pending,
+	}, nil
+}
+
+// FinishImport sends the confirmation emails for the email addresses in
+// preferences from [UserNotificationService.PrepareImport] once they are
+// stored.
+func (s *UserNotificationService) FinishImport(ctx context.Context, userID user.ID, imported ImportedPreferences) error {
+	if len(imported.pending) == 0 {
+		return nil
+	}
+	return s.sendEmailConfirmations(ctx, userID, imported.pending)
 }
 
 // SetUserPreferences sets the preferences of a user.
-func (s *UserNotificationService) SetUserPreferences(ctx context.Context, secret user.Secret, preferences *UserPreferences) error {
-	return s.SetUserPreferencesSafe(ctx, secret, preferences, nil)
+func (s *UserNotificationService) SetUserPreferences(ctx context.Context, userID user.ID, preferences *UserPreferences) error {
+	return s.SetUserPreferencesSafe(ctx, userID, preferences, nil)
 }
 
 // SetUserPreferencesSafe sets the preferences of a user in a safer manner than
@@ -105,8 +744,28 @@ func (s *UserNotificationService) SetUserPreferences(ctx context.Context, secret
 //
 // Realistically, this doesn't happen unless the user is deliberately trying to
 // cause the issue.
-func (s *UserNotificationService) SetUserPreferencesSafe(ctx context.Context, secret user.Secret, newPreferences, oldPreferences *UserPreferences) error {
-	return s.userNotifications.SetUserPreferencesTx(ctx, secret, func(p *UserPreferences) error {
+//
+// Every notification config is validated by its notifier and saved in its
+// normalized form. Configs of methods that are no longer available are only
+// kept if the user already had them.
+//
+// Email addresses that the user didn't have before are held pending, and a
+// confirmation link is sent to each of them once the preferences are saved.
+// Addresses that the user already had keep their state.
+//
+// MQTT topic IDs are set by the server. Configs keep the topic ID that they
+// were sent with only if the user already has it.
+func (s *UserNotificationService) SetUserPreferencesSafe(ctx context.Context, userID user.ID, newPreferences, oldPreferences *UserPreferences) error {
+	if err := newPreferences.Validate(); err != nil {
+		return err
+	}
+	if err := s.notification.validateRoutes(newPreferences.Routes); err != nil {
+		return err
+	}
+
+	var added []EmailNotificationConfig
+
+	err := s.userNotifications.SetUserPreferencesTx(ctx, userID, func(p *UserPreferences) error {
 		if oldPreferences != nil {
 			b1, _ := json.Marshal(oldPreferences)
 			b2, _ := json.Marshal(p)
@@ -114,31 +773,182 @@ func (s *UserNotificationService) SetUserPreferencesSafe(ctx context.Context, se
 				return fmt.Errorf("preferences have changed since you last read them")
 			}
 		}
+
+		configs := slices.Clone(newPreferences.NotificationConfigs)
+		mqttConfigs, err := openConfigsOf[MQTTNotificationConfig](s.notification, userID, configs, MQTTMethod)
+		if err != nil {
+			return publicerrors.Errorf("invalid %s config: %w", MQTTMethod, err)
+		}
+		oldMQTTConfigs, err := openConfigsOf[MQTTNotificationConfig](s.notification, userID, p.NotificationConfigs, MQTTMethod)
+		if err != nil {
+			return err
+		}
+		assignMQTTTopicIDs(mqttConfigs, oldMQTTConfigs)
+		err = UpdateConfigsOf(&configs, MQTTMethod, func(c *MQTTNotificationConfig) bool {
+			*c, mqttConfigs = mqttConfigs[0], mqttConfigs[1:]
+			return true
+		})
+		if err != nil {
+			return err
+		}
+
+		configs, err = s.notification.ValidateConfigs(ctx, userID, configs, p.NotificationConfigs)
+		if err != nil {
+			return err
+		}
+
+		oldEmails, err := ConfigsOf[EmailNotificationConfig](p.NotificationConfigs, EmailMethod)
+		if err != nil {
+			return err
+		}
+
+		added = added[:0]
+		err = UpdateConfigsOf(&configs, EmailMethod, func(c *EmailNotificationConfig) bool {
+			ix := slices.IndexFunc(oldEmails, func(old EmailNotificationConfig) bool {
+				return strings.EqualFold(old.Address, c.Address)
+			})
+			if ix != -1 {
+				c.Pending = oldEmails[ix].Pending
+			} else {
+				c.Pending = true
+				added = append(added, *c)
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(added) > 0 {
+			if s.email == nil {
+				return ErrEmailNotAvailable
+			}
+			if !s.email.CanConfirm() {
+				return ErrEmailConfirmationNotAvailable
+			}
+		}
+
+		lastDigestAt := p.LastDigestAt
 		*p = *newPreferences
+		p.NotificationConfigs = configs
+		p.LastDigestAt = lastDigestAt
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	if len(added) > 0 {
+		if err := s.sendEmailConfirmations(ctx, userID, added); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *UserNotificationService) sendEmailConfirmations(ctx context.Context, userID user.ID, configs []EmailNotificationConfig) error {
+	u, err := s.users.User(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user for email confirmation: %w", err)
+	}
+
+	var errs []error
+	for _, c := range configs {
+		if err := s.email.SendConfirmation(ctx, userID, u.Name, c); err != nil {
+			s.logger.ErrorContext(ctx,
+				"failed to send email confirmation",
+				"err", err)
+			errs = append(errs, fmt.Errorf("cannot send confirmation email to %s: %w", c.Address, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ConfirmEmail confirms the email address that the given confirmation token
+// was made for, so that it starts receiving notifications. The address is
+// returned.
+func (s *UserNotificationService) ConfirmEmail(ctx context.Context, token string) (string, error) {
+	if s.email == nil {
+		return "", ErrInvalidEmailToken
+	}
+
+	userID, address, err := s.email.Confirm(token)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.userNotifications.SetUserPreferencesTx(ctx, userID, func(p *UserPreferences) error {
+		var found bool
+		err := UpdateConfigsOf(&p.NotificationConfigs, EmailMethod, func(c *EmailNotificationConfig) bool {
+			if strings.EqualFold(c.Address, address) {
+				c.Pending = false
+				found = true
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		if !found {
+			// The address was removed since the confirmation was sent.
+			return ErrInvalidEmailToken
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return address, nil
+}
+
+// UnsubscribeEmail removes the email address that the given unsubscribe token
+// was made for from its user's notification preferences. The address is
+// returned.
+func (s *UserNotificationService) UnsubscribeEmail(ctx context.Context, token string) (string, error) {
+	if s.email == nil {
+		return "", ErrInvalidEmailToken
+	}
+
+	userID, address, err := s.email.Unsubscribe(token)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.userNotifications.SetUserPreferencesTx(ctx, userID, func(p *UserPreferences) error {
+		return UpdateConfigsOf(&p.NotificationConfigs, EmailMethod,
+			func(c *EmailNotificationConfig) bool { return !strings.EqualFold(c.Address, address) },
+		)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return address, nil
 }
 
 // WebPushInfo returns the web push information of the server.
 func (s *UserNotificationService) WebPushInfo(ctx context.Context) (openapi.PushInfo, error) {
-	if s.notification.services.WebPush == nil {
+	if s.webPush == nil {
 		return openapi.PushInfo{}, ErrWebPushNotAvailable
 	}
 	return openapi.PushInfo{
-		ApplicationServerKey: s.notification.services.WebPush.VAPIDPublicKey(),
+		ApplicationServerKey: s.webPush.VAPIDPublicKey(),
+		KeyID:                s.webPush.VAPIDKeyID(),
 	}, nil
 }
 
 /*
 // SubscribeWebPush sets the web push subscription of a user.
 // The particular subscription is identified by the device ID.
-func (s *UserNotificationService) SubscribeWebPush(ctx context.Context, secret user.Secret, subscription openapi.PushSubscription) error {
+func (s *UserNotificationService) SubscribeWebPush(ctx context.Context, userID user.ID, subscription openapi.PushSubscription) error {
 	s.logger.Debug(
 		"Updating Web Push subscription",
 		"endpoint", subscription.Endpoint,
 		"expirationTime", subscription.ExpirationTime)
 
-	return s.userNotifications.SetUserPreferencesTx(ctx, secret, func(p *UserPreferences) error {
+	return s.userNotifications.SetUserPreferencesTx(ctx, userID, func(p *UserPreferences) error {
 		ix := slices.IndexFunc(p.NotificationConfigs.WebPush,
 			func(c WebPushNotificationConfig) bool {
 				return c.DeviceID == subscription.DeviceID
@@ -154,8 +964,8 @@ func (s *UserNotificationService) SubscribeWebPush(ctx context.Context, secret u
 }
 
 // UnsubscribeWebPush removes the web push subscription of a user.
-func (s *UserNotificationService) UnsubscribeWebPush(ctx context.Context, secret user.Secret, deviceID string) error {
-	return s.userNotifications.SetUserPreferencesTx(ctx, secret, func(p *UserPreferences) error {
+func (s *UserNotificationService) UnsubscribeWebPush(ctx context.Context, userID user.ID, deviceID string) error {
+	return s.userNotifications.SetUserPreferencesTx(ctx, userID, func(p *UserPreferences) error {
 		p.NotificationConfigs.WebPush = slices.DeleteFunc(p.NotificationConfigs.WebPush,
 			func(c WebPushNotificationConfig) bool { return c.DeviceID == deviceID },
 		)
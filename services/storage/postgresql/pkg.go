@@ -14,9 +14,14 @@ var Module = fx.Module("postgresql",
 		NewStorage,
 		(*Storage).userStorage,
 		(*Storage).userSessionStorage,
+		(*Storage).passkeyStorage,
+		(*Storage).personalTokenStorage,
+		(*Storage).delegationStorage,
 		(*Storage).notificationUserStorage,
 		(*Storage).dosageStorage,
 		(*Storage).doseHistoryStorage,
+		(*Storage).shareLinkStorage,
 		(*Storage).dosageReminderStorage,
+		(*Storage).takeoutStorage,
 	),
 )
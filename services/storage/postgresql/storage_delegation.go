@@ -0,0 +1,154 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"e2clicker.app/internal/sqlc/postgresqlc"
+	"e2clicker.app/services/user"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// uniqueViolation is the SQLSTATE of a unique constraint violation.
+const uniqueViolation = "23505"
+
+type delegationStorage Storage
+
+func (s *Storage) delegationStorage() user.DelegationStorage {
+	return (*delegationStorage)(s)
+}
+
+func (s *delegationStorage) CreateDelegation(ctx context.Context, ownerID user.ID, access user.DelegationAccess, inviteHash []byte, inviteExpiresAt time.Time) (user.Delegation, error) {
+	d, err := s.q.CreateDelegation(ctx, postgresqlc.CreateDelegationParams{
+		OwnerID:         ownerID,
+		Access:          string(access),
+		InviteHash:      inviteHash,
+		InviteExpiresAt: pgtype.Timestamptz{Time: inviteExpiresAt, Valid: true},
+	})
+	if err != nil {
+		return user.Delegation{}, err
+	}
+	return convertDelegation(d), nil
+}
+
+func (s *delegationStorage) AcceptDelegation(ctx context.Context, inviteHash []byte, delegateID user.ID, mirrorNotifications bool) (user.Delegation, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return user.Delegation{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := postgresqlc.New(tx)
+
+	r, err := q.AcceptDelegation(ctx, postgresqlc.AcceptDelegationParams{
+		DelegateID:          int64(delegateID),
+		MirrorNotifications: mirrorNotifications,
+		InviteHash:          inviteHash,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return user.Delegation{}, user.ErrUnknownDelegation
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return user.Delegation{}, user.ErrDelegationExists
+		}
+		return user.Delegation{}, err
+	}
+
+	d := convertDelegation(r)
+
+	owner, err := q.User(ctx, d.OwnerID)
+	if err != nil {
+		return user.Delegation{}, fmt.Errorf("get owner: %w", err)
+	}
+	d.OwnerName = owner.Name
+
+	delegate, err := q.User(ctx, d.DelegateID)
+	if err != nil {
+		return user.Delegation{}, fmt.Errorf("get delegate: %w", err)
+	}
+	d.DelegateName = delegate.Name
+
+	if err := tx.Commit(ctx); err != nil {
+		return user.Delegation{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return d, nil
+}
+
+func (s *delegationStorage) Delegations(ctx context.Context, userID user.ID) ([]user.Delegation, error) {
+	l, err := s.q.ListDelegations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return convertList(l, func(r postgresqlc.ListDelegationsRow) user.Delegation {
+		d := convertDelegation(postgresqlc.UserDelegation{
+			ID:                  r.ID,
+			OwnerID:             r.OwnerID,
+			DelegateID:          r.DelegateID,
+			Access:              r.Access,
+			InviteHash:          r.InviteHash,
+			InviteExpiresAt:     r.InviteExpiresAt,
+			MirrorNotifications: r.MirrorNotifications,
+			CreatedAt:           r.CreatedAt,
+			AcceptedAt:          r.AcceptedAt,
+		})
+		d.OwnerName = r.OwnerName
+		d.DelegateName = r.DelegateName.String
+		return d
+	}), nil
+}
+
+func (s *delegationStorage) Delegation(ctx context.Context, ownerID, delegateID user.ID) (user.Delegation, error) {
+	d, err := s.q.Delegation(ctx, postgresqlc.DelegationParams{
+		OwnerID:    ownerID,
+		DelegateID: int64(delegateID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return user.Delegation{}, user.ErrUnknownDelegation
+		}
+		return user.Delegation{}, err
+	}
+	return convertDelegation(d), nil
+}
+
+func (s *delegationStorage) DeleteDelegation(ctx context.Context, userID user.ID, delegationID int64) error {
+	n, err := s.q.DeleteDelegation(ctx, postgresqlc.DeleteDelegationParams{
+		ID:     delegationID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return user.ErrUnknownDelegation
+	}
+	return nil
+}
+
+func (s *delegationStorage) MirroringDelegates(ctx context.Context, ownerID user.ID) ([]user.ID, error) {
+	ids, err := s.q.MirroringDelegates(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return convertList(ids, func(id int64) user.ID { return user.ID(id) }), nil
+}
+
+func convertDelegation(d postgresqlc.UserDelegation) user.Delegation {
+	return user.Delegation{
+		ID:                  d.ID,
+		OwnerID:             d.OwnerID,
+		DelegateID:          deref(d.DelegateID),
+		Access:              user.DelegationAccess(d.Access),
+		MirrorNotifications: d.MirrorNotifications,
+		CreatedAt:           d.CreatedAt.Time,
+		InviteExpiresAt:     d.InviteExpiresAt.Time,
+		AcceptedAt:          d.AcceptedAt.Time,
+	}
+}
@@ -27,8 +27,8 @@ func (s *dosageStorage) DeliveryMethods(ctx context.Context) ([]dosage.DeliveryM
 	}), nil
 }
 
-func (s *dosageStorage) Dosage(ctx context.Context, secret user.Secret) (*dosage.Dosage, error) {
-	d, err := s.q.DosageSchedule(ctx, secret)
+func (s *dosageStorage) Dosage(ctx context.Context, userID user.ID) (*dosage.Dosage, error) {
+	d, err := s.q.DosageSchedule(ctx, userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -47,7 +47,7 @@ func convertDosage(d postgresqlc.DosageSchedule) dosage.Dosage {
 		(dosage.Days(d.Interval.Months) * 30)
 
 	return dosage.Dosage{
-		UserSecret:     d.UserSecret,
+		UserID:         d.UserID,
 		DeliveryMethod: d.DeliveryMethod.String,
 		Dose:           d.Dose,
 		Interval:       interval,
@@ -56,9 +56,13 @@ func convertDosage(d postgresqlc.DosageSchedule) dosage.Dosage {
 }
 
 func (s *dosageStorage) SetDosage(ctx context.Context, d dosage.Dosage) error {
+	return s.q.SetDosageSchedule(ctx, setDosageScheduleParams(d))
+}
+
+func setDosageScheduleParams(d dosage.Dosage) postgresqlc.SetDosageScheduleParams {
 	int, frac := math.Modf(float64(d.Interval))
-	return s.q.SetDosageSchedule(ctx, postgresqlc.SetDosageScheduleParams{
-		UserSecret:     d.UserSecret,
+	return postgresqlc.SetDosageScheduleParams{
+		UserID:         d.UserID,
 		DeliveryMethod: pgtype.Text{String: d.DeliveryMethod, Valid: true},
 		Dose:           d.Dose,
 		Interval: pgtype.Interval{
@@ -70,9 +74,9 @@ func (s *dosageStorage) SetDosage(ctx context.Context, d dosage.Dosage) error {
 			Int16: int16(min(deref(d.Concurrence), math.MaxInt16)),
 			Valid: d.Concurrence != nil && *d.Concurrence > 0,
 		},
-	})
+	}
 }
 
-func (s *dosageStorage) ClearDosage(ctx context.Context, secret user.Secret) error {
-	return s.q.DeleteDosageSchedule(ctx, secret)
+func (s *dosageStorage) ClearDosage(ctx context.Context, userID user.ID) error {
+	return s.q.DeleteDosageSchedule(ctx, userID)
 }
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"iter"
+	"time"
 
 	"e2clicker.app/internal/ptr"
 	"e2clicker.app/internal/sqlc/postgresqlc"
 	"e2clicker.app/services/dosage"
+	"e2clicker.app/services/user"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -23,7 +25,7 @@ func (s *dosageReminderStorage) UpcomingDosageReminders(ctx context.Context) ite
 	return func(yield func(dosage.DosageReminder, error) bool) {
 		for o1 := range iter.Iterate() {
 			o2 := dosage.DosageReminder{
-				UserSecret:       o1.UserSecret,
+				UserID:           o1.UserID,
 				Username:         o1.UserName,
 				Dosage:           convertDosage(o1.DosageSchedule),
 				LastDose:         convertDose(o1.DosageHistory),
@@ -45,15 +47,46 @@ func (s *dosageReminderStorage) UpcomingDosageReminders(ctx context.Context) ite
 func (s *dosageReminderStorage) RecordRemindedDoseAttempts(ctx context.Context, remindedDoseAttempts []dosage.RemindedDoseAttempt) error {
 	var errs []error
 	for _, attempt := range remindedDoseAttempts {
-		err := s.q.RecordRemindedDoseAttempt(ctx, postgresqlc.RecordRemindedDoseAttemptParams{
-			UserSecret:         attempt.UserSecret,
-			SentAt:             pgtype.Timestamptz{Time: attempt.RemindedAt, Valid: true},
-			SupposedEntityTime: pgtype.Timestamptz{Time: attempt.RemindedDose, Valid: true},
-			ErrorReason:        pgtype.Text{String: ptr.Deref(attempt.ErrorReason), Valid: attempt.ErrorReason != nil},
-		})
+		err := s.q.RecordRemindedDoseAttempt(ctx, recordRemindedDoseAttemptParams(attempt))
 		if err != nil {
 			errs = append(errs, err)
 		}
 	}
 	return errors.Join(errs...)
 }
+
+func recordRemindedDoseAttemptParams(attempt dosage.RemindedDoseAttempt) postgresqlc.RecordRemindedDoseAttemptParams {
+	return postgresqlc.RecordRemindedDoseAttemptParams{
+		UserID:             attempt.UserID,
+		SentAt:             pgtype.Timestamptz{Time: attempt.RemindedAt, Valid: true},
+		SupposedEntityTime: pgtype.Timestamptz{Time: attempt.RemindedDose, Valid: true},
+		ErrorReason:        pgtype.Text{String: ptr.Deref(attempt.ErrorReason), Valid: attempt.ErrorReason != nil},
+	}
+}
+
+func (s *dosageReminderStorage) ReminderAttempts(ctx context.Context, userID user.ID, begin, end time.Time) (sent, failed int, err error) {
+	counts, err := s.q.ReminderHistoryCounts(ctx, postgresqlc.ReminderHistoryCountsParams{
+		UserID: userID,
+		Start:  pgtype.Timestamptz{Time: begin, Valid: true},
+		End:    pgtype.Timestamptz{Time: end, Valid: true},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(counts.Sent), int(counts.Failed), nil
+}
+
+func (s *dosageReminderStorage) ReminderHistory(ctx context.Context, userID user.ID) ([]dosage.RemindedDoseAttempt, error) {
+	rows, err := s.q.ReminderHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return convertList(rows, func(r postgresqlc.ReminderHistoryRow) dosage.RemindedDoseAttempt {
+		return dosage.RemindedDoseAttempt{
+			UserID:       userID,
+			RemindedAt:   r.SentAt.Time,
+			RemindedDose: r.SupposedEntityTime.Time,
+			ErrorReason:  ptr.ToIf(r.ErrorReason.String, r.ErrorReason.Valid),
+		}
+	}), nil
+}
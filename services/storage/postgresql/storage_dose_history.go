@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"e2clicker.app/internal/sqlc/postgresqlc"
 	"e2clicker.app/services/dosage"
@@ -17,9 +18,9 @@ type doseHistoryStorage Storage
 
 func (s *Storage) doseHistoryStorage() dosage.DoseHistoryStorage { return (*doseHistoryStorage)(s) }
 
-func (s *doseHistoryStorage) RecordDose(ctx context.Context, userSecret user.Secret, dose dosage.Dose) error {
+func (s *doseHistoryStorage) RecordDose(ctx context.Context, userID user.ID, dose dosage.Dose) error {
 	return s.q.RecordDose(ctx, postgresqlc.RecordDoseParams{
-		UserSecret:     userSecret,
+		UserID:         userID,
 		DeliveryMethod: pgtype.Text{String: dose.DeliveryMethod, Valid: true},
 		Dose:           dose.Dose,
 		TakenAt:        pgtype.Timestamptz{Time: dose.TakenAt, Valid: true},
@@ -27,34 +28,49 @@ func (s *doseHistoryStorage) RecordDose(ctx context.Context, userSecret user.Sec
 	})
 }
 
-func (s *doseHistoryStorage) ImportDoses(ctx context.Context, userSecret user.Secret, doses iter.Seq[dosage.Dose]) (int64, error) {
+func (s *doseHistoryStorage) ImportDoses(ctx context.Context, userID user.ID, doses iter.Seq[dosage.Dose]) (int64, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	n, err := importDoses(ctx, tx, userID, doses)
+	if err != nil {
+		return 0, fmt.Errorf("ImportDoses: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("ImportDoses: commit: %w", err)
+	}
+
+	return n, nil
+}
+
+// importDoses imports doses within the transaction tx. Doses at the same time
+// as one that the user already has are skipped.
+func importDoses(ctx context.Context, tx pgx.Tx, userID user.ID, doses iter.Seq[dosage.Dose]) (int64, error) {
 	const table = "dosage_history"
 	rows := []string{
-		"user_secret",
+		"user_id",
 		"delivery_method",
 		"dose",
 		"taken_at",
 		"taken_off_at",
 	}
 
-	tx, err := s.pool.Begin(ctx)
-	if err != nil {
-		return 0, err
-	}
-	defer tx.Rollback(ctx)
-
-	_, err = tx.Exec(ctx, fmt.Sprintln(
+	_, err := tx.Exec(ctx, fmt.Sprintln(
 		"CREATE TEMP TABLE tmp_history",
 		"  (LIKE", table, "INCLUDING ALL EXCLUDING STORAGE EXCLUDING CONSTRAINTS)",
 		"ON COMMIT DROP;",
 	))
 	if err != nil {
-		return 0, fmt.Errorf("ImportDoses: create temp table: %w", err)
+		return 0, fmt.Errorf("create temp table: %w", err)
 	}
 
 	iter := newCopyFromIterator(doses, func(d dosage.Dose) ([]any, error) {
 		return []any{
-			userSecret,
+			userID,
 			d.DeliveryMethod,
 			d.Dose,
 			pgtype.Timestamptz{Time: d.TakenAt, Valid: true},
@@ -64,27 +80,24 @@ func (s *doseHistoryStorage) ImportDoses(ctx context.Context, userSecret user.Se
 	defer iter.Close()
 
 	if _, err = tx.CopyFrom(ctx, []string{"tmp_history"}, rows, iter); err != nil {
-		return 0, fmt.Errorf("ImportDoses: copy from: %w", err)
+		return 0, fmt.Errorf("copy from: %w", err)
 	}
 
 	r, err := tx.Exec(ctx, fmt.Sprintln(
 		"INSERT INTO", table, "(", strings.Join(rows, ", "), ")",
 		"SELECT", strings.Join(rows, ", "), "FROM tmp_history ORDER BY taken_at ASC",
-		"ON CONFLICT (user_secret, taken_at) DO NOTHING;",
+		"ON CONFLICT (user_id, taken_at) DO NOTHING;",
 	))
-
-	n := r.RowsAffected()
-
-	if err := tx.Commit(ctx); err != nil {
-		return 0, fmt.Errorf("ImportDoses: commit: %w", err)
+	if err != nil {
+		return 0, fmt.Errorf("insert: %w", err)
 	}
 
-	return n, nil
+	return r.RowsAffected(), nil
 }
 
-func (s *doseHistoryStorage) EditDose(ctx context.Context, userSecret user.Secret, doseTime time.Time, d dosage.Dose) error {
+func (s *doseHistoryStorage) EditDose(ctx context.Context, userID user.ID, doseTime time.Time, d dosage.Dose) error {
 	n, err := s.q.EditDose(ctx, postgresqlc.EditDoseParams{
-		UserSecret: userSecret,
+		UserID:     userID,
 		OldTakenAt: pgtype.Timestamptz{Time: doseTime, Valid: true},
 
 		DeliveryMethod: pgtype.Text{String: d.DeliveryMethod, Valid: true},
@@ -101,15 +114,15 @@ func (s *doseHistoryStorage) EditDose(ctx context.Context, userSecret user.Secre
 	return nil
 }
 
-func (s *doseHistoryStorage) ForgetDoses(ctx context.Context, userSecret user.Secret, doseTimes []time.Time) error {
+func (s *doseHistoryStorage) ForgetDoses(ctx context.Context, userID user.ID, doseTimes []time.Time) error {
 	pgTimes := make([]pgtype.Timestamp, len(doseTimes))
 	for i, t := range doseTimes {
 		pgTimes[i] = pgtype.Timestamp{Time: t, Valid: true}
 	}
 
 	n, err := s.q.ForgetDoses(ctx, postgresqlc.ForgetDosesParams{
-		UserSecret: userSecret,
-		TakenAt:    pgTimes,
+		UserID:  userID,
+		TakenAt: pgTimes,
 	})
 	if err != nil {
 		return err
@@ -120,15 +133,15 @@ func (s *doseHistoryStorage) ForgetDoses(ctx context.Context, userSecret user.Se
 	return nil
 }
 
-func (s *doseHistoryStorage) DoseHistory(ctx context.Context, secret user.Secret, begin, end time.Time) iter.Seq2[dosage.Dose, error] {
+func (s *doseHistoryStorage) DoseHistory(ctx context.Context, userID user.ID, begin, end time.Time) iter.Seq2[dosage.Dose, error] {
 	if end.IsZero() {
 		end = time.Now()
 	}
 
 	iter := s.q.DoseHistory(ctx, postgresqlc.DoseHistoryParams{
-		UserSecret: secret,
-		Start:      pgtype.Timestamptz{Time: begin, Valid: true},
-		End:        pgtype.Timestamptz{Time: end, Valid: true},
+		UserID: userID,
+		Start:  pgtype.Timestamptz{Time: begin, Valid: true},
+		End:    pgtype.Timestamptz{Time: end, Valid: true},
 	})
 
 	return func(yield func(dosage.Dose, error) bool) {
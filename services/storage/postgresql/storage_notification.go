@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 
 	"e2clicker.app/internal/sqlc/postgresqlc"
 	"e2clicker.app/services/notification"
@@ -16,11 +17,11 @@ func (s *Storage) notificationUserStorage() notification.UserNotificationStorage
 
 type notificationUserStorage Storage
 
-func (s *notificationUserStorage) UserPreferences(ctx context.Context, userSecret user.Secret) (notification.UserPreferences, error) {
-	return s.q.UserNotificationPreferences(ctx, userSecret)
+func (s *notificationUserStorage) UserPreferences(ctx context.Context, userID user.ID) (notification.UserPreferences, error) {
+	return s.q.UserNotificationPreferences(ctx, userID)
 }
 
-func (s *notificationUserStorage) SetUserPreferencesTx(ctx context.Context, userSecret user.Secret, prefs func(*notification.UserPreferences) error) error {
+func (s *notificationUserStorage) SetUserPreferencesTx(ctx context.Context, userID user.ID, prefs func(*notification.UserPreferences) error) error {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
@@ -29,7 +30,10 @@ func (s *notificationUserStorage) SetUserPreferencesTx(ctx context.Context, user
 
 	q := postgresqlc.New(tx)
 
-	p, err := q.UserNotificationPreferences(ctx, userSecret)
+	// Lock the row until the transaction is done, so that concurrent
+	// updates, like recording delivery health while the user saves their
+	// preferences, don't overwrite each other.
+	p, err := q.UserNotificationPreferencesForUpdate(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("get user preferences: %w", err)
 	}
@@ -44,7 +48,7 @@ func (s *notificationUserStorage) SetUserPreferencesTx(ctx context.Context, user
 	}
 
 	if err := q.SetUserNotificationPreferences(ctx, postgresqlc.SetUserNotificationPreferencesParams{
-		Secret:  userSecret,
+		ID:      userID,
 		Column2: b,
 	}); err != nil {
 		return fmt.Errorf("set user preferences: %w", err)
@@ -56,3 +60,35 @@ func (s *notificationUserStorage) SetUserPreferencesTx(ctx context.Context, user
 
 	return nil
 }
+
+func (s *notificationUserStorage) UsersWithNotificationMethod(ctx context.Context, method string) iter.Seq2[user.ID, error] {
+	iter := s.q.UsersWithNotificationMethod(ctx, method)
+
+	return func(yield func(user.ID, error) bool) {
+		for userID := range iter.Iterate() {
+			if !yield(userID, nil) {
+				return
+			}
+		}
+
+		if err := iter.Err(); err != nil {
+			yield(0, err)
+		}
+	}
+}
+
+func (s *notificationUserStorage) UsersWithDigest(ctx context.Context) iter.Seq2[user.ID, error] {
+	iter := s.q.UsersWithDigest(ctx)
+
+	return func(yield func(user.ID, error) bool) {
+		for userID := range iter.Iterate() {
+			if !yield(userID, nil) {
+				return
+			}
+		}
+
+		if err := iter.Err(); err != nil {
+			yield(0, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"e2clicker.app/internal/sqlc/postgresqlc"
+	"e2clicker.app/services/user"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+type passkeyStorage Storage
+
+func (s *Storage) passkeyStorage() user.PasskeyStorage { return (*passkeyStorage)(s) }
+
+func (s *passkeyStorage) AddPasskey(ctx context.Context, userID user.ID, name string, credential webauthn.Credential) (user.Passkey, error) {
+	b, err := json.Marshal(credential)
+	if err != nil {
+		return user.Passkey{}, fmt.Errorf("cannot marshal credential: %w", err)
+	}
+
+	p, err := s.q.AddPasskey(ctx, postgresqlc.AddPasskeyParams{
+		UserID:       userID,
+		CredentialID: credential.ID,
+		Credential:   b,
+		Name:         name,
+	})
+	if err != nil {
+		return user.Passkey{}, err
+	}
+
+	return convertPasskey(p)
+}
+
+func (s *passkeyStorage) Passkeys(ctx context.Context, userID user.ID) ([]user.Passkey, error) {
+	l, err := s.q.ListPasskeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys := make([]user.Passkey, len(l))
+	for i, p := range l {
+		passkeys[i], err = convertPasskey(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return passkeys, nil
+}
+
+func (s *passkeyStorage) UpdatePasskeyCredential(ctx context.Context, userID user.ID, credential webauthn.Credential) error {
+	b, err := json.Marshal(credential)
+	if err != nil {
+		return fmt.Errorf("cannot marshal credential: %w", err)
+	}
+
+	return s.q.UpdatePasskeyCredential(ctx, postgresqlc.UpdatePasskeyCredentialParams{
+		UserID:       userID,
+		CredentialID: credential.ID,
+		Credential:   b,
+	})
+}
+
+func (s *passkeyStorage) DeletePasskey(ctx context.Context, userID user.ID, passkeyID int64) error {
+	n, err := s.q.DeletePasskey(ctx, postgresqlc.DeletePasskeyParams{
+		UserID: userID,
+		ID:     passkeyID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return user.ErrUnknownPasskey
+	}
+	return nil
+}
+
+func convertPasskey(p postgresqlc.UserPasskey) (user.Passkey, error) {
+	var credential webauthn.Credential
+	if err := json.Unmarshal(p.Credential, &credential); err != nil {
+		return user.Passkey{}, fmt.Errorf("cannot unmarshal credential of passkey %d: %w", p.ID, err)
+	}
+
+	return user.Passkey{
+		ID:         p.ID,
+		Name:       p.Name,
+		Credential: credential,
+		CreatedAt:  p.CreatedAt.Time,
+		LastUsed:   p.LastUsed.Time,
+	}, nil
+}
@@ -0,0 +1,148 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"e2clicker.app/internal/sqlc/postgresqlc"
+	"e2clicker.app/services/dosage"
+	"e2clicker.app/services/user"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type shareLinkStorage Storage
+
+func (s *Storage) shareLinkStorage() dosage.ShareLinkStorage {
+	return (*shareLinkStorage)(s)
+}
+
+func (s *shareLinkStorage) CreateShareLink(ctx context.Context, link dosage.ShareLink, tokenHash, pinHash []byte) (dosage.ShareLink, error) {
+	l, err := s.q.CreateShareLink(ctx, postgresqlc.CreateShareLinkParams{
+		UserID:     link.UserID,
+		TokenHash:  tokenHash,
+		Name:       link.Name,
+		RangeStart: pgtype.Timestamptz{Time: link.Start, Valid: true},
+		RangeEnd:   pgtype.Timestamptz{Time: link.End, Valid: true},
+		Fields:     convertList(link.Fields, func(f dosage.ShareField) string { return string(f) }),
+		PinHash:    pinHash,
+		ExpiresAt:  pgtype.Timestamptz{Time: link.ExpiresAt, Valid: true},
+	})
+	if err != nil {
+		return dosage.ShareLink{}, err
+	}
+	return convertShareLink(l), nil
+}
+
+func (s *shareLinkStorage) ShareLinks(ctx context.Context, userID user.ID) ([]dosage.ShareLink, error) {
+	l, err := s.q.ListShareLinks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return convertList(l, convertShareLink), nil
+}
+
+func (s *shareLinkStorage) ShareLinkByToken(ctx context.Context, tokenHash []byte) (dosage.ShareLink, []byte, error) {
+	l, err := s.q.ShareLinkByToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dosage.ShareLink{}, nil, dosage.ErrUnknownShareLink
+		}
+		return dosage.ShareLink{}, nil, err
+	}
+	return convertShareLink(l), l.PinHash, nil
+}
+
+func (s *shareLinkStorage) RevokeShareLink(ctx context.Context, userID user.ID, linkID int64) error {
+	n, err := s.q.RevokeShareLink(ctx, postgresqlc.RevokeShareLinkParams{
+		UserID: userID,
+		ID:     linkID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return dosage.ErrUnknownShareLink
+	}
+	return nil
+}
+
+func (s *shareLinkStorage) RecordShareLinkAccess(ctx context.Context, linkID int64, access dosage.ShareLinkAccess) error {
+	return s.q.RecordShareLinkAccess(ctx, postgresqlc.RecordShareLinkAccessParams{
+		ShareLinkID: linkID,
+		UserAgent:   pgtype.Text{String: access.UserAgent, Valid: access.UserAgent != ""},
+		Granted:     access.Granted,
+		LockedOut:   access.LockedOut,
+	})
+}
+
+func (s *shareLinkStorage) RecordIncorrectSharePIN(ctx context.Context, linkID int64, userAgent string, maxAttempts int) (bool, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := postgresqlc.New(tx)
+
+	n, err := q.IncorrectSharePINCount(ctx, linkID)
+	if err != nil {
+		return false, fmt.Errorf("count incorrect PINs: %w", err)
+	}
+
+	locked := n+1 >= int64(maxAttempts)
+	if locked {
+		if err := q.LockShareLink(ctx, linkID); err != nil {
+			return false, fmt.Errorf("lock share link: %w", err)
+		}
+	}
+
+	if err := q.RecordShareLinkAccess(ctx, postgresqlc.RecordShareLinkAccessParams{
+		ShareLinkID: linkID,
+		UserAgent:   pgtype.Text{String: userAgent, Valid: userAgent != ""},
+		Granted:     false,
+		LockedOut:   locked,
+	}); err != nil {
+		return false, fmt.Errorf("record share link access: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return locked, nil
+}
+
+func (s *shareLinkStorage) ShareLinkAccesses(ctx context.Context, userID user.ID, linkID int64) ([]dosage.ShareLinkAccess, error) {
+	l, err := s.q.ListShareLinkAccesses(ctx, postgresqlc.ListShareLinkAccessesParams{
+		UserID: userID,
+		ID:     linkID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convertList(l, func(a postgresqlc.ShareLinkAccess) dosage.ShareLinkAccess {
+		return dosage.ShareLinkAccess{
+			AccessedAt: a.AccessedAt.Time,
+			UserAgent:  a.UserAgent.String,
+			Granted:    a.Granted,
+			LockedOut:  a.LockedOut,
+		}
+	}), nil
+}
+
+func convertShareLink(l postgresqlc.ShareLink) dosage.ShareLink {
+	return dosage.ShareLink{
+		ID:        l.ID,
+		UserID:    l.UserID,
+		Name:      l.Name,
+		Start:     l.RangeStart.Time,
+		End:       l.RangeEnd.Time,
+		Fields:    convertList(l.Fields, func(f string) dosage.ShareField { return dosage.ShareField(f) }),
+		HasPIN:    l.PinHash != nil,
+		CreatedAt: l.CreatedAt.Time,
+		ExpiresAt: l.ExpiresAt.Time,
+		RevokedAt: l.RevokedAt.Time,
+	}
+}
@@ -0,0 +1,87 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"e2clicker.app/internal/sqlc/postgresqlc"
+	"e2clicker.app/services/dosage"
+	"e2clicker.app/services/user"
+)
+
+type takeoutStorage Storage
+
+func (s *Storage) takeoutStorage() dosage.TakeoutStorage { return (*takeoutStorage)(s) }
+
+func (s *takeoutStorage) ImportTakeout(ctx context.Context, userID user.ID, t dosage.TakeoutImport) (int64, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := postgresqlc.New(tx)
+
+	hasData, err := q.HasDosageData(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("check for dosage data: %w", err)
+	}
+	if hasData {
+		return 0, dosage.ErrTakeoutNotFreshAccount
+	}
+
+	if t.Name != "" {
+		if err := q.UpdateUserName(ctx, postgresqlc.UpdateUserNameParams{
+			ID:   userID,
+			Name: t.Name,
+		}); err != nil {
+			return 0, fmt.Errorf("update user name: %w", err)
+		}
+	}
+
+	if t.Locale != "" {
+		if err := q.UpdateUserLocale(ctx, postgresqlc.UpdateUserLocaleParams{
+			ID:     userID,
+			Locale: t.Locale,
+		}); err != nil {
+			return 0, fmt.Errorf("update user locale: %w", err)
+		}
+	}
+
+	if t.Dosage != nil {
+		if err := q.SetDosageSchedule(ctx, setDosageScheduleParams(*t.Dosage)); err != nil {
+			return 0, fmt.Errorf("set dosage schedule: %w", err)
+		}
+	}
+
+	n, err := importDoses(ctx, tx, userID, slices.Values(t.Doses))
+	if err != nil {
+		return 0, fmt.Errorf("import doses: %w", err)
+	}
+
+	prefs, err := json.Marshal(t.NotificationPreferences)
+	if err != nil {
+		return 0, fmt.Errorf("cannot marshal UserPreferences as JSON: %w", err)
+	}
+
+	if err := q.SetUserNotificationPreferences(ctx, postgresqlc.SetUserNotificationPreferencesParams{
+		ID:      userID,
+		Column2: prefs,
+	}); err != nil {
+		return 0, fmt.Errorf("set user preferences: %w", err)
+	}
+
+	for _, attempt := range t.RemindedDoseAttempts {
+		if err := q.RecordRemindedDoseAttempt(ctx, recordRemindedDoseAttemptParams(attempt)); err != nil {
+			return 0, fmt.Errorf("record reminded dose attempt: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return n, nil
+}
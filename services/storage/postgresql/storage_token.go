@@ -0,0 +1,77 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"e2clicker.app/internal/sqlc/postgresqlc"
+	"e2clicker.app/services/user"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type personalTokenStorage Storage
+
+func (s *Storage) personalTokenStorage() user.PersonalTokenStorage {
+	return (*personalTokenStorage)(s)
+}
+
+func (s *personalTokenStorage) CreatePersonalToken(ctx context.Context, userID user.ID, name string, tokenHash []byte, scopes []user.Scope, expiresAt time.Time) (user.PersonalToken, error) {
+	t, err := s.q.CreateToken(ctx, postgresqlc.CreateTokenParams{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: tokenHash,
+		Scopes:    convertList(scopes, func(s user.Scope) string { return string(s) }),
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: !expiresAt.IsZero()},
+	})
+	if err != nil {
+		return user.PersonalToken{}, err
+	}
+	return convertPersonalToken(t), nil
+}
+
+func (s *personalTokenStorage) PersonalTokens(ctx context.Context, userID user.ID) ([]user.PersonalToken, error) {
+	l, err := s.q.ListTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return convertList(l, convertPersonalToken), nil
+}
+
+func (s *personalTokenStorage) DeletePersonalToken(ctx context.Context, userID user.ID, tokenID int64) error {
+	n, err := s.q.DeleteToken(ctx, postgresqlc.DeleteTokenParams{
+		UserID: userID,
+		ID:     tokenID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return user.ErrUnknownPersonalToken
+	}
+	return nil
+}
+
+func (s *personalTokenStorage) ValidatePersonalToken(ctx context.Context, tokenHash []byte) (user.PersonalToken, error) {
+	t, err := s.q.ValidateToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return user.PersonalToken{}, user.ErrInvalidSession
+		}
+		return user.PersonalToken{}, err
+	}
+	return convertPersonalToken(t), nil
+}
+
+func convertPersonalToken(t postgresqlc.UserToken) user.PersonalToken {
+	return user.PersonalToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		Name:      t.Name,
+		Scopes:    convertList(t.Scopes, func(s string) user.Scope { return user.Scope(s) }),
+		CreatedAt: t.CreatedAt.Time,
+		LastUsed:  t.LastUsed.Time,
+		ExpiresAt: t.ExpiresAt.Time,
+	}
+}
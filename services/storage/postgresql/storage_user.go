@@ -2,7 +2,10 @@ package postgresql
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -13,55 +16,328 @@ import (
 func (s *Storage) userStorage() user.UserStorage               { return s }
 func (s *Storage) userSessionStorage() user.UserSessionStorage { return s }
 
-func (s *Storage) CreateUser(ctx context.Context, userSecret user.Secret, name string) (user.User, error) {
+func (s *Storage) CreateUser(ctx context.Context, secretHash []byte, name string) (user.User, error) {
 	u, err := s.q.CreateUser(ctx, postgresqlc.CreateUserParams{
-		Secret: userSecret,
-		Name:   name,
+		SecretHash: secretHash,
+		Name:       name,
 	})
 	if err != nil {
 		return user.User{}, err
 	}
 	return user.User{
+		ID:     u.ID,
 		Name:   u.Name,
 		Locale: u.Locale,
 	}, nil
 }
 
-func (s *Storage) User(ctx context.Context, userSecret user.Secret) (user.User, error) {
-	u, err := s.q.User(ctx, userSecret)
+func (s *Storage) UserIDBySecret(ctx context.Context, secretHash []byte) (user.ID, error) {
+	id, err := s.q.UserIDBySecret(ctx, secretHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, user.ErrUnknownUser
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (s *Storage) User(ctx context.Context, userID user.ID) (user.User, error) {
+	u, err := s.q.User(ctx, userID)
 	if err != nil {
 		return user.User{}, err
 	}
 	return user.User{
-		Name:   u.Name,
-		Locale: u.Locale,
+		ID:      u.ID,
+		Name:    u.Name,
+		Locale:  u.Locale,
+		PurgeAt: u.PurgeAt.Time,
 	}, nil
 }
 
-func (s *Storage) UpdateUserName(ctx context.Context, userSecret user.Secret, name string) error {
+func (s *Storage) UpdateUserName(ctx context.Context, userID user.ID, name string) error {
 	return s.q.UpdateUserName(ctx, postgresqlc.UpdateUserNameParams{
-		Secret: userSecret,
-		Name:   name,
+		ID:   userID,
+		Name: name,
 	})
 }
 
-func (s *Storage) UpdateUserLocale(ctx context.Context, userSecret user.Secret, locale user.Locale) error {
+func (s *Storage) UpdateUserLocale(ctx context.Context, userID user.ID, locale user.Locale) error {
 	return s.q.UpdateUserLocale(ctx, postgresqlc.UpdateUserLocaleParams{
-		Secret: userSecret,
+		ID:     userID,
 		Locale: locale,
 	})
 }
 
-func (s *Storage) RegisterSession(ctx context.Context, token []byte, userSecret user.Secret, userAgent string) error {
+func (s *Storage) UpdateUserProfile(ctx context.Context, userID user.ID, update user.ProfileUpdate) (user.User, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return user.User{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := postgresqlc.New(tx)
+
+	if update.Name != nil {
+		if err := q.UpdateUserName(ctx, postgresqlc.UpdateUserNameParams{
+			ID:   userID,
+			Name: *update.Name,
+		}); err != nil {
+			return user.User{}, fmt.Errorf("update user name: %w", err)
+		}
+	}
+
+	if update.Locale != nil {
+		if err := q.UpdateUserLocale(ctx, postgresqlc.UpdateUserLocaleParams{
+			ID:     userID,
+			Locale: *update.Locale,
+		}); err != nil {
+			return user.User{}, fmt.Errorf("update user locale: %w", err)
+		}
+	}
+
+	if update.Timezone != nil {
+		// Lock the row so that a concurrent SetUserPreferencesTx doesn't
+		// overwrite the time zone with the preferences it read before.
+		prefs, err := q.UserNotificationPreferencesForUpdate(ctx, userID)
+		if err != nil {
+			return user.User{}, fmt.Errorf("get user preferences: %w", err)
+		}
+
+		prefs.Timezone = *update.Timezone
+
+		b, err := json.Marshal(prefs)
+		if err != nil {
+			return user.User{}, fmt.Errorf("cannot marshal UserPreferences as JSON: %w", err)
+		}
+
+		if err := q.SetUserNotificationPreferences(ctx, postgresqlc.SetUserNotificationPreferencesParams{
+			ID:      userID,
+			Column2: b,
+		}); err != nil {
+			return user.User{}, fmt.Errorf("set user preferences: %w", err)
+		}
+	}
+
+	u, err := q.User(ctx, userID)
+	if err != nil {
+		return user.User{}, fmt.Errorf("get user: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return user.User{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return user.User{
+		ID:      u.ID,
+		Name:    u.Name,
+		Locale:  u.Locale,
+		PurgeAt: u.PurgeAt.Time,
+	}, nil
+}
+
+func (s *Storage) ReplaceUserSecret(ctx context.Context, userID user.ID, secretHash []byte, keepSessionID int64) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := postgresqlc.New(tx)
+
+	if err := q.SetUserSecretHash(ctx, postgresqlc.SetUserSecretHashParams{
+		ID:         userID,
+		SecretHash: secretHash,
+	}); err != nil {
+		return fmt.Errorf("set user secret hash: %w", err)
+	}
+
+	if err := q.DeleteOtherSessions(ctx, postgresqlc.DeleteOtherSessionsParams{
+		UserID: userID,
+		ID:     keepSessionID,
+	}); err != nil {
+		return fmt.Errorf("delete other sessions: %w", err)
+	}
+
+	if err := q.DeleteAllPasskeys(ctx, userID); err != nil {
+		return fmt.Errorf("delete passkeys: %w", err)
+	}
+
+	if err := q.DeleteAllTokens(ctx, userID); err != nil {
+		return fmt.Errorf("delete tokens: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) SetUserSecretHash(ctx context.Context, userID user.ID, secretHash []byte) error {
+	return s.q.SetUserSecretHash(ctx, postgresqlc.SetUserSecretHashParams{
+		ID:         userID,
+		SecretHash: secretHash,
+	})
+}
+
+func (s *Storage) ReplaceRecoveryCodes(ctx context.Context, userID user.ID, codeHashes [][]byte) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := postgresqlc.New(tx)
+
+	if err := q.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+
+	for _, codeHash := range codeHashes {
+		if err := q.AddRecoveryCode(ctx, postgresqlc.AddRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: codeHash,
+		}); err != nil {
+			return fmt.Errorf("add recovery code: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) RecoveryCodeCount(ctx context.Context, userID user.ID) (int, error) {
+	n, err := s.q.RecoveryCodeCount(ctx, userID)
+	return int(n), err
+}
+
+func (s *Storage) UseRecoveryCode(ctx context.Context, codeHash []byte) (user.ID, error) {
+	id, err := s.q.UseRecoveryCode(ctx, codeHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, user.ErrUnknownUser
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (s *Storage) HashPlainSecrets(ctx context.Context, hash func([]byte) []byte) (int64, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := postgresqlc.New(tx)
+	var n int64
+
+	secrets, err := q.PlainUserSecrets(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get plain user secrets: %w", err)
+	}
+	for _, r := range secrets {
+		if err := q.SetUserSecretHash(ctx, postgresqlc.SetUserSecretHashParams{
+			ID:         r.ID,
+			SecretHash: hash([]byte(r.Secret)),
+		}); err != nil {
+			return 0, fmt.Errorf("set user secret hash: %w", err)
+		}
+		n++
+	}
+
+	tokens, err := q.PlainSessionTokens(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get plain session tokens: %w", err)
+	}
+	for _, r := range tokens {
+		if err := q.SetSessionTokenHash(ctx, postgresqlc.SetSessionTokenHashParams{
+			ID:        r.ID,
+			TokenHash: hash(r.Token),
+		}); err != nil {
+			return 0, fmt.Errorf("set session token hash: %w", err)
+		}
+		n++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return n, nil
+}
+
+func (s *Storage) ScheduleUserPurge(ctx context.Context, userID user.ID, purgeAt time.Time) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := postgresqlc.New(tx)
+
+	if err := q.ScheduleUserPurge(ctx, postgresqlc.ScheduleUserPurgeParams{
+		ID:      userID,
+		PurgeAt: pgtype.Timestamptz{Time: purgeAt, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("schedule user purge: %w", err)
+	}
+
+	if err := q.DeleteAllSessions(ctx, userID); err != nil {
+		return fmt.Errorf("delete all sessions: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) CancelUserPurge(ctx context.Context, userID user.ID) error {
+	n, err := s.q.CancelUserPurge(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return user.ErrNoAccountDeletion
+	}
+	return nil
+}
+
+func (s *Storage) UsersDueForPurge(ctx context.Context) ([]user.ID, error) {
+	return s.q.UsersDueForPurge(ctx)
+}
+
+func (s *Storage) DeleteUser(ctx context.Context, userID user.ID) error {
+	n, err := s.q.DeleteUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return user.ErrUnknownUser
+	}
+	return nil
+}
+
+func (s *Storage) RegisterSession(ctx context.Context, tokenHash []byte, userID user.ID, userAgent string) error {
 	return s.q.RegisterSession(ctx, postgresqlc.RegisterSessionParams{
-		UserSecret: userSecret,
-		Token:      token,
-		UserAgent:  pgtype.Text{String: userAgent, Valid: userAgent != ""},
+		UserID:    userID,
+		TokenHash: tokenHash,
+		UserAgent: pgtype.Text{String: userAgent, Valid: userAgent != ""},
 	})
 }
 
-func (s *Storage) ValidateSession(ctx context.Context, token []byte) (user.Session, error) {
-	r, err := s.q.ValidateSession(ctx, token)
+func (s *Storage) ValidateSession(ctx context.Context, tokenHash []byte, lifetime user.SessionLifetime) (user.Session, error) {
+	r, err := s.q.ValidateSession(ctx, postgresqlc.ValidateSessionParams{
+		TokenHash: tokenHash,
+		MaxAge:    durationInterval(lifetime.MaxAge),
+		MaxIdle:   durationInterval(lifetime.MaxIdle),
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return user.Session{}, user.ErrInvalidSession
@@ -71,8 +347,15 @@ func (s *Storage) ValidateSession(ctx context.Context, token []byte) (user.Sessi
 	return convertSession(r), nil
 }
 
-func (s *Storage) ListSessions(ctx context.Context, userSecret user.Secret) ([]user.Session, error) {
-	l, err := s.q.ListSessions(ctx, userSecret)
+func (s *Storage) SetSessionTokenHash(ctx context.Context, sessionID int64, tokenHash []byte) error {
+	return s.q.SetSessionTokenHash(ctx, postgresqlc.SetSessionTokenHashParams{
+		ID:        sessionID,
+		TokenHash: tokenHash,
+	})
+}
+
+func (s *Storage) ListSessions(ctx context.Context, userID user.ID) ([]user.Session, error) {
+	l, err := s.q.ListSessions(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -81,18 +364,18 @@ func (s *Storage) ListSessions(ctx context.Context, userSecret user.Secret) ([]u
 
 func convertSession(r postgresqlc.UserSession) user.Session {
 	return user.Session{
-		ID:         r.ID,
-		UserSecret: user.Secret(r.UserSecret),
-		UserAgent:  r.UserAgent.String,
-		CreatedAt:  r.CreatedAt.Time,
-		LastUsed:   r.LastUsed.Time,
+		ID:        r.ID,
+		UserID:    r.UserID,
+		UserAgent: r.UserAgent.String,
+		CreatedAt: r.CreatedAt.Time,
+		LastUsed:  r.LastUsed.Time,
 	}
 }
 
-func (s *Storage) DeleteSession(ctx context.Context, userSecret user.Secret, sessionID int64) error {
+func (s *Storage) DeleteSession(ctx context.Context, userID user.ID, sessionID int64) error {
 	err := s.q.DeleteSession(ctx, postgresqlc.DeleteSessionParams{
-		UserSecret: userSecret,
-		ID:         sessionID,
+		UserID: userID,
+		ID:     sessionID,
 	})
 	if errors.Is(err, pgx.ErrNoRows) {
 		return user.ErrInvalidSession
@@ -100,6 +383,29 @@ func (s *Storage) DeleteSession(ctx context.Context, userSecret user.Secret, ses
 	return err
 }
 
+func (s *Storage) DeleteOtherSessions(ctx context.Context, userID user.ID, keepSessionID int64) error {
+	return s.q.DeleteOtherSessions(ctx, postgresqlc.DeleteOtherSessionsParams{
+		UserID: userID,
+		ID:     keepSessionID,
+	})
+}
+
+func (s *Storage) DeleteExpiredSessions(ctx context.Context, lifetime user.SessionLifetime) (int64, error) {
+	return s.q.DeleteExpiredSessions(ctx, postgresqlc.DeleteExpiredSessionsParams{
+		MaxAge:  durationInterval(lifetime.MaxAge),
+		MaxIdle: durationInterval(lifetime.MaxIdle),
+	})
+}
+
+// durationInterval converts a duration to an interval. Zero durations become
+// NULL.
+func durationInterval(d time.Duration) pgtype.Interval {
+	return pgtype.Interval{
+		Microseconds: d.Microseconds(),
+		Valid:        d != 0,
+	}
+}
+
 func convertList[T1, T2 any](vs []T1, c func(T1) T2) []T2 {
 	v2 := make([]T2, len(vs))
 	for i, v := range vs {
@@ -0,0 +1,213 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// delegationInviteLifetime is how long a delegation invite can be accepted
+// for.
+const delegationInviteLifetime = 7 * 24 * time.Hour
+
+type DelegationStorage interface {
+	// CreateDelegation creates a pending delegation of the owner's data with
+	// the given access. The invite code is generated by [UserService], which
+	// only gives the storage its hash.
+	CreateDelegation(ctx context.Context, ownerID ID, access DelegationAccess, inviteHash []byte, inviteExpiresAt time.Time) (Delegation, error)
+	// AcceptDelegation makes the user the delegate of the pending delegation
+	// whose invite code hashes to inviteHash. [ErrUnknownDelegation] is
+	// returned if there is no such invite, it has expired or it was made by
+	// the user themselves, and [ErrDelegationExists] if the user is already a
+	// delegate of the owner.
+	AcceptDelegation(ctx context.Context, inviteHash []byte, delegateID ID, mirrorNotifications bool) (Delegation, error)
+	// Delegations lists the delegations that the user is the owner or the
+	// delegate of, including pending ones that haven't expired, with the
+	// newest first.
+	Delegations(ctx context.Context, userID ID) ([]Delegation, error)
+	// Delegation returns the accepted delegation of the owner's data to the
+	// delegate. [ErrUnknownDelegation] is returned if there is none, or if the
+	// owner's account is scheduled to be deleted.
+	Delegation(ctx context.Context, ownerID, delegateID ID) (Delegation, error)
+	// DeleteDelegation deletes a delegation that the user is the owner or the
+	// delegate of. [ErrUnknownDelegation] is returned if there is no such
+	// delegation.
+	DeleteDelegation(ctx context.Context, userID ID, delegationID int64) error
+	// MirroringDelegates returns the IDs of the owner's delegates that want
+	// the owner's reminders, except for delegates whose account is scheduled
+	// to be deleted.
+	MirroringDelegates(ctx context.Context, ownerID ID) ([]ID, error)
+}
+
+// DelegationAccess is what a delegate may do with the owner's data.
+type DelegationAccess string
+
+const (
+	// DelegationRead allows the delegate to see the owner's dosage and dose
+	// history.
+	DelegationRead DelegationAccess = "read"
+	// DelegationRecord also allows the delegate to record, edit and forget
+	// doses on behalf of the owner.
+	DelegationRecord DelegationAccess = "record"
+)
+
+// Validate returns an error if the access is unknown.
+func (a DelegationAccess) Validate() error {
+	if a.Scopes() == nil {
+		return fmt.Errorf("%w %q", ErrInvalidDelegationAccess, a)
+	}
+	return nil
+}
+
+// Scopes returns the scopes of the operations that the access allows. They
+// have the same meaning as the scopes of a [PersonalToken].
+func (a DelegationAccess) Scopes() []Scope {
+	switch a {
+	case DelegationRead:
+		return []Scope{ScopeDosesRead}
+	case DelegationRecord:
+		return []Scope{ScopeDosesRead, ScopeDosesWrite}
+	default:
+		return nil
+	}
+}
+
+// Delegation gives another user, the delegate, access to the owner's data,
+// e.g. a partner who helps the owner remember their doses or a clinician who
+// checks on their adherence.
+type Delegation struct {
+	// ID uniquely identifies the delegation.
+	ID int64
+	// OwnerID is the ID of the user whose data is delegated.
+	OwnerID ID
+	// OwnerName is the name of the owner.
+	OwnerName string
+	// DelegateID is the ID of the user that was given access.
+	// If zero, the invite wasn't accepted yet.
+	DelegateID ID
+	// DelegateName is the name of the delegate, if there is one.
+	DelegateName string
+	// Access is what the delegate may do with the owner's data.
+	Access DelegationAccess
+	// MirrorNotifications is true if the owner's reminders are also sent to
+	// the delegate.
+	MirrorNotifications bool
+	// CreatedAt is the time that the invite was created.
+	CreatedAt time.Time
+	// InviteExpiresAt is the time that the invite expires.
+	// It is zero once the invite was accepted.
+	InviteExpiresAt time.Time
+	// AcceptedAt is the time that the invite was accepted.
+	// If zero, the invite wasn't accepted yet.
+	AcceptedAt time.Time
+}
+
+// IsPending returns true if the invite wasn't accepted yet.
+func (d Delegation) IsPending() bool {
+	return d.DelegateID == 0
+}
+
+// Allows returns true if the delegation allows operations that need all of
+// the given scopes. Operations that don't need any scopes, such as managing
+// the account, are never allowed.
+func (d Delegation) Allows(scopes ...Scope) bool {
+	if d.IsPending() || len(scopes) == 0 {
+		return false
+	}
+	allowed := d.Access.Scopes()
+	for _, s := range scopes {
+		if !slices.Contains(allowed, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// InviteCode is the code that a delegate enters to accept a delegation. Like
+// [Secret], only its hash is stored.
+type InviteCode string
+
+// PrettyString returns the invite code as a pretty string, which is split
+// into groups of four characters by dashes.
+func (c InviteCode) PrettyString() string {
+	return RecoveryCode(c).PrettyString()
+}
+
+// DelegationInvite is a pending delegation along with its invite code, which
+// is only known when the invite is created.
+type DelegationInvite struct {
+	Delegation
+	Code InviteCode
+}
+
+// InviteDelegate creates an invite that gives whoever accepts it the given
+// access to the owner's data. The invite expires after a week, and its code
+// is only ever returned here.
+func (s UserService) InviteDelegate(ctx context.Context, ownerID ID, access DelegationAccess) (DelegationInvite, error) {
+	if err := access.Validate(); err != nil {
+		return DelegationInvite{}, err
+	}
+
+	code := InviteCode(generateRecoveryCode())
+	expiresAt := time.Now().Add(delegationInviteLifetime)
+
+	d, err := s.delegations.CreateDelegation(ctx,
+		ownerID, access, s.hasher.hash([]byte(code)), expiresAt)
+	if err != nil {
+		return DelegationInvite{}, err
+	}
+
+	return DelegationInvite{d, code}, nil
+}
+
+// AcceptDelegation makes the user a delegate using the invite code that the
+// owner gave them. If mirrorNotifications is true, the user also gets the
+// owner's reminders.
+func (s UserService) AcceptDelegation(ctx context.Context, delegateID ID, code InviteCode, mirrorNotifications bool) (Delegation, error) {
+	code = InviteCode(RecoveryCode(code).normalize())
+	return s.delegations.AcceptDelegation(ctx,
+		s.hasher.hash([]byte(code)), delegateID, mirrorNotifications)
+}
+
+// Delegations lists the delegations that the user is the owner or the
+// delegate of.
+func (s UserService) Delegations(ctx context.Context, userID ID) ([]Delegation, error) {
+	return s.delegations.Delegations(ctx, userID)
+}
+
+// DeleteDelegation ends a delegation. Both the owner and the delegate may end
+// it, and the owner may also cancel a pending invite this way.
+func (s UserService) DeleteDelegation(ctx context.Context, userID ID, delegationID int64) error {
+	return s.delegations.DeleteDelegation(ctx, userID, delegationID)
+}
+
+// AuthorizeDelegate checks that the delegate may do an operation that needs
+// the given scopes on the owner's data. [ErrNotDelegated] is returned if not.
+// Users may always act on their own data.
+func (s UserService) AuthorizeDelegate(ctx context.Context, ownerID, delegateID ID, scopes []Scope) error {
+	if ownerID == delegateID {
+		return nil
+	}
+
+	d, err := s.delegations.Delegation(ctx, ownerID, delegateID)
+	if err != nil {
+		if errors.Is(err, ErrUnknownDelegation) {
+			return ErrNotDelegated
+		}
+		return err
+	}
+
+	if !d.Allows(scopes...) {
+		return ErrNotDelegated
+	}
+
+	return nil
+}
+
+// MirroringDelegates returns the IDs of the owner's delegates that want the
+// owner's reminders.
+func (s UserService) MirroringDelegates(ctx context.Context, ownerID ID) ([]ID, error) {
+	return s.delegations.MirroringDelegates(ctx, ownerID)
+}
@@ -0,0 +1,96 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestUserService_Delegations(t *testing.T) {
+	ctx := context.Background()
+	ownerID := ID(1)
+	delegateID := ID(2)
+	strangerID := ID(3)
+
+	s := newMockUserService(t)
+
+	var invite Delegation
+	var inviteHash []byte
+	s.delegations.CreateDelegationFunc = func(ctx context.Context, ownerID ID, access DelegationAccess, hash []byte, expiresAt time.Time) (Delegation, error) {
+		inviteHash = hash
+		invite = Delegation{ID: 1, OwnerID: ownerID, Access: access, InviteExpiresAt: expiresAt}
+		return invite, nil
+	}
+	s.delegations.AcceptDelegationFunc = func(ctx context.Context, hash []byte, delegateID ID, mirror bool) (Delegation, error) {
+		if !bytes.Equal(hash, inviteHash) || delegateID == invite.OwnerID {
+			return Delegation{}, ErrUnknownDelegation
+		}
+		invite.DelegateID = delegateID
+		invite.MirrorNotifications = mirror
+		invite.InviteExpiresAt = time.Time{}
+		invite.AcceptedAt = time.Now()
+		return invite, nil
+	}
+	s.delegations.DelegationFunc = func(ctx context.Context, ownerID, delegateID ID) (Delegation, error) {
+		if invite.IsPending() || invite.OwnerID != ownerID || invite.DelegateID != delegateID {
+			return Delegation{}, ErrUnknownDelegation
+		}
+		return invite, nil
+	}
+
+	_, err := s.InviteDelegate(ctx, ownerID, "admin")
+	assert.IsError(t, err, ErrInvalidDelegationAccess)
+
+	i, err := s.InviteDelegate(ctx, ownerID, DelegationRead)
+	assert.NoError(t, err)
+	assert.True(t, i.IsPending())
+	assert.True(t, i.InviteExpiresAt.After(time.Now()))
+
+	// Pending invites don't give any access.
+	err = s.AuthorizeDelegate(ctx, ownerID, delegateID, []Scope{ScopeDosesRead})
+	assert.IsError(t, err, ErrNotDelegated)
+
+	// The code is accepted however the delegate types it.
+	typed := strings.ToLower(i.Code.PrettyString())
+	d, err := s.AcceptDelegation(ctx, delegateID, InviteCode(typed), true)
+	assert.NoError(t, err)
+	assert.Equal(t, delegateID, d.DelegateID)
+	assert.True(t, d.MirrorNotifications)
+
+	t.Run("read", func(t *testing.T) {
+		assert.NoError(t, s.AuthorizeDelegate(ctx, ownerID, delegateID, []Scope{ScopeDosesRead}))
+
+		err := s.AuthorizeDelegate(ctx, ownerID, delegateID, []Scope{ScopeDosesWrite})
+		assert.IsError(t, err, ErrNotDelegated)
+	})
+
+	t.Run("record", func(t *testing.T) {
+		invite.Access = DelegationRecord
+		defer func() { invite.Access = DelegationRead }()
+
+		assert.NoError(t, s.AuthorizeDelegate(ctx, ownerID, delegateID, []Scope{ScopeDosesWrite}))
+
+		err := s.AuthorizeDelegate(ctx, ownerID, delegateID, []Scope{ScopeScheduleWrite})
+		assert.IsError(t, err, ErrNotDelegated)
+	})
+
+	t.Run("no scopes", func(t *testing.T) {
+		// Operations without scopes, such as managing the account, can never
+		// be delegated.
+		err := s.AuthorizeDelegate(ctx, ownerID, delegateID, nil)
+		assert.IsError(t, err, ErrNotDelegated)
+	})
+
+	t.Run("stranger", func(t *testing.T) {
+		err := s.AuthorizeDelegate(ctx, ownerID, strangerID, []Scope{ScopeDosesRead})
+		assert.IsError(t, err, ErrNotDelegated)
+	})
+
+	t.Run("self", func(t *testing.T) {
+		assert.NoError(t, s.AuthorizeDelegate(ctx, ownerID, ownerID, nil))
+	})
+}
@@ -0,0 +1,207 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.uber.org/fx"
+
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
+)
+
+// accountPurgeInterval is how often accounts that are due to be deleted are
+// purged.
+const accountPurgeInterval = 10 * time.Minute
+
+// AccountPurgeListener is told about accounts right before they are purged.
+// Services that keep state about users outside of the storage, such as rate
+// limits, use it to forget about them, and the user can be told one last
+// time that their account is gone.
+//
+// Listeners are provided to [AccountDeletionService] through the
+// `account_purge_listeners` value group, see [ProvideAccountPurgeListener].
+type AccountPurgeListener interface {
+	// AccountPurging is called right before the user's account is deleted,
+	// while all of their data is still there. An error doesn't stop the
+	// account from being deleted; it is only logged.
+	AccountPurging(ctx context.Context, userID ID) error
+}
+
+// ProvideAccountPurgeListener adds the service of type T, which must be
+// provided elsewhere, to the `account_purge_listeners` value group.
+func ProvideAccountPurgeListener[T AccountPurgeListener]() fx.Option {
+	return fx.Provide(fx.Annotate(
+		func(l T) AccountPurgeListener { return l },
+		fx.ResultTags(`group:"account_purge_listeners"`),
+	))
+}
+
+// AccountDeletionService deletes the accounts of users who ask for it. The
+// account is kept for a grace period first, during which the user can log in
+// again and cancel the deletion. Once it is over, the account is purged along
+// with all of its data.
+type AccountDeletionService struct {
+	users       UserStorage
+	hasher      secretHasher
+	gracePeriod time.Duration
+	listeners   []AccountPurgeListener
+	logger      *slog.Logger
+}
+
+// AccountDeletionServiceConfig is a dependency injection container for
+// [AccountDeletionService].
+type AccountDeletionServiceConfig struct {
+	fx.In
+
+	Users     *UserService
+	Listeners []AccountPurgeListener `group:"account_purge_listeners"`
+	Config    e2clickermodule.API
+	Lifecycle fx.Lifecycle
+	Logger    *slog.Logger
+}
+
+// NewAccountDeletionService creates a new AccountDeletionService. In the
+// background, it periodically purges the accounts whose grace period is over.
+func NewAccountDeletionService(c AccountDeletionServiceConfig) (*AccountDeletionService, error) {
+	gracePeriod, err := time.ParseDuration(c.Config.AccountDeletionGracePeriod)
+	if err != nil {
+		return nil, fmt.Errorf("invalid account deletion grace period %q: %w", c.Config.AccountDeletionGracePeriod, err)
+	}
+	if gracePeriod < 0 {
+		return nil, fmt.Errorf("account deletion grace period must not be negative")
+	}
+
+	s := &AccountDeletionService{
+		users:       c.Users.users,
+		hasher:      c.Users.hasher,
+		gracePeriod: gracePeriod,
+		listeners:   c.Listeners,
+		logger:      c.Logger,
+	}
+
+	fakectx, stop := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	c.Lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				s.run(fakectx)
+				close(done)
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stop()
+			<-done
+			return nil
+		},
+	})
+
+	return s, nil
+}
+
+// DeleteAccount deletes the user's account after the user confirmed it with
+// their secret. [ErrIncorrectSecret] is returned if the secret isn't theirs.
+//
+// The user is logged out everywhere right away, and their personal access
+// tokens, share links and delegations stop working until the deletion is
+// cancelled. The account is purged once the grace period is over. The
+// returned time is when that happens, or now if there is no grace period, in
+// which case the account is already gone.
+func (s *AccountDeletionService) DeleteAccount(ctx context.Context, userID ID, secret Secret) (time.Time, error) {
+	secretUserID, err := s.users.UserIDBySecret(ctx, s.hasher.hashSecret(secret))
+	if err != nil {
+		if errors.Is(err, ErrUnknownUser) {
+			return time.Time{}, ErrIncorrectSecret
+		}
+		return time.Time{}, err
+	}
+	if secretUserID != userID {
+		return time.Time{}, ErrIncorrectSecret
+	}
+
+	now := time.Now()
+
+	if s.gracePeriod == 0 {
+		if err := s.purge(ctx, userID); err != nil {
+			return time.Time{}, err
+		}
+		return now, nil
+	}
+
+	purgeAt := now.Add(s.gracePeriod)
+	if err := s.users.ScheduleUserPurge(ctx, userID, purgeAt); err != nil {
+		return time.Time{}, err
+	}
+
+	return purgeAt, nil
+}
+
+// CancelAccountDeletion keeps the user's account after all. It only works
+// during the grace period; [ErrNoAccountDeletion] is returned if the account
+// isn't scheduled to be deleted.
+func (s *AccountDeletionService) CancelAccountDeletion(ctx context.Context, userID ID) error {
+	return s.users.CancelUserPurge(ctx, userID)
+}
+
+// purge tells the listeners about the user's account and then deletes it.
+func (s *AccountDeletionService) purge(ctx context.Context, userID ID) error {
+	for _, l := range s.listeners {
+		if err := l.AccountPurging(ctx, userID); err != nil {
+			s.logger.WarnContext(ctx,
+				"AccountDeletionService: error preparing to purge account",
+				"user_id", userID,
+				"err", err)
+		}
+	}
+
+	if err := s.users.DeleteUser(ctx, userID); err != nil {
+		return fmt.Errorf("cannot delete user: %w", err)
+	}
+
+	s.logger.InfoContext(ctx,
+		"AccountDeletionService: purged account",
+		"user_id", userID)
+
+	return nil
+}
+
+func (s *AccountDeletionService) run(ctx context.Context) {
+	ticker := time.NewTicker(accountPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.purgeDue(ctx); err != nil {
+			s.logger.Error(
+				"AccountDeletionService: error purging accounts",
+				"err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// keep running
+		}
+	}
+}
+
+// purgeDue purges every account whose grace period is over.
+func (s *AccountDeletionService) purgeDue(ctx context.Context) error {
+	userIDs, err := s.users.UsersDueForPurge(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot get accounts due for purging: %w", err)
+	}
+
+	var errs []error
+	for _, userID := range userIDs {
+		if err := s.purge(ctx, userID); err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", userID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,112 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/neilotoole/slogt"
+)
+
+type fakePurgeListener struct {
+	purging []ID
+}
+
+func (l *fakePurgeListener) AccountPurging(ctx context.Context, userID ID) error {
+	l.purging = append(l.purging, userID)
+	return nil
+}
+
+func TestAccountDeletionService(t *testing.T) {
+	ctx := context.Background()
+	userID := ID(1)
+	otherID := ID(2)
+
+	newService := func(t *testing.T, gracePeriod time.Duration) (*AccountDeletionService, *mockUserService, *fakePurgeListener) {
+		m := newMockUserService(t)
+		m.users.UserIDBySecretFunc = func(ctx context.Context, secretHash []byte) (ID, error) {
+			switch {
+			case bytes.Equal(secretHash, m.hasher.hashSecret("MINE")):
+				return userID, nil
+			case bytes.Equal(secretHash, m.hasher.hashSecret("THEIRS")):
+				return otherID, nil
+			default:
+				return 0, ErrUnknownUser
+			}
+		}
+
+		listener := &fakePurgeListener{}
+		s := &AccountDeletionService{
+			users:       m.users,
+			hasher:      m.hasher,
+			gracePeriod: gracePeriod,
+			listeners:   []AccountPurgeListener{listener},
+			logger:      slogt.New(t),
+		}
+		return s, m, listener
+	}
+
+	t.Run("incorrect secret", func(t *testing.T) {
+		s, m, _ := newService(t, 0)
+
+		_, err := s.DeleteAccount(ctx, userID, "WRONG")
+		assert.IsError(t, err, ErrIncorrectSecret)
+
+		// Another user's secret doesn't confirm it either.
+		_, err = s.DeleteAccount(ctx, userID, "THEIRS")
+		assert.IsError(t, err, ErrIncorrectSecret)
+
+		assert.Equal(t, 0, len(m.users.DeleteUserCalls()))
+	})
+
+	t.Run("grace period", func(t *testing.T) {
+		s, m, listener := newService(t, 24*time.Hour)
+		m.users.ScheduleUserPurgeFunc = func(ctx context.Context, userID ID, purgeAt time.Time) error {
+			return nil
+		}
+
+		purgeAt, err := s.DeleteAccount(ctx, userID, "MINE")
+		assert.NoError(t, err)
+		assert.True(t, purgeAt.After(time.Now().Add(23*time.Hour)))
+
+		calls := m.users.ScheduleUserPurgeCalls()
+		assert.Equal(t, 1, len(calls))
+		assert.Equal(t, userID, calls[0].UserID)
+		assert.Equal(t, purgeAt, calls[0].PurgeAt)
+
+		// Nothing is purged until the grace period is over.
+		assert.Equal(t, 0, len(m.users.DeleteUserCalls()))
+		assert.Zero(t, listener.purging)
+	})
+
+	t.Run("immediate", func(t *testing.T) {
+		s, m, listener := newService(t, 0)
+		m.users.DeleteUserFunc = func(ctx context.Context, id ID) error {
+			// The listeners must see the user before they're gone.
+			assert.Equal(t, []ID{userID}, listener.purging)
+			return nil
+		}
+
+		_, err := s.DeleteAccount(ctx, userID, "MINE")
+		assert.NoError(t, err)
+
+		assert.Equal(t, 1, len(m.users.DeleteUserCalls()))
+		assert.Equal(t, 0, len(m.users.ScheduleUserPurgeCalls()))
+	})
+
+	t.Run("purge due", func(t *testing.T) {
+		s, m, listener := newService(t, 24*time.Hour)
+		m.users.UsersDueForPurgeFunc = func(ctx context.Context) ([]ID, error) {
+			return []ID{userID, otherID}, nil
+		}
+		m.users.DeleteUserFunc = func(ctx context.Context, id ID) error {
+			return nil
+		}
+
+		assert.NoError(t, s.purgeDue(ctx))
+		assert.Equal(t, []ID{userID, otherID}, listener.purging)
+		assert.Equal(t, 2, len(m.users.DeleteUserCalls()))
+	})
+}
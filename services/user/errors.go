@@ -2,6 +2,7 @@ package user
 
 import (
 	"errors"
+	"fmt"
 
 	"e2clicker.app/internal/publicerrors"
 )
@@ -12,6 +13,21 @@ func init() {
 		ErrUnknownUser,
 		ErrPasswordTooShort,
 		ErrInvalidSession,
+		ErrPasskeysUnavailable,
+		ErrInvalidPasskey,
+		ErrUnknownPasskey,
+		ErrInvalidScope,
+		ErrPersonalTokenExpiry,
+		ErrUnknownPersonalToken,
+		ErrInvalidDelegationAccess,
+		ErrUnknownDelegation,
+		ErrDelegationExists,
+		ErrNotDelegated,
+		ErrIncorrectSecret,
+		ErrNoAccountDeletion,
+		ErrInvalidName,
+		ErrInvalidLocale,
+		ErrUnknownTimezone,
 	)
 }
 
@@ -27,3 +43,64 @@ var ErrPasswordTooShort = errors.New("password too short")
 // ErrInvalidSession is returned when the session is invalid, either because it
 // is unknown or expired.
 var ErrInvalidSession = errors.New("invalid session")
+
+// ErrPasskeysUnavailable is returned when passkeys are used but the server has
+// no WebAuthn configuration.
+var ErrPasskeysUnavailable = errors.New("passkeys are not available on this server")
+
+// ErrInvalidPasskey is returned when a passkey could not be added or logged in
+// with, either because the ceremony expired or the authenticator's response
+// could not be verified.
+var ErrInvalidPasskey = errors.New("invalid passkey")
+
+// ErrUnknownPasskey is returned when the user has no passkey with the given
+// ID.
+var ErrUnknownPasskey = errors.New("unknown passkey")
+
+// ErrInvalidScope is returned when a personal access token is created with an
+// unknown scope or without any scopes.
+var ErrInvalidScope = errors.New("invalid scope")
+
+// ErrPersonalTokenExpiry is returned when a personal access token is created
+// with an expiry time that has already passed.
+var ErrPersonalTokenExpiry = errors.New("personal access token would already be expired")
+
+// ErrUnknownPersonalToken is returned when the user has no personal access
+// token with the given ID.
+var ErrUnknownPersonalToken = errors.New("unknown personal access token")
+
+// ErrInvalidDelegationAccess is returned when a delegation is given an unknown
+// access.
+var ErrInvalidDelegationAccess = errors.New("invalid delegation access")
+
+// ErrUnknownDelegation is returned when a delegation or its invite doesn't
+// exist or has expired.
+var ErrUnknownDelegation = errors.New("unknown delegation")
+
+// ErrDelegationExists is returned when a user accepts an invite from an owner
+// whose delegate they already are.
+var ErrDelegationExists = errors.New("already a delegate of this user")
+
+// ErrNotDelegated is returned when a user acts on another user's data without
+// a delegation that allows it.
+var ErrNotDelegated = errors.New("no delegated access to this user's data")
+
+// ErrIncorrectSecret is returned when the user confirms an action with a
+// secret that isn't theirs.
+var ErrIncorrectSecret = errors.New("incorrect secret")
+
+// ErrNoAccountDeletion is returned when the user cancels the deletion of
+// their account but it isn't scheduled to be deleted.
+var ErrNoAccountDeletion = errors.New("account is not scheduled for deletion")
+
+// ErrInvalidName is returned when the user's name is empty or longer than
+// [MaxNameLength].
+var ErrInvalidName = fmt.Errorf("name must be between 1 and %d characters long", MaxNameLength)
+
+// ErrInvalidLocale is returned when the user's locale can't be parsed as a
+// list of languages.
+var ErrInvalidLocale = errors.New("invalid locale")
+
+// ErrUnknownTimezone is returned when the user's time zone is not a known IANA
+// time zone.
+var ErrUnknownTimezone = errors.New("unknown time zone")
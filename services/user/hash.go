@@ -0,0 +1,113 @@
+package user
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
+)
+
+// minPepperSize is the minimum size of the pepper in bytes.
+const minPepperSize = 16
+
+// secretHasher hashes user secrets and session tokens before they are stored,
+// so that a copy of the database cannot be used to log in. The hashes are
+// HMACs keyed with a pepper that is only in the server's configuration.
+type secretHasher struct {
+	pepper []byte
+	// previous are the peppers that hashes may still have been made with,
+	// newest first. A nil pepper means that the hash was not keyed.
+	previous [][]byte
+}
+
+// newSecretHasher loads the pepper and the previous peppers from the config.
+// The hashes are not keyed if no pepper is configured. If one is, hashes that
+// were made before it was configured are only accepted if the config says so.
+func newSecretHasher(config e2clickermodule.API) (secretHasher, error) {
+	if config.PepperFile == nil {
+		if len(config.PreviousPepperFiles) > 0 {
+			return secretHasher{}, errors.New("previous peppers are configured without a pepper")
+		}
+		return secretHasher{}, nil
+	}
+
+	pepper, err := readPepperFile(*config.PepperFile)
+	if err != nil {
+		return secretHasher{}, err
+	}
+
+	h := secretHasher{pepper: pepper}
+	for _, path := range config.PreviousPepperFiles {
+		previous, err := readPepperFile(path)
+		if err != nil {
+			return secretHasher{}, err
+		}
+		h.previous = append(h.previous, previous)
+	}
+	if config.AcceptUnpepperedHashes {
+		h.previous = append(h.previous, nil)
+	}
+
+	return h, nil
+}
+
+func readPepperFile(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read pepper file at %s: %w", path, err)
+	}
+
+	pepper, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(b)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pepper at %s: %w", path, err)
+	}
+
+	if len(pepper) < minPepperSize {
+		return nil, fmt.Errorf("pepper at %s is too short: need at least %d bytes", path, minPepperSize)
+	}
+
+	return pepper, nil
+}
+
+// hash returns the keyed hash of b.
+func (h secretHasher) hash(b []byte) []byte {
+	return hashWithPepper(h.pepper, b)
+}
+
+// hashSecret returns the hash of a user secret.
+func (h secretHasher) hashSecret(secret Secret) []byte {
+	return h.hash([]byte(secret))
+}
+
+func hashWithPepper(pepper, b []byte) []byte {
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write(b)
+	return mac.Sum(nil)
+}
+
+// lookupHash calls find with the hash of b, and if that fails, with its hash
+// under each previous pepper in turn until one succeeds. It returns what find
+// returned along with the hash that it succeeded with, which callers should
+// replace with the current hash if it differs. If find never succeeds, its
+// error for the current hash is returned.
+func lookupHash[T any](h secretHasher, b []byte, find func(hash []byte) (T, error)) (T, []byte, error) {
+	hash := h.hash(b)
+	v, err := find(hash)
+	if err == nil {
+		return v, hash, nil
+	}
+
+	for _, pepper := range h.previous {
+		previousHash := hashWithPepper(pepper, b)
+		if pv, perr := find(previousHash); perr == nil {
+			return pv, previousHash, nil
+		}
+	}
+
+	return v, nil, err
+}
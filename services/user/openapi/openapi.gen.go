@@ -4,14 +4,135 @@
 package openapi
 
 import (
+	"encoding/json"
 	"time"
 
 	"e2clicker.app/services/user"
 )
 
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for DelegationRole.
+const (
+	Delegate DelegationRole = "delegate"
+	Owner    DelegationRole = "owner"
+)
+
+// Defines values for ExportTakeoutParamsAccept.
+const (
+	ExportTakeoutParamsAcceptApplicationXTar ExportTakeoutParamsAccept = "application/x-tar"
+	ExportTakeoutParamsAcceptApplicationZip  ExportTakeoutParamsAccept = "application/zip"
+)
+
+// Defines values for ImportTakeoutParamsContentType.
+const (
+	ImportTakeoutParamsContentTypeApplicationXTar ImportTakeoutParamsContentType = "application/x-tar"
+	ImportTakeoutParamsContentTypeApplicationZip  ImportTakeoutParamsContentType = "application/zip"
+)
+
+// AccountDeletion The deletion of a user's account.
+type AccountDeletion struct {
+	// PurgeAt The time the account is purged. If the server has no grace period, the account was already purged and this is the current time.
+	PurgeAt time.Time `json:"purgeAt"`
+}
+
+// Delegation Access that one user, the owner, gave another user, the delegate, to their data.
+type Delegation struct {
+	// ID The delegation identifier
+	ID int64 `json:"id"`
+
+	// OwnerID The ID of the owner, which the delegate puts in the `X-On-Behalf-Of` header
+	OwnerID int64 `json:"ownerID"`
+
+	// OwnerName The name of the owner
+	OwnerName string `json:"ownerName"`
+
+	// DelegateName The name of the delegate, or null if the invite wasn't accepted yet
+	DelegateName *string `json:"delegateName,omitempty"`
+
+	// MirrorNotifications Whether the owner's reminders and digests are also sent to the delegate
+	MirrorNotifications bool `json:"mirrorNotifications"`
+
+	// CreatedAt The time the invite was created
+	CreatedAt time.Time `json:"createdAt"`
+
+	// InviteExpiresAt The time the invite expires, or null if it was accepted
+	InviteExpiresAt *time.Time `json:"inviteExpiresAt,omitempty"`
+
+	// AcceptedAt The time the invite was accepted, or null if it wasn't yet
+	AcceptedAt *time.Time `json:"acceptedAt,omitempty"`
+
+	// Access What a delegate may do with the owner's data:
+	// - `read`: see the dosage and dose history - `record`: also record, edit and forget doses
+	Access DelegationAccess `json:"access"`
+
+	// Role Whether the current user is the owner or the delegate of a delegation.
+	Role DelegationRole `json:"role"`
+}
+
+// DelegationAccess What a delegate may do with the owner's data:
+// - `read`: see the dosage and dose history - `record`: also record, edit and forget doses
+type DelegationAccess = user.DelegationAccess
+
+// DelegationRole Whether the current user is the owner or the delegate of a delegation.
+type DelegationRole string
+
 // Locale A locale identifier.
 type Locale = user.Locale
 
+// Passkey A passkey that a user can log in with instead of their secret.
+type Passkey struct {
+	// ID The passkey identifier
+	ID int64 `json:"id"`
+
+	// Name The name of the passkey
+	Name string `json:"name"`
+
+	// CreatedAt The time the passkey was added
+	CreatedAt time.Time `json:"createdAt"`
+
+	// LastUsed The last time the passkey was used to log in, or null if it was never used
+	LastUsed *time.Time `json:"lastUsed,omitempty"`
+}
+
+// PasskeyChallenge The start of a WebAuthn ceremony.
+type PasskeyChallenge struct {
+	// CeremonyID The ceremony identifier, which must be sent back to finish the ceremony
+	CeremonyID string `json:"ceremonyID"`
+
+	// Options The options to give to `navigator.credentials.create()` or `navigator.credentials.get()`, which contain a `publicKey` object
+	Options json.RawMessage `json:"options"`
+}
+
+// PersonalToken A long-lived token that can be used instead of a session for the operations that its scopes allow.
+type PersonalToken struct {
+	// ID The token identifier
+	ID int64 `json:"id"`
+
+	// Name The name of the token
+	Name string `json:"name"`
+
+	// Scopes The scopes that the token may be used for
+	Scopes []Scope `json:"scopes"`
+
+	// CreatedAt The time the token was created
+	CreatedAt time.Time `json:"createdAt"`
+
+	// LastUsed The last time the token was used, or null if it was never used
+	LastUsed *time.Time `json:"lastUsed,omitempty"`
+
+	// ExpiresAt The time the token expires, or null if it never expires
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// RecoveryCode A single-use code that a user can log in with if they lost their secret. Spaces and dashes in it are ignored.
+type RecoveryCode = user.RecoveryCode
+
+// Scope A scope that a personal access token may be used for.
+type Scope = user.Scope
+
 // Session A session for a user.
 type Session struct {
 	// ID The session identifier
@@ -25,6 +146,24 @@ type Session struct {
 
 	// ExpiresAt The time the session expires, or null if it never expires
 	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+
+	// Current Whether this is the session that made the request
+	Current bool `json:"current"`
+}
+
+// TakeoutImportResult The result of restoring an account export.
+type TakeoutImportResult struct {
+	// Doses The number of doses that were restored.
+	Doses int `json:"doses"`
+
+	// ExportedAt The time the export was made.
+	ExportedAt time.Time `json:"exportedAt"`
+
+	// SkippedNotificationConfigs The number of notification configs that couldn't be restored on this server.
+	SkippedNotificationConfigs int `json:"skippedNotificationConfigs"`
+
+	// Version The version of the archive format that the export was made with.
+	Version int `json:"version"`
 }
 
 // User A user of the system.
@@ -34,14 +173,35 @@ type User struct {
 
 	// Locale A locale identifier.
 	Locale Locale `json:"locale"`
+
+	// PurgeAt The time the user's account is deleted, if the user asked for it to be
+	PurgeAt *time.Time `json:"purgeAt,omitempty"`
+
+	// Timezone The IANA time zone of the user, such as `Europe/Paris`. Times in notifications are shown in it. If empty, UTC is used.
+	Timezone *string `json:"timezone,omitempty"`
 }
 
-// UserSecret A secret and unique user identifier. This secret is generated once and never changes. It is used to both authenticate and identify a user, so it should be kept secret.
+// UserSecret A secret and unique user identifier. This secret is generated when registering and only changes when the user rotates it. It is used to authenticate a user, so it should be kept secret. The server only stores a hash of it, so it is only ever returned when registering or rotating it.
 type UserSecret = user.Secret
 
+// UserUpdate A change to a user's profile. Fields that are not given are left as they are.
+type UserUpdate struct {
+	// Name The user's new name. Surrounding whitespace is trimmed, and it must be between 1 and 64 characters long.
+	Name *string `json:"name,omitempty"`
+
+	// Locale A locale identifier.
+	Locale *Locale `json:"locale,omitempty"`
+
+	// Timezone The user's new IANA time zone, such as `Europe/Paris`. An empty string resets it to UTC. This is the same time zone as the one in the notification preferences.
+	Timezone *string `json:"timezone,omitempty"`
+}
+
+// WebAuthnCredential The `PublicKeyCredential` that the browser returned, encoded as JSON with `toJSON()`.
+type WebAuthnCredential = json.RawMessage
+
 // AuthJSONBody defines parameters for Auth.
 type AuthJSONBody struct {
-	// Secret A secret and unique user identifier. This secret is generated once and never changes. It is used to both authenticate and identify a user, so it should be kept secret.
+	// Secret A secret and unique user identifier. This secret is generated when registering and only changes when the user rotates it. It is used to authenticate a user, so it should be kept secret. The server only stores a hash of it, so it is only ever returned when registering or rotating it.
 	Secret UserSecret `json:"secret"`
 }
 
@@ -51,11 +211,120 @@ type AuthParams struct {
 	UserAgent *string `json:"User-Agent,omitempty"`
 }
 
+// FinishPasskeyLoginJSONBody defines parameters for FinishPasskeyLogin.
+type FinishPasskeyLoginJSONBody struct {
+	// CeremonyID The ceremony ID from `/auth/passkey/begin`
+	CeremonyID string `json:"ceremonyID"`
+
+	// Credential The `PublicKeyCredential` that the browser returned, encoded as JSON with `toJSON()`.
+	Credential WebAuthnCredential `json:"credential"`
+}
+
+// FinishPasskeyLoginParams defines parameters for FinishPasskeyLogin.
+type FinishPasskeyLoginParams struct {
+	// UserAgent The user agent of the client making the request.
+	UserAgent *string `json:"User-Agent,omitempty"`
+}
+
+// RecoveryAuthJSONBody defines parameters for RecoveryAuth.
+type RecoveryAuthJSONBody struct {
+	// Code A single-use code that a user can log in with if they lost their secret. Spaces and dashes in it are ignored.
+	Code RecoveryCode `json:"code"`
+}
+
+// RecoveryAuthParams defines parameters for RecoveryAuth.
+type RecoveryAuthParams struct {
+	// UserAgent The user agent of the client making the request.
+	UserAgent *string `json:"User-Agent,omitempty"`
+}
+
+// DeleteCurrentUserJSONBody defines parameters for DeleteCurrentUser.
+type DeleteCurrentUserJSONBody struct {
+	// Secret A secret and unique user identifier. This secret is generated when registering and only changes when the user rotates it. It is used to authenticate a user, so it should be kept secret. The server only stores a hash of it, so it is only ever returned when registering or rotating it.
+	Secret UserSecret `json:"secret"`
+}
+
+// DeleteDelegationParams defines parameters for DeleteDelegation.
+type DeleteDelegationParams struct {
+	ID int64 `form:"id" json:"id"`
+}
+
+// InviteDelegateJSONBody defines parameters for InviteDelegate.
+type InviteDelegateJSONBody struct {
+	// Access What a delegate may do with the owner's data:
+	// - `read`: see the dosage and dose history - `record`: also record, edit and forget doses
+	Access DelegationAccess `json:"access"`
+}
+
+// AcceptDelegationJSONBody defines parameters for AcceptDelegation.
+type AcceptDelegationJSONBody struct {
+	// Code The invite code that the owner gave the user
+	Code string `json:"code"`
+
+	// MirrorNotifications Whether the user also gets the owner's reminders and digests
+	MirrorNotifications *bool `json:"mirrorNotifications,omitempty"`
+}
+
+// DeleteUserPasskeyParams defines parameters for DeleteUserPasskey.
+type DeleteUserPasskeyParams struct {
+	ID int64 `form:"id" json:"id"`
+}
+
+// FinishPasskeyRegistrationJSONBody defines parameters for FinishPasskeyRegistration.
+type FinishPasskeyRegistrationJSONBody struct {
+	// CeremonyID The ceremony ID from `/me/passkeys/begin`
+	CeremonyID string `json:"ceremonyID"`
+
+	// Credential The `PublicKeyCredential` that the browser returned, encoded as JSON with `toJSON()`.
+	Credential WebAuthnCredential `json:"credential"`
+
+	// Name A name for the passkey, e.g. the device it is on
+	Name *string `json:"name,omitempty"`
+}
+
 // DeleteUserSessionParams defines parameters for DeleteUserSession.
 type DeleteUserSessionParams struct {
 	ID int64 `form:"id" json:"id"`
 }
 
+// ExportTakeoutParams defines parameters for ExportTakeout.
+type ExportTakeoutParams struct {
+	// IncludeCredentials Include the credentials of the user's notification configs instead of redacting them. Configs with redacted credentials are skipped when the archive is restored.
+	IncludeCredentials *bool `form:"includeCredentials,omitempty" json:"includeCredentials,omitempty"`
+
+	// Accept The archive format to export the account in.
+	Accept ExportTakeoutParamsAccept `json:"Accept"`
+}
+
+// ExportTakeoutParamsAccept defines parameters for ExportTakeout.
+type ExportTakeoutParamsAccept string
+
+// ImportTakeoutParams defines parameters for ImportTakeout.
+type ImportTakeoutParams struct {
+	// ContentType The archive format of the export.
+	ContentType ImportTakeoutParamsContentType `json:"Content-Type"`
+}
+
+// ImportTakeoutParamsContentType defines parameters for ImportTakeout.
+type ImportTakeoutParamsContentType string
+
+// DeleteUserTokenParams defines parameters for DeleteUserToken.
+type DeleteUserTokenParams struct {
+	ID int64 `form:"id" json:"id"`
+}
+
+// CreateUserTokenJSONBody defines parameters for CreateUserToken.
+type CreateUserTokenJSONBody struct {
+	// ExpiresAt The time the token expires. If not given, the token never expires.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// Name A name for the token, e.g. what it is used for
+	Name *string `json:"name,omitempty"`
+
+	// Scopes The scopes that the token may be used for
+	Scopes []Scope `json:"scopes"`
+}
+
 // RegisterJSONBody defines parameters for Register.
 type RegisterJSONBody struct {
 	// Name The name to register with
@@ -65,5 +334,29 @@ type RegisterJSONBody struct {
 // AuthJSONRequestBody defines body for Auth for application/json ContentType.
 type AuthJSONRequestBody AuthJSONBody
 
+// FinishPasskeyLoginJSONRequestBody defines body for FinishPasskeyLogin for application/json ContentType.
+type FinishPasskeyLoginJSONRequestBody FinishPasskeyLoginJSONBody
+
+// RecoveryAuthJSONRequestBody defines body for RecoveryAuth for application/json ContentType.
+type RecoveryAuthJSONRequestBody RecoveryAuthJSONBody
+
+// DeleteCurrentUserJSONRequestBody defines body for DeleteCurrentUser for application/json ContentType.
+type DeleteCurrentUserJSONRequestBody DeleteCurrentUserJSONBody
+
+// UpdateCurrentUserJSONRequestBody defines body for UpdateCurrentUser for application/json ContentType.
+type UpdateCurrentUserJSONRequestBody = UserUpdate
+
+// InviteDelegateJSONRequestBody defines body for InviteDelegate for application/json ContentType.
+type InviteDelegateJSONRequestBody InviteDelegateJSONBody
+
+// AcceptDelegationJSONRequestBody defines body for AcceptDelegation for application/json ContentType.
+type AcceptDelegationJSONRequestBody AcceptDelegationJSONBody
+
+// FinishPasskeyRegistrationJSONRequestBody defines body for FinishPasskeyRegistration for application/json ContentType.
+type FinishPasskeyRegistrationJSONRequestBody FinishPasskeyRegistrationJSONBody
+
+// CreateUserTokenJSONRequestBody defines body for CreateUserToken for application/json ContentType.
+type CreateUserTokenJSONRequestBody CreateUserTokenJSONBody
+
 // RegisterJSONRequestBody defines body for Register for application/json ContentType.
 type RegisterJSONRequestBody RegisterJSONBody
@@ -0,0 +1,333 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
+)
+
+// passkeyCeremonyTimeout is how long a user has to finish adding a passkey or
+// logging in with one.
+const passkeyCeremonyTimeout = 5 * time.Minute
+
+type PasskeyStorage interface {
+	// AddPasskey adds a passkey with the given WebAuthn credential to the user.
+	AddPasskey(ctx context.Context, userID ID, name string, credential webauthn.Credential) (Passkey, error)
+	// Passkeys lists all passkeys of a user.
+	Passkeys(ctx context.Context, userID ID) ([]Passkey, error)
+	// UpdatePasskeyCredential replaces the stored credential of a passkey after
+	// it was used to log in, since the authenticator's state such as its sign
+	// count changes.
+	UpdatePasskeyCredential(ctx context.Context, userID ID, credential webauthn.Credential) error
+	// DeletePasskey deletes a passkey of a user. [ErrUnknownPasskey] is
+	// returned if the user has no such passkey.
+	DeletePasskey(ctx context.Context, userID ID, passkeyID int64) error
+}
+
+// Passkey is a WebAuthn credential that a user can log in with instead of
+// their secret.
+type Passkey struct {
+	// ID uniquely identifies the passkey.
+	ID int64
+	// Name is the name that the user gave the passkey.
+	Name string
+	// Credential is the WebAuthn credential of the passkey.
+	Credential webauthn.Credential
+	// CreatedAt is the time that the passkey was added.
+	CreatedAt time.Time
+	// LastUsed is the time that the passkey was last used to log in.
+	// If zero, the passkey was never used.
+	LastUsed time.Time
+}
+
+// PasskeyChallenge is the start of a passkey ceremony. The options are given
+// to the browser's WebAuthn API, and the ceremony ID must be sent back along
+// with the browser's response.
+type PasskeyChallenge struct {
+	// CeremonyID identifies the ceremony.
+	CeremonyID string
+	// Options are the WebAuthn options in JSON, which are either
+	// PublicKeyCredentialCreationOptions or PublicKeyCredentialRequestOptions
+	// wrapped in a "publicKey" object.
+	Options json.RawMessage
+}
+
+func newWebAuthn(config e2clickermodule.API) (*webauthn.WebAuthn, error) {
+	if config.WebAuthn == nil {
+		return nil, nil
+	}
+
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    passkeyCeremonyTimeout,
+		TimeoutUVD: passkeyCeremonyTimeout,
+	}
+
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          config.WebAuthn.RelyingPartyID,
+		RPDisplayName: config.WebAuthn.RelyingPartyName,
+		RPOrigins:     config.WebAuthn.Origins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid WebAuthn config: %w", err)
+	}
+
+	return w, nil
+}
+
+// BeginPasskeyRegistration starts adding a passkey to the user.
+// [ErrPasskeysUnavailable] is returned if passkeys are not configured.
+func (s UserService) BeginPasskeyRegistration(ctx context.Context, userID ID) (PasskeyChallenge, error) {
+	if s.webAuthn == nil {
+		return PasskeyChallenge{}, ErrPasskeysUnavailable
+	}
+
+	u, err := s.webAuthnUser(ctx, userID)
+	if err != nil {
+		return PasskeyChallenge{}, err
+	}
+
+	creation, session, err := s.webAuthn.BeginRegistration(u,
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithExclusions(webauthn.Credentials(u.WebAuthnCredentials()).CredentialDescriptors()),
+	)
+	if err != nil {
+		return PasskeyChallenge{}, fmt.Errorf("cannot begin passkey registration: %w", err)
+	}
+
+	return s.passkeyCeremonies.begin(*session, creation)
+}
+
+// FinishPasskeyRegistration finishes adding a passkey to the user using the
+// browser's response to the challenge from [BeginPasskeyRegistration].
+func (s UserService) FinishPasskeyRegistration(ctx context.Context, userID ID, ceremonyID string, name string, response json.RawMessage) (Passkey, error) {
+	if s.webAuthn == nil {
+		return Passkey{}, ErrPasskeysUnavailable
+	}
+
+	session, ok := s.passkeyCeremonies.finish(ceremonyID)
+	if !ok {
+		return Passkey{}, ErrInvalidPasskey
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return Passkey{}, ErrInvalidPasskey
+	}
+
+	u, err := s.webAuthnUser(ctx, userID)
+	if err != nil {
+		return Passkey{}, err
+	}
+
+	credential, err := s.webAuthn.CreateCredential(u, session, parsed)
+	if err != nil {
+		return Passkey{}, ErrInvalidPasskey
+	}
+
+	if name == "" {
+		name = "Passkey"
+	}
+
+	return s.passkeys.AddPasskey(ctx, userID, name, *credential)
+}
+
+// Passkeys lists the passkeys of the user.
+func (s UserService) Passkeys(ctx context.Context, userID ID) ([]Passkey, error) {
+	return s.passkeys.Passkeys(ctx, userID)
+}
+
+// DeletePasskey deletes a passkey of the user. The user can still log in with
+// their secret.
+func (s UserService) DeletePasskey(ctx context.Context, userID ID, passkeyID int64) error {
+	return s.passkeys.DeletePasskey(ctx, userID, passkeyID)
+}
+
+// BeginPasskeyLogin starts logging in with a passkey. The user isn't known
+// until the browser responds, since passkeys are discoverable.
+func (s UserService) BeginPasskeyLogin(ctx context.Context) (PasskeyChallenge, error) {
+	if s.webAuthn == nil {
+		return PasskeyChallenge{}, ErrPasskeysUnavailable
+	}
+
+	assertion, session, err := s.webAuthn.BeginDiscoverableLogin()
+	if err != nil {
+		return PasskeyChallenge{}, fmt.Errorf("cannot begin passkey login: %w", err)
+	}
+
+	return s.passkeyCeremonies.begin(*session, assertion)
+}
+
+// CreateSessionFromPasskey logs in the user who owns the passkey that signed
+// the browser's response to the challenge from [BeginPasskeyLogin], and
+// returns the token of their new session.
+func (s UserService) CreateSessionFromPasskey(ctx context.Context, ceremonyID string, response json.RawMessage, userAgent string) (SessionToken, error) {
+	if s.webAuthn == nil {
+		return "", ErrPasskeysUnavailable
+	}
+
+	session, ok := s.passkeyCeremonies.finish(ceremonyID)
+	if !ok {
+		return "", ErrInvalidPasskey
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return "", ErrInvalidPasskey
+	}
+
+	user, credential, err := s.webAuthn.ValidatePasskeyLogin(
+		func(rawID, userHandle []byte) (webauthn.User, error) {
+			userID, ok := userIDFromWebAuthnHandle(userHandle)
+			if !ok {
+				return nil, ErrUnknownUser
+			}
+			return s.webAuthnUser(ctx, userID)
+		},
+		session, parsed)
+	if err != nil {
+		return "", ErrInvalidPasskey
+	}
+
+	if credential.Authenticator.CloneWarning {
+		// The sign count went backwards, so the passkey may have been cloned.
+		return "", ErrInvalidPasskey
+	}
+
+	userID := user.(webAuthnUser).ID
+	if err := s.passkeys.UpdatePasskeyCredential(ctx, userID, *credential); err != nil {
+		return "", fmt.Errorf("cannot update passkey: %w", err)
+	}
+
+	return s.createSession(ctx, userID, userAgent)
+}
+
+func (s UserService) webAuthnUser(ctx context.Context, userID ID) (webAuthnUser, error) {
+	u, err := s.users.User(ctx, userID)
+	if err != nil {
+		return webAuthnUser{}, err
+	}
+
+	passkeys, err := s.passkeys.Passkeys(ctx, userID)
+	if err != nil {
+		return webAuthnUser{}, err
+	}
+
+	return webAuthnUser{u, passkeys}, nil
+}
+
+// webAuthnUser is a user with their passkeys. It implements [webauthn.User].
+type webAuthnUser struct {
+	User
+	passkeys []Passkey
+}
+
+var _ webauthn.User = webAuthnUser{}
+
+// WebAuthnID returns the user handle, which is the user's ID. The user's secret
+// must never be used here, since authenticators don't keep it secret.
+func (u webAuthnUser) WebAuthnID() []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(u.ID))
+}
+
+func (u webAuthnUser) WebAuthnName() string {
+	return u.Name
+}
+
+func (u webAuthnUser) WebAuthnDisplayName() string {
+	return u.Name
+}
+
+func (u webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, len(u.passkeys))
+	for i, p := range u.passkeys {
+		credentials[i] = p.Credential
+	}
+	return credentials
+}
+
+func userIDFromWebAuthnHandle(handle []byte) (ID, bool) {
+	if len(handle) != 8 {
+		return 0, false
+	}
+	return ID(binary.BigEndian.Uint64(handle)), true
+}
+
+// passkeyCeremonies keeps the state of passkey ceremonies that were begun but
+// not yet finished. Ceremonies only last a few minutes, so they are only kept
+// in memory.
+type passkeyCeremonies struct {
+	mu       sync.Mutex
+	sessions map[string]webauthn.SessionData
+}
+
+func newPasskeyCeremonies() *passkeyCeremonies {
+	return &passkeyCeremonies{
+		sessions: make(map[string]webauthn.SessionData),
+	}
+}
+
+// begin stores the session of a new ceremony and returns its challenge.
+func (c *passkeyCeremonies) begin(session webauthn.SessionData, options any) (PasskeyChallenge, error) {
+	b, err := json.Marshal(options)
+	if err != nil {
+		return PasskeyChallenge{}, fmt.Errorf("cannot marshal WebAuthn options: %w", err)
+	}
+
+	var rawID [16]byte
+	if _, err := rand.Read(rawID[:]); err != nil {
+		return PasskeyChallenge{}, fmt.Errorf("cannot generate ceremony ID: %w", err)
+	}
+	id := base64.RawURLEncoding.EncodeToString(rawID[:])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for id, s := range c.sessions {
+		if s.Expires.Before(now) {
+			delete(c.sessions, id)
+		}
+	}
+
+	c.sessions[id] = session
+
+	return PasskeyChallenge{
+		CeremonyID: id,
+		Options:    b,
+	}, nil
+}
+
+// finish removes the session of a ceremony and returns it. A ceremony can
+// only be finished once. False is returned if the ceremony is unknown or has
+// expired.
+func (c *passkeyCeremonies) finish(id string) (webauthn.SessionData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	session, ok := c.sessions[id]
+	if !ok {
+		return webauthn.SessionData{}, false
+	}
+	delete(c.sessions, id)
+
+	if session.Expires.Before(time.Now()) {
+		return webauthn.SessionData{}, false
+	}
+
+	return session, true
+}
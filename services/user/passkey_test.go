@@ -0,0 +1,271 @@
+package user
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
+)
+
+const testPasskeyOrigin = "https://e2clicker.example"
+
+func TestUserService_Passkeys(t *testing.T) {
+	ctx := context.Background()
+	userID := ID(42)
+
+	s := newMockPasskeyUserService(t)
+	s.users.UserFunc = func(ctx context.Context, id ID) (User, error) {
+		if id != userID {
+			return User{}, ErrUnknownUser
+		}
+		return User{ID: userID, Name: "Diamond"}, nil
+	}
+
+	var passkeys []Passkey
+	s.passkeys.PasskeysFunc = func(ctx context.Context, id ID) ([]Passkey, error) {
+		assert.Equal(t, id, userID)
+		return passkeys, nil
+	}
+	s.passkeys.AddPasskeyFunc = func(ctx context.Context, id ID, name string, credential webauthn.Credential) (Passkey, error) {
+		p := Passkey{ID: int64(len(passkeys) + 1), Name: name, Credential: credential}
+		passkeys = append(passkeys, p)
+		return p, nil
+	}
+	s.passkeys.UpdatePasskeyCredentialFunc = func(ctx context.Context, id ID, credential webauthn.Credential) error {
+		assert.Equal(t, id, userID)
+		passkeys[0].Credential = credential
+		return nil
+	}
+
+	authenticator := newSoftwareAuthenticator(t)
+
+	challenge, err := s.BeginPasskeyRegistration(ctx, userID)
+	assert.NoError(t, err)
+
+	passkey, err := s.FinishPasskeyRegistration(ctx, userID,
+		challenge.CeremonyID, "Phone", authenticator.create(t, challenge))
+	assert.NoError(t, err)
+	assert.Equal(t, passkey.Name, "Phone")
+	assert.Equal(t, passkey.Credential.ID, authenticator.credentialID)
+
+	t.Run("registration is single use", func(t *testing.T) {
+		_, err := s.FinishPasskeyRegistration(ctx, userID,
+			challenge.CeremonyID, "Phone", authenticator.create(t, challenge))
+		assert.IsError(t, err, ErrInvalidPasskey)
+	})
+
+	challenge, err = s.BeginPasskeyLogin(ctx)
+	assert.NoError(t, err)
+
+	response := authenticator.get(t, challenge)
+
+	token, err := s.CreateSessionFromPasskey(ctx, challenge.CeremonyID, response, "user agent")
+	assert.NoError(t, err)
+	assert.NotZero(t, token)
+
+	register := s.sessions.RegisterSessionCalls()[0]
+	assert.Equal(t, register.UserID, userID)
+	assert.Equal(t, register.UserAgent, "user agent")
+
+	update := s.passkeys.UpdatePasskeyCredentialCalls()[0]
+	assert.Equal(t, update.Credential.Authenticator.SignCount, authenticator.signCount)
+
+	t.Run("replayed assertion", func(t *testing.T) {
+		_, err := s.CreateSessionFromPasskey(ctx, challenge.CeremonyID, response, "user agent")
+		assert.IsError(t, err, ErrInvalidPasskey)
+	})
+
+	t.Run("wrong challenge", func(t *testing.T) {
+		other, err := s.BeginPasskeyLogin(ctx)
+		assert.NoError(t, err)
+
+		_, err = s.CreateSessionFromPasskey(ctx, other.CeremonyID, authenticator.get(t, challenge), "user agent")
+		assert.IsError(t, err, ErrInvalidPasskey)
+	})
+
+	t.Run("unknown passkey", func(t *testing.T) {
+		challenge, err := s.BeginPasskeyLogin(ctx)
+		assert.NoError(t, err)
+
+		stranger := newSoftwareAuthenticator(t)
+		stranger.userHandle = authenticator.userHandle
+
+		_, err = s.CreateSessionFromPasskey(ctx, challenge.CeremonyID, stranger.get(t, challenge), "user agent")
+		assert.IsError(t, err, ErrInvalidPasskey)
+	})
+
+	assert.Equal(t, len(s.sessions.RegisterSessionCalls()), 1)
+}
+
+func TestUserService_PasskeysUnavailable(t *testing.T) {
+	ctx := context.Background()
+
+	s := newMockUserService(t)
+
+	_, err := s.BeginPasskeyLogin(ctx)
+	assert.IsError(t, err, ErrPasskeysUnavailable)
+
+	_, err = s.BeginPasskeyRegistration(ctx, 42)
+	assert.IsError(t, err, ErrPasskeysUnavailable)
+}
+
+func newMockPasskeyUserService(t *testing.T) *mockUserService {
+	s := newMockUserService(t)
+
+	w, err := newWebAuthn(e2clickermodule.API{
+		WebAuthn: &e2clickermodule.WebAuthn{
+			Origins:          []string{testPasskeyOrigin},
+			RelyingPartyID:   "e2clicker.example",
+			RelyingPartyName: "e2clicker",
+		},
+	})
+	assert.NoError(t, err)
+
+	s.webAuthn = w
+	return s
+}
+
+// softwareAuthenticator is a WebAuthn authenticator with a single ES256
+// passkey that answers challenges the way a browser would.
+type softwareAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newSoftwareAuthenticator(t *testing.T) *softwareAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	credentialID := make([]byte, 16)
+	_, err = rand.Read(credentialID)
+	assert.NoError(t, err)
+
+	return &softwareAuthenticator{
+		key:          key,
+		credentialID: credentialID,
+	}
+}
+
+type softwareAuthenticatorOptions struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		RPID      string `json:"rpId"`
+		RP        struct {
+			ID string `json:"id"`
+		} `json:"rp"`
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	} `json:"publicKey"`
+}
+
+// create answers a registration challenge with a new passkey.
+func (a *softwareAuthenticator) create(t *testing.T, challenge PasskeyChallenge) json.RawMessage {
+	var options softwareAuthenticatorOptions
+	assert.NoError(t, json.Unmarshal(challenge.Options, &options))
+
+	userHandle, err := base64.RawURLEncoding.DecodeString(options.PublicKey.User.ID)
+	assert.NoError(t, err)
+	a.userHandle = userHandle
+
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.PublicKey.X.FillBytes(x)
+	a.key.PublicKey.Y.FillBytes(y)
+
+	publicKey, err := cbor.Marshal(map[int]any{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: x,
+		-3: y,
+	})
+	assert.NoError(t, err)
+
+	// flags: user present, user verified, attested credential data
+	authData := a.authData(options.PublicKey.RP.ID, 0x45)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, publicKey...)
+
+	attestation, err := cbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	assert.NoError(t, err)
+
+	return a.response(t, map[string]string{
+		"clientDataJSON":    a.clientData(t, "webauthn.create", options.PublicKey.Challenge),
+		"attestationObject": base64.RawURLEncoding.EncodeToString(attestation),
+	})
+}
+
+// get answers a login challenge by signing it with the passkey.
+func (a *softwareAuthenticator) get(t *testing.T, challenge PasskeyChallenge) json.RawMessage {
+	var options softwareAuthenticatorOptions
+	assert.NoError(t, json.Unmarshal(challenge.Options, &options))
+
+	a.signCount++
+
+	// flags: user present, user verified
+	authData := a.authData(options.PublicKey.RPID, 0x05)
+	clientData := a.clientData(t, "webauthn.get", options.PublicKey.Challenge)
+
+	clientDataJSON, err := base64.RawURLEncoding.DecodeString(clientData)
+	assert.NoError(t, err)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	assert.NoError(t, err)
+
+	return a.response(t, map[string]string{
+		"clientDataJSON":    clientData,
+		"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+		"signature":         base64.RawURLEncoding.EncodeToString(signature),
+		"userHandle":        base64.RawURLEncoding.EncodeToString(a.userHandle),
+	})
+}
+
+func (a *softwareAuthenticator) authData(rpID string, flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	authData := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(authData, a.signCount)
+}
+
+func (a *softwareAuthenticator) clientData(t *testing.T, typ, challenge string) string {
+	b, err := json.Marshal(map[string]any{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    testPasskeyOrigin,
+	})
+	assert.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (a *softwareAuthenticator) response(t *testing.T, response map[string]string) json.RawMessage {
+	id := base64.RawURLEncoding.EncodeToString(a.credentialID)
+	b, err := json.Marshal(map[string]any{
+		"id":       id,
+		"rawId":    id,
+		"type":     "public-key",
+		"response": response,
+	})
+	assert.NoError(t, err)
+	return b
+}
@@ -12,5 +12,7 @@ var Module = fx.Module("user",
 	}),
 	fx.Provide(
 		NewUserService,
+		NewSessionCleanupService,
+		NewAccountDeletionService,
 	),
 )
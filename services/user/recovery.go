@@ -0,0 +1,103 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+)
+
+// recoveryCodeCount is the number of recovery codes that are generated at
+// once.
+const recoveryCodeCount = 10
+
+// RecoveryCode is a single-use code that a user can log in with if they lost
+// their secret. Like [Secret], only its hash is stored.
+type RecoveryCode string
+
+func generateRecoveryCode() RecoveryCode {
+	var b [10]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+
+	s := base32.
+		StdEncoding.
+		WithPadding(base32.NoPadding).
+		EncodeToString(b[:])
+
+	return RecoveryCode(s)
+}
+
+// PrettyString returns the recovery code as a pretty string, which is split
+// into groups of four characters by dashes.
+func (c RecoveryCode) PrettyString() string {
+	var b strings.Builder
+	b.Grow(len(c) + (len(c)/4 + 1))
+	for i, r := range c {
+		if i != 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// normalize removes the separators from a recovery code that a user typed in
+// and uppercases it.
+func (c RecoveryCode) normalize() RecoveryCode {
+	s := strings.NewReplacer(" ", "", "-", "").Replace(string(c))
+	return RecoveryCode(strings.ToUpper(s))
+}
+
+// RotateSecret replaces the secret of the user that the session belongs to
+// with a new one and returns it. All other sessions of the user are logged
+// out and all of their passkeys and personal access tokens are deleted, but
+// the user's data is kept.
+func (s UserService) RotateSecret(ctx context.Context, session Session) (Secret, error) {
+	secret := generateUserSecret()
+	hash := s.hasher.hashSecret(secret)
+	if err := s.users.ReplaceUserSecret(ctx, session.UserID, hash, session.ID); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// GenerateRecoveryCodes generates new recovery codes for the user, replacing
+// any that they had before. The codes are only ever returned here.
+func (s UserService) GenerateRecoveryCodes(ctx context.Context, userID ID) ([]RecoveryCode, error) {
+	codes := make([]RecoveryCode, recoveryCodeCount)
+	hashes := make([][]byte, recoveryCodeCount)
+	for i := range codes {
+		codes[i] = generateRecoveryCode()
+		hashes[i] = s.hasher.hash([]byte(codes[i]))
+	}
+
+	if err := s.users.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// RecoveryCodesRemaining returns the number of recovery codes that the user
+// has not used yet.
+func (s UserService) RecoveryCodesRemaining(ctx context.Context, userID ID) (int, error) {
+	return s.users.RecoveryCodeCount(ctx, userID)
+}
+
+// DeleteRecoveryCodes deletes all recovery codes of the user.
+func (s UserService) DeleteRecoveryCodes(ctx context.Context, userID ID) error {
+	return s.users.ReplaceRecoveryCodes(ctx, userID, nil)
+}
+
+// CreateSessionFromRecoveryCode logs in the user with the given recovery code
+// and returns the token of their new session. The recovery code cannot be
+// used again. [ErrUnknownUser] is returned if no user has the recovery code.
+func (s UserService) CreateSessionFromRecoveryCode(ctx context.Context, code RecoveryCode, userAgent string) (SessionToken, error) {
+	userID, err := s.users.UseRecoveryCode(ctx, s.hasher.hash([]byte(code.normalize())))
+	if err != nil {
+		return "", err
+	}
+	return s.createSession(ctx, userID, userAgent)
+}
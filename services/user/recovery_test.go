@@ -0,0 +1,82 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestUserService_RotateSecret(t *testing.T) {
+	ctx := context.Background()
+	session := Session{ID: 3, UserID: 42}
+
+	s := newMockUserService(t)
+	s.users.ReplaceUserSecretFunc = func(ctx context.Context, userID ID, secretHash []byte, keepSessionID int64) error {
+		return nil
+	}
+
+	secret, err := s.RotateSecret(ctx, session)
+	assert.NoError(t, err)
+	assert.NotZero(t, secret)
+
+	call := s.users.ReplaceUserSecretCalls()[0]
+	assert.Equal(t, call.UserID, session.UserID)
+	assert.Equal(t, call.SecretHash, s.hasher.hashSecret(secret))
+	assert.Equal(t, call.KeepSessionID, session.ID)
+}
+
+func TestUserService_RecoveryCodes(t *testing.T) {
+	ctx := context.Background()
+	userID := ID(42)
+
+	s := newMockUserService(t)
+
+	var stored [][]byte
+	s.users.ReplaceRecoveryCodesFunc = func(ctx context.Context, id ID, codeHashes [][]byte) error {
+		assert.Equal(t, id, userID)
+		stored = codeHashes
+		return nil
+	}
+	s.users.UseRecoveryCodeFunc = func(ctx context.Context, codeHash []byte) (ID, error) {
+		for i, h := range stored {
+			if bytes.Equal(h, codeHash) {
+				stored = append(stored[:i], stored[i+1:]...)
+				return userID, nil
+			}
+		}
+		return 0, ErrUnknownUser
+	}
+
+	codes, err := s.GenerateRecoveryCodes(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, len(codes), recoveryCodeCount)
+	assert.Equal(t, len(stored), recoveryCodeCount)
+
+	for i, code := range codes {
+		assert.False(t, bytes.Contains(stored[i], []byte(code)), "code must not be stored in plain")
+	}
+
+	t.Run("unknown code", func(t *testing.T) {
+		_, err := s.CreateSessionFromRecoveryCode(ctx, generateRecoveryCode(), "user agent")
+		assert.IsError(t, err, ErrUnknownUser)
+	})
+
+	t.Run("pretty code", func(t *testing.T) {
+		code := RecoveryCode(" " + codes[0].PrettyString() + " ")
+		token, err := s.CreateSessionFromRecoveryCode(ctx, code, "user agent")
+		assert.NoError(t, err)
+		assert.NotZero(t, token)
+	})
+
+	t.Run("single use", func(t *testing.T) {
+		_, err := s.CreateSessionFromRecoveryCode(ctx, codes[0], "user agent")
+		assert.IsError(t, err, ErrUnknownUser)
+	})
+
+	register := s.sessions.RegisterSessionCalls()
+	assert.Equal(t, len(register), 1)
+	assert.Equal(t, register[0].UserID, userID)
+	assert.Equal(t, len(stored), recoveryCodeCount-1)
+}
@@ -1,17 +1,31 @@
 package user
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
+	"slices"
+	"time"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"go.uber.org/fx"
 	"e2clicker.app/services/user/naming"
+
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
 )
 
 // UserService is a service for managing users.
 type UserService struct {
-	users        UserStorage
-	userSessions UserSessionStorage
+	users             UserStorage
+	userSessions      UserSessionStorage
+	sessionLifetime   SessionLifetime
+	passkeys          PasskeyStorage
+	personalTokens    PersonalTokenStorage
+	delegations       DelegationStorage
+	hasher            secretHasher
+	webAuthn          *webauthn.WebAuthn
+	passkeyCeremonies *passkeyCeremonies
 }
 
 // UserServiceConfig is a dependency injection container for [UserService].
@@ -20,44 +34,158 @@ type UserServiceConfig struct {
 
 	UserStorage
 	UserSessionStorage
+	PasskeyStorage
+	PersonalTokenStorage
+	DelegationStorage
+	Config    e2clickermodule.API
+	Lifecycle fx.Lifecycle
+	Logger    *slog.Logger
 }
 
 // NewUserService creates a new user service.
 func NewUserService(c UserServiceConfig) (*UserService, error) {
-	return &UserService{
+	hasher, err := newSecretHasher(c.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasher.pepper == nil {
+		c.Logger.Warn(
+			"no pepper is configured, so user secrets and session tokens are hashed without a key")
+	}
+
+	sessionLifetime, err := newSessionLifetime(c.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	webAuthn, err := newWebAuthn(c.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &UserService{
 		c.UserStorage,
 		c.UserSessionStorage,
-	}, nil
+		sessionLifetime,
+		c.PasskeyStorage,
+		c.PersonalTokenStorage,
+		c.DelegationStorage,
+		hasher,
+		webAuthn,
+		newPasskeyCeremonies(),
+	}
+
+	c.Lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			// Secrets that were stored before they were hashed can only be
+			// hashed by us, since we have the pepper. This must be done before
+			// anyone logs in.
+			n, err := s.users.HashPlainSecrets(ctx, s.hasher.hash)
+			if err != nil {
+				return fmt.Errorf("cannot hash plain user secrets: %w", err)
+			}
+			if n > 0 {
+				c.Logger.Info(
+					"hashed plain user secrets and session tokens",
+					"count", n)
+			}
+			return nil
+		},
+	})
+
+	return s, nil
 }
 
 func (s UserService) CreateUser(ctx context.Context, name string) (UserWithSecret, error) {
 	if name == "" {
 		name = naming.RandomName()
 	}
+	name, err := ParseName(name)
+	if err != nil {
+		return UserWithSecret{}, err
+	}
 	secret := generateUserSecret()
-	u, err := s.users.CreateUser(ctx, secret, name)
+	u, err := s.users.CreateUser(ctx, s.hasher.hashSecret(secret), name)
 	if err != nil {
 		return UserWithSecret{}, err
 	}
 	return UserWithSecret{u, secret}, nil
 }
 
-func (s UserService) User(ctx context.Context, secret Secret) (User, error) {
-	return s.users.User(ctx, secret)
+func (s UserService) User(ctx context.Context, userID ID) (User, error) {
+	return s.users.User(ctx, userID)
 }
 
-func (s UserService) UpdateUserName(ctx context.Context, secret Secret, name string) error {
-	return s.users.UpdateUserName(ctx, secret, name)
+// UpdateUserName updates the user's name. [ErrInvalidName] is returned if the
+// name is empty or too long, see [ParseName].
+func (s UserService) UpdateUserName(ctx context.Context, userID ID, name string) error {
+	name, err := ParseName(name)
+	if err != nil {
+		return err
+	}
+	return s.users.UpdateUserName(ctx, userID, name)
 }
 
-func (s UserService) UpdateUserLocale(ctx context.Context, secret Secret, locale Locale) error {
+// UpdateUserLocale updates the user's locale. [ErrInvalidLocale] is returned
+// if the locale can't be parsed.
+func (s UserService) UpdateUserLocale(ctx context.Context, userID ID, locale Locale) error {
 	if err := locale.Validate(); err != nil {
-		return fmt.Errorf("invalid locale: %w", err)
+		return fmt.Errorf("%w %q", ErrInvalidLocale, locale)
+	}
+	return s.users.UpdateUserLocale(ctx, userID, locale)
+}
+
+// UpdateUserProfile applies the update to the user's profile and returns the
+// updated user. Every field is validated first, and then all of them are
+// changed at once, so either the whole update is applied or none of it is.
+func (s UserService) UpdateUserProfile(ctx context.Context, userID ID, update ProfileUpdate) (User, error) {
+	if err := update.Validate(); err != nil {
+		return User{}, err
+	}
+
+	if update.Name != nil {
+		name, _ := ParseName(*update.Name)
+		update.Name = &name
 	}
-	return s.users.UpdateUserLocale(ctx, secret, locale)
+
+	return s.users.UpdateUserProfile(ctx, userID, update)
 }
 
+// CreateSession logs in the user with the given secret and returns the token
+// of their new session. [ErrUnknownUser] is returned if no user has the
+// secret.
 func (s UserService) CreateSession(ctx context.Context, userSecret Secret, userAgent string) (SessionToken, error) {
+	userID, err := userIDBySecret(ctx, s.users, s.hasher, userSecret)
+	if err != nil {
+		return "", err
+	}
+	return s.createSession(ctx, userID, userAgent)
+}
+
+// userIDBySecret returns the ID of the user with the given secret. If the
+// secret was hashed with a previous pepper, it is rehashed with the current
+// one.
+func userIDBySecret(ctx context.Context, users UserStorage, hasher secretHasher, secret Secret) (ID, error) {
+	userID, hash, err := lookupHash(hasher, []byte(secret), func(hash []byte) (ID, error) {
+		return users.UserIDBySecret(ctx, hash)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if newHash := hasher.hashSecret(secret); !bytes.Equal(hash, newHash) {
+		if err := users.SetUserSecretHash(ctx, userID, newHash); err != nil {
+			return 0, fmt.Errorf("cannot rehash user secret: %w", err)
+		}
+	}
+
+	return userID, nil
+}
+
+// createSession creates a new session for the user, who must already be
+// authenticated.
+func (s UserService) createSession(ctx context.Context, userID ID, userAgent string) (SessionToken, error) {
 	token, err := generateSessionToken()
 	if err != nil {
 		return "", err
@@ -68,27 +196,57 @@ func (s UserService) CreateSession(ctx context.Context, userSecret Secret, userA
 		panic(err)
 	}
 
-	return token, s.userSessions.RegisterSession(ctx, tokenBytes, userSecret, userAgent)
+	return token, s.userSessions.RegisterSession(ctx, s.hasher.hash(tokenBytes), userID, userAgent)
 }
 
+// ValidateSession returns the session with the given token. If the token was
+// hashed with a previous pepper, it is rehashed with the current one.
 func (s UserService) ValidateSession(ctx context.Context, token SessionToken) (Session, error) {
 	tokenBytes, err := token.asBytes()
 	if err != nil {
 		return Session{}, ErrInvalidSession
 	}
 
-	session, err := s.userSessions.ValidateSession(ctx, tokenBytes)
+	session, hash, err := lookupHash(s.hasher, tokenBytes, func(hash []byte) (Session, error) {
+		return s.userSessions.ValidateSession(ctx, hash, s.sessionLifetime)
+	})
 	if err != nil {
 		return Session{}, ErrInvalidSession
 	}
 
+	if newHash := s.hasher.hash(tokenBytes); !bytes.Equal(hash, newHash) {
+		if err := s.userSessions.SetSessionTokenHash(ctx, session.ID, newHash); err != nil {
+			return Session{}, fmt.Errorf("cannot rehash session token: %w", err)
+		}
+	}
+
+	session.ExpiresAt = s.sessionLifetime.expiresAt(session)
 	return session, nil
 }
 
-func (s UserService) ListSessions(ctx context.Context, userSecret Secret) ([]Session, error) {
-	return s.userSessions.ListSessions(ctx, userSecret)
+func (s UserService) ListSessions(ctx context.Context, userID ID) ([]Session, error) {
+	sessions, err := s.userSessions.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range sessions {
+		sessions[i].ExpiresAt = s.sessionLifetime.expiresAt(sessions[i])
+	}
+
+	// Expired sessions may not have been cleaned up yet.
+	return slices.DeleteFunc(sessions, func(session Session) bool {
+		return !session.ExpiresAt.IsZero() && !session.ExpiresAt.After(now)
+	}), nil
+}
+
+func (s UserService) DeleteSession(ctx context.Context, userID ID, sessionID int64) error {
+	return s.userSessions.DeleteSession(ctx, userID, sessionID)
 }
 
-func (s UserService) DeleteSession(ctx context.Context, userSecret Secret, sessionID int64) error {
-	return s.userSessions.DeleteSession(ctx, userSecret, sessionID)
+// DeleteOtherSessions logs out the user that the session belongs to
+// everywhere except for that session. Their personal access tokens are kept.
+func (s UserService) DeleteOtherSessions(ctx context.Context, session Session) error {
+	return s.userSessions.DeleteOtherSessions(ctx, session.UserID, session.ID)
 }
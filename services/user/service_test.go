@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"e2clicker.app/internal/ptr"
 	"github.com/alecthomas/assert/v2"
 )
 
@@ -18,10 +21,26 @@ func TestUserService_CreateUser(t *testing.T) {
 	assert.NoError(t, err)
 
 	call := s.users.CreateUserCalls()[0]
-	assert.NotZero(t, call.Secret)
+	assert.NotZero(t, call.SecretHash)
 	assert.Equal(t, call.Name, "Diamond")
 }
 
+func TestUserService_CreateUserHashesSecret(t *testing.T) {
+	ctx := context.Background()
+
+	s := newMockUserService(t)
+
+	u, err := s.CreateUser(ctx, "Diamond")
+	assert.NoError(t, err)
+
+	call := s.users.CreateUserCalls()[0]
+	assert.False(t, bytes.Contains(call.SecretHash, []byte(u.Secret)), "secret must not be stored in plain")
+	assert.Equal(t, call.SecretHash, s.hasher.hashSecret(u.Secret))
+
+	unpeppered := secretHasher{}
+	assert.NotEqual(t, call.SecretHash, unpeppered.hashSecret(u.Secret))
+}
+
 func TestUserService_UpdateUserLocale(t *testing.T) {
 	tests := []struct {
 		locale Locale
@@ -35,13 +54,13 @@ func TestUserService_UpdateUserLocale(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	secret := generateUserSecret()
+	userID := ID(42)
 
 	for i, test := range tests {
 		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
 			s := newMockUserService(t)
 
-			err := s.UpdateUserLocale(ctx, secret, test.locale)
+			err := s.UpdateUserLocale(ctx, userID, test.locale)
 			if !test.valid {
 				assert.Error(t, err)
 				assert.Equal(t, len(s.users.UpdateUserLocaleCalls()), 0)
@@ -51,17 +70,131 @@ func TestUserService_UpdateUserLocale(t *testing.T) {
 			assert.NoError(t, err)
 
 			call := s.users.UpdateUserLocaleCalls()[0]
-			assert.Equal(t, call.Secret, secret)
+			assert.Equal(t, call.UserID, userID)
 			assert.Equal(t, call.Locale, test.locale)
 		})
 	}
 }
 
+func TestUserService_UpdateUserName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		err  error
+	}{
+		{"Diamond", "Diamond", nil},
+		{"  Diamond \n", "Diamond", nil},
+		{"🌸 Lily", "🌸 Lily", nil},
+		{"", "", ErrInvalidName},
+		{"   ", "", ErrInvalidName},
+		{strings.Repeat("a", MaxNameLength), strings.Repeat("a", MaxNameLength), nil},
+		{strings.Repeat("a", MaxNameLength+1), "", ErrInvalidName},
+	}
+
+	ctx := context.Background()
+	userID := ID(42)
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			s := newMockUserService(t)
+
+			err := s.UpdateUserName(ctx, userID, test.name)
+			if test.err != nil {
+				assert.IsError(t, err, test.err)
+				assert.Equal(t, len(s.users.UpdateUserNameCalls()), 0)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			call := s.users.UpdateUserNameCalls()[0]
+			assert.Equal(t, call.UserID, userID)
+			assert.Equal(t, call.Name, test.want)
+		})
+	}
+}
+
+func TestUserService_UpdateUserProfile(t *testing.T) {
+	ctx := context.Background()
+	userID := ID(42)
+
+	t.Run("partial", func(t *testing.T) {
+		s := newMockUserService(t)
+		s.users.UpdateUserProfileFunc = func(ctx context.Context, id ID, update ProfileUpdate) (User, error) {
+			return User{ID: id, Name: *update.Name, Locale: "en"}, nil
+		}
+
+		u, err := s.UpdateUserProfile(ctx, userID, ProfileUpdate{
+			Name: ptr.To("  Diamond "),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "Diamond", u.Name)
+
+		call := s.users.UpdateUserProfileCalls()[0]
+		assert.Equal(t, call.UserID, userID)
+		assert.Equal(t, call.Update, ProfileUpdate{Name: ptr.To("Diamond")})
+	})
+
+	t.Run("all at once", func(t *testing.T) {
+		s := newMockUserService(t)
+		s.users.UpdateUserProfileFunc = func(ctx context.Context, id ID, update ProfileUpdate) (User, error) {
+			return User{ID: id}, nil
+		}
+
+		update := ProfileUpdate{
+			Name:     ptr.To("Diamond"),
+			Locale:   ptr.To(Locale("fr")),
+			Timezone: ptr.To("Europe/Paris"),
+		}
+		_, err := s.UpdateUserProfile(ctx, userID, update)
+		assert.NoError(t, err)
+
+		// Every field is written by a single storage call, so that the
+		// update can't be applied halfway.
+		assert.Equal(t, len(s.users.UpdateUserProfileCalls()), 1)
+		assert.Equal(t, s.users.UpdateUserProfileCalls()[0].Update, update)
+		assert.Equal(t, len(s.users.UpdateUserNameCalls()), 0)
+		assert.Equal(t, len(s.users.UpdateUserLocaleCalls()), 0)
+	})
+
+	t.Run("invalid field", func(t *testing.T) {
+		s := newMockUserService(t)
+
+		// The valid name must not be saved if the locale is invalid.
+		_, err := s.UpdateUserProfile(ctx, userID, ProfileUpdate{
+			Name:   ptr.To("Diamond"),
+			Locale: ptr.To(Locale("whatever lol")),
+		})
+		assert.IsError(t, err, ErrInvalidLocale)
+
+		_, err = s.UpdateUserProfile(ctx, userID, ProfileUpdate{
+			Name:     ptr.To("Diamond"),
+			Timezone: ptr.To("Mars/Olympus_Mons"),
+		})
+		assert.IsError(t, err, ErrUnknownTimezone)
+
+		assert.Equal(t, len(s.users.UpdateUserProfileCalls()), 0)
+	})
+}
+
 func TestUserService_CreateSession(t *testing.T) {
 	ctx := context.Background()
 	secret := generateUserSecret()
+	userID := ID(42)
 
 	s := newMockUserService(t)
+	s.users.UserIDBySecretFunc = func(ctx context.Context, secretHash []byte) (ID, error) {
+		if !bytes.Equal(secretHash, s.hasher.hashSecret(secret)) {
+			return 0, ErrUnknownUser
+		}
+		return userID, nil
+	}
+
+	t.Run("unknown user", func(t *testing.T) {
+		token, err := s.CreateSession(ctx, generateUserSecret(), "user agent")
+		assert.Equal(t, err, ErrUnknownUser)
+		assert.Zero(t, token)
+	})
 
 	token, err := s.CreateSession(ctx, secret, "user agent")
 	assert.NoError(t, err)
@@ -70,14 +203,18 @@ func TestUserService_CreateSession(t *testing.T) {
 	tokenBytes, err := token.asBytes()
 	assert.NoError(t, err)
 
-	s.sessions.ValidateSessionFunc = func(ctx context.Context, wantToken []byte) (Session, error) {
-		if !bytes.Equal(wantToken, tokenBytes) {
+	register := s.sessions.RegisterSessionCalls()[0]
+	assert.Equal(t, register.UserID, userID)
+	assert.NotEqual(t, register.TokenHash, tokenBytes)
+
+	s.sessions.ValidateSessionFunc = func(ctx context.Context, tokenHash []byte, lifetime SessionLifetime) (Session, error) {
+		if !bytes.Equal(tokenHash, register.TokenHash) {
 			return Session{}, fmt.Errorf("token not found")
 		}
 		return Session{
-			ID:         1,
-			UserSecret: secret,
-			UserAgent:  "user agent",
+			ID:        1,
+			UserID:    userID,
+			UserAgent: "user agent",
 		}, nil
 	}
 
@@ -85,7 +222,7 @@ func TestUserService_CreateSession(t *testing.T) {
 		s, err := s.ValidateSession(ctx, token)
 		assert.NoError(t, err)
 		assert.Equal(t, s.ID, int64(1))
-		assert.Equal(t, s.UserSecret, secret)
+		assert.Equal(t, s.UserID, userID)
 		assert.Equal(t, s.UserAgent, "user agent")
 	})
 
@@ -104,3 +241,160 @@ func TestUserService_CreateSession(t *testing.T) {
 		assert.Zero(t, s)
 	})
 }
+
+func TestUserService_SessionExpiry(t *testing.T) {
+	ctx := context.Background()
+	userID := ID(42)
+	now := time.Now()
+
+	s := newMockUserService(t)
+	s.sessionLifetime = SessionLifetime{
+		MaxAge:  30 * 24 * time.Hour,
+		MaxIdle: 7 * 24 * time.Hour,
+	}
+
+	s.sessions.ListSessionsFunc = func(ctx context.Context, id ID) ([]Session, error) {
+		return []Session{
+			{ID: 1, UserID: id, CreatedAt: now.Add(-time.Hour), LastUsed: now},
+			{ID: 2, UserID: id, CreatedAt: now.Add(-29 * 24 * time.Hour), LastUsed: now},
+			{ID: 3, UserID: id, CreatedAt: now.Add(-31 * 24 * time.Hour), LastUsed: now},
+			{ID: 4, UserID: id, CreatedAt: now.Add(-8 * 24 * time.Hour), LastUsed: now.Add(-8 * 24 * time.Hour)},
+		}, nil
+	}
+
+	sessions, err := s.ListSessions(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, len(sessions), 2)
+
+	assert.Equal(t, sessions[0].ID, int64(1))
+	assert.Equal(t, sessions[0].ExpiresAt, now.Add(7*24*time.Hour), "idle timeout comes first")
+
+	assert.Equal(t, sessions[1].ID, int64(2))
+	assert.Equal(t, sessions[1].ExpiresAt, now.Add(24*time.Hour), "max age comes first")
+
+	s.sessions.ValidateSessionFunc = func(ctx context.Context, tokenHash []byte, lifetime SessionLifetime) (Session, error) {
+		assert.Equal(t, lifetime, s.sessionLifetime)
+		return Session{ID: 2, UserID: userID, CreatedAt: now.Add(-29 * 24 * time.Hour), LastUsed: now}, nil
+	}
+
+	token, err := generateSessionToken()
+	assert.NoError(t, err)
+
+	session, err := s.ValidateSession(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, session.ExpiresAt, now.Add(24*time.Hour))
+}
+
+func TestUserService_DeleteOtherSessions(t *testing.T) {
+	ctx := context.Background()
+	session := Session{ID: 3, UserID: 42}
+
+	s := newMockUserService(t)
+
+	err := s.DeleteOtherSessions(ctx, session)
+	assert.NoError(t, err)
+
+	call := s.sessions.DeleteOtherSessionsCalls()[0]
+	assert.Equal(t, call.UserID, session.UserID)
+	assert.Equal(t, call.KeepSessionID, session.ID)
+}
+
+func TestUserService_PepperChange(t *testing.T) {
+	ctx := context.Background()
+	secret := generateUserSecret()
+	userID := ID(42)
+
+	oldHasher := secretHasher{pepper: []byte("old test pepper")}
+	storedHash := oldHasher.hashSecret(secret)
+
+	s := newMockUserService(t)
+	s.hasher = secretHasher{
+		pepper:   []byte("new test pepper"),
+		previous: [][]byte{oldHasher.pepper, nil},
+	}
+	s.users.UserIDBySecretFunc = func(ctx context.Context, secretHash []byte) (ID, error) {
+		if !bytes.Equal(secretHash, storedHash) {
+			return 0, ErrUnknownUser
+		}
+		return userID, nil
+	}
+	s.users.SetUserSecretHashFunc = func(ctx context.Context, id ID, secretHash []byte) error {
+		assert.Equal(t, id, userID)
+		storedHash = secretHash
+		return nil
+	}
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, err := s.CreateSession(ctx, generateUserSecret(), "user agent")
+		assert.Equal(t, err, ErrUnknownUser)
+		assert.Equal(t, len(s.users.SetUserSecretHashCalls()), 0)
+	})
+
+	_, err := s.CreateSession(ctx, secret, "user agent")
+	assert.NoError(t, err)
+	assert.Equal(t, len(s.users.SetUserSecretHashCalls()), 1)
+	assert.Equal(t, storedHash, s.hasher.hashSecret(secret))
+
+	// The secret is found with the current pepper now, so it is not rehashed
+	// again.
+	_, err = s.CreateSession(ctx, secret, "user agent")
+	assert.NoError(t, err)
+	assert.Equal(t, len(s.users.SetUserSecretHashCalls()), 1)
+
+	t.Run("without the previous pepper", func(t *testing.T) {
+		s := newMockUserService(t)
+		s.users.UserIDBySecretFunc = func(ctx context.Context, secretHash []byte) (ID, error) {
+			if !bytes.Equal(secretHash, oldHasher.hashSecret(secret)) {
+				return 0, ErrUnknownUser
+			}
+			return userID, nil
+		}
+
+		_, err := s.CreateSession(ctx, secret, "user agent")
+		assert.Equal(t, err, ErrUnknownUser)
+	})
+}
+
+func TestUserService_PepperChangeSessions(t *testing.T) {
+	ctx := context.Background()
+	session := Session{ID: 3, UserID: 42}
+
+	token, err := generateSessionToken()
+	assert.NoError(t, err)
+	tokenBytes, err := token.asBytes()
+	assert.NoError(t, err)
+
+	// The session was created before a pepper was configured.
+	storedHash := secretHasher{}.hash(tokenBytes)
+
+	s := newMockUserService(t)
+	s.sessions.ValidateSessionFunc = func(ctx context.Context, tokenHash []byte, lifetime SessionLifetime) (Session, error) {
+		if !bytes.Equal(tokenHash, storedHash) {
+			return Session{}, ErrInvalidSession
+		}
+		return session, nil
+	}
+	s.sessions.SetSessionTokenHashFunc = func(ctx context.Context, sessionID int64, tokenHash []byte) error {
+		assert.Equal(t, sessionID, session.ID)
+		storedHash = tokenHash
+		return nil
+	}
+
+	t.Run("unpeppered hashes not accepted", func(t *testing.T) {
+		_, err := s.ValidateSession(ctx, token)
+		assert.IsError(t, err, ErrInvalidSession)
+	})
+
+	s.hasher.previous = [][]byte{nil}
+
+	_, err = s.ValidateSession(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, len(s.sessions.SetSessionTokenHashCalls()), 1)
+	assert.Equal(t, storedHash, s.hasher.hash(tokenBytes))
+
+	// The token is found with the current pepper now, so it is not rehashed
+	// again.
+	_, err = s.ValidateSession(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, len(s.sessions.SetSessionTokenHashCalls()), 1)
+}
@@ -7,27 +7,97 @@ import (
 	"fmt"
 	"io"
 	"time"
+
+	e2clickermodule "e2clicker.app/nix/modules/e2clicker"
 )
 
 type UserSessionStorage interface {
 	// RegisterSession registers a session for a user. The token is generated by
-	// [UserService]. The userAgent is optional.
-	RegisterSession(ctx context.Context, token []byte, userSecret Secret, userAgent string) error
-	// ValidateSession validates a session for a user. The user that the session
-	// belongs to is returned.
-	ValidateSession(ctx context.Context, token []byte) (Session, error)
+	// [UserService], which only gives the storage its hash. The userAgent is
+	// optional.
+	RegisterSession(ctx context.Context, tokenHash []byte, userID ID, userAgent string) error
+	// ValidateSession validates a session for a user and marks it as used. The
+	// user that the session belongs to is returned. Sessions that have expired
+	// according to lifetime are treated as unknown.
+	ValidateSession(ctx context.Context, tokenHash []byte, lifetime SessionLifetime) (Session, error)
+	// SetSessionTokenHash replaces the hash of the session's token without
+	// changing the token, e.g. because it was hashed with an old pepper.
+	SetSessionTokenHash(ctx context.Context, sessionID int64, tokenHash []byte) error
 	// ListSessions lists all sessions for a user.
-	ListSessions(ctx context.Context, userSecret Secret) ([]Session, error)
+	ListSessions(ctx context.Context, userID ID) ([]Session, error)
 	// DeleteSession deletes a session for a user.
-	DeleteSession(ctx context.Context, userSecret Secret, sessionID int64) error
+	DeleteSession(ctx context.Context, userID ID, sessionID int64) error
+	// DeleteOtherSessions deletes all sessions for a user except for the one
+	// with the ID keepSessionID.
+	DeleteOtherSessions(ctx context.Context, userID ID, keepSessionID int64) error
+	// DeleteExpiredSessions deletes the sessions of all users that have expired
+	// according to lifetime. It returns the number of deleted sessions.
+	DeleteExpiredSessions(ctx context.Context, lifetime SessionLifetime) (int64, error)
+}
+
+// SessionLifetime limits how long a session stays valid.
+type SessionLifetime struct {
+	// MaxAge is how long a session stays valid after it was created.
+	// If zero, sessions are valid regardless of their age.
+	MaxAge time.Duration
+	// MaxIdle is how long a session stays valid after it was last used.
+	// If zero, sessions are valid regardless of how long they were unused.
+	MaxIdle time.Duration
+}
+
+// newSessionLifetime parses the session lifetime from the config.
+func newSessionLifetime(config e2clickermodule.API) (SessionLifetime, error) {
+	var l SessionLifetime
+	var err error
+
+	if config.SessionMaxAge != nil {
+		l.MaxAge, err = time.ParseDuration(*config.SessionMaxAge)
+		if err != nil {
+			return SessionLifetime{}, fmt.Errorf("invalid session max age %q: %w", *config.SessionMaxAge, err)
+		}
+	}
+
+	if config.SessionMaxIdle != nil {
+		l.MaxIdle, err = time.ParseDuration(*config.SessionMaxIdle)
+		if err != nil {
+			return SessionLifetime{}, fmt.Errorf("invalid session max idle time %q: %w", *config.SessionMaxIdle, err)
+		}
+	}
+
+	if l.MaxAge < 0 || l.MaxIdle < 0 {
+		return SessionLifetime{}, fmt.Errorf("session lifetime must not be negative")
+	}
+
+	return l, nil
+}
+
+// IsZero returns true if sessions never expire.
+func (l SessionLifetime) IsZero() bool {
+	return l.MaxAge == 0 && l.MaxIdle == 0
+}
+
+// expiresAt returns the time that the session expires, or zero if it never
+// does.
+func (l SessionLifetime) expiresAt(s Session) time.Time {
+	var t time.Time
+	if l.MaxAge > 0 {
+		t = s.CreatedAt.Add(l.MaxAge)
+	}
+	if l.MaxIdle > 0 {
+		idle := s.LastUsed.Add(l.MaxIdle)
+		if t.IsZero() || idle.Before(t) {
+			t = idle
+		}
+	}
+	return t
 }
 
 // Session is a user session.
 type Session struct {
 	// ID uniquely identifies the session.
 	ID int64
-	// UserSecret is the secret of the user that the session belongs to.
-	UserSecret Secret
+	// UserID is the ID of the user that the session belongs to.
+	UserID ID
 	// UserAgent is the user agent that the session was created with.
 	UserAgent string
 	// CreatedAt is the time that the session was created.
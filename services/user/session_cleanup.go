@@ -0,0 +1,84 @@
+package user
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// sessionCleanupInterval is how often expired sessions are deleted.
+const sessionCleanupInterval = time.Hour
+
+// SessionCleanupService periodically deletes expired sessions. Expired
+// sessions can't be used anyway, so this only keeps them from piling up.
+type SessionCleanupService struct {
+	sessions UserSessionStorage
+	lifetime SessionLifetime
+	logger   *slog.Logger
+}
+
+// NewSessionCleanupService creates a new SessionCleanupService.
+func NewSessionCleanupService(
+	sessions UserSessionStorage,
+	users *UserService,
+	logger *slog.Logger,
+	lc fx.Lifecycle,
+) *SessionCleanupService {
+	s := &SessionCleanupService{
+		sessions: sessions,
+		lifetime: users.sessionLifetime,
+		logger:   logger,
+	}
+
+	if s.lifetime.IsZero() {
+		// Sessions never expire, so there is nothing to clean up.
+		return s
+	}
+
+	fakectx, stop := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				s.run(fakectx)
+				close(done)
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stop()
+			<-done
+			return nil
+		},
+	})
+
+	return s
+}
+
+func (s *SessionCleanupService) run(ctx context.Context) {
+	ticker := time.NewTicker(sessionCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := s.sessions.DeleteExpiredSessions(ctx, s.lifetime)
+		if err != nil {
+			s.logger.Error(
+				"SessionCleanupService: error deleting expired sessions",
+				"err", err)
+		} else if n > 0 {
+			s.logger.Info(
+				"SessionCleanupService: deleted expired sessions",
+				"count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// keep running
+		}
+	}
+}
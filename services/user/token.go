@@ -0,0 +1,196 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// personalTokenPrefix is the prefix of every personal access token, which
+// makes them easy to find if they are leaked.
+const personalTokenPrefix = "e2c_"
+
+// personalTokenSize is the number of random bytes in a personal access token.
+// Along with the prefix, it makes personal access tokens longer than session
+// tokens, so the two can't be mistaken for each other.
+const personalTokenSize = 32
+
+// personalTokenLength is the length of a personal access token.
+var personalTokenLength = len(personalTokenPrefix) + base64.RawURLEncoding.EncodedLen(personalTokenSize)
+
+type PersonalTokenStorage interface {
+	// CreatePersonalToken creates a personal access token for the user. The
+	// token is generated by [UserService], which only gives the storage its
+	// hash. If expiresAt is zero, the token never expires.
+	CreatePersonalToken(ctx context.Context, userID ID, name string, tokenHash []byte, scopes []Scope, expiresAt time.Time) (PersonalToken, error)
+	// PersonalTokens lists all personal access tokens of a user.
+	PersonalTokens(ctx context.Context, userID ID) ([]PersonalToken, error)
+	// DeletePersonalToken deletes a personal access token of a user.
+	// [ErrUnknownPersonalToken] is returned if the user has no such token.
+	DeletePersonalToken(ctx context.Context, userID ID, tokenID int64) error
+	// ValidatePersonalToken validates a personal access token and marks it as
+	// used. [ErrInvalidSession] is returned if the token is unknown, has
+	// expired or belongs to a user whose account is scheduled to be deleted.
+	ValidatePersonalToken(ctx context.Context, tokenHash []byte) (PersonalToken, error)
+}
+
+// Scope limits what a personal access token can be used for. Each API
+// operation that personal access tokens may be used for lists the scopes that
+// it needs.
+type Scope string
+
+const (
+	// ScopeProfileRead allows reading the user's name and locale.
+	ScopeProfileRead Scope = "profile:read"
+	// ScopeDosesRead allows reading the dosage schedule and dose history.
+	ScopeDosesRead Scope = "doses:read"
+	// ScopeDosesWrite allows recording, editing, forgetting and importing
+	// doses.
+	ScopeDosesWrite Scope = "doses:write"
+	// ScopeScheduleWrite allows changing the dosage schedule.
+	ScopeScheduleWrite Scope = "schedule:write"
+	// ScopeNotificationsRead allows reading the notification preferences.
+	ScopeNotificationsRead Scope = "notifications:read"
+	// ScopeNotificationsWrite allows changing the notification preferences
+	// and sending test notifications.
+	ScopeNotificationsWrite Scope = "notifications:write"
+)
+
+// Scopes is the list of all scopes.
+var Scopes = []Scope{
+	ScopeProfileRead,
+	ScopeDosesRead,
+	ScopeDosesWrite,
+	ScopeScheduleWrite,
+	ScopeNotificationsRead,
+	ScopeNotificationsWrite,
+}
+
+// Validate returns an error if the scope is unknown.
+func (s Scope) Validate() error {
+	if !slices.Contains(Scopes, s) {
+		return fmt.Errorf("%w %q", ErrInvalidScope, s)
+	}
+	return nil
+}
+
+// PersonalToken is a long-lived token that a user can give to scripts and
+// other programs to use the API on their behalf. Unlike a session, it can only
+// be used for the operations that its scopes allow.
+type PersonalToken struct {
+	// ID uniquely identifies the token.
+	ID int64
+	// UserID is the ID of the user that the token belongs to.
+	UserID ID
+	// Name is the name that the user gave the token.
+	Name string
+	// Scopes are the scopes that the token may be used for.
+	Scopes []Scope
+	// CreatedAt is the time that the token was created.
+	CreatedAt time.Time
+	// LastUsed is the time that the token was last used.
+	// If zero, the token was never used.
+	LastUsed time.Time
+	// ExpiresAt is the time that the token will expire.
+	// If zero, the token does not expire.
+	ExpiresAt time.Time
+}
+
+// HasScopes returns true if the token may be used for all of the given scopes.
+func (t PersonalToken) HasScopes(scopes ...Scope) bool {
+	for _, s := range scopes {
+		if !slices.Contains(t.Scopes, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// PersonalTokenWithSecret is a personal access token along with the token
+// itself, which is only known when the token is created.
+type PersonalTokenWithSecret struct {
+	PersonalToken
+	Token string
+}
+
+// IsPersonalToken returns true if the given bearer token looks like a
+// personal access token rather than a session token.
+func IsPersonalToken(token string) bool {
+	return len(token) == personalTokenLength && strings.HasPrefix(token, personalTokenPrefix)
+}
+
+func generatePersonalToken() (string, error) {
+	var b [personalTokenSize]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate personal access token: %w", err)
+	}
+	return personalTokenPrefix + base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// CreatePersonalToken creates a personal access token for the user that may
+// be used for the given scopes. If expiresAt is zero, the token never expires.
+// The token itself is only ever returned here.
+func (s UserService) CreatePersonalToken(ctx context.Context, userID ID, name string, scopes []Scope, expiresAt time.Time) (PersonalTokenWithSecret, error) {
+	if len(scopes) == 0 {
+		return PersonalTokenWithSecret{}, fmt.Errorf("%w: a token needs at least one scope", ErrInvalidScope)
+	}
+	for _, scope := range scopes {
+		if err := scope.Validate(); err != nil {
+			return PersonalTokenWithSecret{}, err
+		}
+	}
+
+	if !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		return PersonalTokenWithSecret{}, ErrPersonalTokenExpiry
+	}
+
+	if name == "" {
+		name = "Token"
+	}
+
+	token, err := generatePersonalToken()
+	if err != nil {
+		return PersonalTokenWithSecret{}, err
+	}
+
+	scopes = slices.Compact(slices.Sorted(slices.Values(scopes)))
+
+	t, err := s.personalTokens.CreatePersonalToken(ctx,
+		userID, name, s.hasher.hash([]byte(token)), scopes, expiresAt)
+	if err != nil {
+		return PersonalTokenWithSecret{}, err
+	}
+
+	return PersonalTokenWithSecret{t, token}, nil
+}
+
+// PersonalTokens lists the personal access tokens of the user.
+func (s UserService) PersonalTokens(ctx context.Context, userID ID) ([]PersonalToken, error) {
+	return s.personalTokens.PersonalTokens(ctx, userID)
+}
+
+// DeletePersonalToken deletes a personal access token of the user, after
+// which it can no longer be used.
+func (s UserService) DeletePersonalToken(ctx context.Context, userID ID, tokenID int64) error {
+	return s.personalTokens.DeletePersonalToken(ctx, userID, tokenID)
+}
+
+// ValidatePersonalToken returns the personal access token with the given
+// value. [ErrInvalidSession] is returned if the token is unknown or has
+// expired. The caller must check the token's scopes.
+func (s UserService) ValidatePersonalToken(ctx context.Context, token string) (PersonalToken, error) {
+	if !IsPersonalToken(token) {
+		return PersonalToken{}, ErrInvalidSession
+	}
+
+	t, err := s.personalTokens.ValidatePersonalToken(ctx, s.hasher.hash([]byte(token)))
+	if err != nil {
+		return PersonalToken{}, ErrInvalidSession
+	}
+
+	return t, nil
+}
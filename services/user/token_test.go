@@ -0,0 +1,154 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestUserService_PersonalTokens(t *testing.T) {
+	ctx := context.Background()
+	userID := ID(42)
+
+	s := newMockUserService(t)
+
+	var tokenHash []byte
+	s.tokens.CreatePersonalTokenFunc = func(ctx context.Context, id ID, name string, hash []byte, scopes []Scope, expiresAt time.Time) (PersonalToken, error) {
+		tokenHash = hash
+		return PersonalToken{ID: 1, UserID: id, Name: name, Scopes: scopes, ExpiresAt: expiresAt}, nil
+	}
+	s.tokens.ValidatePersonalTokenFunc = func(ctx context.Context, hash []byte) (PersonalToken, error) {
+		if !bytes.Equal(hash, tokenHash) {
+			return PersonalToken{}, ErrInvalidSession
+		}
+		return PersonalToken{ID: 1, UserID: userID, Scopes: []Scope{ScopeDosesRead, ScopeDosesWrite}}, nil
+	}
+
+	t.Run("no scopes", func(t *testing.T) {
+		_, err := s.CreatePersonalToken(ctx, userID, "script", nil, time.Time{})
+		assert.IsError(t, err, ErrInvalidScope)
+	})
+
+	t.Run("unknown scope", func(t *testing.T) {
+		_, err := s.CreatePersonalToken(ctx, userID, "script", []Scope{"everything"}, time.Time{})
+		assert.IsError(t, err, ErrInvalidScope)
+	})
+
+	t.Run("already expired", func(t *testing.T) {
+		_, err := s.CreatePersonalToken(ctx, userID, "script", []Scope{ScopeDosesRead}, time.Now().Add(-time.Hour))
+		assert.IsError(t, err, ErrPersonalTokenExpiry)
+	})
+
+	assert.Equal(t, len(s.tokens.CreatePersonalTokenCalls()), 0)
+
+	token, err := s.CreatePersonalToken(ctx, userID, "",
+		[]Scope{ScopeDosesWrite, ScopeDosesRead, ScopeDosesWrite}, time.Time{})
+	assert.NoError(t, err)
+	assert.True(t, IsPersonalToken(token.Token))
+	assert.Equal(t, token.Name, "Token")
+	assert.Equal(t, token.Scopes, []Scope{ScopeDosesRead, ScopeDosesWrite})
+	assert.Equal(t, tokenHash, s.hasher.hash([]byte(token.Token)))
+
+	validated, err := s.ValidatePersonalToken(ctx, token.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, validated.UserID, userID)
+	assert.True(t, validated.HasScopes(ScopeDosesRead))
+	assert.True(t, validated.HasScopes(ScopeDosesRead, ScopeDosesWrite))
+	assert.False(t, validated.HasScopes(ScopeDosesRead, ScopeNotificationsRead))
+
+	t.Run("unknown token", func(t *testing.T) {
+		other, err := generatePersonalToken()
+		assert.NoError(t, err)
+
+		_, err = s.ValidatePersonalToken(ctx, other)
+		assert.IsError(t, err, ErrInvalidSession)
+	})
+
+	t.Run("session token", func(t *testing.T) {
+		session, err := generateSessionToken()
+		assert.NoError(t, err)
+		assert.False(t, IsPersonalToken(string(session)))
+
+		_, err = s.ValidatePersonalToken(ctx, string(session))
+		assert.IsError(t, err, ErrInvalidSession)
+	})
+}
+
+func TestUserService_PersonalTokensRevoked(t *testing.T) {
+	ctx := context.Background()
+	session := Session{ID: 3, UserID: 42}
+
+	tests := []struct {
+		name    string
+		revoke  func(s *mockUserService) error
+		revoked bool
+	}{
+		{
+			name: "secret rotated",
+			revoke: func(s *mockUserService) error {
+				_, err := s.RotateSecret(ctx, session)
+				return err
+			},
+			revoked: true,
+		},
+		{
+			name: "other sessions deleted",
+			revoke: func(s *mockUserService) error {
+				return s.DeleteOtherSessions(ctx, session)
+			},
+			revoked: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newMockUserService(t)
+
+			// Only rotating the secret deletes the user's personal tokens, and
+			// a token of another user always survives.
+			tokenHashes := map[ID][]byte{}
+			deleteTokens := func(userID ID) { delete(tokenHashes, userID) }
+
+			s.tokens.CreatePersonalTokenFunc = func(ctx context.Context, id ID, name string, hash []byte, scopes []Scope, expiresAt time.Time) (PersonalToken, error) {
+				tokenHashes[id] = hash
+				return PersonalToken{UserID: id, Name: name, Scopes: scopes}, nil
+			}
+			s.tokens.ValidatePersonalTokenFunc = func(ctx context.Context, hash []byte) (PersonalToken, error) {
+				for id, h := range tokenHashes {
+					if bytes.Equal(h, hash) {
+						return PersonalToken{UserID: id}, nil
+					}
+				}
+				return PersonalToken{}, ErrInvalidSession
+			}
+			s.users.ReplaceUserSecretFunc = func(ctx context.Context, userID ID, secretHash []byte, keepSessionID int64) error {
+				deleteTokens(userID)
+				return nil
+			}
+			s.sessions.DeleteOtherSessionsFunc = func(ctx context.Context, userID ID, keepSessionID int64) error {
+				return nil
+			}
+
+			token, err := s.CreatePersonalToken(ctx, session.UserID, "script", []Scope{ScopeDosesRead}, time.Time{})
+			assert.NoError(t, err)
+
+			otherToken, err := s.CreatePersonalToken(ctx, session.UserID+1, "script", []Scope{ScopeDosesRead}, time.Time{})
+			assert.NoError(t, err)
+
+			assert.NoError(t, test.revoke(s))
+
+			_, err = s.ValidatePersonalToken(ctx, token.Token)
+			if test.revoked {
+				assert.IsError(t, err, ErrInvalidSession)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			_, err = s.ValidatePersonalToken(ctx, otherToken.Token)
+			assert.NoError(t, err)
+		})
+	}
+}
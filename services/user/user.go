@@ -6,13 +6,21 @@ import (
 	"crypto/rand"
 	"encoding"
 	"encoding/base32"
+	"fmt"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // User is a user in the system.
 type User struct {
+	ID     ID
 	Name   string
 	Locale Locale
+	// PurgeAt is the time that the user's account is deleted. It is zero
+	// unless the user asked for their account to be deleted, see
+	// [AccountDeletionService].
+	PurgeAt time.Time
 }
 
 // UserWithSecret is a user with their secret.
@@ -21,20 +29,126 @@ type UserWithSecret struct {
 	Secret Secret
 }
 
+// MaxNameLength is the maximum length of a user's name in characters.
+const MaxNameLength = 64
+
+// ParseName trims the name and checks that it is neither empty nor longer than
+// [MaxNameLength]. [ErrInvalidName] is returned if it is.
+func ParseName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxNameLength {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
+
+// ValidateTimezone returns [ErrUnknownTimezone] if tz is not a known IANA time
+// zone. An empty tz is valid and means UTC.
+func ValidateTimezone(tz string) error {
+	if tz == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return ErrUnknownTimezone
+	}
+	return nil
+}
+
+// ProfileUpdate is a change to a user's profile. Fields that are nil are left
+// as they are.
+type ProfileUpdate struct {
+	Name   *string
+	Locale *Locale
+	// Timezone is the user's IANA time zone, which is kept in their
+	// notification preferences.
+	Timezone *string
+}
+
+// Validate checks that every field that is set is valid.
+func (u ProfileUpdate) Validate() error {
+	if u.Name != nil {
+		if _, err := ParseName(*u.Name); err != nil {
+			return err
+		}
+	}
+	if u.Locale != nil {
+		if err := u.Locale.Validate(); err != nil {
+			return fmt.Errorf("%w %q", ErrInvalidLocale, *u.Locale)
+		}
+	}
+	if u.Timezone != nil {
+		if err := ValidateTimezone(*u.Timezone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ID identifies a user. Unlike [Secret], it cannot be used to log in, so it is
+// what other services and storages refer to users by.
+type ID int64
+
 type UserStorage interface {
-	// CreateUser creates a user in the storage with the given name.
-	CreateUser(ctx context.Context, secret Secret, name string) (User, error)
-	// User gets the user identified by the given secret.
-	User(ctx context.Context, secret Secret) (User, error)
+	// CreateUser creates a user in the storage with the given name. The user
+	// logs in with the secret that hashes to secretHash.
+	CreateUser(ctx context.Context, secretHash []byte, name string) (User, error)
+	// UserIDBySecret returns the ID of the user whose secret hashes to
+	// secretHash. [ErrUnknownUser] is returned if there is no such user.
+	UserIDBySecret(ctx context.Context, secretHash []byte) (ID, error)
+	// User gets the user identified by the given ID.
+	User(ctx context.Context, userID ID) (User, error)
 	// UpdateUserName updates the user's name.
-	UpdateUserName(ctx context.Context, secret Secret, name string) error
+	UpdateUserName(ctx context.Context, userID ID, name string) error
 	// UpdateUserLocale updates the user's locale.
-	UpdateUserLocale(ctx context.Context, secret Secret, locale Locale) error
+	UpdateUserLocale(ctx context.Context, userID ID, locale Locale) error
+	// UpdateUserProfile applies every field of the update that is set within
+	// a single transaction and returns the updated user. Updating the time
+	// zone waits for concurrent updates of the user's notification
+	// preferences, so neither is lost.
+	UpdateUserProfile(ctx context.Context, userID ID, update ProfileUpdate) (User, error)
+	// ReplaceUserSecret replaces the user's secret with the one that hashes to
+	// secretHash and deletes all of the user's sessions except for the one
+	// with the ID keepSessionID, as well as all of the user's passkeys and
+	// personal access tokens, since they may have been added by whoever had
+	// the old secret.
+	ReplaceUserSecret(ctx context.Context, userID ID, secretHash []byte, keepSessionID int64) error
+	// SetUserSecretHash replaces the hash of the user's secret without
+	// changing the secret, e.g. because it was hashed with an old pepper.
+	SetUserSecretHash(ctx context.Context, userID ID, secretHash []byte) error
+	// ReplaceRecoveryCodes replaces all of the user's recovery codes with the
+	// ones that hash to codeHashes.
+	ReplaceRecoveryCodes(ctx context.Context, userID ID, codeHashes [][]byte) error
+	// RecoveryCodeCount returns the number of unused recovery codes that the
+	// user has.
+	RecoveryCodeCount(ctx context.Context, userID ID) (int, error)
+	// UseRecoveryCode deletes the recovery code that hashes to codeHash and
+	// returns the ID of the user that it belonged to. [ErrUnknownUser] is
+	// returned if there is no such recovery code.
+	UseRecoveryCode(ctx context.Context, codeHash []byte) (ID, error)
+	// HashPlainSecrets replaces the user secrets and session tokens that are
+	// still stored in plain text with their hashes, as computed by hash. It
+	// returns the number of secrets and tokens that were hashed.
+	HashPlainSecrets(ctx context.Context, hash func([]byte) []byte) (int64, error)
+	// ScheduleUserPurge schedules the user's account to be deleted at purgeAt
+	// and logs the user out everywhere by deleting all of their sessions.
+	// Until the purge is cancelled, the user's personal access tokens, share
+	// links and delegations are treated as unknown.
+	ScheduleUserPurge(ctx context.Context, userID ID, purgeAt time.Time) error
+	// CancelUserPurge cancels the scheduled deletion of the user's account.
+	// [ErrNoAccountDeletion] is returned if none is scheduled.
+	CancelUserPurge(ctx context.Context, userID ID) error
+	// UsersDueForPurge returns the IDs of the users whose accounts are
+	// scheduled to be deleted by now.
+	UsersDueForPurge(ctx context.Context) ([]ID, error)
+	// DeleteUser deletes the user along with all of their data.
+	// [ErrUnknownUser] is returned if there is no such user.
+	DeleteUser(ctx context.Context, userID ID) error
 }
 
-// Secret is a secret identifier for a user. This secret is generated once
-// and never changes. It is used to both authenticate and identify a user, so it
-// should be kept secret.
+// Secret is a secret identifier for a user. This secret is generated when the
+// user is created and only changes when the user rotates it. It is used to
+// authenticate a user, so it should be kept secret. Only its hash is stored,
+// see [UserService].
 type Secret string
 
 var (
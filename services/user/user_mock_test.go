@@ -6,6 +6,9 @@ package user
 import (
 	"context"
 	"sync"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
 )
 
 // Ensure, that UserStorageMock does implement UserStorage.
@@ -18,18 +21,54 @@ var _ UserStorage = &UserStorageMock{}
 //
 //		// make and configure a mocked UserStorage
 //		mockedUserStorage := &UserStorageMock{
-//			CreateUserFunc: func(ctx context.Context, secret Secret, name string) (User, error) {
+//			CancelUserPurgeFunc: func(ctx context.Context, userID ID) error {
+//				panic("mock out the CancelUserPurge method")
+//			},
+//			CreateUserFunc: func(ctx context.Context, secretHash []byte, name string) (User, error) {
 //				panic("mock out the CreateUser method")
 //			},
-//			UpdateUserLocaleFunc: func(ctx context.Context, secret Secret, locale Locale) error {
+//			DeleteUserFunc: func(ctx context.Context, userID ID) error {
+//				panic("mock out the DeleteUser method")
+//			},
+//			HashPlainSecretsFunc: func(ctx context.Context, hash func([]byte) []byte) (int64, error) {
+//				panic("mock out the HashPlainSecrets method")
+//			},
+//			RecoveryCodeCountFunc: func(ctx context.Context, userID ID) (int, error) {
+//				panic("mock out the RecoveryCodeCount method")
+//			},
+//			ReplaceRecoveryCodesFunc: func(ctx context.Context, userID ID, codeHashes [][]byte) error {
+//				panic("mock out the ReplaceRecoveryCodes method")
+//			},
+//			ReplaceUserSecretFunc: func(ctx context.Context, userID ID, secretHash []byte, keepSessionID int64) error {
+//				panic("mock out the ReplaceUserSecret method")
+//			},
+//			ScheduleUserPurgeFunc: func(ctx context.Context, userID ID, purgeAt time.Time) error {
+//				panic("mock out the ScheduleUserPurge method")
+//			},
+//			SetUserSecretHashFunc: func(ctx context.Context, userID ID, secretHash []byte) error {
+//				panic("mock out the SetUserSecretHash method")
+//			},
+//			UpdateUserLocaleFunc: func(ctx context.Context, userID ID, locale Locale) error {
 //				panic("mock out the UpdateUserLocale method")
 //			},
-//			UpdateUserNameFunc: func(ctx context.Context, secret Secret, name string) error {
+//			UpdateUserNameFunc: func(ctx context.Context, userID ID, name string) error {
 //				panic("mock out the UpdateUserName method")
 //			},
-//			UserFunc: func(ctx context.Context, secret Secret) (User, error) {
+//			UpdateUserProfileFunc: func(ctx context.Context, userID ID, update ProfileUpdate) (User, error) {
+//				panic("mock out the UpdateUserProfile method")
+//			},
+//			UseRecoveryCodeFunc: func(ctx context.Context, codeHash []byte) (ID, error) {
+//				panic("mock out the UseRecoveryCode method")
+//			},
+//			UserFunc: func(ctx context.Context, userID ID) (User, error) {
 //				panic("mock out the User method")
 //			},
+//			UserIDBySecretFunc: func(ctx context.Context, secretHash []byte) (ID, error) {
+//				panic("mock out the UserIDBySecret method")
+//			},
+//			UsersDueForPurgeFunc: func(ctx context.Context) ([]ID, error) {
+//				panic("mock out the UsersDueForPurge method")
+//			},
 //		}
 //
 //		// use mockedUserStorage in code that requires UserStorage
@@ -37,35 +76,137 @@ var _ UserStorage = &UserStorageMock{}
 //
 //	}
 type UserStorageMock struct {
+	// CancelUserPurgeFunc mocks the CancelUserPurge method.
+	CancelUserPurgeFunc func(ctx context.Context, userID ID) error
+
 	// CreateUserFunc mocks the CreateUser method.
-	CreateUserFunc func(ctx context.Context, secret Secret, name string) (User, error)
+	CreateUserFunc func(ctx context.Context, secretHash []byte, name string) (User, error)
+
+	// DeleteUserFunc mocks the DeleteUser method.
+	DeleteUserFunc func(ctx context.Context, userID ID) error
+
+	// HashPlainSecretsFunc mocks the HashPlainSecrets method.
+	HashPlainSecretsFunc func(ctx context.Context, hash func([]byte) []byte) (int64, error)
+
+	// RecoveryCodeCountFunc mocks the RecoveryCodeCount method.
+	RecoveryCodeCountFunc func(ctx context.Context, userID ID) (int, error)
+
+	// ReplaceRecoveryCodesFunc mocks the ReplaceRecoveryCodes method.
+	ReplaceRecoveryCodesFunc func(ctx context.Context, userID ID, codeHashes [][]byte) error
+
+	// ReplaceUserSecretFunc mocks the ReplaceUserSecret method.
+	ReplaceUserSecretFunc func(ctx context.Context, userID ID, secretHash []byte, keepSessionID int64) error
+
+	// ScheduleUserPurgeFunc mocks the ScheduleUserPurge method.
+	ScheduleUserPurgeFunc func(ctx context.Context, userID ID, purgeAt time.Time) error
+
+	// SetUserSecretHashFunc mocks the SetUserSecretHash method.
+	SetUserSecretHashFunc func(ctx context.Context, userID ID, secretHash []byte) error
 
 	// UpdateUserLocaleFunc mocks the UpdateUserLocale method.
-	UpdateUserLocaleFunc func(ctx context.Context, secret Secret, locale Locale) error
+	UpdateUserLocaleFunc func(ctx context.Context, userID ID, locale Locale) error
 
 	// UpdateUserNameFunc mocks the UpdateUserName method.
-	UpdateUserNameFunc func(ctx context.Context, secret Secret, name string) error
+	UpdateUserNameFunc func(ctx context.Context, userID ID, name string) error
+
+	// UpdateUserProfileFunc mocks the UpdateUserProfile method.
+	UpdateUserProfileFunc func(ctx context.Context, userID ID, update ProfileUpdate) (User, error)
+
+	// UseRecoveryCodeFunc mocks the UseRecoveryCode method.
+	UseRecoveryCodeFunc func(ctx context.Context, codeHash []byte) (ID, error)
 
 	// UserFunc mocks the User method.
-	UserFunc func(ctx context.Context, secret Secret) (User, error)
+	UserFunc func(ctx context.Context, userID ID) (User, error)
+
+	// UserIDBySecretFunc mocks the UserIDBySecret method.
+	UserIDBySecretFunc func(ctx context.Context, secretHash []byte) (ID, error)
+
+	// UsersDueForPurgeFunc mocks the UsersDueForPurge method.
+	UsersDueForPurgeFunc func(ctx context.Context) ([]ID, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// CancelUserPurge holds details about calls to the CancelUserPurge method.
+		CancelUserPurge []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+		}
 		// CreateUser holds details about calls to the CreateUser method.
 		CreateUser []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// Secret is the secret argument value.
-			Secret Secret
+			// SecretHash is the secretHash argument value.
+			SecretHash []byte
 			// Name is the name argument value.
 			Name string
 		}
+		// DeleteUser holds details about calls to the DeleteUser method.
+		DeleteUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+		}
+		// HashPlainSecrets holds details about calls to the HashPlainSecrets method.
+		HashPlainSecrets []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Hash is the hash argument value.
+			Hash func([]byte) []byte
+		}
+		// RecoveryCodeCount holds details about calls to the RecoveryCodeCount method.
+		RecoveryCodeCount []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+		}
+		// ReplaceRecoveryCodes holds details about calls to the ReplaceRecoveryCodes method.
+		ReplaceRecoveryCodes []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+			// CodeHashes is the codeHashes argument value.
+			CodeHashes [][]byte
+		}
+		// ReplaceUserSecret holds details about calls to the ReplaceUserSecret method.
+		ReplaceUserSecret []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+			// SecretHash is the secretHash argument value.
+			SecretHash []byte
+			// KeepSessionID is the keepSessionID argument value.
+			KeepSessionID int64
+		}
+		// ScheduleUserPurge holds details about calls to the ScheduleUserPurge method.
+		ScheduleUserPurge []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+			// PurgeAt is the purgeAt argument value.
+			PurgeAt time.Time
+		}
+		// SetUserSecretHash holds details about calls to the SetUserSecretHash method.
+		SetUserSecretHash []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+			// SecretHash is the secretHash argument value.
+			SecretHash []byte
+		}
 		// UpdateUserLocale holds details about calls to the UpdateUserLocale method.
 		UpdateUserLocale []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// Secret is the secret argument value.
-			Secret Secret
+			// UserID is the userID argument value.
+			UserID ID
 			// Locale is the locale argument value.
 			Locale Locale
 		}
@@ -73,35 +214,114 @@ type UserStorageMock struct {
 		UpdateUserName []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// Secret is the secret argument value.
-			Secret Secret
+			// UserID is the userID argument value.
+			UserID ID
 			// Name is the name argument value.
 			Name string
 		}
+		// UpdateUserProfile holds details about calls to the UpdateUserProfile method.
+		UpdateUserProfile []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+			// Update is the update argument value.
+			Update ProfileUpdate
+		}
+		// UseRecoveryCode holds details about calls to the UseRecoveryCode method.
+		UseRecoveryCode []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CodeHash is the codeHash argument value.
+			CodeHash []byte
+		}
 		// User holds details about calls to the User method.
 		User []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// Secret is the secret argument value.
-			Secret Secret
+			// UserID is the userID argument value.
+			UserID ID
+		}
+		// UserIDBySecret holds details about calls to the UserIDBySecret method.
+		UserIDBySecret []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SecretHash is the secretHash argument value.
+			SecretHash []byte
+		}
+		// UsersDueForPurge holds details about calls to the UsersDueForPurge method.
+		UsersDueForPurge []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 		}
 	}
-	lockCreateUser       sync.RWMutex
-	lockUpdateUserLocale sync.RWMutex
-	lockUpdateUserName   sync.RWMutex
-	lockUser             sync.RWMutex
+	lockCancelUserPurge      sync.RWMutex
+	lockCreateUser           sync.RWMutex
+	lockDeleteUser           sync.RWMutex
+	lockHashPlainSecrets     sync.RWMutex
+	lockRecoveryCodeCount    sync.RWMutex
+	lockReplaceRecoveryCodes sync.RWMutex
+	lockReplaceUserSecret    sync.RWMutex
+	lockScheduleUserPurge    sync.RWMutex
+	lockSetUserSecretHash    sync.RWMutex
+	lockUpdateUserLocale     sync.RWMutex
+	lockUpdateUserName       sync.RWMutex
+	lockUpdateUserProfile    sync.RWMutex
+	lockUseRecoveryCode      sync.RWMutex
+	lockUser                 sync.RWMutex
+	lockUserIDBySecret       sync.RWMutex
+	lockUsersDueForPurge     sync.RWMutex
 }
 
-// CreateUser calls CreateUserFunc.
-func (mock *UserStorageMock) CreateUser(ctx context.Context, secret Secret, name string) (User, error) {
+// CancelUserPurge calls CancelUserPurgeFunc.
+func (mock *UserStorageMock) CancelUserPurge(ctx context.Context, userID ID) error {
 	callInfo := struct {
 		Ctx    context.Context
-		Secret Secret
-		Name   string
+		UserID ID
 	}{
 		Ctx:    ctx,
-		Secret: secret,
-		Name:   name,
+		UserID: userID,
+	}
+	mock.lockCancelUserPurge.Lock()
+	mock.calls.CancelUserPurge = append(mock.calls.CancelUserPurge, callInfo)
+	mock.lockCancelUserPurge.Unlock()
+	if mock.CancelUserPurgeFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.CancelUserPurgeFunc(ctx, userID)
+}
+
+// CancelUserPurgeCalls gets all the calls that were made to CancelUserPurge.
+// Check the length with:
+//
+//	len(mockedUserStorage.CancelUserPurgeCalls())
+func (mock *UserStorageMock) CancelUserPurgeCalls() []struct {
+	Ctx    context.Context
+	UserID ID
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID ID
+	}
+	mock.lockCancelUserPurge.RLock()
+	calls = mock.calls.CancelUserPurge
+	mock.lockCancelUserPurge.RUnlock()
+	return calls
+}
+
+// CreateUser calls CreateUserFunc.
+func (mock *UserStorageMock) CreateUser(ctx context.Context, secretHash []byte, name string) (User, error) {
+	callInfo := struct {
+		Ctx        context.Context
+		SecretHash []byte
+		Name       string
+	}{
+		Ctx:        ctx,
+		SecretHash: secretHash,
+		Name:       name,
 	}
 	mock.lockCreateUser.Lock()
 	mock.calls.CreateUser = append(mock.calls.CreateUser, callInfo)
@@ -113,7 +333,7 @@ func (mock *UserStorageMock) CreateUser(ctx context.Context, secret Secret, name
 		)
 		return userOut, errOut
 	}
-	return mock.CreateUserFunc(ctx, secret, name)
+	return mock.CreateUserFunc(ctx, secretHash, name)
 }
 
 // CreateUserCalls gets all the calls that were made to CreateUser.
@@ -121,14 +341,14 @@ func (mock *UserStorageMock) CreateUser(ctx context.Context, secret Secret, name
 //
 //	len(mockedUserStorage.CreateUserCalls())
 func (mock *UserStorageMock) CreateUserCalls() []struct {
-	Ctx    context.Context
-	Secret Secret
-	Name   string
+	Ctx        context.Context
+	SecretHash []byte
+	Name       string
 } {
 	var calls []struct {
-		Ctx    context.Context
-		Secret Secret
-		Name   string
+		Ctx        context.Context
+		SecretHash []byte
+		Name       string
 	}
 	mock.lockCreateUser.RLock()
 	calls = mock.calls.CreateUser
@@ -136,382 +356,1929 @@ func (mock *UserStorageMock) CreateUserCalls() []struct {
 	return calls
 }
 
-// UpdateUserLocale calls UpdateUserLocaleFunc.
-func (mock *UserStorageMock) UpdateUserLocale(ctx context.Context, secret Secret, locale Locale) error {
+// DeleteUser calls DeleteUserFunc.
+func (mock *UserStorageMock) DeleteUser(ctx context.Context, userID ID) error {
 	callInfo := struct {
 		Ctx    context.Context
-		Secret Secret
-		Locale Locale
+		UserID ID
 	}{
 		Ctx:    ctx,
-		Secret: secret,
-		Locale: locale,
+		UserID: userID,
 	}
-	mock.lockUpdateUserLocale.Lock()
-	mock.calls.UpdateUserLocale = append(mock.calls.UpdateUserLocale, callInfo)
-	mock.lockUpdateUserLocale.Unlock()
-	if mock.UpdateUserLocaleFunc == nil {
+	mock.lockDeleteUser.Lock()
+	mock.calls.DeleteUser = append(mock.calls.DeleteUser, callInfo)
+	mock.lockDeleteUser.Unlock()
+	if mock.DeleteUserFunc == nil {
 		var (
 			errOut error
 		)
 		return errOut
 	}
-	return mock.UpdateUserLocaleFunc(ctx, secret, locale)
+	return mock.DeleteUserFunc(ctx, userID)
 }
 
-// UpdateUserLocaleCalls gets all the calls that were made to UpdateUserLocale.
+// DeleteUserCalls gets all the calls that were made to DeleteUser.
 // Check the length with:
 //
-//	len(mockedUserStorage.UpdateUserLocaleCalls())
-func (mock *UserStorageMock) UpdateUserLocaleCalls() []struct {
+//	len(mockedUserStorage.DeleteUserCalls())
+func (mock *UserStorageMock) DeleteUserCalls() []struct {
 	Ctx    context.Context
-	Secret Secret
-	Locale Locale
+	UserID ID
 } {
 	var calls []struct {
 		Ctx    context.Context
-		Secret Secret
-		Locale Locale
+		UserID ID
 	}
-	mock.lockUpdateUserLocale.RLock()
-	calls = mock.calls.UpdateUserLocale
-	mock.lockUpdateUserLocale.RUnlock()
+	mock.lockDeleteUser.RLock()
+	calls = mock.calls.DeleteUser
+	mock.lockDeleteUser.RUnlock()
 	return calls
 }
 
-// UpdateUserName calls UpdateUserNameFunc.
-func (mock *UserStorageMock) UpdateUserName(ctx context.Context, secret Secret, name string) error {
+// HashPlainSecrets calls HashPlainSecretsFunc.
+func (mock *UserStorageMock) HashPlainSecrets(ctx context.Context, hash func([]byte) []byte) (int64, error) {
 	callInfo := struct {
-		Ctx    context.Context
-		Secret Secret
-		Name   string
+		Ctx  context.Context
+		Hash func([]byte) []byte
 	}{
-		Ctx:    ctx,
-		Secret: secret,
-		Name:   name,
+		Ctx:  ctx,
+		Hash: hash,
 	}
-	mock.lockUpdateUserName.Lock()
-	mock.calls.UpdateUserName = append(mock.calls.UpdateUserName, callInfo)
-	mock.lockUpdateUserName.Unlock()
-	if mock.UpdateUserNameFunc == nil {
+	mock.lockHashPlainSecrets.Lock()
+	mock.calls.HashPlainSecrets = append(mock.calls.HashPlainSecrets, callInfo)
+	mock.lockHashPlainSecrets.Unlock()
+	if mock.HashPlainSecretsFunc == nil {
 		var (
+			nOut   int64
 			errOut error
 		)
-		return errOut
+		return nOut, errOut
 	}
-	return mock.UpdateUserNameFunc(ctx, secret, name)
+	return mock.HashPlainSecretsFunc(ctx, hash)
 }
 
-// UpdateUserNameCalls gets all the calls that were made to UpdateUserName.
+// HashPlainSecretsCalls gets all the calls that were made to HashPlainSecrets.
 // Check the length with:
 //
-//	len(mockedUserStorage.UpdateUserNameCalls())
-func (mock *UserStorageMock) UpdateUserNameCalls() []struct {
-	Ctx    context.Context
-	Secret Secret
-	Name   string
+//	len(mockedUserStorage.HashPlainSecretsCalls())
+func (mock *UserStorageMock) HashPlainSecretsCalls() []struct {
+	Ctx  context.Context
+	Hash func([]byte) []byte
 } {
 	var calls []struct {
-		Ctx    context.Context
-		Secret Secret
-		Name   string
+		Ctx  context.Context
+		Hash func([]byte) []byte
 	}
-	mock.lockUpdateUserName.RLock()
-	calls = mock.calls.UpdateUserName
-	mock.lockUpdateUserName.RUnlock()
+	mock.lockHashPlainSecrets.RLock()
+	calls = mock.calls.HashPlainSecrets
+	mock.lockHashPlainSecrets.RUnlock()
 	return calls
 }
 
-// User calls UserFunc.
-func (mock *UserStorageMock) User(ctx context.Context, secret Secret) (User, error) {
+// RecoveryCodeCount calls RecoveryCodeCountFunc.
+func (mock *UserStorageMock) RecoveryCodeCount(ctx context.Context, userID ID) (int, error) {
 	callInfo := struct {
 		Ctx    context.Context
-		Secret Secret
+		UserID ID
 	}{
 		Ctx:    ctx,
-		Secret: secret,
+		UserID: userID,
 	}
-	mock.lockUser.Lock()
-	mock.calls.User = append(mock.calls.User, callInfo)
-	mock.lockUser.Unlock()
-	if mock.UserFunc == nil {
+	mock.lockRecoveryCodeCount.Lock()
+	mock.calls.RecoveryCodeCount = append(mock.calls.RecoveryCodeCount, callInfo)
+	mock.lockRecoveryCodeCount.Unlock()
+	if mock.RecoveryCodeCountFunc == nil {
 		var (
-			userOut User
-			errOut  error
+			nOut   int
+			errOut error
 		)
-		return userOut, errOut
+		return nOut, errOut
 	}
-	return mock.UserFunc(ctx, secret)
+	return mock.RecoveryCodeCountFunc(ctx, userID)
 }
 
-// UserCalls gets all the calls that were made to User.
+// RecoveryCodeCountCalls gets all the calls that were made to RecoveryCodeCount.
 // Check the length with:
 //
-//	len(mockedUserStorage.UserCalls())
-func (mock *UserStorageMock) UserCalls() []struct {
+//	len(mockedUserStorage.RecoveryCodeCountCalls())
+func (mock *UserStorageMock) RecoveryCodeCountCalls() []struct {
 	Ctx    context.Context
-	Secret Secret
+	UserID ID
 } {
 	var calls []struct {
 		Ctx    context.Context
-		Secret Secret
+		UserID ID
 	}
-	mock.lockUser.RLock()
-	calls = mock.calls.User
-	mock.lockUser.RUnlock()
+	mock.lockRecoveryCodeCount.RLock()
+	calls = mock.calls.RecoveryCodeCount
+	mock.lockRecoveryCodeCount.RUnlock()
 	return calls
 }
 
-// Ensure, that UserSessionStorageMock does implement UserSessionStorage.
-// If this is not the case, regenerate this file with moq.
-var _ UserSessionStorage = &UserSessionStorageMock{}
-
-// UserSessionStorageMock is a mock implementation of UserSessionStorage.
-//
-//	func TestSomethingThatUsesUserSessionStorage(t *testing.T) {
-//
-//		// make and configure a mocked UserSessionStorage
-//		mockedUserSessionStorage := &UserSessionStorageMock{
-//			DeleteSessionFunc: func(ctx context.Context, userSecret Secret, sessionID int64) error {
-//				panic("mock out the DeleteSession method")
-//			},
-//			ListSessionsFunc: func(ctx context.Context, userSecret Secret) ([]Session, error) {
-//				panic("mock out the ListSessions method")
-//			},
-//			RegisterSessionFunc: func(ctx context.Context, token []byte, userSecret Secret, userAgent string) error {
-//				panic("mock out the RegisterSession method")
-//			},
-//			ValidateSessionFunc: func(ctx context.Context, token []byte) (Session, error) {
-//				panic("mock out the ValidateSession method")
-//			},
-//		}
-//
-//		// use mockedUserSessionStorage in code that requires UserSessionStorage
-//		// and then make assertions.
-//
-//	}
-type UserSessionStorageMock struct {
-	// DeleteSessionFunc mocks the DeleteSession method.
-	DeleteSessionFunc func(ctx context.Context, userSecret Secret, sessionID int64) error
-
-	// ListSessionsFunc mocks the ListSessions method.
-	ListSessionsFunc func(ctx context.Context, userSecret Secret) ([]Session, error)
-
-	// RegisterSessionFunc mocks the RegisterSession method.
-	RegisterSessionFunc func(ctx context.Context, token []byte, userSecret Secret, userAgent string) error
-
-	// ValidateSessionFunc mocks the ValidateSession method.
-	ValidateSessionFunc func(ctx context.Context, token []byte) (Session, error)
-
-	// calls tracks calls to the methods.
-	calls struct {
-		// DeleteSession holds details about calls to the DeleteSession method.
-		DeleteSession []struct {
-			// Ctx is the ctx argument value.
-			Ctx context.Context
-			// UserSecret is the userSecret argument value.
-			UserSecret Secret
-			// SessionID is the sessionID argument value.
-			SessionID int64
-		}
-		// ListSessions holds details about calls to the ListSessions method.
-		ListSessions []struct {
-			// Ctx is the ctx argument value.
-			Ctx context.Context
-			// UserSecret is the userSecret argument value.
-			UserSecret Secret
-		}
-		// RegisterSession holds details about calls to the RegisterSession method.
-		RegisterSession []struct {
-			// Ctx is the ctx argument value.
-			Ctx context.Context
-			// Token is the token argument value.
-			Token []byte
-			// UserSecret is the userSecret argument value.
-			UserSecret Secret
-			// UserAgent is the userAgent argument value.
-			UserAgent string
-		}
-		// ValidateSession holds details about calls to the ValidateSession method.
-		ValidateSession []struct {
-			// Ctx is the ctx argument value.
-			Ctx context.Context
-			// Token is the token argument value.
-			Token []byte
-		}
-	}
-	lockDeleteSession   sync.RWMutex
-	lockListSessions    sync.RWMutex
-	lockRegisterSession sync.RWMutex
-	lockValidateSession sync.RWMutex
-}
-
-// DeleteSession calls DeleteSessionFunc.
-func (mock *UserSessionStorageMock) DeleteSession(ctx context.Context, userSecret Secret, sessionID int64) error {
+// ReplaceRecoveryCodes calls ReplaceRecoveryCodesFunc.
+func (mock *UserStorageMock) ReplaceRecoveryCodes(ctx context.Context, userID ID, codeHashes [][]byte) error {
 	callInfo := struct {
 		Ctx        context.Context
-		UserSecret Secret
-		SessionID  int64
+		UserID     ID
+		CodeHashes [][]byte
 	}{
 		Ctx:        ctx,
-		UserSecret: userSecret,
-		SessionID:  sessionID,
+		UserID:     userID,
+		CodeHashes: codeHashes,
 	}
-	mock.lockDeleteSession.Lock()
-	mock.calls.DeleteSession = append(mock.calls.DeleteSession, callInfo)
-	mock.lockDeleteSession.Unlock()
-	if mock.DeleteSessionFunc == nil {
+	mock.lockReplaceRecoveryCodes.Lock()
+	mock.calls.ReplaceRecoveryCodes = append(mock.calls.ReplaceRecoveryCodes, callInfo)
+	mock.lockReplaceRecoveryCodes.Unlock()
+	if mock.ReplaceRecoveryCodesFunc == nil {
 		var (
 			errOut error
 		)
 		return errOut
 	}
-	return mock.DeleteSessionFunc(ctx, userSecret, sessionID)
+	return mock.ReplaceRecoveryCodesFunc(ctx, userID, codeHashes)
 }
 
-// DeleteSessionCalls gets all the calls that were made to DeleteSession.
+// ReplaceRecoveryCodesCalls gets all the calls that were made to ReplaceRecoveryCodes.
 // Check the length with:
 //
-//	len(mockedUserSessionStorage.DeleteSessionCalls())
-func (mock *UserSessionStorageMock) DeleteSessionCalls() []struct {
+//	len(mockedUserStorage.ReplaceRecoveryCodesCalls())
+func (mock *UserStorageMock) ReplaceRecoveryCodesCalls() []struct {
 	Ctx        context.Context
-	UserSecret Secret
-	SessionID  int64
+	UserID     ID
+	CodeHashes [][]byte
 } {
 	var calls []struct {
 		Ctx        context.Context
-		UserSecret Secret
-		SessionID  int64
+		UserID     ID
+		CodeHashes [][]byte
 	}
-	mock.lockDeleteSession.RLock()
-	calls = mock.calls.DeleteSession
-	mock.lockDeleteSession.RUnlock()
+	mock.lockReplaceRecoveryCodes.RLock()
+	calls = mock.calls.ReplaceRecoveryCodes
+	mock.lockReplaceRecoveryCodes.RUnlock()
 	return calls
 }
 
-// ListSessions calls ListSessionsFunc.
-func (mock *UserSessionStorageMock) ListSessions(ctx context.Context, userSecret Secret) ([]Session, error) {
+// ReplaceUserSecret calls ReplaceUserSecretFunc.
+func (mock *UserStorageMock) ReplaceUserSecret(ctx context.Context, userID ID, secretHash []byte, keepSessionID int64) error {
 	callInfo := struct {
-		Ctx        context.Context
-		UserSecret Secret
+		Ctx           context.Context
+		UserID        ID
+		SecretHash    []byte
+		KeepSessionID int64
 	}{
-		Ctx:        ctx,
-		UserSecret: userSecret,
+		Ctx:           ctx,
+		UserID:        userID,
+		SecretHash:    secretHash,
+		KeepSessionID: keepSessionID,
 	}
-	mock.lockListSessions.Lock()
-	mock.calls.ListSessions = append(mock.calls.ListSessions, callInfo)
-	mock.lockListSessions.Unlock()
-	if mock.ListSessionsFunc == nil {
+	mock.lockReplaceUserSecret.Lock()
+	mock.calls.ReplaceUserSecret = append(mock.calls.ReplaceUserSecret, callInfo)
+	mock.lockReplaceUserSecret.Unlock()
+	if mock.ReplaceUserSecretFunc == nil {
 		var (
-			sessionsOut []Session
-			errOut      error
+			errOut error
 		)
-		return sessionsOut, errOut
+		return errOut
 	}
-	return mock.ListSessionsFunc(ctx, userSecret)
+	return mock.ReplaceUserSecretFunc(ctx, userID, secretHash, keepSessionID)
 }
 
-// ListSessionsCalls gets all the calls that were made to ListSessions.
+// ReplaceUserSecretCalls gets all the calls that were made to ReplaceUserSecret.
 // Check the length with:
 //
-//	len(mockedUserSessionStorage.ListSessionsCalls())
-func (mock *UserSessionStorageMock) ListSessionsCalls() []struct {
-	Ctx        context.Context
-	UserSecret Secret
+//	len(mockedUserStorage.ReplaceUserSecretCalls())
+func (mock *UserStorageMock) ReplaceUserSecretCalls() []struct {
+	Ctx           context.Context
+	UserID        ID
+	SecretHash    []byte
+	KeepSessionID int64
 } {
 	var calls []struct {
-		Ctx        context.Context
-		UserSecret Secret
+		Ctx           context.Context
+		UserID        ID
+		SecretHash    []byte
+		KeepSessionID int64
 	}
-	mock.lockListSessions.RLock()
-	calls = mock.calls.ListSessions
-	mock.lockListSessions.RUnlock()
+	mock.lockReplaceUserSecret.RLock()
+	calls = mock.calls.ReplaceUserSecret
+	mock.lockReplaceUserSecret.RUnlock()
 	return calls
 }
 
-// RegisterSession calls RegisterSessionFunc.
-func (mock *UserSessionStorageMock) RegisterSession(ctx context.Context, token []byte, userSecret Secret, userAgent string) error {
+// ScheduleUserPurge calls ScheduleUserPurgeFunc.
+func (mock *UserStorageMock) ScheduleUserPurge(ctx context.Context, userID ID, purgeAt time.Time) error {
 	callInfo := struct {
-		Ctx        context.Context
-		Token      []byte
-		UserSecret Secret
-		UserAgent  string
+		Ctx     context.Context
+		UserID  ID
+		PurgeAt time.Time
 	}{
-		Ctx:        ctx,
-		Token:      token,
-		UserSecret: userSecret,
-		UserAgent:  userAgent,
+		Ctx:     ctx,
+		UserID:  userID,
+		PurgeAt: purgeAt,
 	}
-	mock.lockRegisterSession.Lock()
-	mock.calls.RegisterSession = append(mock.calls.RegisterSession, callInfo)
-	mock.lockRegisterSession.Unlock()
-	if mock.RegisterSessionFunc == nil {
+	mock.lockScheduleUserPurge.Lock()
+	mock.calls.ScheduleUserPurge = append(mock.calls.ScheduleUserPurge, callInfo)
+	mock.lockScheduleUserPurge.Unlock()
+	if mock.ScheduleUserPurgeFunc == nil {
 		var (
 			errOut error
 		)
 		return errOut
 	}
-	return mock.RegisterSessionFunc(ctx, token, userSecret, userAgent)
+	return mock.ScheduleUserPurgeFunc(ctx, userID, purgeAt)
 }
 
-// RegisterSessionCalls gets all the calls that were made to RegisterSession.
+// ScheduleUserPurgeCalls gets all the calls that were made to ScheduleUserPurge.
 // Check the length with:
 //
-//	len(mockedUserSessionStorage.RegisterSessionCalls())
-func (mock *UserSessionStorageMock) RegisterSessionCalls() []struct {
-	Ctx        context.Context
-	Token      []byte
-	UserSecret Secret
-	UserAgent  string
+//	len(mockedUserStorage.ScheduleUserPurgeCalls())
+func (mock *UserStorageMock) ScheduleUserPurgeCalls() []struct {
+	Ctx     context.Context
+	UserID  ID
+	PurgeAt time.Time
 } {
 	var calls []struct {
-		Ctx        context.Context
-		Token      []byte
-		UserSecret Secret
-		UserAgent  string
+		Ctx     context.Context
+		UserID  ID
+		PurgeAt time.Time
 	}
-	mock.lockRegisterSession.RLock()
-	calls = mock.calls.RegisterSession
-	mock.lockRegisterSession.RUnlock()
+	mock.lockScheduleUserPurge.RLock()
+	calls = mock.calls.ScheduleUserPurge
+	mock.lockScheduleUserPurge.RUnlock()
 	return calls
 }
 
-// ValidateSession calls ValidateSessionFunc.
-func (mock *UserSessionStorageMock) ValidateSession(ctx context.Context, token []byte) (Session, error) {
+// SetUserSecretHash calls SetUserSecretHashFunc.
+func (mock *UserStorageMock) SetUserSecretHash(ctx context.Context, userID ID, secretHash []byte) error {
 	callInfo := struct {
-		Ctx   context.Context
-		Token []byte
+		Ctx        context.Context
+		UserID     ID
+		SecretHash []byte
 	}{
-		Ctx:   ctx,
-		Token: token,
+		Ctx:        ctx,
+		UserID:     userID,
+		SecretHash: secretHash,
 	}
-	mock.lockValidateSession.Lock()
-	mock.calls.ValidateSession = append(mock.calls.ValidateSession, callInfo)
+	mock.lockSetUserSecretHash.Lock()
+	mock.calls.SetUserSecretHash = append(mock.calls.SetUserSecretHash, callInfo)
+	mock.lockSetUserSecretHash.Unlock()
+	if mock.SetUserSecretHashFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.SetUserSecretHashFunc(ctx, userID, secretHash)
+}
+
+// SetUserSecretHashCalls gets all the calls that were made to SetUserSecretHash.
+// Check the length with:
+//
+//	len(mockedUserStorage.SetUserSecretHashCalls())
+func (mock *UserStorageMock) SetUserSecretHashCalls() []struct {
+	Ctx        context.Context
+	UserID     ID
+	SecretHash []byte
+} {
+	var calls []struct {
+		Ctx        context.Context
+		UserID     ID
+		SecretHash []byte
+	}
+	mock.lockSetUserSecretHash.RLock()
+	calls = mock.calls.SetUserSecretHash
+	mock.lockSetUserSecretHash.RUnlock()
+	return calls
+}
+
+// UpdateUserLocale calls UpdateUserLocaleFunc.
+func (mock *UserStorageMock) UpdateUserLocale(ctx context.Context, userID ID, locale Locale) error {
+	callInfo := struct {
+		Ctx    context.Context
+		UserID ID
+		Locale Locale
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Locale: locale,
+	}
+	mock.lockUpdateUserLocale.Lock()
+	mock.calls.UpdateUserLocale = append(mock.calls.UpdateUserLocale, callInfo)
+	mock.lockUpdateUserLocale.Unlock()
+	if mock.UpdateUserLocaleFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.UpdateUserLocaleFunc(ctx, userID, locale)
+}
+
+// UpdateUserLocaleCalls gets all the calls that were made to UpdateUserLocale.
+// Check the length with:
+//
+//	len(mockedUserStorage.UpdateUserLocaleCalls())
+func (mock *UserStorageMock) UpdateUserLocaleCalls() []struct {
+	Ctx    context.Context
+	UserID ID
+	Locale Locale
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID ID
+		Locale Locale
+	}
+	mock.lockUpdateUserLocale.RLock()
+	calls = mock.calls.UpdateUserLocale
+	mock.lockUpdateUserLocale.RUnlock()
+	return calls
+}
+
+// UpdateUserName calls UpdateUserNameFunc.
+func (mock *UserStorageMock) UpdateUserName(ctx context.Context, userID ID, name string) error {
+	callInfo := struct {
+		Ctx    context.Context
+		UserID ID
+		Name   string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Name:   name,
+	}
+	mock.lockUpdateUserName.Lock()
+	mock.calls.UpdateUserName = append(mock.calls.UpdateUserName, callInfo)
+	mock.lockUpdateUserName.Unlock()
+	if mock.UpdateUserNameFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.UpdateUserNameFunc(ctx, userID, name)
+}
+
+// UpdateUserNameCalls gets all the calls that were made to UpdateUserName.
+// Check the length with:
+//
+//	len(mockedUserStorage.UpdateUserNameCalls())
+func (mock *UserStorageMock) UpdateUserNameCalls() []struct {
+	Ctx    context.Context
+	UserID ID
+	Name   string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID ID
+		Name   string
+	}
+	mock.lockUpdateUserName.RLock()
+	calls = mock.calls.UpdateUserName
+	mock.lockUpdateUserName.RUnlock()
+	return calls
+}
+
+// UpdateUserProfile calls UpdateUserProfileFunc.
+func (mock *UserStorageMock) UpdateUserProfile(ctx context.Context, userID ID, update ProfileUpdate) (User, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		UserID ID
+		Update ProfileUpdate
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Update: update,
+	}
+	mock.lockUpdateUserProfile.Lock()
+	mock.calls.UpdateUserProfile = append(mock.calls.UpdateUserProfile, callInfo)
+	mock.lockUpdateUserProfile.Unlock()
+	if mock.UpdateUserProfileFunc == nil {
+		var (
+			userOut User
+			errOut  error
+		)
+		return userOut, errOut
+	}
+	return mock.UpdateUserProfileFunc(ctx, userID, update)
+}
+
+// UpdateUserProfileCalls gets all the calls that were made to UpdateUserProfile.
+// Check the length with:
+//
+//	len(mockedUserStorage.UpdateUserProfileCalls())
+func (mock *UserStorageMock) UpdateUserProfileCalls() []struct {
+	Ctx    context.Context
+	UserID ID
+	Update ProfileUpdate
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID ID
+		Update ProfileUpdate
+	}
+	mock.lockUpdateUserProfile.RLock()
+	calls = mock.calls.UpdateUserProfile
+	mock.lockUpdateUserProfile.RUnlock()
+	return calls
+}
+
+// UseRecoveryCode calls UseRecoveryCodeFunc.
+func (mock *UserStorageMock) UseRecoveryCode(ctx context.Context, codeHash []byte) (ID, error) {
+	callInfo := struct {
+		Ctx      context.Context
+		CodeHash []byte
+	}{
+		Ctx:      ctx,
+		CodeHash: codeHash,
+	}
+	mock.lockUseRecoveryCode.Lock()
+	mock.calls.UseRecoveryCode = append(mock.calls.UseRecoveryCode, callInfo)
+	mock.lockUseRecoveryCode.Unlock()
+	if mock.UseRecoveryCodeFunc == nil {
+		var (
+			iDOut  ID
+			errOut error
+		)
+		return iDOut, errOut
+	}
+	return mock.UseRecoveryCodeFunc(ctx, codeHash)
+}
+
+// UseRecoveryCodeCalls gets all the calls that were made to UseRecoveryCode.
+// Check the length with:
+//
+//	len(mockedUserStorage.UseRecoveryCodeCalls())
+func (mock *UserStorageMock) UseRecoveryCodeCalls() []struct {
+	Ctx      context.Context
+	CodeHash []byte
+} {
+	var calls []struct {
+		Ctx      context.Context
+		CodeHash []byte
+	}
+	mock.lockUseRecoveryCode.RLock()
+	calls = mock.calls.UseRecoveryCode
+	mock.lockUseRecoveryCode.RUnlock()
+	return calls
+}
+
+// User calls UserFunc.
+func (mock *UserStorageMock) User(ctx context.Context, userID ID) (User, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		UserID ID
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockUser.Lock()
+	mock.calls.User = append(mock.calls.User, callInfo)
+	mock.lockUser.Unlock()
+	if mock.UserFunc == nil {
+		var (
+			userOut User
+			errOut  error
+		)
+		return userOut, errOut
+	}
+	return mock.UserFunc(ctx, userID)
+}
+
+// UserCalls gets all the calls that were made to User.
+// Check the length with:
+//
+//	len(mockedUserStorage.UserCalls())
+func (mock *UserStorageMock) UserCalls() []struct {
+	Ctx    context.Context
+	UserID ID
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID ID
+	}
+	mock.lockUser.RLock()
+	calls = mock.calls.User
+	mock.lockUser.RUnlock()
+	return calls
+}
+
+// UserIDBySecret calls UserIDBySecretFunc.
+func (mock *UserStorageMock) UserIDBySecret(ctx context.Context, secretHash []byte) (ID, error) {
+	callInfo := struct {
+		Ctx        context.Context
+		SecretHash []byte
+	}{
+		Ctx:        ctx,
+		SecretHash: secretHash,
+	}
+	mock.lockUserIDBySecret.Lock()
+	mock.calls.UserIDBySecret = append(mock.calls.UserIDBySecret, callInfo)
+	mock.lockUserIDBySecret.Unlock()
+	if mock.UserIDBySecretFunc == nil {
+		var (
+			iDOut  ID
+			errOut error
+		)
+		return iDOut, errOut
+	}
+	return mock.UserIDBySecretFunc(ctx, secretHash)
+}
+
+// UserIDBySecretCalls gets all the calls that were made to UserIDBySecret.
+// Check the length with:
+//
+//	len(mockedUserStorage.UserIDBySecretCalls())
+func (mock *UserStorageMock) UserIDBySecretCalls() []struct {
+	Ctx        context.Context
+	SecretHash []byte
+} {
+	var calls []struct {
+		Ctx        context.Context
+		SecretHash []byte
+	}
+	mock.lockUserIDBySecret.RLock()
+	calls = mock.calls.UserIDBySecret
+	mock.lockUserIDBySecret.RUnlock()
+	return calls
+}
+
+// UsersDueForPurge calls UsersDueForPurgeFunc.
+func (mock *UserStorageMock) UsersDueForPurge(ctx context.Context) ([]ID, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockUsersDueForPurge.Lock()
+	mock.calls.UsersDueForPurge = append(mock.calls.UsersDueForPurge, callInfo)
+	mock.lockUsersDueForPurge.Unlock()
+	if mock.UsersDueForPurgeFunc == nil {
+		var (
+			iDsOut []ID
+			errOut error
+		)
+		return iDsOut, errOut
+	}
+	return mock.UsersDueForPurgeFunc(ctx)
+}
+
+// UsersDueForPurgeCalls gets all the calls that were made to UsersDueForPurge.
+// Check the length with:
+//
+//	len(mockedUserStorage.UsersDueForPurgeCalls())
+func (mock *UserStorageMock) UsersDueForPurgeCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockUsersDueForPurge.RLock()
+	calls = mock.calls.UsersDueForPurge
+	mock.lockUsersDueForPurge.RUnlock()
+	return calls
+}
+
+// Ensure, that UserSessionStorageMock does implement UserSessionStorage.
+// If this is not the case, regenerate this file with moq.
+var _ UserSessionStorage = &UserSessionStorageMock{}
+
+// UserSessionStorageMock is a mock implementation of UserSessionStorage.
+//
+//	func TestSomethingThatUsesUserSessionStorage(t *testing.T) {
+//
+//		// make and configure a mocked UserSessionStorage
+//		mockedUserSessionStorage := &UserSessionStorageMock{
+//			DeleteExpiredSessionsFunc: func(ctx context.Context, lifetime SessionLifetime) (int64, error) {
+//				panic("mock out the DeleteExpiredSessions method")
+//			},
+//			DeleteOtherSessionsFunc: func(ctx context.Context, userID ID, keepSessionID int64) error {
+//				panic("mock out the DeleteOtherSessions method")
+//			},
+//			DeleteSessionFunc: func(ctx context.Context, userID ID, sessionID int64) error {
+//				panic("mock out the DeleteSession method")
+//			},
+//			ListSessionsFunc: func(ctx context.Context, userID ID) ([]Session, error) {
+//				panic("mock out the ListSessions method")
+//			},
+//			RegisterSessionFunc: func(ctx context.Context, tokenHash []byte, userID ID, userAgent string) error {
+//				panic("mock out the RegisterSession method")
+//			},
+//			SetSessionTokenHashFunc: func(ctx context.Context, sessionID int64, tokenHash []byte) error {
+//				panic("mock out the SetSessionTokenHash method")
+//			},
+//			ValidateSessionFunc: func(ctx context.Context, tokenHash []byte, lifetime SessionLifetime) (Session, error) {
+//				panic("mock out the ValidateSession method")
+//			},
+//		}
+//
+//		// use mockedUserSessionStorage in code that requires UserSessionStorage
+//		// and then make assertions.
+//
+//	}
+type UserSessionStorageMock struct {
+	// DeleteExpiredSessionsFunc mocks the DeleteExpiredSessions method.
+	DeleteExpiredSessionsFunc func(ctx context.Context, lifetime SessionLifetime) (int64, error)
+
+	// DeleteOtherSessionsFunc mocks the DeleteOtherSessions method.
+	DeleteOtherSessionsFunc func(ctx context.Context, userID ID, keepSessionID int64) error
+
+	// DeleteSessionFunc mocks the DeleteSession method.
+	DeleteSessionFunc func(ctx context.Context, userID ID, sessionID int64) error
+
+	// ListSessionsFunc mocks the ListSessions method.
+	ListSessionsFunc func(ctx context.Context, userID ID) ([]Session, error)
+
+	// RegisterSessionFunc mocks the RegisterSession method.
+	RegisterSessionFunc func(ctx context.Context, tokenHash []byte, userID ID, userAgent string) error
+
+	// SetSessionTokenHashFunc mocks the SetSessionTokenHash method.
+	SetSessionTokenHashFunc func(ctx context.Context, sessionID int64, tokenHash []byte) error
+
+	// ValidateSessionFunc mocks the ValidateSession method.
+	ValidateSessionFunc func(ctx context.Context, tokenHash []byte, lifetime SessionLifetime) (Session, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// DeleteExpiredSessions holds details about calls to the DeleteExpiredSessions method.
+		DeleteExpiredSessions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Lifetime is the lifetime argument value.
+			Lifetime SessionLifetime
+		}
+		// DeleteOtherSessions holds details about calls to the DeleteOtherSessions method.
+		DeleteOtherSessions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+			// KeepSessionID is the keepSessionID argument value.
+			KeepSessionID int64
+		}
+		// DeleteSession holds details about calls to the DeleteSession method.
+		DeleteSession []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+			// SessionID is the sessionID argument value.
+			SessionID int64
+		}
+		// ListSessions holds details about calls to the ListSessions method.
+		ListSessions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+		}
+		// RegisterSession holds details about calls to the RegisterSession method.
+		RegisterSession []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// TokenHash is the tokenHash argument value.
+			TokenHash []byte
+			// UserID is the userID argument value.
+			UserID ID
+			// UserAgent is the userAgent argument value.
+			UserAgent string
+		}
+		// SetSessionTokenHash holds details about calls to the SetSessionTokenHash method.
+		SetSessionTokenHash []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SessionID is the sessionID argument value.
+			SessionID int64
+			// TokenHash is the tokenHash argument value.
+			TokenHash []byte
+		}
+		// ValidateSession holds details about calls to the ValidateSession method.
+		ValidateSession []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// TokenHash is the tokenHash argument value.
+			TokenHash []byte
+			// Lifetime is the lifetime argument value.
+			Lifetime SessionLifetime
+		}
+	}
+	lockDeleteExpiredSessions sync.RWMutex
+	lockDeleteOtherSessions   sync.RWMutex
+	lockDeleteSession         sync.RWMutex
+	lockListSessions          sync.RWMutex
+	lockRegisterSession       sync.RWMutex
+	lockSetSessionTokenHash   sync.RWMutex
+	lockValidateSession       sync.RWMutex
+}
+
+// DeleteExpiredSessions calls DeleteExpiredSessionsFunc.
+func (mock *UserSessionStorageMock) DeleteExpiredSessions(ctx context.Context, lifetime SessionLifetime) (int64, error) {
+	callInfo := struct {
+		Ctx      context.Context
+		Lifetime SessionLifetime
+	}{
+		Ctx:      ctx,
+		Lifetime: lifetime,
+	}
+	mock.lockDeleteExpiredSessions.Lock()
+	mock.calls.DeleteExpiredSessions = append(mock.calls.DeleteExpiredSessions, callInfo)
+	mock.lockDeleteExpiredSessions.Unlock()
+	if mock.DeleteExpiredSessionsFunc == nil {
+		var (
+			nOut   int64
+			errOut error
+		)
+		return nOut, errOut
+	}
+	return mock.DeleteExpiredSessionsFunc(ctx, lifetime)
+}
+
+// DeleteExpiredSessionsCalls gets all the calls that were made to DeleteExpiredSessions.
+// Check the length with:
+//
+//	len(mockedUserSessionStorage.DeleteExpiredSessionsCalls())
+func (mock *UserSessionStorageMock) DeleteExpiredSessionsCalls() []struct {
+	Ctx      context.Context
+	Lifetime SessionLifetime
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Lifetime SessionLifetime
+	}
+	mock.lockDeleteExpiredSessions.RLock()
+	calls = mock.calls.DeleteExpiredSessions
+	mock.lockDeleteExpiredSessions.RUnlock()
+	return calls
+}
+
+// DeleteOtherSessions calls DeleteOtherSessionsFunc.
+func (mock *UserSessionStorageMock) DeleteOtherSessions(ctx context.Context, userID ID, keepSessionID int64) error {
+	callInfo := struct {
+		Ctx           context.Context
+		UserID        ID
+		KeepSessionID int64
+	}{
+		Ctx:           ctx,
+		UserID:        userID,
+		KeepSessionID: keepSessionID,
+	}
+	mock.lockDeleteOtherSessions.Lock()
+	mock.calls.DeleteOtherSessions = append(mock.calls.DeleteOtherSessions, callInfo)
+	mock.lockDeleteOtherSessions.Unlock()
+	if mock.DeleteOtherSessionsFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DeleteOtherSessionsFunc(ctx, userID, keepSessionID)
+}
+
+// DeleteOtherSessionsCalls gets all the calls that were made to DeleteOtherSessions.
+// Check the length with:
+//
+//	len(mockedUserSessionStorage.DeleteOtherSessionsCalls())
+func (mock *UserSessionStorageMock) DeleteOtherSessionsCalls() []struct {
+	Ctx           context.Context
+	UserID        ID
+	KeepSessionID int64
+} {
+	var calls []struct {
+		Ctx           context.Context
+		UserID        ID
+		KeepSessionID int64
+	}
+	mock.lockDeleteOtherSessions.RLock()
+	calls = mock.calls.DeleteOtherSessions
+	mock.lockDeleteOtherSessions.RUnlock()
+	return calls
+}
+
+// DeleteSession calls DeleteSessionFunc.
+func (mock *UserSessionStorageMock) DeleteSession(ctx context.Context, userID ID, sessionID int64) error {
+	callInfo := struct {
+		Ctx       context.Context
+		UserID    ID
+		SessionID int64
+	}{
+		Ctx:       ctx,
+		UserID:    userID,
+		SessionID: sessionID,
+	}
+	mock.lockDeleteSession.Lock()
+	mock.calls.DeleteSession = append(mock.calls.DeleteSession, callInfo)
+	mock.lockDeleteSession.Unlock()
+	if mock.DeleteSessionFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DeleteSessionFunc(ctx, userID, sessionID)
+}
+
+// DeleteSessionCalls gets all the calls that were made to DeleteSession.
+// Check the length with:
+//
+//	len(mockedUserSessionStorage.DeleteSessionCalls())
+func (mock *UserSessionStorageMock) DeleteSessionCalls() []struct {
+	Ctx       context.Context
+	UserID    ID
+	SessionID int64
+} {
+	var calls []struct {
+		Ctx       context.Context
+		UserID    ID
+		SessionID int64
+	}
+	mock.lockDeleteSession.RLock()
+	calls = mock.calls.DeleteSession
+	mock.lockDeleteSession.RUnlock()
+	return calls
+}
+
+// ListSessions calls ListSessionsFunc.
+func (mock *UserSessionStorageMock) ListSessions(ctx context.Context, userID ID) ([]Session, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		UserID ID
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockListSessions.Lock()
+	mock.calls.ListSessions = append(mock.calls.ListSessions, callInfo)
+	mock.lockListSessions.Unlock()
+	if mock.ListSessionsFunc == nil {
+		var (
+			sessionsOut []Session
+			errOut      error
+		)
+		return sessionsOut, errOut
+	}
+	return mock.ListSessionsFunc(ctx, userID)
+}
+
+// ListSessionsCalls gets all the calls that were made to ListSessions.
+// Check the length with:
+//
+//	len(mockedUserSessionStorage.ListSessionsCalls())
+func (mock *UserSessionStorageMock) ListSessionsCalls() []struct {
+	Ctx    context.Context
+	UserID ID
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID ID
+	}
+	mock.lockListSessions.RLock()
+	calls = mock.calls.ListSessions
+	mock.lockListSessions.RUnlock()
+	return calls
+}
+
+// RegisterSession calls RegisterSessionFunc.
+func (mock *UserSessionStorageMock) RegisterSession(ctx context.Context, tokenHash []byte, userID ID, userAgent string) error {
+	callInfo := struct {
+		Ctx       context.Context
+		TokenHash []byte
+		UserID    ID
+		UserAgent string
+	}{
+		Ctx:       ctx,
+		TokenHash: tokenHash,
+		UserID:    userID,
+		UserAgent: userAgent,
+	}
+	mock.lockRegisterSession.Lock()
+	mock.calls.RegisterSession = append(mock.calls.RegisterSession, callInfo)
+	mock.lockRegisterSession.Unlock()
+	if mock.RegisterSessionFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.RegisterSessionFunc(ctx, tokenHash, userID, userAgent)
+}
+
+// RegisterSessionCalls gets all the calls that were made to RegisterSession.
+// Check the length with:
+//
+//	len(mockedUserSessionStorage.RegisterSessionCalls())
+func (mock *UserSessionStorageMock) RegisterSessionCalls() []struct {
+	Ctx       context.Context
+	TokenHash []byte
+	UserID    ID
+	UserAgent string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		TokenHash []byte
+		UserID    ID
+		UserAgent string
+	}
+	mock.lockRegisterSession.RLock()
+	calls = mock.calls.RegisterSession
+	mock.lockRegisterSession.RUnlock()
+	return calls
+}
+
+// SetSessionTokenHash calls SetSessionTokenHashFunc.
+func (mock *UserSessionStorageMock) SetSessionTokenHash(ctx context.Context, sessionID int64, tokenHash []byte) error {
+	callInfo := struct {
+		Ctx       context.Context
+		SessionID int64
+		TokenHash []byte
+	}{
+		Ctx:       ctx,
+		SessionID: sessionID,
+		TokenHash: tokenHash,
+	}
+	mock.lockSetSessionTokenHash.Lock()
+	mock.calls.SetSessionTokenHash = append(mock.calls.SetSessionTokenHash, callInfo)
+	mock.lockSetSessionTokenHash.Unlock()
+	if mock.SetSessionTokenHashFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.SetSessionTokenHashFunc(ctx, sessionID, tokenHash)
+}
+
+// SetSessionTokenHashCalls gets all the calls that were made to SetSessionTokenHash.
+// Check the length with:
+//
+//	len(mockedUserSessionStorage.SetSessionTokenHashCalls())
+func (mock *UserSessionStorageMock) SetSessionTokenHashCalls() []struct {
+	Ctx       context.Context
+	SessionID int64
+	TokenHash []byte
+} {
+	var calls []struct {
+		Ctx       context.Context
+		SessionID int64
+		TokenHash []byte
+	}
+	mock.lockSetSessionTokenHash.RLock()
+	calls = mock.calls.SetSessionTokenHash
+	mock.lockSetSessionTokenHash.RUnlock()
+	return calls
+}
+
+// ValidateSession calls ValidateSessionFunc.
+func (mock *UserSessionStorageMock) ValidateSession(ctx context.Context, tokenHash []byte, lifetime SessionLifetime) (Session, error) {
+	callInfo := struct {
+		Ctx       context.Context
+		TokenHash []byte
+		Lifetime  SessionLifetime
+	}{
+		Ctx:       ctx,
+		TokenHash: tokenHash,
+		Lifetime:  lifetime,
+	}
+	mock.lockValidateSession.Lock()
+	mock.calls.ValidateSession = append(mock.calls.ValidateSession, callInfo)
 	mock.lockValidateSession.Unlock()
 	if mock.ValidateSessionFunc == nil {
 		var (
-			sessionOut Session
-			errOut     error
+			sessionOut Session
+			errOut     error
+		)
+		return sessionOut, errOut
+	}
+	return mock.ValidateSessionFunc(ctx, tokenHash, lifetime)
+}
+
+// ValidateSessionCalls gets all the calls that were made to ValidateSession.
+// Check the length with:
+//
+//	len(mockedUserSessionStorage.ValidateSessionCalls())
+func (mock *UserSessionStorageMock) ValidateSessionCalls() []struct {
+	Ctx       context.Context
+	TokenHash []byte
+	Lifetime  SessionLifetime
+} {
+	var calls []struct {
+		Ctx       context.Context
+		TokenHash []byte
+		Lifetime  SessionLifetime
+	}
+	mock.lockValidateSession.RLock()
+	calls = mock.calls.ValidateSession
+	mock.lockValidateSession.RUnlock()
+	return calls
+}
+
+// Ensure, that PasskeyStorageMock does implement PasskeyStorage.
+// If this is not the case, regenerate this file with moq.
+var _ PasskeyStorage = &PasskeyStorageMock{}
+
+// PasskeyStorageMock is a mock implementation of PasskeyStorage.
+//
+//	func TestSomethingThatUsesPasskeyStorage(t *testing.T) {
+//
+//		// make and configure a mocked PasskeyStorage
+//		mockedPasskeyStorage := &PasskeyStorageMock{
+//			AddPasskeyFunc: func(ctx context.Context, userID ID, name string, credential webauthn.Credential) (Passkey, error) {
+//				panic("mock out the AddPasskey method")
+//			},
+//			DeletePasskeyFunc: func(ctx context.Context, userID ID, passkeyID int64) error {
+//				panic("mock out the DeletePasskey method")
+//			},
+//			PasskeysFunc: func(ctx context.Context, userID ID) ([]Passkey, error) {
+//				panic("mock out the Passkeys method")
+//			},
+//			UpdatePasskeyCredentialFunc: func(ctx context.Context, userID ID, credential webauthn.Credential) error {
+//				panic("mock out the UpdatePasskeyCredential method")
+//			},
+//		}
+//
+//		// use mockedPasskeyStorage in code that requires PasskeyStorage
+//		// and then make assertions.
+//
+//	}
+type PasskeyStorageMock struct {
+	// AddPasskeyFunc mocks the AddPasskey method.
+	AddPasskeyFunc func(ctx context.Context, userID ID, name string, credential webauthn.Credential) (Passkey, error)
+
+	// DeletePasskeyFunc mocks the DeletePasskey method.
+	DeletePasskeyFunc func(ctx context.Context, userID ID, passkeyID int64) error
+
+	// PasskeysFunc mocks the Passkeys method.
+	PasskeysFunc func(ctx context.Context, userID ID) ([]Passkey, error)
+
+	// UpdatePasskeyCredentialFunc mocks the UpdatePasskeyCredential method.
+	UpdatePasskeyCredentialFunc func(ctx context.Context, userID ID, credential webauthn.Credential) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AddPasskey holds details about calls to the AddPasskey method.
+		AddPasskey []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+			// Name is the name argument value.
+			Name string
+			// Credential is the credential argument value.
+			Credential webauthn.Credential
+		}
+		// DeletePasskey holds details about calls to the DeletePasskey method.
+		DeletePasskey []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+			// PasskeyID is the passkeyID argument value.
+			PasskeyID int64
+		}
+		// Passkeys holds details about calls to the Passkeys method.
+		Passkeys []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+		}
+		// UpdatePasskeyCredential holds details about calls to the UpdatePasskeyCredential method.
+		UpdatePasskeyCredential []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+			// Credential is the credential argument value.
+			Credential webauthn.Credential
+		}
+	}
+	lockAddPasskey              sync.RWMutex
+	lockDeletePasskey           sync.RWMutex
+	lockPasskeys                sync.RWMutex
+	lockUpdatePasskeyCredential sync.RWMutex
+}
+
+// AddPasskey calls AddPasskeyFunc.
+func (mock *PasskeyStorageMock) AddPasskey(ctx context.Context, userID ID, name string, credential webauthn.Credential) (Passkey, error) {
+	callInfo := struct {
+		Ctx        context.Context
+		UserID     ID
+		Name       string
+		Credential webauthn.Credential
+	}{
+		Ctx:        ctx,
+		UserID:     userID,
+		Name:       name,
+		Credential: credential,
+	}
+	mock.lockAddPasskey.Lock()
+	mock.calls.AddPasskey = append(mock.calls.AddPasskey, callInfo)
+	mock.lockAddPasskey.Unlock()
+	if mock.AddPasskeyFunc == nil {
+		var (
+			passkeyOut Passkey
+			errOut     error
+		)
+		return passkeyOut, errOut
+	}
+	return mock.AddPasskeyFunc(ctx, userID, name, credential)
+}
+
+// AddPasskeyCalls gets all the calls that were made to AddPasskey.
+// Check the length with:
+//
+//	len(mockedPasskeyStorage.AddPasskeyCalls())
+func (mock *PasskeyStorageMock) AddPasskeyCalls() []struct {
+	Ctx        context.Context
+	UserID     ID
+	Name       string
+	Credential webauthn.Credential
+} {
+	var calls []struct {
+		Ctx        context.Context
+		UserID     ID
+		Name       string
+		Credential webauthn.Credential
+	}
+	mock.lockAddPasskey.RLock()
+	calls = mock.calls.AddPasskey
+	mock.lockAddPasskey.RUnlock()
+	return calls
+}
+
+// DeletePasskey calls DeletePasskeyFunc.
+func (mock *PasskeyStorageMock) DeletePasskey(ctx context.Context, userID ID, passkeyID int64) error {
+	callInfo := struct {
+		Ctx       context.Context
+		UserID    ID
+		PasskeyID int64
+	}{
+		Ctx:       ctx,
+		UserID:    userID,
+		PasskeyID: passkeyID,
+	}
+	mock.lockDeletePasskey.Lock()
+	mock.calls.DeletePasskey = append(mock.calls.DeletePasskey, callInfo)
+	mock.lockDeletePasskey.Unlock()
+	if mock.DeletePasskeyFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DeletePasskeyFunc(ctx, userID, passkeyID)
+}
+
+// DeletePasskeyCalls gets all the calls that were made to DeletePasskey.
+// Check the length with:
+//
+//	len(mockedPasskeyStorage.DeletePasskeyCalls())
+func (mock *PasskeyStorageMock) DeletePasskeyCalls() []struct {
+	Ctx       context.Context
+	UserID    ID
+	PasskeyID int64
+} {
+	var calls []struct {
+		Ctx       context.Context
+		UserID    ID
+		PasskeyID int64
+	}
+	mock.lockDeletePasskey.RLock()
+	calls = mock.calls.DeletePasskey
+	mock.lockDeletePasskey.RUnlock()
+	return calls
+}
+
+// Passkeys calls PasskeysFunc.
+func (mock *PasskeyStorageMock) Passkeys(ctx context.Context, userID ID) ([]Passkey, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		UserID ID
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockPasskeys.Lock()
+	mock.calls.Passkeys = append(mock.calls.Passkeys, callInfo)
+	mock.lockPasskeys.Unlock()
+	if mock.PasskeysFunc == nil {
+		var (
+			passkeysOut []Passkey
+			errOut      error
+		)
+		return passkeysOut, errOut
+	}
+	return mock.PasskeysFunc(ctx, userID)
+}
+
+// PasskeysCalls gets all the calls that were made to Passkeys.
+// Check the length with:
+//
+//	len(mockedPasskeyStorage.PasskeysCalls())
+func (mock *PasskeyStorageMock) PasskeysCalls() []struct {
+	Ctx    context.Context
+	UserID ID
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID ID
+	}
+	mock.lockPasskeys.RLock()
+	calls = mock.calls.Passkeys
+	mock.lockPasskeys.RUnlock()
+	return calls
+}
+
+// UpdatePasskeyCredential calls UpdatePasskeyCredentialFunc.
+func (mock *PasskeyStorageMock) UpdatePasskeyCredential(ctx context.Context, userID ID, credential webauthn.Credential) error {
+	callInfo := struct {
+		Ctx        context.Context
+		UserID     ID
+		Credential webauthn.Credential
+	}{
+		Ctx:        ctx,
+		UserID:     userID,
+		Credential: credential,
+	}
+	mock.lockUpdatePasskeyCredential.Lock()
+	mock.calls.UpdatePasskeyCredential = append(mock.calls.UpdatePasskeyCredential, callInfo)
+	mock.lockUpdatePasskeyCredential.Unlock()
+	if mock.UpdatePasskeyCredentialFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.UpdatePasskeyCredentialFunc(ctx, userID, credential)
+}
+
+// UpdatePasskeyCredentialCalls gets all the calls that were made to UpdatePasskeyCredential.
+// Check the length with:
+//
+//	len(mockedPasskeyStorage.UpdatePasskeyCredentialCalls())
+func (mock *PasskeyStorageMock) UpdatePasskeyCredentialCalls() []struct {
+	Ctx        context.Context
+	UserID     ID
+	Credential webauthn.Credential
+} {
+	var calls []struct {
+		Ctx        context.Context
+		UserID     ID
+		Credential webauthn.Credential
+	}
+	mock.lockUpdatePasskeyCredential.RLock()
+	calls = mock.calls.UpdatePasskeyCredential
+	mock.lockUpdatePasskeyCredential.RUnlock()
+	return calls
+}
+
+// Ensure, that PersonalTokenStorageMock does implement PersonalTokenStorage.
+// If this is not the case, regenerate this file with moq.
+var _ PersonalTokenStorage = &PersonalTokenStorageMock{}
+
+// PersonalTokenStorageMock is a mock implementation of PersonalTokenStorage.
+//
+//	func TestSomethingThatUsesPersonalTokenStorage(t *testing.T) {
+//
+//		// make and configure a mocked PersonalTokenStorage
+//		mockedPersonalTokenStorage := &PersonalTokenStorageMock{
+//			CreatePersonalTokenFunc: func(ctx context.Context, userID ID, name string, tokenHash []byte, scopes []Scope, expiresAt time.Time) (PersonalToken, error) {
+//				panic("mock out the CreatePersonalToken method")
+//			},
+//			DeletePersonalTokenFunc: func(ctx context.Context, userID ID, tokenID int64) error {
+//				panic("mock out the DeletePersonalToken method")
+//			},
+//			PersonalTokensFunc: func(ctx context.Context, userID ID) ([]PersonalToken, error) {
+//				panic("mock out the PersonalTokens method")
+//			},
+//			ValidatePersonalTokenFunc: func(ctx context.Context, tokenHash []byte) (PersonalToken, error) {
+//				panic("mock out the ValidatePersonalToken method")
+//			},
+//		}
+//
+//		// use mockedPersonalTokenStorage in code that requires PersonalTokenStorage
+//		// and then make assertions.
+//
+//	}
+type PersonalTokenStorageMock struct {
+	// CreatePersonalTokenFunc mocks the CreatePersonalToken method.
+	CreatePersonalTokenFunc func(ctx context.Context, userID ID, name string, tokenHash []byte, scopes []Scope, expiresAt time.Time) (PersonalToken, error)
+
+	// DeletePersonalTokenFunc mocks the DeletePersonalToken method.
+	DeletePersonalTokenFunc func(ctx context.Context, userID ID, tokenID int64) error
+
+	// PersonalTokensFunc mocks the PersonalTokens method.
+	PersonalTokensFunc func(ctx context.Context, userID ID) ([]PersonalToken, error)
+
+	// ValidatePersonalTokenFunc mocks the ValidatePersonalToken method.
+	ValidatePersonalTokenFunc func(ctx context.Context, tokenHash []byte) (PersonalToken, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CreatePersonalToken holds details about calls to the CreatePersonalToken method.
+		CreatePersonalToken []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+			// Name is the name argument value.
+			Name string
+			// TokenHash is the tokenHash argument value.
+			TokenHash []byte
+			// Scopes is the scopes argument value.
+			Scopes []Scope
+			// ExpiresAt is the expiresAt argument value.
+			ExpiresAt time.Time
+		}
+		// DeletePersonalToken holds details about calls to the DeletePersonalToken method.
+		DeletePersonalToken []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+			// TokenID is the tokenID argument value.
+			TokenID int64
+		}
+		// PersonalTokens holds details about calls to the PersonalTokens method.
+		PersonalTokens []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+		}
+		// ValidatePersonalToken holds details about calls to the ValidatePersonalToken method.
+		ValidatePersonalToken []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// TokenHash is the tokenHash argument value.
+			TokenHash []byte
+		}
+	}
+	lockCreatePersonalToken   sync.RWMutex
+	lockDeletePersonalToken   sync.RWMutex
+	lockPersonalTokens        sync.RWMutex
+	lockValidatePersonalToken sync.RWMutex
+}
+
+// CreatePersonalToken calls CreatePersonalTokenFunc.
+func (mock *PersonalTokenStorageMock) CreatePersonalToken(ctx context.Context, userID ID, name string, tokenHash []byte, scopes []Scope, expiresAt time.Time) (PersonalToken, error) {
+	callInfo := struct {
+		Ctx       context.Context
+		UserID    ID
+		Name      string
+		TokenHash []byte
+		Scopes    []Scope
+		ExpiresAt time.Time
+	}{
+		Ctx:       ctx,
+		UserID:    userID,
+		Name:      name,
+		TokenHash: tokenHash,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	mock.lockCreatePersonalToken.Lock()
+	mock.calls.CreatePersonalToken = append(mock.calls.CreatePersonalToken, callInfo)
+	mock.lockCreatePersonalToken.Unlock()
+	if mock.CreatePersonalTokenFunc == nil {
+		var (
+			personalTokenOut PersonalToken
+			errOut           error
 		)
-		return sessionOut, errOut
+		return personalTokenOut, errOut
 	}
-	return mock.ValidateSessionFunc(ctx, token)
+	return mock.CreatePersonalTokenFunc(ctx, userID, name, tokenHash, scopes, expiresAt)
 }
 
-// ValidateSessionCalls gets all the calls that were made to ValidateSession.
+// CreatePersonalTokenCalls gets all the calls that were made to CreatePersonalToken.
 // Check the length with:
 //
-//	len(mockedUserSessionStorage.ValidateSessionCalls())
-func (mock *UserSessionStorageMock) ValidateSessionCalls() []struct {
-	Ctx   context.Context
-	Token []byte
+//	len(mockedPersonalTokenStorage.CreatePersonalTokenCalls())
+func (mock *PersonalTokenStorageMock) CreatePersonalTokenCalls() []struct {
+	Ctx       context.Context
+	UserID    ID
+	Name      string
+	TokenHash []byte
+	Scopes    []Scope
+	ExpiresAt time.Time
 } {
 	var calls []struct {
-		Ctx   context.Context
-		Token []byte
+		Ctx       context.Context
+		UserID    ID
+		Name      string
+		TokenHash []byte
+		Scopes    []Scope
+		ExpiresAt time.Time
 	}
-	mock.lockValidateSession.RLock()
-	calls = mock.calls.ValidateSession
-	mock.lockValidateSession.RUnlock()
+	mock.lockCreatePersonalToken.RLock()
+	calls = mock.calls.CreatePersonalToken
+	mock.lockCreatePersonalToken.RUnlock()
+	return calls
+}
+
+// DeletePersonalToken calls DeletePersonalTokenFunc.
+func (mock *PersonalTokenStorageMock) DeletePersonalToken(ctx context.Context, userID ID, tokenID int64) error {
+	callInfo := struct {
+		Ctx     context.Context
+		UserID  ID
+		TokenID int64
+	}{
+		Ctx:     ctx,
+		UserID:  userID,
+		TokenID: tokenID,
+	}
+	mock.lockDeletePersonalToken.Lock()
+	mock.calls.DeletePersonalToken = append(mock.calls.DeletePersonalToken, callInfo)
+	mock.lockDeletePersonalToken.Unlock()
+	if mock.DeletePersonalTokenFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DeletePersonalTokenFunc(ctx, userID, tokenID)
+}
+
+// DeletePersonalTokenCalls gets all the calls that were made to DeletePersonalToken.
+// Check the length with:
+//
+//	len(mockedPersonalTokenStorage.DeletePersonalTokenCalls())
+func (mock *PersonalTokenStorageMock) DeletePersonalTokenCalls() []struct {
+	Ctx     context.Context
+	UserID  ID
+	TokenID int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		UserID  ID
+		TokenID int64
+	}
+	mock.lockDeletePersonalToken.RLock()
+	calls = mock.calls.DeletePersonalToken
+	mock.lockDeletePersonalToken.RUnlock()
+	return calls
+}
+
+// PersonalTokens calls PersonalTokensFunc.
+func (mock *PersonalTokenStorageMock) PersonalTokens(ctx context.Context, userID ID) ([]PersonalToken, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		UserID ID
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockPersonalTokens.Lock()
+	mock.calls.PersonalTokens = append(mock.calls.PersonalTokens, callInfo)
+	mock.lockPersonalTokens.Unlock()
+	if mock.PersonalTokensFunc == nil {
+		var (
+			personalTokensOut []PersonalToken
+			errOut            error
+		)
+		return personalTokensOut, errOut
+	}
+	return mock.PersonalTokensFunc(ctx, userID)
+}
+
+// PersonalTokensCalls gets all the calls that were made to PersonalTokens.
+// Check the length with:
+//
+//	len(mockedPersonalTokenStorage.PersonalTokensCalls())
+func (mock *PersonalTokenStorageMock) PersonalTokensCalls() []struct {
+	Ctx    context.Context
+	UserID ID
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID ID
+	}
+	mock.lockPersonalTokens.RLock()
+	calls = mock.calls.PersonalTokens
+	mock.lockPersonalTokens.RUnlock()
+	return calls
+}
+
+// ValidatePersonalToken calls ValidatePersonalTokenFunc.
+func (mock *PersonalTokenStorageMock) ValidatePersonalToken(ctx context.Context, tokenHash []byte) (PersonalToken, error) {
+	callInfo := struct {
+		Ctx       context.Context
+		TokenHash []byte
+	}{
+		Ctx:       ctx,
+		TokenHash: tokenHash,
+	}
+	mock.lockValidatePersonalToken.Lock()
+	mock.calls.ValidatePersonalToken = append(mock.calls.ValidatePersonalToken, callInfo)
+	mock.lockValidatePersonalToken.Unlock()
+	if mock.ValidatePersonalTokenFunc == nil {
+		var (
+			personalTokenOut PersonalToken
+			errOut           error
+		)
+		return personalTokenOut, errOut
+	}
+	return mock.ValidatePersonalTokenFunc(ctx, tokenHash)
+}
+
+// ValidatePersonalTokenCalls gets all the calls that were made to ValidatePersonalToken.
+// Check the length with:
+//
+//	len(mockedPersonalTokenStorage.ValidatePersonalTokenCalls())
+func (mock *PersonalTokenStorageMock) ValidatePersonalTokenCalls() []struct {
+	Ctx       context.Context
+	TokenHash []byte
+} {
+	var calls []struct {
+		Ctx       context.Context
+		TokenHash []byte
+	}
+	mock.lockValidatePersonalToken.RLock()
+	calls = mock.calls.ValidatePersonalToken
+	mock.lockValidatePersonalToken.RUnlock()
+	return calls
+}
+
+// Ensure, that DelegationStorageMock does implement DelegationStorage.
+// If this is not the case, regenerate this file with moq.
+var _ DelegationStorage = &DelegationStorageMock{}
+
+// DelegationStorageMock is a mock implementation of DelegationStorage.
+//
+//	func TestSomethingThatUsesDelegationStorage(t *testing.T) {
+//
+//		// make and configure a mocked DelegationStorage
+//		mockedDelegationStorage := &DelegationStorageMock{
+//			AcceptDelegationFunc: func(ctx context.Context, inviteHash []byte, delegateID ID, mirrorNotifications bool) (Delegation, error) {
+//				panic("mock out the AcceptDelegation method")
+//			},
+//			CreateDelegationFunc: func(ctx context.Context, ownerID ID, access DelegationAccess, inviteHash []byte, inviteExpiresAt time.Time) (Delegation, error) {
+//				panic("mock out the CreateDelegation method")
+//			},
+//			DelegationFunc: func(ctx context.Context, ownerID ID, delegateID ID) (Delegation, error) {
+//				panic("mock out the Delegation method")
+//			},
+//			DelegationsFunc: func(ctx context.Context, userID ID) ([]Delegation, error) {
+//				panic("mock out the Delegations method")
+//			},
+//			DeleteDelegationFunc: func(ctx context.Context, userID ID, delegationID int64) error {
+//				panic("mock out the DeleteDelegation method")
+//			},
+//			MirroringDelegatesFunc: func(ctx context.Context, ownerID ID) ([]ID, error) {
+//				panic("mock out the MirroringDelegates method")
+//			},
+//		}
+//
+//		// use mockedDelegationStorage in code that requires DelegationStorage
+//		// and then make assertions.
+//
+//	}
+type DelegationStorageMock struct {
+	// AcceptDelegationFunc mocks the AcceptDelegation method.
+	AcceptDelegationFunc func(ctx context.Context, inviteHash []byte, delegateID ID, mirrorNotifications bool) (Delegation, error)
+
+	// CreateDelegationFunc mocks the CreateDelegation method.
+	CreateDelegationFunc func(ctx context.Context, ownerID ID, access DelegationAccess, inviteHash []byte, inviteExpiresAt time.Time) (Delegation, error)
+
+	// DelegationFunc mocks the Delegation method.
+	DelegationFunc func(ctx context.Context, ownerID ID, delegateID ID) (Delegation, error)
+
+	// DelegationsFunc mocks the Delegations method.
+	DelegationsFunc func(ctx context.Context, userID ID) ([]Delegation, error)
+
+	// DeleteDelegationFunc mocks the DeleteDelegation method.
+	DeleteDelegationFunc func(ctx context.Context, userID ID, delegationID int64) error
+
+	// MirroringDelegatesFunc mocks the MirroringDelegates method.
+	MirroringDelegatesFunc func(ctx context.Context, ownerID ID) ([]ID, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AcceptDelegation holds details about calls to the AcceptDelegation method.
+		AcceptDelegation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// InviteHash is the inviteHash argument value.
+			InviteHash []byte
+			// DelegateID is the delegateID argument value.
+			DelegateID ID
+			// MirrorNotifications is the mirrorNotifications argument value.
+			MirrorNotifications bool
+		}
+		// CreateDelegation holds details about calls to the CreateDelegation method.
+		CreateDelegation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// OwnerID is the ownerID argument value.
+			OwnerID ID
+			// Access is the access argument value.
+			Access DelegationAccess
+			// InviteHash is the inviteHash argument value.
+			InviteHash []byte
+			// InviteExpiresAt is the inviteExpiresAt argument value.
+			InviteExpiresAt time.Time
+		}
+		// Delegation holds details about calls to the Delegation method.
+		Delegation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// OwnerID is the ownerID argument value.
+			OwnerID ID
+			// DelegateID is the delegateID argument value.
+			DelegateID ID
+		}
+		// Delegations holds details about calls to the Delegations method.
+		Delegations []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+		}
+		// DeleteDelegation holds details about calls to the DeleteDelegation method.
+		DeleteDelegation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID ID
+			// DelegationID is the delegationID argument value.
+			DelegationID int64
+		}
+		// MirroringDelegates holds details about calls to the MirroringDelegates method.
+		MirroringDelegates []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// OwnerID is the ownerID argument value.
+			OwnerID ID
+		}
+	}
+	lockAcceptDelegation   sync.RWMutex
+	lockCreateDelegation   sync.RWMutex
+	lockDelegation         sync.RWMutex
+	lockDelegations        sync.RWMutex
+	lockDeleteDelegation   sync.RWMutex
+	lockMirroringDelegates sync.RWMutex
+}
+
+// AcceptDelegation calls AcceptDelegationFunc.
+func (mock *DelegationStorageMock) AcceptDelegation(ctx context.Context, inviteHash []byte, delegateID ID, mirrorNotifications bool) (Delegation, error) {
+	callInfo := struct {
+		Ctx                 context.Context
+		InviteHash          []byte
+		DelegateID          ID
+		MirrorNotifications bool
+	}{
+		Ctx:                 ctx,
+		InviteHash:          inviteHash,
+		DelegateID:          delegateID,
+		MirrorNotifications: mirrorNotifications,
+	}
+	mock.lockAcceptDelegation.Lock()
+	mock.calls.AcceptDelegation = append(mock.calls.AcceptDelegation, callInfo)
+	mock.lockAcceptDelegation.Unlock()
+	if mock.AcceptDelegationFunc == nil {
+		var (
+			delegationOut Delegation
+			errOut        error
+		)
+		return delegationOut, errOut
+	}
+	return mock.AcceptDelegationFunc(ctx, inviteHash, delegateID, mirrorNotifications)
+}
+
+// AcceptDelegationCalls gets all the calls that were made to AcceptDelegation.
+// Check the length with:
+//
+//	len(mockedDelegationStorage.AcceptDelegationCalls())
+func (mock *DelegationStorageMock) AcceptDelegationCalls() []struct {
+	Ctx                 context.Context
+	InviteHash          []byte
+	DelegateID          ID
+	MirrorNotifications bool
+} {
+	var calls []struct {
+		Ctx                 context.Context
+		InviteHash          []byte
+		DelegateID          ID
+		MirrorNotifications bool
+	}
+	mock.lockAcceptDelegation.RLock()
+	calls = mock.calls.AcceptDelegation
+	mock.lockAcceptDelegation.RUnlock()
+	return calls
+}
+
+// CreateDelegation calls CreateDelegationFunc.
+func (mock *DelegationStorageMock) CreateDelegation(ctx context.Context, ownerID ID, access DelegationAccess, inviteHash []byte, inviteExpiresAt time.Time) (Delegation, error) {
+	callInfo := struct {
+		Ctx             context.Context
+		OwnerID         ID
+		Access          DelegationAccess
+		InviteHash      []byte
+		InviteExpiresAt time.Time
+	}{
+		Ctx:             ctx,
+		OwnerID:         ownerID,
+		Access:          access,
+		InviteHash:      inviteHash,
+		InviteExpiresAt: inviteExpiresAt,
+	}
+	mock.lockCreateDelegation.Lock()
+	mock.calls.CreateDelegation = append(mock.calls.CreateDelegation, callInfo)
+	mock.lockCreateDelegation.Unlock()
+	if mock.CreateDelegationFunc == nil {
+		var (
+			delegationOut Delegation
+			errOut        error
+		)
+		return delegationOut, errOut
+	}
+	return mock.CreateDelegationFunc(ctx, ownerID, access, inviteHash, inviteExpiresAt)
+}
+
+// CreateDelegationCalls gets all the calls that were made to CreateDelegation.
+// Check the length with:
+//
+//	len(mockedDelegationStorage.CreateDelegationCalls())
+func (mock *DelegationStorageMock) CreateDelegationCalls() []struct {
+	Ctx             context.Context
+	OwnerID         ID
+	Access          DelegationAccess
+	InviteHash      []byte
+	InviteExpiresAt time.Time
+} {
+	var calls []struct {
+		Ctx             context.Context
+		OwnerID         ID
+		Access          DelegationAccess
+		InviteHash      []byte
+		InviteExpiresAt time.Time
+	}
+	mock.lockCreateDelegation.RLock()
+	calls = mock.calls.CreateDelegation
+	mock.lockCreateDelegation.RUnlock()
+	return calls
+}
+
+// Delegation calls DelegationFunc.
+func (mock *DelegationStorageMock) Delegation(ctx context.Context, ownerID ID, delegateID ID) (Delegation, error) {
+	callInfo := struct {
+		Ctx        context.Context
+		OwnerID    ID
+		DelegateID ID
+	}{
+		Ctx:        ctx,
+		OwnerID:    ownerID,
+		DelegateID: delegateID,
+	}
+	mock.lockDelegation.Lock()
+	mock.calls.Delegation = append(mock.calls.Delegation, callInfo)
+	mock.lockDelegation.Unlock()
+	if mock.DelegationFunc == nil {
+		var (
+			delegationOut Delegation
+			errOut        error
+		)
+		return delegationOut, errOut
+	}
+	return mock.DelegationFunc(ctx, ownerID, delegateID)
+}
+
+// DelegationCalls gets all the calls that were made to Delegation.
+// Check the length with:
+//
+//	len(mockedDelegationStorage.DelegationCalls())
+func (mock *DelegationStorageMock) DelegationCalls() []struct {
+	Ctx        context.Context
+	OwnerID    ID
+	DelegateID ID
+} {
+	var calls []struct {
+		Ctx        context.Context
+		OwnerID    ID
+		DelegateID ID
+	}
+	mock.lockDelegation.RLock()
+	calls = mock.calls.Delegation
+	mock.lockDelegation.RUnlock()
+	return calls
+}
+
+// Delegations calls DelegationsFunc.
+func (mock *DelegationStorageMock) Delegations(ctx context.Context, userID ID) ([]Delegation, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		UserID ID
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockDelegations.Lock()
+	mock.calls.Delegations = append(mock.calls.Delegations, callInfo)
+	mock.lockDelegations.Unlock()
+	if mock.DelegationsFunc == nil {
+		var (
+			delegationsOut []Delegation
+			errOut         error
+		)
+		return delegationsOut, errOut
+	}
+	return mock.DelegationsFunc(ctx, userID)
+}
+
+// DelegationsCalls gets all the calls that were made to Delegations.
+// Check the length with:
+//
+//	len(mockedDelegationStorage.DelegationsCalls())
+func (mock *DelegationStorageMock) DelegationsCalls() []struct {
+	Ctx    context.Context
+	UserID ID
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID ID
+	}
+	mock.lockDelegations.RLock()
+	calls = mock.calls.Delegations
+	mock.lockDelegations.RUnlock()
+	return calls
+}
+
+// DeleteDelegation calls DeleteDelegationFunc.
+func (mock *DelegationStorageMock) DeleteDelegation(ctx context.Context, userID ID, delegationID int64) error {
+	callInfo := struct {
+		Ctx          context.Context
+		UserID       ID
+		DelegationID int64
+	}{
+		Ctx:          ctx,
+		UserID:       userID,
+		DelegationID: delegationID,
+	}
+	mock.lockDeleteDelegation.Lock()
+	mock.calls.DeleteDelegation = append(mock.calls.DeleteDelegation, callInfo)
+	mock.lockDeleteDelegation.Unlock()
+	if mock.DeleteDelegationFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DeleteDelegationFunc(ctx, userID, delegationID)
+}
+
+// DeleteDelegationCalls gets all the calls that were made to DeleteDelegation.
+// Check the length with:
+//
+//	len(mockedDelegationStorage.DeleteDelegationCalls())
+func (mock *DelegationStorageMock) DeleteDelegationCalls() []struct {
+	Ctx          context.Context
+	UserID       ID
+	DelegationID int64
+} {
+	var calls []struct {
+		Ctx          context.Context
+		UserID       ID
+		DelegationID int64
+	}
+	mock.lockDeleteDelegation.RLock()
+	calls = mock.calls.DeleteDelegation
+	mock.lockDeleteDelegation.RUnlock()
+	return calls
+}
+
+// MirroringDelegates calls MirroringDelegatesFunc.
+func (mock *DelegationStorageMock) MirroringDelegates(ctx context.Context, ownerID ID) ([]ID, error) {
+	callInfo := struct {
+		Ctx     context.Context
+		OwnerID ID
+	}{
+		Ctx:     ctx,
+		OwnerID: ownerID,
+	}
+	mock.lockMirroringDelegates.Lock()
+	mock.calls.MirroringDelegates = append(mock.calls.MirroringDelegates, callInfo)
+	mock.lockMirroringDelegates.Unlock()
+	if mock.MirroringDelegatesFunc == nil {
+		var (
+			iDsOut []ID
+			errOut error
+		)
+		return iDsOut, errOut
+	}
+	return mock.MirroringDelegatesFunc(ctx, ownerID)
+}
+
+// MirroringDelegatesCalls gets all the calls that were made to MirroringDelegates.
+// Check the length with:
+//
+//	len(mockedDelegationStorage.MirroringDelegatesCalls())
+func (mock *DelegationStorageMock) MirroringDelegatesCalls() []struct {
+	Ctx     context.Context
+	OwnerID ID
+} {
+	var calls []struct {
+		Ctx     context.Context
+		OwnerID ID
+	}
+	mock.lockMirroringDelegates.RLock()
+	calls = mock.calls.MirroringDelegates
+	mock.lockMirroringDelegates.RUnlock()
 	return calls
 }
@@ -2,22 +2,35 @@ package user
 
 import "testing"
 
-//go:generate moq -out user_mock_test.go -stub . UserStorage UserSessionStorage
+//go:generate moq -out user_mock_test.go -stub . UserStorage UserSessionStorage PasskeyStorage PersonalTokenStorage DelegationStorage
 
 type mockUserService struct {
 	UserService
-	users    *UserStorageMock
-	sessions *UserSessionStorageMock
+	users       *UserStorageMock
+	sessions    *UserSessionStorageMock
+	passkeys    *PasskeyStorageMock
+	tokens      *PersonalTokenStorageMock
+	delegations *DelegationStorageMock
 }
 
 func newMockUserService(*testing.T) *mockUserService {
 	s := &mockUserService{
-		users:    &UserStorageMock{},
-		sessions: &UserSessionStorageMock{},
+		users:       &UserStorageMock{},
+		sessions:    &UserSessionStorageMock{},
+		passkeys:    &PasskeyStorageMock{},
+		tokens:      &PersonalTokenStorageMock{},
+		delegations: &DelegationStorageMock{},
 	}
 	s.UserService = UserService{
 		s.users,
 		s.sessions,
+		SessionLifetime{},
+		s.passkeys,
+		s.tokens,
+		s.delegations,
+		secretHasher{pepper: []byte("test pepper")},
+		nil,
+		newPasskeyCeremonies(),
 	}
 	return s
 }